@@ -0,0 +1,149 @@
+// Package basenc provides the base64, base32 and basenc commands, which
+// encode data to text and decode it back, streaming any amount of input.
+package basenc
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+
+	"github.com/skraio/unix-utilities/cmdflags"
+	"github.com/skraio/unix-utilities/internal/exit"
+	"github.com/skraio/unix-utilities/internal/fileinput"
+	"github.com/spf13/cobra"
+)
+
+// encFlags holds flags for the encoding commands.
+type encFlags struct {
+	decode        bool
+	ignoreGarbage bool
+	wrap          int
+
+	// encoding is the name of the encoding basenc was asked for.
+	encoding string
+}
+
+// encodingFlags are the flags choosing the encoding of basenc, in the
+// order of its help.
+var encodingFlags = []struct{ name, description string }{
+	{"base64", "same as 'base64' program (RFC4648 section 4)"},
+	{"base64url", "file- and url-safe base64 (RFC4648 section 5)"},
+	{"base32", "same as 'base32' program (RFC4648 section 6)"},
+	{"base32hex", "extended hex alphabet base32 (RFC4648 section 7)"},
+	{"base16", "hex encoding (RFC4648 section 8)"},
+	{"base2msbf", "bit string with most significant bit (msb) first"},
+	{"base2lsbf", "bit string with least significant bit (lsb) first"},
+	{"z85", "ascii85-like encoding (ZeroMQ spec:32/Z85); when encoding, input length must be a multiple of 4; when decoding, input length must be a multiple of 5"},
+}
+
+// The commands: base64 and base32 with a fixed encoding, and basenc with
+// one chosen by flag.
+var (
+	Base64Cmd = newCmd("base64", "Base64 encode/decode data and print to standard output")
+	Base32Cmd = newCmd("base32", "Base32 encode/decode data and print to standard output")
+	Cmd       = newCmd("basenc", "Encode/decode data and print to standard output")
+)
+
+// choice is a flag setting a string to its name, so that the last of
+// several such flags given wins.
+type choice struct {
+	dst  *string
+	name string
+}
+
+// String returns the default value of the flag.
+func (c choice) String() string { return "false" }
+
+// Set records the flag as the choice made.
+func (c choice) Set(string) error {
+	*c.dst = c.name
+	return nil
+}
+
+// Reset drops the choice made, for the command to run again.
+func (c choice) Reset() { *c.dst = "" }
+
+// Type returns the type of the flag's value.
+func (c choice) Type() string { return "bool" }
+
+// newCmd returns an encoding command. The commands named after an encoding
+// use it; basenc takes one from its flags.
+func newCmd(name, short string) *cobra.Command {
+	f := &encFlags{}
+	flags := []cmdflags.Flag{
+		{Value: &f.decode, Name: "decode", ShortHand: "d", DefaultValue: false, Description: "decode data"},
+		{Value: &f.ignoreGarbage, Name: "ignore-garbage", ShortHand: "i", DefaultValue: false, Description: "when decoding, ignore non-alphabet characters"},
+	}
+	intFlags := []cmdflags.IntFlag{
+		{Value: &f.wrap, Name: "wrap", ShortHand: "w", DefaultValue: 76, Description: "wrap encoded lines after COLS character; use 0 to disable line wrapping"},
+	}
+
+	cmd := &cobra.Command{
+		Use:           name + " [-f flags] [file]",
+		Short:         short,
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return exit.Status(execute(name, f, args))
+		},
+	}
+	cmdflags.ParseFlags(flags, cmd)
+	cmdflags.ParseIntFlags(intFlags, cmd)
+
+	if name == "basenc" {
+		for _, e := range encodingFlags {
+			flag := cmd.Flags().VarPF(choice{dst: &f.encoding, name: e.name}, e.name, "", e.description)
+			flag.NoOptDefVal = "true"
+		}
+	} else {
+		f.encoding = name
+	}
+
+	cmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", name, err)
+		return exit.Status(1)
+	})
+	return cmd
+}
+
+// execute runs an encoding command with given arguments and returns its
+// exit status.
+func execute(name string, f *encFlags, args []string) int {
+	e, ok := encodings[f.encoding]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "%s: missing encoding type\n", name)
+		return 1
+	}
+	if len(args) > 1 {
+		fmt.Fprintf(os.Stderr, "%s: extra operand '%s'\n", name, args[1])
+		return 1
+	}
+	if f.wrap < 0 {
+		fmt.Fprintf(os.Stderr, "%s: invalid wrap size: '%d'\n", name, f.wrap)
+		return 1
+	}
+
+	operand := fileinput.Args(args)[0]
+	in, err := fileinput.Open(operand)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %s: %v\n", name, operand, exit.Unwrap(err))
+		return 1
+	}
+	defer fileinput.Close(in)
+
+	if f.decode {
+		err = decode(in, os.Stdout, e, f.ignoreGarbage)
+	} else {
+		err = encode(in, os.Stdout, e, f.wrap)
+	}
+	if err != nil {
+		var pe *fs.PathError
+		if errors.As(err, &pe) {
+			err = fmt.Errorf("%s: %v", operand, exit.Unwrap(pe))
+		}
+		fmt.Fprintf(os.Stderr, "%s: %v\n", name, err)
+		return 1
+	}
+	return 0
+}
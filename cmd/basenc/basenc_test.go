@@ -0,0 +1,93 @@
+package basenc
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/skraio/unix-utilities/internal/assert"
+)
+
+func TestEncode(t *testing.T) {
+	tests := []struct {
+		encoding string
+		width    int
+		input    string
+		want     string
+	}{
+		{"base64", 76, "abc", "YWJj\n"},
+		{"base64", 76, "ab", "YWI=\n"},
+		{"base64", 4, "abcdef", "YWJj\nZGVm\n"},
+		{"base64", 3, "abcd", "YWJ\njZA\n==\n"},
+		{"base64", 0, "abcd", "YWJjZA=="},
+		{"base64", 76, "", ""},
+		{"base64url", 0, "\xfb\xff", "-_8="},
+		{"base32", 76, "abc", "MFRGG===\n"},
+		{"base32hex", 76, "abc", "C5H66===\n"},
+		{"base16", 76, "\x01\xab", "01AB\n"},
+		{"base2msbf", 76, "a", "01100001\n"},
+		{"base2lsbf", 76, "a", "10000110\n"},
+		{"z85", 76, "\x86\x4f\xd2\x6f\xb5\x59\xf7\x5b", "HelloWorld\n"},
+	}
+
+	for _, test := range tests {
+		var out bytes.Buffer
+		err := encode(strings.NewReader(test.input), &out, encodings[test.encoding], test.width)
+		assert.Equal(t, err, nil)
+		assert.Equal(t, out.String(), test.want)
+	}
+}
+
+func TestEncodeZ85Length(t *testing.T) {
+	var out bytes.Buffer
+	err := encode(strings.NewReader("abc"), &out, encodings["z85"], 76)
+	assert.Equal(t, err != nil, true)
+}
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		encoding      string
+		ignoreGarbage bool
+		input         string
+		want          string
+		invalid       bool
+	}{
+		{"base64", false, "YWJj\nZGVm\n", "abcdef", false},
+		{"base64", false, "YQ==YQ==", "aa", false},
+		{"base64", false, "YQ", "a", true},
+		{"base64", false, "YQ==Y!Q", "a", true},
+		{"base64", true, "!Y\tQ==", "a", false},
+		{"base64url", false, "-_8=", "\xfb\xff", false},
+		{"base32", false, "MFRGG===", "abc", false},
+		{"base32hex", false, "C5H66===", "abc", false},
+		{"base16", false, "01AB", "\x01\xab", false},
+		{"base16", false, "01ab", "\x01", true},
+		{"base2msbf", false, "01100001", "a", false},
+		{"base2lsbf", false, "10000110", "a", false},
+		{"z85", false, "HelloWorld", "\x86\x4f\xd2\x6f\xb5\x59\xf7\x5b", false},
+	}
+
+	for _, test := range tests {
+		var out bytes.Buffer
+		err := decode(strings.NewReader(test.input), &out, encodings[test.encoding], test.ignoreGarbage)
+		assert.Equal(t, err == errInvalid, test.invalid)
+		assert.Equal(t, out.String(), test.want)
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	input := make([]byte, 3*blocks*5+7)
+	for i := range input {
+		input[i] = byte(i * 7)
+	}
+	input = input[:len(input)/4*4]
+
+	for name, e := range encodings {
+		var encoded, decoded bytes.Buffer
+		assert.Equal(t, encode(bytes.NewReader(input), &encoded, e, 76), nil)
+		assert.Equal(t, decode(&encoded, &decoded, e, false), nil)
+		if !bytes.Equal(decoded.Bytes(), input) {
+			t.Errorf("%s: round trip changed the data", name)
+		}
+	}
+}
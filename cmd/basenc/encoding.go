@@ -0,0 +1,169 @@
+package basenc
+
+import (
+	"bytes"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"strings"
+)
+
+// errInvalid is the error for data that is not in the encoding.
+var errInvalid = errors.New("invalid input")
+
+// encoding is a binary-to-text encoding turning blocks of inSize bytes
+// into blocks of outSize characters.
+type encoding struct {
+	inSize  int
+	outSize int
+
+	// alphabet holds the characters of the encoding, padding included.
+	alphabet string
+
+	// pad is the padding completing the last block, or 0 when the input
+	// must be made of whole blocks.
+	pad byte
+
+	// encode encodes src, made of whole blocks except at the end of the
+	// input, into dst.
+	encode func(dst, src []byte) (int, error)
+
+	// decode decodes src, made of whole blocks, into dst.
+	decode func(dst, src []byte) (int, error)
+}
+
+// encodings are the encodings by flag name.
+var encodings = map[string]*encoding{
+	"base64":    padded(base64.StdEncoding, 3, 4, "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789+/="),
+	"base64url": padded(base64.URLEncoding, 3, 4, "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789-_="),
+	"base32":    padded(base32.StdEncoding, 5, 8, "ABCDEFGHIJKLMNOPQRSTUVWXYZ234567="),
+	"base32hex": padded(base32.HexEncoding, 5, 8, "0123456789ABCDEFGHIJKLMNOPQRSTUV="),
+	"base16": {
+		inSize: 1, outSize: 2, alphabet: "0123456789ABCDEF",
+		encode: func(dst, src []byte) (int, error) {
+			n := hex.Encode(dst, src)
+			copy(dst, bytes.ToUpper(dst[:n]))
+			return n, nil
+		},
+		decode: hex.Decode,
+	},
+	"base2msbf": {inSize: 1, outSize: 8, alphabet: "01", encode: encodeBase2(true), decode: decodeBase2(true)},
+	"base2lsbf": {inSize: 1, outSize: 8, alphabet: "01", encode: encodeBase2(false), decode: decodeBase2(false)},
+	"z85":       {inSize: 4, outSize: 5, alphabet: z85Alphabet, encode: encodeZ85, decode: decodeZ85},
+}
+
+// stdEncoding is an encoding of the standard library.
+type stdEncoding interface {
+	Encode(dst, src []byte)
+	EncodedLen(n int) int
+	Decode(dst, src []byte) (int, error)
+}
+
+// padded returns a padded encoding of the standard library. Padding may
+// end blocks in the middle of the data too, as when encoded files are
+// concatenated.
+func padded(e stdEncoding, inSize, outSize int, alphabet string) *encoding {
+	return &encoding{
+		inSize: inSize, outSize: outSize, alphabet: alphabet, pad: '=',
+		encode: func(dst, src []byte) (int, error) {
+			e.Encode(dst, src)
+			return e.EncodedLen(len(src)), nil
+		},
+		decode: func(dst, src []byte) (int, error) {
+			n := 0
+			for len(src) > 0 {
+				// The decoders of the standard library stop at padding, so
+				// the data is decoded up to each padded block.
+				end := len(src)
+				if i := bytes.IndexByte(src, '='); i >= 0 {
+					end = min(end, (i/outSize+1)*outSize)
+				}
+				k, err := e.Decode(dst[n:], src[:end])
+				n += k
+				if err != nil {
+					return n, errInvalid
+				}
+				src = src[end:]
+			}
+			return n, nil
+		},
+	}
+}
+
+// encodeBase2 returns an encoder writing each byte as eight binary digits,
+// most significant bit first when msbFirst is set.
+func encodeBase2(msbFirst bool) func(dst, src []byte) (int, error) {
+	return func(dst, src []byte) (int, error) {
+		for i, b := range src {
+			for j := 0; j < 8; j++ {
+				bit := b >> uint(j) & 1
+				if msbFirst {
+					bit = b >> uint(7-j) & 1
+				}
+				dst[i*8+j] = '0' + bit
+			}
+		}
+		return len(src) * 8, nil
+	}
+}
+
+// decodeBase2 returns the decoder of encodeBase2.
+func decodeBase2(msbFirst bool) func(dst, src []byte) (int, error) {
+	return func(dst, src []byte) (int, error) {
+		for i := 0; i+8 <= len(src); i += 8 {
+			var b byte
+			for j, c := range src[i : i+8] {
+				if c != '0' && c != '1' {
+					return i / 8, errInvalid
+				}
+				if msbFirst {
+					b |= (c - '0') << uint(7-j)
+				} else {
+					b |= (c - '0') << uint(j)
+				}
+			}
+			dst[i/8] = b
+		}
+		return len(src) / 8, nil
+	}
+}
+
+// z85Alphabet holds the digits of Z85, the base-85 encoding of ZeroMQ.
+const z85Alphabet = "0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ.-:+=^!/*?&<>()[]{}@%$#"
+
+// encodeZ85 encodes each big-endian 32-bit word as five base-85 digits,
+// the most significant first.
+func encodeZ85(dst, src []byte) (int, error) {
+	if len(src)%4 != 0 {
+		return 0, errors.New("invalid input (length must be multiple of 4 characters)")
+	}
+	for i := 0; i < len(src); i += 4 {
+		v := binary.BigEndian.Uint32(src[i:])
+		for j := 4; j >= 0; j-- {
+			dst[i/4*5+j] = z85Alphabet[v%85]
+			v /= 85
+		}
+	}
+	return len(src) / 4 * 5, nil
+}
+
+// decodeZ85 decodes groups of five base-85 digits into 32-bit words.
+func decodeZ85(dst, src []byte) (int, error) {
+	for i := 0; i+5 <= len(src); i += 5 {
+		var v uint64
+		for _, c := range src[i : i+5] {
+			d := strings.IndexByte(z85Alphabet, c)
+			if d < 0 {
+				return i / 5 * 4, errInvalid
+			}
+			v = v*85 + uint64(d)
+		}
+		if v > 0xffffffff {
+			return i / 5 * 4, errInvalid
+		}
+		binary.BigEndian.PutUint32(dst[i/5*4:], uint32(v))
+	}
+	return len(src) / 5 * 4, nil
+}
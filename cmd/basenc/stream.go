@@ -0,0 +1,144 @@
+package basenc
+
+import (
+	"bufio"
+	"io"
+)
+
+// blocks is the number of blocks encoded or decoded at a time.
+const blocks = 1024
+
+// wrapper writes text broken into lines of width characters, or unbroken
+// when width is 0.
+type wrapper struct {
+	w      *bufio.Writer
+	width  int
+	column int
+}
+
+// Write writes p, starting new lines as needed.
+func (w *wrapper) Write(p []byte) (int, error) {
+	if w.width == 0 {
+		return w.w.Write(p)
+	}
+	written := len(p)
+	for len(p) > 0 {
+		if w.column == w.width {
+			if err := w.w.WriteByte('\n'); err != nil {
+				return 0, err
+			}
+			w.column = 0
+		}
+		n := min(len(p), w.width-w.column)
+		if _, err := w.w.Write(p[:n]); err != nil {
+			return 0, err
+		}
+		w.column += n
+		p = p[n:]
+	}
+	return written, nil
+}
+
+// encode encodes all of r to w, in lines of width characters. Input is read
+// as it comes, in whole blocks, so that any amount of it can be encoded.
+func encode(r io.Reader, w io.Writer, e *encoding, width int) error {
+	out := bufio.NewWriter(w)
+	wr := &wrapper{w: out, width: width}
+	in := make([]byte, e.inSize*blocks)
+	enc := make([]byte, e.outSize*blocks)
+	wrote := false
+
+	for {
+		n, err := io.ReadFull(r, in)
+		if n > 0 {
+			k, encErr := e.encode(enc, in[:n])
+			if k > 0 {
+				wrote = true
+				wr.Write(enc[:k])
+			}
+			if encErr != nil {
+				out.Flush()
+				return encErr
+			}
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			break
+		}
+		if err != nil {
+			out.Flush()
+			return err
+		}
+	}
+
+	if wrote && width > 0 {
+		out.WriteByte('\n')
+	}
+	return out.Flush()
+}
+
+// decode decodes all of r to w. Newlines are skipped, and so are other
+// characters outside the alphabet when ignoreGarbage is set. The data
+// decoded before invalid input is written out.
+func decode(r io.Reader, w io.Writer, e *encoding, ignoreGarbage bool) error {
+	in := bufio.NewReader(r)
+	out := bufio.NewWriter(w)
+	defer out.Flush()
+
+	var valid [256]bool
+	for i := 0; i < len(e.alphabet); i++ {
+		valid[e.alphabet[i]] = true
+	}
+	chunk := make([]byte, 0, e.outSize*blocks)
+	dec := make([]byte, e.inSize*blocks)
+
+	// flush decodes the whole blocks gathered, or all of them at the end
+	// of the input, where the last block is padded if it can be.
+	flush := func(final bool) error {
+		n := len(chunk) - len(chunk)%e.outSize
+		partial := final && n < len(chunk)
+		if partial && e.pad != 0 {
+			for len(chunk)%e.outSize != 0 {
+				chunk = append(chunk, e.pad)
+			}
+			n = len(chunk)
+		}
+
+		k, err := e.decode(dec, chunk[:n])
+		out.Write(dec[:k])
+		chunk = append(chunk[:0], chunk[n:]...)
+		if err == nil && partial {
+			err = errInvalid
+		}
+		return err
+	}
+
+	for {
+		c, err := in.ReadByte()
+		if err == io.EOF {
+			return flush(true)
+		}
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case c == '\n':
+			continue
+		case valid[c]:
+			chunk = append(chunk, c)
+		case ignoreGarbage:
+			continue
+		default:
+			if err := flush(false); err != nil {
+				return err
+			}
+			return errInvalid
+		}
+
+		if len(chunk) == cap(chunk) {
+			if err := flush(false); err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -3,13 +3,16 @@
 package cat
 
 import (
-	"bufio"
 	"fmt"
-	"log"
+	"io"
+	"io/fs"
 	"os"
 	"text/tabwriter"
 
 	"github.com/skraio/unix-utilities/cmdflags"
+	"github.com/skraio/unix-utilities/internal/exit"
+	"github.com/skraio/unix-utilities/internal/lineio"
+	"github.com/skraio/unix-utilities/internal/translate"
 	"github.com/spf13/cobra"
 )
 
@@ -17,6 +20,8 @@ import (
 type content struct {
 	lineNumber []string
 	text       []string
+	translator *translate.Translator
+	output     fs.FileInfo
 }
 
 // catFlags represents the flags used by the cat command.
@@ -25,6 +30,10 @@ type catFlags struct {
 	endOfLine      bool
 	numberNonblank bool
 	number         bool
+	translate      string
+	translateTo    string
+	output         string
+	inPlace        bool
 }
 
 var pFlags catFlags
@@ -35,35 +44,80 @@ var flags = []cmdflags.Flag{
 	{Value: &pFlags.endOfLine, Name: "end-line-chars", ShortHand: "e", DefaultValue: false, Description: "display end-of-line characters $"},
 	{Value: &pFlags.numberNonblank, Name: "number-nonblank", ShortHand: "b", DefaultValue: false, Description: "number non-blank output lines"},
 	{Value: &pFlags.number, Name: "number", ShortHand: "n", DefaultValue: false, Description: "number all output lines"},
+	{Value: &pFlags.inPlace, Name: "in-place", ShortHand: "", DefaultValue: false, Description: "replace the single FILE with the output"},
+}
+
+// stringFlags definition for cat command.
+var stringFlags = []cmdflags.StringFlag{
+	{Value: &pFlags.translate, Name: "translate", ShortHand: "", DefaultValue: "", Description: "translate characters in SET1 as tr does"},
+	{Value: &pFlags.translateTo, Name: "translate-to", ShortHand: "", DefaultValue: "", Description: "characters of SET2 replacing those given to --translate"},
+	{Value: &pFlags.output, Name: "output", ShortHand: "o", DefaultValue: "", Description: "write to FILE, replacing it atomically, instead of standard output"},
 }
 
 // Cmd represents the 'cat' command configuration using Cobra.
 var Cmd = &cobra.Command{
-	Use:   "cat [-f flags] [file]...",
-	Short: "",
-	Args:  cobra.MinimumNArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
+	Use:           "cat [-f flags] [file]...",
+	Short:         "",
+	Args:          cobra.MinimumNArgs(1),
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	RunE: func(cmd *cobra.Command, args []string) error {
 		cont := &content{}
-		err := cont.executeCat(args)
-		if err != nil {
-			log.Print(err.Error())
-			return
-		}
+		return exit.Status(cont.executeCat(args))
 	},
 }
 
 // init initializes the 'cat' command by setting up flags.
 func init() {
 	cmdflags.ParseFlags(flags, Cmd)
+	cmdflags.ParseStringFlags(stringFlags, Cmd)
+
+	Cmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		fmt.Fprintf(os.Stderr, "cat: %v\n", err)
+		return exit.Status(1)
+	})
 }
 
-// executeLs executes the cat command with given arguments.
-func (cont *content) executeCat(args []string) error {
+// executeCat executes the cat command with given arguments and returns its
+// exit status. Files that cannot be read are reported and skipped.
+func (cont *content) executeCat(args []string) int {
+	if pFlags.translate != "" {
+		t, err := translate.New(pFlags.translate, pFlags.translateTo, true, translate.Options{})
+		if err != nil {
+			return exit.Fail("cat", err)
+		}
+		cont.translator = t
+	}
+
+	output, err := outputPath(args)
+	if err != nil {
+		return exit.Fail("cat", err)
+	}
+	if cont.output, err = outputInfo(output); err != nil {
+		return exit.Fail("cat", err)
+	}
+
+	// An output file is replaced as a whole, so an input that is also the
+	// output is refused before anything is read or written.
+	if output != "" && !pFlags.inPlace {
+		refused := false
+		for _, arg := range args {
+			if cont.isOutput(arg) {
+				exit.Fail("cat", fmt.Errorf("%s: input file is output file", arg))
+				refused = true
+			}
+		}
+		if refused {
+			return 1
+		}
+	}
+
+	status := 0
 	startIdx := 0
 	for _, arg := range args {
 		err := cont.execute(arg, startIdx)
 		if err != nil {
-			return err
+			status = exit.Fail("cat", err)
 		}
 	}
 
@@ -71,11 +125,23 @@ func (cont *content) executeCat(args []string) error {
 		cont.numberLines()
 	}
 
-	cont.printText()
-	return nil
+	if output == "" {
+		cont.printText(os.Stdout)
+		return status
+	}
+	// What was read is incomplete, and would replace the whole output.
+	if status != 0 {
+		return status
+	}
+	if err := writeAtomic(output, func(w io.Writer) { cont.printText(w) }); err != nil {
+		return exit.Fail("cat", err)
+	}
+	return status
 }
 
 // execute reads the content of the file and stores it in the content struct.
+// A file that is also the output is refused, as writing would clobber it
+// while it is read.
 func (cont *content) execute(filename string, startIdx int) error {
 	file, err := os.Open(filename)
 	if err != nil {
@@ -83,7 +149,16 @@ func (cont *content) execute(filename string, startIdx int) error {
 	}
 	defer file.Close()
 
-	fileContent, err := readFileContent(file)
+	if !pFlags.inPlace && cont.isOutput(filename) {
+		return fmt.Errorf("%s: input file is output file", filename)
+	}
+
+	var r io.Reader = file
+	if cont.translator != nil {
+		r = cont.translator.Reader(file)
+	}
+
+	fileContent, err := readFileContent(r)
 	if err != nil {
 		return err
 	}
@@ -97,22 +172,30 @@ func (cont *content) execute(filename string, startIdx int) error {
 	return nil
 }
 
-// readFileContent reads the content of a file.
-func readFileContent(f *os.File) ([]string, error) {
-	_, err := f.Seek(0, 0)
-	if err != nil {
-		return []string{}, err
+// isOutput reports whether the named file is the one the output goes to.
+func (cont *content) isOutput(filename string) bool {
+	if cont.output == nil {
+		return false
 	}
+	info, err := os.Stat(filename)
+	return err == nil && os.SameFile(info, cont.output)
+}
 
-	fileScanner := bufio.NewScanner(f)
-	fileScanner.Split(bufio.ScanLines)
+// readFileContent reads the lines of a file, however long they are.
+func readFileContent(r io.Reader) ([]string, error) {
+	lr := lineio.NewReader(r)
 
 	text := []string{}
-	for fileScanner.Scan() {
-		text = append(text, fileScanner.Text())
+	for {
+		line, err := lr.ReadLine()
+		if err == io.EOF {
+			return text, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		text = append(text, string(lr.TrimDelim(line)))
 	}
-
-	return text, nil
 }
 
 // numberLines numbers the lines based on the flags.
@@ -124,16 +207,16 @@ func (cont *content) numberLines() {
 	}
 }
 
-// printText prints the content stored in the content struct.
-func (cont *content) printText() {
+// printText prints the content stored in the content struct to out.
+func (cont *content) printText(out io.Writer) {
 	if len(cont.lineNumber) == 0 {
 		for _, l := range cont.text {
-			fmt.Println(l)
+			fmt.Fprintln(out, l)
 		}
 		return
 	}
 
-	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', tabwriter.AlignRight)
+	w := tabwriter.NewWriter(out, 0, 0, 3, ' ', tabwriter.AlignRight)
 	defer w.Flush()
 
 	for i := range cont.lineNumber {
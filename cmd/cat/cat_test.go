@@ -1,6 +1,10 @@
 package cat
 
 import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/skraio/unix-utilities/internal/assert"
@@ -97,3 +101,108 @@ func TestNumberAllLines(t *testing.T) {
 		assert.EqualStr(t, ans.lineNumber, tt.want)
 	}
 }
+
+func TestWriteAtomic(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "out")
+	if err := os.WriteFile(path, []byte("old\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	err := writeAtomic(path, func(w io.Writer) {
+		io.WriteString(w, "new\n")
+	})
+	assert.Equal(t, err, nil)
+
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, string(got), "new\n")
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, info.Mode().Perm(), os.FileMode(0600))
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, len(entries), 1)
+}
+
+func TestInputIsOutput(t *testing.T) {
+	dir := t.TempDir()
+	in := filepath.Join(dir, "in")
+	if err := os.WriteFile(in, []byte("line\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pFlags = catFlags{}
+	cont := &content{output: info}
+	err = cont.execute(in, 0)
+	assert.Equal(t, err != nil && err.Error() == in+": input file is output file", true)
+
+	pFlags = catFlags{inPlace: true}
+	assert.Equal(t, cont.execute(in, 0), nil)
+	assert.EqualStr(t, cont.text, []string{"line"})
+	pFlags = catFlags{}
+}
+
+func TestOutputOperand(t *testing.T) {
+	dir := t.TempDir()
+	keep, other := filepath.Join(dir, "k"), filepath.Join(dir, "o")
+	if err := os.WriteFile(keep, []byte("keep"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(other, []byte("other"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	pFlags = catFlags{output: keep}
+	cont := &content{}
+	assert.Equal(t, cont.executeCat([]string{keep, other, keep}), 1)
+	pFlags = catFlags{}
+
+	got, err := os.ReadFile(keep)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, string(got), "keep")
+}
+
+func TestInPlace(t *testing.T) {
+	dir := t.TempDir()
+	long := filepath.Join(dir, "long")
+	want := strings.Repeat("x", 100*1024) + "\nend\n"
+	if err := os.WriteFile(long, []byte(want), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	pFlags = catFlags{inPlace: true}
+	defer func() { pFlags = catFlags{} }()
+
+	t.Run("Long line", func(t *testing.T) {
+		cont := &content{}
+		assert.Equal(t, cont.executeCat([]string{long}), 0)
+		got, err := os.ReadFile(long)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, string(got), want)
+	})
+
+	t.Run("Missing operand", func(t *testing.T) {
+		missing := filepath.Join(dir, "missing")
+		cont := &content{}
+		assert.Equal(t, cont.executeCat([]string{missing}), 1)
+		_, err := os.Stat(missing)
+		assert.Equal(t, os.IsNotExist(err), true)
+	})
+}
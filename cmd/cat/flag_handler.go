@@ -1,11 +1,18 @@
 package cat
 
-import "strconv"
+import (
+	"strconv"
+
+	"github.com/skraio/unix-utilities/internal/linenum"
+)
 
 // squeezeBlankLines squeezes the multiple adjacent blank lines from the given
 // text slice.
 func squeezeBlankLines(text []string) []string {
 	result := []string{}
+	if len(text) == 0 {
+		return result
+	}
 	result = append(result, text[0])
 
 	for i := 1; i < len(text); i++ {
@@ -20,23 +27,23 @@ func squeezeBlankLines(text []string) []string {
 
 // numberNonblankLines numbers non-blank lines in the content structure.
 func (cont *content) numberNonblankLines() {
-	lineCounter := 1
-
-	for _, line := range cont.text {
-		if line != "" {
-			cont.lineNumber = append(cont.lineNumber, strconv.Itoa(lineCounter))
-			lineCounter++
-		} else {
-			cont.lineNumber = append(cont.lineNumber, "")
-		}
-	}
+	cont.numberSelectedLines(linenum.NonEmpty)
 }
 
 // numberAllLines nubmers all lines in the content structure.
 func (cont *content) numberAllLines() {
+	cont.numberSelectedLines(linenum.All)
+}
+
+// numberSelectedLines numbers the lines selected by style, counting from 1,
+// and leaves the others without a number.
+func (cont *content) numberSelectedLines(style linenum.Style) {
+	counter := linenum.NewCounter(1, 1)
 	cont.lineNumber = make([]string, len(cont.text))
 
-	for i := range cont.text {
-		cont.lineNumber[i] = strconv.Itoa(i + 1)
+	for i, line := range cont.text {
+		if style.Selects([]byte(line)) {
+			cont.lineNumber[i] = strconv.FormatInt(counter.Next(), 10)
+		}
 	}
 }
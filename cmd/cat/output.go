@@ -0,0 +1,96 @@
+package cat
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/skraio/unix-utilities/internal/fileinfo"
+	"github.com/skraio/unix-utilities/internal/filemode"
+)
+
+// outputPath returns the file named by --output or --in-place, or the empty
+// string when writing to the standard output.
+func outputPath(args []string) (string, error) {
+	switch {
+	case pFlags.inPlace && pFlags.output != "":
+		return "", errors.New("--in-place and --output are mutually exclusive")
+	case pFlags.inPlace && len(args) != 1:
+		return "", errors.New("--in-place requires exactly one file")
+	case pFlags.inPlace:
+		return args[0], nil
+	}
+	return pFlags.output, nil
+}
+
+// outputInfo returns the information of the file the output goes to, for
+// telling whether an input is the output. The standard output only counts
+// when it is a regular file, and a missing output file is not an error.
+func outputInfo(path string) (fs.FileInfo, error) {
+	if path == "" {
+		info, err := os.Stdout.Stat()
+		if err != nil || !info.Mode().IsRegular() {
+			return nil, nil
+		}
+		return info, nil
+	}
+
+	info, err := os.Stat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	return info, err
+}
+
+// writeAtomic replaces the file at path with what write produces. The data
+// goes to a temporary file in the same directory, which is renamed over the
+// target once complete, so the target is never seen half written. An
+// existing target keeps its mode and ownership; symbolic links to it are
+// followed.
+func writeAtomic(path string, write func(w io.Writer)) (err error) {
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tmp.Close()
+			os.Remove(tmp.Name())
+		}
+	}()
+
+	w := bufio.NewWriter(tmp)
+	write(w)
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if info, statErr := os.Stat(path); statErr == nil {
+		if err := tmp.Chmod(info.Mode() & (fs.ModePerm | fs.ModeSetuid | fs.ModeSetgid | fs.ModeSticky)); err != nil {
+			return err
+		}
+		if stat, ok := fileinfo.Sys(info); ok {
+			err := tmp.Chown(int(stat.Uid), int(stat.Gid))
+			if err != nil && !errors.Is(err, fs.ErrPermission) {
+				return err
+			}
+		}
+	} else if err := tmp.Chmod(0666 &^ filemode.Umask()); err != nil {
+		return err
+	}
+
+	if err := tmp.Sync(); err != nil {
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,33 @@
+package chgrp
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/skraio/unix-utilities/internal/assert"
+)
+
+func TestExecuteChgrp(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "f")
+	if err := os.WriteFile(file, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	group := strconv.Itoa(os.Getgid())
+
+	tests := []struct {
+		args []string
+		want int
+	}{
+		{args: []string{}, want: 1},
+		{args: []string{group}, want: 1},
+		{args: []string{group, file}, want: 0},
+		{args: []string{"no such group", file}, want: 1},
+		{args: []string{group, file + ".missing"}, want: 1},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, executeChgrp(tt.args, false), tt.want)
+	}
+}
@@ -0,0 +1,116 @@
+// Package chmod provides functionality for changing file mode bits.
+package chmod
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/skraio/unix-utilities/cmdflags"
+	"github.com/skraio/unix-utilities/internal/exit"
+	"github.com/skraio/unix-utilities/internal/filemode"
+	"github.com/spf13/cobra"
+)
+
+// chmodFlags holds flags for chmod command.
+type chmodFlags struct {
+	changes   bool
+	silent    bool
+	verbose   bool
+	recursive bool
+	reference string
+}
+
+var pFlags chmodFlags
+
+// flags definition for chmod command.
+var flags = []cmdflags.Flag{
+	{Value: &pFlags.changes, Name: "changes", ShortHand: "c", DefaultValue: false, Description: "like verbose but report only when a change is made"},
+	{Value: &pFlags.silent, Name: "silent", ShortHand: "f", DefaultValue: false, Description: "suppress most error messages"},
+	{Value: &pFlags.verbose, Name: "verbose", ShortHand: "v", DefaultValue: false, Description: "output a diagnostic for every file processed"},
+	{Value: &pFlags.recursive, Name: "recursive", ShortHand: "R", DefaultValue: false, Description: "change files and directories recursively"},
+}
+
+// stringFlags definition for chmod command.
+var stringFlags = []cmdflags.StringFlag{
+	{Value: &pFlags.reference, Name: "reference", ShortHand: "", DefaultValue: "", Description: "use the mode of this file instead of a mode"},
+}
+
+// Cmd represents the 'chmod' command configuration using Cobra. Flag
+// parsing is done in RunE so that modes such as -w are not taken for
+// flags.
+var Cmd = &cobra.Command{
+	Use:                "chmod [-f flags] mode file...",
+	Short:              "Change file mode bits",
+	Long:               "Change file mode bits.\n\nA mode is octal or symbolic: comma-separated [ugoa]*([-+=]([rwxXst]*|[ugo]))+.",
+	DisableFlagParsing: true,
+	SilenceErrors:      true,
+	SilenceUsage:       true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		mode, args := splitMode(args)
+		if err := cmd.Flags().Parse(args); err != nil {
+			fmt.Fprintf(os.Stderr, "chmod: %v\n", err)
+			return exit.Status(1)
+		}
+		if help, _ := cmd.Flags().GetBool("help"); help {
+			return cmd.Help()
+		}
+
+		args = cmd.Flags().Args()
+		if mode != "" {
+			args = append([]string{mode}, args...)
+		}
+		return exit.Status(executeChmod(args, cmd.Flags().Changed("reference")))
+	},
+}
+
+// init initializes the 'chmod' command by setting up flags.
+func init() {
+	cmdflags.ParseFlags(flags, Cmd)
+	cmdflags.ParseStringFlags(stringFlags, Cmd)
+}
+
+// executeChmod executes the chmod command with given arguments and returns
+// its exit status. Without a reference file the first argument is the mode.
+func executeChmod(args []string, hasReference bool) int {
+	c := &changer{
+		umask:     filemode.Umask(),
+		recursive: pFlags.recursive,
+		verbose:   pFlags.verbose,
+		changes:   pFlags.changes,
+		silent:    pFlags.silent,
+	}
+
+	if hasReference {
+		info, err := os.Stat(pFlags.reference)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "chmod: failed to get attributes of '%s': %v\n", pFlags.reference, exit.Unwrap(err))
+			return 1
+		}
+		c.mode = filemode.Of(info.Mode())
+	} else {
+		if len(args) == 0 {
+			fmt.Fprintln(os.Stderr, "chmod: missing operand")
+			return 1
+		}
+		if len(args) == 1 {
+			fmt.Fprintf(os.Stderr, "chmod: missing operand after '%s'\n", args[0])
+			return 1
+		}
+		m, err := filemode.Parse(args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "chmod: %v\n", err)
+			return 1
+		}
+		c.mode, args = m, args[1:]
+	}
+
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, "chmod: missing operand")
+		return 1
+	}
+
+	for _, path := range args {
+		c.chmodOperand(path)
+	}
+	return c.status
+}
@@ -0,0 +1,69 @@
+package chmod
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/skraio/unix-utilities/internal/assert"
+	"github.com/skraio/unix-utilities/internal/filemode"
+)
+
+func TestSplitMode(t *testing.T) {
+	tests := []struct {
+		args     []string
+		wantMode string
+		wantRest []string
+	}{
+		{args: []string{"-R", "-w", "f"}, wantMode: "-w", wantRest: []string{"-R", "f"}},
+		{args: []string{"-rwx,o=r", "f"}, wantMode: "-rwx,o=r", wantRest: []string{"f"}},
+		{args: []string{"-v", "755", "f"}, wantMode: "", wantRest: []string{"-v", "755", "f"}},
+		{args: []string{"-Rv", "u+x", "f"}, wantMode: "", wantRest: []string{"-Rv", "u+x", "f"}},
+		{args: []string{"--", "-w", "f"}, wantMode: "", wantRest: []string{"--", "-w", "f"}},
+	}
+
+	for _, tt := range tests {
+		mode, rest := splitMode(tt.args)
+		assert.Equal(t, mode, tt.wantMode)
+		assert.EqualStr(t, rest, tt.wantRest)
+	}
+}
+
+func TestChmod(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"a/b", "a/c"} {
+		if err := os.MkdirAll(filepath.Join(dir, name), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	file := filepath.Join(dir, "a/b/f")
+	if err := os.WriteFile(file, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(file, filepath.Join(dir, "a/l")); err != nil {
+		t.Fatal(err)
+	}
+
+	mode, err := filemode.Parse("go-rx,u+X")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &changer{mode: mode, recursive: true}
+	c.chmodOperand(filepath.Join(dir, "a"))
+	assert.Equal(t, c.status, 0)
+
+	perm := func(name string) fs.FileMode {
+		info, err := os.Stat(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return info.Mode().Perm()
+	}
+	assert.Equal(t, perm("a"), fs.FileMode(0700))
+	assert.Equal(t, perm("a/c"), fs.FileMode(0700))
+	assert.Equal(t, perm("a/b/f"), fs.FileMode(0600))
+
+	c.chmodOperand(filepath.Join(dir, "missing"))
+	assert.Equal(t, c.status, 1)
+}
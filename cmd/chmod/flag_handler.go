@@ -0,0 +1,123 @@
+package chmod
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/skraio/unix-utilities/internal/exit"
+	"github.com/skraio/unix-utilities/internal/filemode"
+)
+
+// splitMode takes out of the options the first one that is a mode, such as
+// -w or -rwx,o=r, so that it is not parsed as flags. The options end at the
+// first operand or at "--".
+func splitMode(args []string) (string, []string) {
+	for i, arg := range args {
+		if arg == "--" || !strings.HasPrefix(arg, "-") {
+			break
+		}
+		if len(arg) > 1 && strings.IndexByte("rwxXst", arg[1]) >= 0 &&
+			strings.Trim(arg, "rwxXstugoa,+=-") == "" {
+			rest := append(append([]string{}, args[:i]...), args[i+1:]...)
+			return arg, rest
+		}
+	}
+	return "", args
+}
+
+// changer changes the mode of files, recording failures in its exit
+// status.
+type changer struct {
+	mode      *filemode.Mode
+	umask     fs.FileMode
+	recursive bool
+	verbose   bool
+	changes   bool
+	silent    bool
+	status    int
+}
+
+// report prints an error unless errors are silenced, and makes chmod exit
+// unsuccessfully.
+func (c *changer) report(format string, a ...any) {
+	if !c.silent {
+		fmt.Fprintf(os.Stderr, "chmod: "+format+"\n", a...)
+	}
+	c.status = 1
+}
+
+// chmodOperand changes the mode of a command-line operand, following it if
+// it is a symbolic link.
+func (c *changer) chmodOperand(path string) {
+	info, err := os.Stat(path)
+	if err != nil {
+		if _, lerr := os.Lstat(path); lerr == nil {
+			c.report("cannot operate on dangling symlink '%s'", path)
+		} else {
+			c.report("cannot access '%s': %v", path, exit.Unwrap(err))
+		}
+		return
+	}
+	c.chmod(path, info)
+}
+
+// chmod changes the mode of a file and, when recursive, of the files below
+// it. Symbolic links met in the hierarchy are neither followed nor changed,
+// as their own mode does not matter.
+func (c *changer) chmod(path string, info fs.FileInfo) {
+	if info.Mode()&fs.ModeSymlink != 0 {
+		if c.verbose {
+			fmt.Printf("neither symbolic link '%s' nor referent has been changed\n", path)
+		}
+		return
+	}
+
+	old := info.Mode()
+	mode := c.mode.Apply(old, c.umask)
+	changed := filemode.Bits(mode) != filemode.Bits(old)
+	if changed {
+		if err := os.Chmod(path, mode); err != nil {
+			c.report("changing permissions of '%s': %v", path, exit.Unwrap(err))
+			return
+		}
+	}
+	c.describe(path, old, mode, changed)
+
+	// Bits the umask kept from being cleared are an error, as the file is
+	// left more permissive than asked.
+	if want := c.mode.Apply(old, 0); filemode.Bits(mode)&^filemode.Bits(want) != 0 {
+		c.report("%s: new permissions are %s, not %s", path, filemode.String(mode)[1:], filemode.String(want)[1:])
+	}
+
+	if !c.recursive || !info.IsDir() {
+		return
+	}
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		c.report("cannot read directory '%s': %v", path, exit.Unwrap(err))
+		return
+	}
+	for _, e := range entries {
+		child := filepath.Join(path, e.Name())
+		info, err := os.Lstat(child)
+		if err != nil {
+			c.report("cannot access '%s': %v", child, exit.Unwrap(err))
+			continue
+		}
+		c.chmod(child, info)
+	}
+}
+
+// describe prints the change made to a file as -v and -c ask.
+func (c *changer) describe(path string, old, mode fs.FileMode, changed bool) {
+	switch {
+	case changed && (c.verbose || c.changes):
+		fmt.Printf("mode of '%s' changed from %04o (%s) to %04o (%s)\n",
+			path, filemode.Bits(old), filemode.String(old)[1:], filemode.Bits(mode), filemode.String(mode)[1:])
+	case !changed && c.verbose:
+		fmt.Printf("mode of '%s' retained as %04o (%s)\n", path, filemode.Bits(mode), filemode.String(mode)[1:])
+	}
+}
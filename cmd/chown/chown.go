@@ -0,0 +1,131 @@
+// Package chown provides functionality for changing the user and group
+// owning files.
+package chown
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/skraio/unix-utilities/cmdflags"
+	"github.com/skraio/unix-utilities/internal/exit"
+	"github.com/skraio/unix-utilities/internal/owner"
+	"github.com/spf13/cobra"
+)
+
+// chownFlags holds flags for chown command.
+type chownFlags struct {
+	changes       bool
+	silent        bool
+	verbose       bool
+	recursive     bool
+	noDereference bool
+	from          string
+	reference     string
+}
+
+var pFlags chownFlags
+
+// flags definition for chown command.
+var flags = []cmdflags.Flag{
+	{Value: &pFlags.changes, Name: "changes", ShortHand: "c", DefaultValue: false, Description: "like verbose but report only when a change is made"},
+	{Value: &pFlags.silent, Name: "silent", ShortHand: "f", DefaultValue: false, Description: "suppress most error messages"},
+	{Value: &pFlags.verbose, Name: "verbose", ShortHand: "v", DefaultValue: false, Description: "output a diagnostic for every file processed"},
+	{Value: &pFlags.recursive, Name: "recursive", ShortHand: "R", DefaultValue: false, Description: "operate on files and directories recursively"},
+	{Value: &pFlags.noDereference, Name: "no-dereference", ShortHand: "h", DefaultValue: false, Description: "affect symbolic links instead of any referenced file"},
+}
+
+// stringFlags definition for chown command.
+var stringFlags = []cmdflags.StringFlag{
+	{Value: &pFlags.from, Name: "from", ShortHand: "", DefaultValue: "", Description: "change the owner only if it matches CURRENT_OWNER:CURRENT_GROUP"},
+	{Value: &pFlags.reference, Name: "reference", ShortHand: "", DefaultValue: "", Description: "use the owner and group of this file instead of an owner"},
+}
+
+// Cmd represents the 'chown' command configuration using Cobra.
+var Cmd = &cobra.Command{
+	Use:           "chown [-f flags] [owner][:[group]] file...",
+	Short:         "Change file owner and group",
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return exit.Status(executeChown(args, cmd.Flags().Changed("reference")))
+	},
+}
+
+// init initializes the 'chown' command by setting up flags.
+func init() {
+	cmdflags.ParseFlags(flags, Cmd)
+	cmdflags.ParseStringFlags(stringFlags, Cmd)
+	Cmd.PersistentFlags().BoolP("help", "", false, "help for this command")
+
+	Cmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		fmt.Fprintf(os.Stderr, "chown: %v\n", err)
+		return exit.Status(1)
+	})
+}
+
+// executeChown executes the chown command with given arguments and returns
+// its exit status. Without a reference file the first argument is the
+// owner.
+func executeChown(args []string, hasReference bool) int {
+	var to owner.Spec
+	if hasReference {
+		info, err := os.Stat(pFlags.reference)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "chown: failed to get attributes of '%s': %v\n", pFlags.reference, exit.Unwrap(err))
+			return 1
+		}
+		to = owner.Of(info)
+	} else {
+		if len(args) == 0 {
+			fmt.Fprintln(os.Stderr, "chown: missing operand")
+			return 1
+		}
+		if len(args) == 1 {
+			fmt.Fprintf(os.Stderr, "chown: missing operand after '%s'\n", args[0])
+			return 1
+		}
+		spec, err := owner.Parse(args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "chown: %v\n", err)
+			return 1
+		}
+		to, args = spec, args[1:]
+	}
+
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, "chown: missing operand")
+		return 1
+	}
+
+	opts := owner.Options{
+		Recursive:     pFlags.recursive,
+		NoDereference: pFlags.noDereference,
+		From:          owner.Any,
+		Report: func(err error) {
+			if !pFlags.silent {
+				fmt.Fprintf(os.Stderr, "chown: %v\n", err)
+			}
+		},
+		Describe: func(message string, changed bool) {
+			if pFlags.verbose || pFlags.changes && changed {
+				fmt.Println(message)
+			}
+		},
+	}
+	if pFlags.from != "" {
+		from, err := owner.Parse(pFlags.from)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "chown: %v\n", err)
+			return 1
+		}
+		opts.From = from
+	}
+
+	status := 0
+	for _, path := range args {
+		if !owner.Change(path, to, opts) {
+			status = 1
+		}
+	}
+	return status
+}
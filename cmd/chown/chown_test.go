@@ -0,0 +1,37 @@
+package chown
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/skraio/unix-utilities/internal/assert"
+)
+
+func TestExecuteChown(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "f")
+	if err := os.WriteFile(file, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	own := strconv.Itoa(os.Getuid()) + ":" + strconv.Itoa(os.Getgid())
+
+	tests := []struct {
+		args []string
+		want int
+	}{
+		{args: []string{}, want: 1},
+		{args: []string{own}, want: 1},
+		{args: []string{own, file}, want: 0},
+		{args: []string{"no such user", file}, want: 1},
+		{args: []string{own, file, file + ".missing"}, want: 1},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, executeChown(tt.args, false), tt.want)
+	}
+
+	pFlags.reference = file
+	assert.Equal(t, executeChown([]string{file}, true), 0)
+	pFlags.reference = ""
+}
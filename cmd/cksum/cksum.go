@@ -0,0 +1,57 @@
+// Package cksum provides functionality for printing the POSIX CRC checksum
+// and byte count of files.
+package cksum
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash"
+	"os"
+
+	"github.com/skraio/unix-utilities/internal/checksum"
+	"github.com/skraio/unix-utilities/internal/exit"
+	"github.com/skraio/unix-utilities/internal/fileinput"
+	"github.com/spf13/cobra"
+)
+
+// Cmd represents the 'cksum' command configuration using Cobra.
+var Cmd = &cobra.Command{
+	Use:           "cksum [file]...",
+	Short:         "Print CRC checksum and byte counts",
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return exit.Status(executeCksum(args))
+	},
+}
+
+// init initializes the 'cksum' command.
+func init() {
+	Cmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		fmt.Fprintf(os.Stderr, "cksum: %v\n", err)
+		return exit.Status(1)
+	})
+}
+
+// executeCksum executes the cksum command with given arguments and returns
+// its exit status. The standard input read for lack of operands is printed
+// without a name.
+func executeCksum(args []string) int {
+	named := len(args) > 0
+	status := 0
+	checksum.Files(fileinput.Args(args), func(int) hash.Hash { return checksum.NewCRC() }, func(r checksum.Result) {
+		if r.Err != nil {
+			fmt.Fprintf(os.Stderr, "cksum: %s: %v\n", r.Name, exit.Unwrap(r.Err))
+			status = 1
+			return
+		}
+
+		crc := binary.BigEndian.Uint32(r.Sum)
+		if named {
+			fmt.Printf("%d %d %s\n", crc, r.Size, r.Name)
+		} else {
+			fmt.Printf("%d %d\n", crc, r.Size)
+		}
+	})
+	return status
+}
@@ -0,0 +1,19 @@
+package cksum
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/skraio/unix-utilities/internal/assert"
+)
+
+func TestExecuteCksum(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "f")
+	if err := os.WriteFile(file, []byte("abc"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, executeCksum([]string{file}), 0)
+	assert.Equal(t, executeCksum([]string{file, file + ".missing"}), 1)
+}
@@ -0,0 +1,329 @@
+// Package cmp provides functionality for comparing two files byte by byte.
+package cmp
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/skraio/unix-utilities/cmdflags"
+	"github.com/skraio/unix-utilities/internal/exit"
+	"github.com/skraio/unix-utilities/internal/fileinput"
+	"github.com/skraio/unix-utilities/internal/units"
+	"github.com/spf13/cobra"
+)
+
+// cmpFlags holds flags for cmp command.
+type cmpFlags struct {
+	printBytes bool
+	verbose    bool
+	quiet      bool
+	ignore     string
+	bytes      string
+}
+
+var pFlags cmpFlags
+
+// flags definition for cmp command.
+var flags = []cmdflags.Flag{
+	{Value: &pFlags.printBytes, Name: "print-bytes", ShortHand: "b", DefaultValue: false, Description: "print differing bytes"},
+	{Value: &pFlags.verbose, Name: "verbose", ShortHand: "l", DefaultValue: false, Description: "output byte numbers and differing byte values"},
+	{Value: &pFlags.quiet, Name: "quiet", ShortHand: "s", DefaultValue: false, Description: "suppress all normal output"},
+}
+
+// stringFlags definition for cmp command.
+var stringFlags = []cmdflags.StringFlag{
+	{Value: &pFlags.ignore, Name: "ignore-initial", ShortHand: "i", DefaultValue: "", Description: "skip first SKIP bytes of both inputs, or SKIP1 and SKIP2 bytes given as SKIP1:SKIP2"},
+	{Value: &pFlags.bytes, Name: "bytes", ShortHand: "n", DefaultValue: "", Description: "compare at most LIMIT bytes"},
+}
+
+// Cmd represents the 'cmp' command configuration using Cobra.
+var Cmd = &cobra.Command{
+	Use:           "cmp [-f flags] file1 [file2 [skip1 [skip2]]]",
+	Short:         "Compare two files byte by byte",
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return exit.Status(executeCmp(args))
+	},
+}
+
+// init initializes the 'cmp' command by setting up flags.
+func init() {
+	cmdflags.ParseFlags(flags, Cmd)
+	cmdflags.ParseStringFlags(stringFlags, Cmd)
+
+	Cmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		fmt.Fprintf(os.Stderr, "cmp: %v\n", err)
+		return exit.Status(2)
+	})
+}
+
+// executeCmp executes the cmp command with given arguments and returns its
+// exit status: 0 when the files are the same, 1 when they differ and 2 on
+// trouble.
+func executeCmp(args []string) int {
+	switch {
+	case len(args) == 0:
+		return fail(errors.New("missing operand after 'cmp'"))
+	case pFlags.verbose && pFlags.quiet:
+		return fail(errors.New("options -l and -s are incompatible"))
+	}
+
+	var skips [2]int64
+	if pFlags.ignore != "" {
+		var err error
+		if skips, err = parseSkips(pFlags.ignore); err != nil {
+			return fail(err)
+		}
+	}
+	for i, arg := range args[min(len(args), 2):min(len(args), 4)] {
+		skip, err := units.Parse(arg)
+		if err != nil {
+			return fail(fmt.Errorf("invalid --ignore-initial value '%s'", arg))
+		}
+		skips[i] = max(skips[i], skip)
+	}
+	if len(args) > 4 {
+		return fail(fmt.Errorf("extra operand '%s'", args[4]))
+	}
+
+	c := &comparer{
+		limit:      -1,
+		printBytes: pFlags.printBytes,
+		verbose:    pFlags.verbose,
+		quiet:      pFlags.quiet,
+	}
+	if pFlags.bytes != "" {
+		limit, err := units.Parse(pFlags.bytes)
+		if err != nil {
+			return fail(fmt.Errorf("invalid --bytes value '%s'", pFlags.bytes))
+		}
+		c.limit = limit
+	}
+
+	name2 := fileinput.Stdin
+	if len(args) > 1 {
+		name2 = args[1]
+	}
+	p, err := fileinput.OpenPair(args[0], name2)
+	if err != nil {
+		return c.trouble(err)
+	}
+	defer p.Close()
+
+	// A file is the same as itself from the same place.
+	if p.Same() && skips[0] == skips[1] {
+		return 0
+	}
+	for i, skip := range skips {
+		if err := p.Skip(i, skip); err != nil {
+			return c.trouble(err)
+		}
+	}
+	c.names = p.Names
+	c.width = c.offsetWidth(p)
+
+	c.w = bufio.NewWriter(os.Stdout)
+	status, err := c.compare([2]*bufio.Reader{p.Reader(0), p.Reader(1)})
+	c.w.Flush()
+	if err != nil {
+		return c.trouble(err)
+	}
+	return status
+}
+
+// parseSkips parses the argument of -i, which gives the number of bytes to
+// skip at the start of both inputs or of each one as SKIP1:SKIP2.
+func parseSkips(arg string) ([2]int64, error) {
+	first, second, found := strings.Cut(arg, ":")
+	skip1, err := units.Parse(first)
+	if err != nil {
+		return [2]int64{}, fmt.Errorf("invalid --ignore-initial value '%s'", arg)
+	}
+	if !found {
+		return [2]int64{skip1, skip1}, nil
+	}
+	skip2, err := units.Parse(second)
+	if err != nil {
+		return [2]int64{}, fmt.Errorf("invalid --ignore-initial value '%s'", second)
+	}
+	return [2]int64{skip1, skip2}, nil
+}
+
+// comparer compares two inputs.
+type comparer struct {
+	w     *bufio.Writer
+	names [2]string
+
+	// limit is the number of bytes compared at most, or -1 for no limit.
+	limit int64
+
+	printBytes bool
+	verbose    bool
+	quiet      bool
+
+	// width is that of the byte numbers printed with -l.
+	width int
+}
+
+// offsetWidth returns the width of the byte numbers printed with -l, that
+// of the largest number of bytes that may be compared.
+func (c *comparer) offsetWidth(p *fileinput.Pair) int {
+	most := int64(math.MaxInt64)
+	if c.limit >= 0 {
+		most = c.limit
+	}
+	for i := range p.Names {
+		if size := p.Remaining(i); size >= 0 {
+			most = min(most, size)
+		}
+	}
+	return len(strconv.FormatInt(most, 10))
+}
+
+// compare compares the two inputs and returns the exit status. All the
+// differences are printed with -l, only the first otherwise.
+func (c *comparer) compare(r [2]*bufio.Reader) (int, error) {
+	var offset, lines int64
+	newline, differ := false, false
+	for c.limit < 0 || offset < c.limit {
+		var b [2][]byte
+		for i := range r {
+			var err error
+			if b[i], err = buffered(r[i]); err != nil {
+				return 0, err
+			}
+		}
+		if len(b[0]) == 0 || len(b[1]) == 0 {
+			if len(b[0]) == len(b[1]) {
+				break
+			}
+			ended := 0
+			if len(b[1]) == 0 {
+				ended = 1
+			}
+			c.eof(ended, offset, lines, newline)
+			return 1, nil
+		}
+
+		n := min(len(b[0]), len(b[1]))
+		if c.limit >= 0 {
+			n = int(min(int64(n), c.limit-offset))
+		}
+		for i := 0; i < n; i++ {
+			if b[0][i] == b[1][i] {
+				continue
+			}
+			differ = true
+			if c.quiet {
+				return 1, nil
+			}
+			if !c.verbose {
+				c.differ(offset+int64(i)+1, lines+int64(bytes.Count(b[0][:i], []byte{'\n'}))+1, b[0][i], b[1][i])
+				return 1, nil
+			}
+			c.list(offset+int64(i)+1, b[0][i], b[1][i])
+		}
+
+		lines += int64(bytes.Count(b[0][:n], []byte{'\n'}))
+		newline = b[0][n-1] == '\n'
+		offset += int64(n)
+		r[0].Discard(n)
+		r[1].Discard(n)
+	}
+	if differ {
+		return 1, nil
+	}
+	return 0, nil
+}
+
+// buffered returns the bytes buffered by r, reading more when it holds
+// none. It returns no bytes at the end of the input.
+func buffered(r *bufio.Reader) ([]byte, error) {
+	if r.Buffered() == 0 {
+		if _, err := r.Peek(1); err == io.EOF {
+			return nil, nil
+		} else if err != nil {
+			return nil, err
+		}
+	}
+	return r.Peek(r.Buffered())
+}
+
+// differ prints the first difference found.
+func (c *comparer) differ(offset, line int64, b1, b2 byte) {
+	if c.printBytes {
+		fmt.Fprintf(c.w, "%s %s differ: byte %d, line %d is %3o %s %3o %s\n",
+			c.names[0], c.names[1], offset, line, b1, printable(b1), b2, printable(b2))
+		return
+	}
+	fmt.Fprintf(c.w, "%s %s differ: char %d, line %d\n", c.names[0], c.names[1], offset, line)
+}
+
+// list prints a difference in the list printed with -l.
+func (c *comparer) list(offset int64, b1, b2 byte) {
+	if c.printBytes {
+		fmt.Fprintf(c.w, "%*d %3o %-4s %3o %s\n", c.width, offset, b1, printable(b1), b2, printable(b2))
+		return
+	}
+	fmt.Fprintf(c.w, "%*d %3o %3o\n", c.width, offset, b1, b2)
+}
+
+// eof reports that input i ended after the given number of bytes, the same
+// in both inputs, holding the given number of newlines and ending with one
+// or not.
+func (c *comparer) eof(i int, offset, lines int64, newline bool) {
+	if c.quiet {
+		return
+	}
+	c.w.Flush()
+	name := c.names[i]
+	switch {
+	case offset == 0:
+		fmt.Fprintf(os.Stderr, "cmp: EOF on %s which is empty\n", name)
+	case c.verbose:
+		fmt.Fprintf(os.Stderr, "cmp: EOF on %s after byte %d\n", name, offset)
+	case newline:
+		fmt.Fprintf(os.Stderr, "cmp: EOF on %s after byte %d, line %d\n", name, offset, lines)
+	default:
+		fmt.Fprintf(os.Stderr, "cmp: EOF on %s after byte %d, in line %d\n", name, offset, lines+1)
+	}
+}
+
+// printable returns a byte as shown by -b: control characters in caret
+// notation, those with the high bit set prefixed with M-.
+func printable(b byte) string {
+	prefix := ""
+	if b >= 0x80 {
+		prefix, b = "M-", b-0x80
+	}
+	switch {
+	case b < ' ':
+		return prefix + "^" + string(rune(b+'@'))
+	case b == 0x7f:
+		return prefix + "^?"
+	}
+	return prefix + string(rune(b))
+}
+
+// trouble reports an error reading the inputs, unless working silently,
+// and returns the exit status of trouble.
+func (c *comparer) trouble(err error) int {
+	if c.quiet {
+		return 2
+	}
+	return fail(err)
+}
+
+// fail prints err and returns the exit status of trouble.
+func fail(err error) int {
+	exit.Fail("cmp", err)
+	return 2
+}
@@ -0,0 +1,63 @@
+package cmp
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+
+	"github.com/skraio/unix-utilities/internal/assert"
+)
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		name   string
+		c      comparer
+		file1  string
+		file2  string
+		want   string
+		status int
+	}{
+		{name: "Same", file1: "abc\n", file2: "abc\n", status: 0},
+		{name: "First difference", file1: "abc\ndef\n", file2: "abc\ndxf\n", want: "a b differ: char 6, line 2\n", status: 1},
+		{name: "Print bytes", c: comparer{printBytes: true}, file1: "\x01", file2: "\xff", want: "a b differ: byte 1, line 1 is   1 ^A 377 M-^?\n", status: 1},
+		{name: "All differences", c: comparer{verbose: true, width: 2}, file1: "abcdefghij", file2: "xbcdefghiy", want: " 1 141 170\n10 152 171\n", status: 1},
+		{name: "Limit", c: comparer{limit: 3}, file1: "abcd", file2: "abcx", status: 0},
+		{name: "Silent", c: comparer{quiet: true}, file1: "a", file2: "b", status: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out strings.Builder
+			c := tt.c
+			if c.limit == 0 {
+				c.limit = -1
+			}
+			c.names = [2]string{"a", "b"}
+			c.w = bufio.NewWriter(&out)
+			status, err := c.compare([2]*bufio.Reader{
+				bufio.NewReader(strings.NewReader(tt.file1)),
+				bufio.NewReader(strings.NewReader(tt.file2)),
+			})
+			c.w.Flush()
+			assert.Equal(t, err, nil)
+			assert.Equal(t, status, tt.status)
+			assert.Equal(t, out.String(), tt.want)
+		})
+	}
+}
+
+func TestParseSkips(t *testing.T) {
+	skips, err := parseSkips("1K:2")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, skips, [2]int64{1024, 2})
+
+	_, err = parseSkips("1:2:3")
+	assert.Equal(t, err.Error(), "invalid --ignore-initial value '2:3'")
+}
+
+func TestPrintable(t *testing.T) {
+	tests := map[byte]string{'a': "a", ' ': " ", '\n': "^J", 0x7f: "^?", 0xe1: "M-a", 0x80: "M-^@"}
+	for b, want := range tests {
+		assert.Equal(t, printable(b), want)
+	}
+}
@@ -0,0 +1,239 @@
+// Package column provides functionality for formatting input into columns:
+// either filling as many columns of entries as fit in the output width, or
+// aligning the fields of each line into a table.
+package column
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/skraio/unix-utilities/cmdflags"
+	"github.com/skraio/unix-utilities/internal/exit"
+	"github.com/skraio/unix-utilities/internal/fileinput"
+	"github.com/skraio/unix-utilities/internal/lineio"
+	"github.com/skraio/unix-utilities/internal/textwidth"
+	"github.com/spf13/cobra"
+)
+
+// defaultWidth is the output width when neither -c nor COLUMNS give one.
+const defaultWidth = 80
+
+// columnFlags holds flags for column command.
+type columnFlags struct {
+	table           bool
+	fillRows        bool
+	json            bool
+	separator       string
+	outputSeparator string
+	names           string
+	right           string
+	hide            string
+	width           int
+}
+
+var pFlags columnFlags
+
+// flags definition for column command.
+var flags = []cmdflags.Flag{
+	{Value: &pFlags.table, Name: "table", ShortHand: "t", DefaultValue: false, Description: "create a table"},
+	{Value: &pFlags.fillRows, Name: "fillrows", ShortHand: "x", DefaultValue: false, Description: "fill rows before columns"},
+	{Value: &pFlags.json, Name: "json", ShortHand: "J", DefaultValue: false, Description: "use JSON output format for table"},
+}
+
+// stringFlags definition for column command.
+var stringFlags = []cmdflags.StringFlag{
+	{Value: &pFlags.separator, Name: "separator", ShortHand: "s", DefaultValue: " \t", Description: "possible table delimiters"},
+	{Value: &pFlags.outputSeparator, Name: "output-separator", ShortHand: "o", DefaultValue: "  ", Description: "columns separator for table output"},
+	{Value: &pFlags.names, Name: "table-columns", ShortHand: "N", DefaultValue: "", Description: "comma separated columns names"},
+	{Value: &pFlags.right, Name: "table-right", ShortHand: "R", DefaultValue: "", Description: "right align text in these columns"},
+	{Value: &pFlags.hide, Name: "table-hide", ShortHand: "H", DefaultValue: "", Description: "don't print the columns"},
+}
+
+// intFlags definition for column command.
+var intFlags = []cmdflags.IntFlag{
+	{Value: &pFlags.width, Name: "output-width", ShortHand: "c", DefaultValue: 0, Description: "width of output in number of characters"},
+}
+
+// Cmd represents the 'column' command configuration using Cobra.
+var Cmd = &cobra.Command{
+	Use:           "column [-f flags] [file]...",
+	Short:         "Columnate lists",
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return exit.Status(executeColumn(args))
+	},
+}
+
+// init initializes the 'column' command by setting up flags.
+func init() {
+	cmdflags.ParseFlags(flags, Cmd)
+	cmdflags.ParseStringFlags(stringFlags, Cmd)
+	cmdflags.ParseIntFlags(intFlags, Cmd)
+
+	Cmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		fmt.Fprintf(os.Stderr, "column: %v\n", err)
+		return exit.Status(1)
+	})
+}
+
+// executeColumn executes the column command with given arguments and
+// returns its exit status.
+func executeColumn(args []string) int {
+	var t *table
+	if pFlags.table || pFlags.json {
+		var err error
+		if t, err = newTable(); err != nil {
+			return exit.Fail("column", err)
+		}
+	}
+	width, err := outputWidth()
+	if err != nil {
+		return exit.Fail("column", err)
+	}
+
+	status := 0
+	var lines []string
+	for _, name := range fileinput.Args(args) {
+		read, err := readLines(name)
+		lines = append(lines, read...)
+		if err != nil {
+			status = exit.Fail("column", err)
+		}
+	}
+
+	out := bufio.NewWriter(os.Stdout)
+	defer out.Flush()
+	switch {
+	case t != nil:
+		for _, line := range lines {
+			if err := t.addLine(line); err != nil {
+				return exit.Fail("column", err)
+			}
+		}
+		t.print(out)
+	case pFlags.fillRows:
+		fillRows(out, lines, width)
+	default:
+		fillColumns(out, lines, width)
+	}
+	return status
+}
+
+// outputWidth returns the width given with -c, or else by the COLUMNS
+// environment variable.
+func outputWidth() (int, error) {
+	if pFlags.width < 0 {
+		return 0, fmt.Errorf("invalid columns argument: '%d'", pFlags.width)
+	}
+	if pFlags.width > 0 {
+		return pFlags.width, nil
+	}
+	if n, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && n > 0 {
+		return n, nil
+	}
+	return defaultWidth, nil
+}
+
+// readLines returns the lines of the named file that are not empty,
+// without their newlines.
+func readLines(name string) ([]string, error) {
+	f, err := fileinput.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer fileinput.Close(f)
+
+	var lines []string
+	lr := lineio.NewReader(f)
+	for {
+		line, err := lr.ReadLine()
+		if err == io.EOF {
+			return lines, nil
+		}
+		if err != nil {
+			return lines, err
+		}
+		if text := lr.TrimDelim(line); len(text) > 0 {
+			lines = append(lines, string(text))
+		}
+	}
+}
+
+// tabSize is the width to which entries are padded with tabs when filling
+// columns.
+const tabSize = 8
+
+// entryWidth returns the width of the columns entries are filled in: that
+// of the widest entry, rounded past the next tab stop.
+func entryWidth(entries []string) int {
+	widest := 0
+	for _, e := range entries {
+		widest = max(widest, textwidth.String(e))
+	}
+	return (widest + tabSize) &^ (tabSize - 1)
+}
+
+// padTo pads an entry ending at column to the column end with tabs.
+func padTo(out *bufio.Writer, column, end int) {
+	for {
+		next := (column + tabSize) &^ (tabSize - 1)
+		if next > end {
+			return
+		}
+		out.WriteByte('\t')
+		column = next
+	}
+}
+
+// fillColumns prints the entries in as many columns as fit in width,
+// filling each column before the next.
+func fillColumns(out *bufio.Writer, entries []string, width int) {
+	if len(entries) == 0 {
+		return
+	}
+	size := entryWidth(entries)
+	columns := max(width/size, 1)
+	rows := (len(entries) + columns - 1) / columns
+
+	for row := 0; row < rows; row++ {
+		column, end := 0, size
+		for i := row; i < len(entries); i += rows {
+			if i != row {
+				padTo(out, column, end)
+				column, end = end, end+size
+			}
+			out.WriteString(entries[i])
+			column += textwidth.String(entries[i])
+		}
+		out.WriteByte('\n')
+	}
+}
+
+// fillRows prints the entries in as many columns as fit in width, filling
+// each row before the next.
+func fillRows(out *bufio.Writer, entries []string, width int) {
+	size := entryWidth(entries)
+	columns := max(width/size, 1)
+	for i, e := range entries {
+		if i%columns != 0 {
+			padTo(out, textwidth.String(entries[i-1])+(i%columns-1)*size, i%columns*size)
+		}
+		out.WriteString(e)
+		if i%columns == columns-1 || i == len(entries)-1 {
+			out.WriteByte('\n')
+		}
+	}
+}
+
+// splitList splits a comma separated list, an empty one having no items.
+func splitList(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, ",")
+}
@@ -0,0 +1,156 @@
+package column
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+
+	"github.com/skraio/unix-utilities/internal/assert"
+)
+
+func TestFill(t *testing.T) {
+	entries := []string{"1", "2", "3", "4", "5", "6", "7"}
+
+	var out strings.Builder
+	w := bufio.NewWriter(&out)
+	fillColumns(w, entries, 24)
+	w.Flush()
+	assert.Equal(t, out.String(), "1\t4\t7\n2\t5\n3\t6\n")
+
+	out.Reset()
+	fillRows(w, entries, 24)
+	w.Flush()
+	assert.Equal(t, out.String(), "1\t2\t3\n4\t5\t6\n7\n")
+
+	out.Reset()
+	fillColumns(w, []string{"longer than", "the width"}, 10)
+	w.Flush()
+	assert.Equal(t, out.String(), "longer than\nthe width\n")
+}
+
+func TestTable(t *testing.T) {
+	input := []string{"name age city", "Alice 30 Paris", "李小龙\t33  香港", "Bob"}
+
+	tests := []struct {
+		name  string
+		flags columnFlags
+		want  string
+	}{
+		{
+			name:  "Aligned by display width",
+			flags: columnFlags{},
+			want:  "name    age  city\nAlice   30   Paris\n李小龙  33   香港\nBob          \n",
+		},
+		{
+			name:  "Names and right alignment",
+			flags: columnFlags{names: "N,A,C", right: "A", outputSeparator: " | "},
+			want:  "N      |   A | C\nname   | age | city\nAlice  |  30 | Paris\n李小龙 |  33 | 香港\nBob    |     | \n",
+		},
+		{
+			name:  "Hidden columns",
+			flags: columnFlags{names: "N,A,C", hide: "3,N"},
+			want:  "A\nage\n30\n33\n\n",
+		},
+		{
+			name:  "Unnamed columns hidden",
+			flags: columnFlags{names: "N,A", hide: "-"},
+			want:  "N       A\nname    age\nAlice   30\n李小龙  33\nBob     \n",
+		},
+		{
+			name:  "JSON",
+			flags: columnFlags{names: "Name,Age,City", hide: "City", json: true},
+			want: `{
+   "table": [
+      {
+         "name": "name",
+         "age": "age"
+      },{
+         "name": "Alice",
+         "age": "30"
+      },{
+         "name": "李小龙",
+         "age": "33"
+      },{
+         "name": "Bob",
+         "age": null
+      }
+   ]
+}
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pFlags = tt.flags
+			pFlags.separator = " \t"
+			if pFlags.outputSeparator == "" {
+				pFlags.outputSeparator = "  "
+			}
+			defer func() { pFlags = columnFlags{} }()
+
+			tab, err := newTable()
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, line := range input {
+				if err := tab.addLine(line); err != nil {
+					t.Fatal(err)
+				}
+			}
+			var out strings.Builder
+			w := bufio.NewWriter(&out)
+			tab.print(w)
+			w.Flush()
+			assert.Equal(t, out.String(), tt.want)
+		})
+	}
+}
+
+func TestNewTableErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		flags columnFlags
+		err   string
+	}{
+		{name: "JSON without names", flags: columnFlags{json: true}, err: "option --table-columns required for --json"},
+		{name: "Undefined name", flags: columnFlags{names: "a,b", right: "c"}, err: "undefined column name 'c'"},
+		{name: "Column number", flags: columnFlags{hide: "0"}, err: "invalid column number: '0'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pFlags = tt.flags
+			defer func() { pFlags = columnFlags{} }()
+
+			_, err := newTable()
+			assert.Equal(t, err.Error(), tt.err)
+		})
+	}
+}
+
+func TestJSONUnnamedField(t *testing.T) {
+	pFlags = columnFlags{names: "a,b", json: true, separator: " "}
+	defer func() { pFlags = columnFlags{} }()
+
+	tab, err := newTable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tab.addLine("1 2"); err != nil {
+		t.Fatal(err)
+	}
+	err = tab.addLine("1 2 3")
+	if err == nil {
+		t.Fatal("addLine succeeded on a field past the names; want error")
+	}
+	assert.Equal(t, err.Error(), "line 2: for JSON the name of the column 3 is required")
+
+	pFlags.hide = "-"
+	if tab, err = newTable(); err != nil {
+		t.Fatal(err)
+	}
+	if err := tab.addLine("1 2 3"); err != nil {
+		t.Fatal(err)
+	}
+}
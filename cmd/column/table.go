@@ -0,0 +1,227 @@
+package column
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/skraio/unix-utilities/internal/textwidth"
+)
+
+// table aligns the fields of lines into columns.
+type table struct {
+	// separators are the characters between fields, any run of them
+	// counting as one.
+	separators string
+	output     string
+
+	// names are those of the columns, printed as a header. Lines may have
+	// more fields than there are names.
+	names []string
+	rows  [][]string
+
+	// right and hidden hold the columns aligned right and those not
+	// printed, by index.
+	right  map[int]bool
+	hidden map[int]bool
+	// hideUnnamed hides the columns past the names, given as '-' in the
+	// columns to hide.
+	hideUnnamed bool
+
+	json bool
+}
+
+// newTable builds the table from the flags.
+func newTable() (*table, error) {
+	t := &table{
+		separators: pFlags.separator,
+		output:     pFlags.outputSeparator,
+		names:      splitList(pFlags.names),
+		json:       pFlags.json,
+	}
+	if t.json && len(t.names) == 0 {
+		return nil, errors.New("option --table-columns required for --json")
+	}
+
+	hide := splitList(pFlags.hide)
+	if i := indexOf(hide, "-"); i >= 0 {
+		t.hideUnnamed = true
+		hide = append(hide[:i], hide[i+1:]...)
+	}
+
+	var err error
+	if t.right, err = t.columnSet(splitList(pFlags.right)); err != nil {
+		return nil, err
+	}
+	if t.hidden, err = t.columnSet(hide); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// columnSet parses a list of columns, each given by its name or by its
+// number counting from 1.
+func (t *table) columnSet(list []string) (map[int]bool, error) {
+	set := make(map[int]bool)
+	for _, item := range list {
+		if n, err := strconv.Atoi(item); err == nil {
+			if n < 1 {
+				return nil, fmt.Errorf("invalid column number: '%s'", item)
+			}
+			set[n-1] = true
+			continue
+		}
+
+		i := indexOf(t.names, item)
+		if i < 0 {
+			return nil, fmt.Errorf("undefined column name '%s'", item)
+		}
+		set[i] = true
+	}
+	return set, nil
+}
+
+// indexOf returns the index of the first name equal to s, or -1.
+func indexOf(names []string, s string) int {
+	for i, name := range names {
+		if name == s {
+			return i
+		}
+	}
+	return -1
+}
+
+// addLine splits line into the fields of a row. In JSON, where fields are
+// members named after their columns, a field past the names is an error
+// unless unnamed columns are hidden.
+func (t *table) addLine(line string) error {
+	fields := strings.FieldsFunc(line, func(r rune) bool {
+		return strings.ContainsRune(t.separators, r)
+	})
+	if len(fields) == 0 {
+		return nil
+	}
+	if t.json && !t.hideUnnamed && len(fields) > len(t.names) {
+		return fmt.Errorf("line %d: for JSON the name of the column %d is required", len(t.rows)+1, len(t.names)+1)
+	}
+	t.rows = append(t.rows, fields)
+	return nil
+}
+
+// isHidden reports whether column i is not printed.
+func (t *table) isHidden(i int) bool {
+	return t.hidden[i] || (t.hideUnnamed && i >= len(t.names))
+}
+
+// print prints the table, as JSON if asked.
+func (t *table) print(out *bufio.Writer) {
+	if t.json {
+		t.printJSON(out)
+		return
+	}
+
+	columns := len(t.names)
+	for _, row := range t.rows {
+		columns = max(columns, len(row))
+	}
+	widths := make([]int, columns)
+	for _, row := range append([][]string{t.names}, t.rows...) {
+		for i, field := range row {
+			widths[i] = max(widths[i], textwidth.String(field))
+		}
+	}
+
+	// The last column printed is only padded when aligned right.
+	last := columns - 1
+	for last >= 0 && t.isHidden(last) {
+		last--
+	}
+
+	printRow := func(row []string) {
+		for i := 0; i <= last; i++ {
+			if t.isHidden(i) {
+				continue
+			}
+			var field string
+			if i < len(row) {
+				field = row[i]
+			}
+			padding := strings.Repeat(" ", widths[i]-textwidth.String(field))
+			switch {
+			case t.right[i]:
+				out.WriteString(padding + field)
+			case i == last:
+				out.WriteString(field)
+			default:
+				out.WriteString(field + padding)
+			}
+			if i != last {
+				out.WriteString(t.output)
+			}
+		}
+		out.WriteByte('\n')
+	}
+
+	if len(t.names) > 0 {
+		printRow(t.names)
+	}
+	for _, row := range t.rows {
+		printRow(row)
+	}
+}
+
+// printJSON prints the rows as an array of objects with a member for each
+// named column, missing fields being null.
+func (t *table) printJSON(out *bufio.Writer) {
+	out.WriteString("{\n   \"table\": [")
+	for r, row := range t.rows {
+		if r == 0 {
+			out.WriteString("\n      {")
+		} else {
+			out.WriteString(",{")
+		}
+		first := true
+		for i, name := range t.names {
+			if t.isHidden(i) {
+				continue
+			}
+			if !first {
+				out.WriteString(",")
+			}
+			first = false
+			out.WriteString("\n         " + quoteJSON(strings.ToLower(name)) + ": ")
+			if i < len(row) {
+				out.WriteString(quoteJSON(row[i]))
+			} else {
+				out.WriteString("null")
+			}
+		}
+		out.WriteString("\n      }")
+	}
+	out.WriteString("\n   ]\n}\n")
+}
+
+// quoteJSON returns s as a JSON string.
+func quoteJSON(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch {
+		case r == '"' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r == '\n':
+			b.WriteString(`\n`)
+		case r == '\t':
+			b.WriteString(`\t`)
+		case r < 0x20:
+			fmt.Fprintf(&b, `\u%04x`, r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
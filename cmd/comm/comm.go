@@ -0,0 +1,247 @@
+// Package comm provides functionality for comparing two sorted files line
+// by line, in three columns: lines only in the first, lines only in the
+// second and lines in both.
+package comm
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/skraio/unix-utilities/cmdflags"
+	"github.com/skraio/unix-utilities/internal/exit"
+	"github.com/skraio/unix-utilities/internal/fileinput"
+	"github.com/skraio/unix-utilities/internal/lineio"
+	"github.com/spf13/cobra"
+)
+
+// Order checking modes.
+const (
+	checkDefault = iota
+	checkStrict
+	checkNone
+)
+
+// commFlags holds flags for comm command.
+type commFlags struct {
+	hide1        bool
+	hide2        bool
+	hide3        bool
+	checkOrder   bool
+	noCheckOrder bool
+	zero         bool
+	delimiter    string
+}
+
+var pFlags commFlags
+
+// flags definition for comm command.
+var flags = []cmdflags.Flag{
+	{Value: &pFlags.hide1, Name: "1", ShortHand: "1", DefaultValue: false, Description: "suppress column 1 (lines unique to FILE1)"},
+	{Value: &pFlags.hide2, Name: "2", ShortHand: "2", DefaultValue: false, Description: "suppress column 2 (lines unique to FILE2)"},
+	{Value: &pFlags.hide3, Name: "3", ShortHand: "3", DefaultValue: false, Description: "suppress column 3 (lines that appear in both files)"},
+	{Value: &pFlags.checkOrder, Name: "check-order", ShortHand: "", DefaultValue: false, Description: "check that the input is correctly sorted, even if all input lines are pairable"},
+	{Value: &pFlags.noCheckOrder, Name: "nocheck-order", ShortHand: "", DefaultValue: false, Description: "do not check that the input is correctly sorted"},
+	{Value: &pFlags.zero, Name: "zero-terminated", ShortHand: "z", DefaultValue: false, Description: "line delimiter is NUL, not newline"},
+}
+
+// stringFlags definition for comm command.
+var stringFlags = []cmdflags.StringFlag{
+	{Value: &pFlags.delimiter, Name: "output-delimiter", ShortHand: "", DefaultValue: "\t", Description: "separate columns with STR"},
+}
+
+// Cmd represents the 'comm' command configuration using Cobra.
+var Cmd = &cobra.Command{
+	Use:           "comm [-f flags] file1 file2",
+	Short:         "Compare two sorted files line by line",
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return exit.Status(executeComm(args))
+	},
+}
+
+// init initializes the 'comm' command by setting up flags.
+func init() {
+	cmdflags.ParseFlags(flags, Cmd)
+	cmdflags.ParseStringFlags(stringFlags, Cmd)
+
+	Cmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		fmt.Fprintf(os.Stderr, "comm: %v\n", err)
+		return exit.Status(1)
+	})
+}
+
+// executeComm executes the comm command with given arguments and returns
+// its exit status.
+func executeComm(args []string) int {
+	switch len(args) {
+	case 0:
+		return exit.Fail("comm", errors.New("missing operand"))
+	case 1:
+		return exit.Fail("comm", fmt.Errorf("missing operand after '%s'", args[0]))
+	case 2:
+	default:
+		return exit.Fail("comm", fmt.Errorf("extra operand '%s'", args[2]))
+	}
+
+	c := &commer{
+		lineEnd:   '\n',
+		separator: []byte(pFlags.delimiter),
+		columns:   [3]bool{!pFlags.hide1, !pFlags.hide2, !pFlags.hide3},
+	}
+	if pFlags.zero {
+		c.lineEnd = 0
+	}
+	// An empty delimiter separates columns with a NUL character.
+	if len(c.separator) == 0 {
+		c.separator = []byte{0}
+	}
+	switch {
+	case pFlags.checkOrder:
+		c.checkOrder = checkStrict
+	case pFlags.noCheckOrder:
+		c.checkOrder = checkNone
+	}
+
+	p, err := fileinput.OpenPair(args[0], args[1])
+	if err != nil {
+		return exit.Fail("comm", err)
+	}
+	defer p.Close()
+
+	c.out = bufio.NewWriter(os.Stdout)
+	err = c.comm(p.Lines(c.lineEnd))
+	c.out.Flush()
+	if err != nil {
+		return exit.Fail("comm", err)
+	}
+	if c.disorder[0] || c.disorder[1] {
+		return exit.Fail("comm", errors.New("input is not in sorted order"))
+	}
+	return 0
+}
+
+// commer compares the lines of two sorted files.
+type commer struct {
+	out       *bufio.Writer
+	lineEnd   byte
+	separator []byte
+
+	// columns tells which of the three columns are printed.
+	columns [3]bool
+
+	checkOrder int
+
+	// unpaired is set once a line only in one of the files is seen, from
+	// which on the order is checked by default.
+	unpaired bool
+
+	// disorder tells of which files a line out of order was reported.
+	disorder [2]bool
+}
+
+// comm merges the lines of both files, printing each in its column.
+func (c *commer) comm(in [2]*lineio.Reader) error {
+	var lines, previous, earlier [2][]byte
+	for i := range in {
+		line, err := c.read(in[i], i, nil, nil)
+		if err != nil {
+			return err
+		}
+		lines[i] = line
+	}
+
+	for lines[0] != nil || lines[1] != nil {
+		order := 0
+		switch {
+		case lines[0] == nil:
+			order = 1
+		case lines[1] == nil:
+			order = -1
+		default:
+			order = bytes.Compare(lines[0], lines[1])
+		}
+
+		switch {
+		case order < 0:
+			c.unpaired = true
+			c.write(0, lines[0])
+		case order > 0:
+			c.unpaired = true
+			c.write(1, lines[1])
+		default:
+			c.write(2, lines[0])
+		}
+
+		for i := range in {
+			if order < 0 && i == 1 || order > 0 && i == 0 {
+				continue
+			}
+			earlier[i] = append(earlier[i][:0], previous[i]...)
+			previous[i] = append(previous[i][:0], lines[i]...)
+			line, err := c.read(in[i], i, earlier[i], previous[i])
+			if err != nil {
+				return err
+			}
+			lines[i] = line
+		}
+	}
+	return nil
+}
+
+// read returns a copy of the next line of file i without its delimiter,
+// or nil at the end of the file, checking that it does not come before
+// the previous line. As GNU comm does, the last two lines, earlier and
+// previous, are checked again at the end of the file, since a line left
+// unpaired since they were read turns the check on by default.
+func (c *commer) read(in *lineio.Reader, i int, earlier, previous []byte) ([]byte, error) {
+	line, err := in.ReadLine()
+	if err == io.EOF {
+		return nil, c.check(i, earlier, previous)
+	}
+	if err != nil {
+		return nil, err
+	}
+	line = append([]byte{}, in.TrimDelim(line)...)
+	return line, c.check(i, previous, line)
+}
+
+// check reports that file i is out of order when line comes before
+// previous, once the order is checked. Only strict checking makes it an
+// error.
+func (c *commer) check(i int, previous, line []byte) error {
+	if previous == nil || line == nil || c.disorder[i] || bytes.Compare(previous, line) <= 0 {
+		return nil
+	}
+	if c.checkOrder != checkStrict && (c.checkOrder != checkDefault || !c.unpaired) {
+		return nil
+	}
+
+	err := fmt.Errorf("file %d is not in sorted order", i+1)
+	if c.checkOrder == checkStrict {
+		return err
+	}
+	c.out.Flush()
+	fmt.Fprintf(os.Stderr, "comm: %v\n", err)
+	c.disorder[i] = true
+	return nil
+}
+
+// write prints a line in a column, 0 to 2, after the separators of the
+// columns before it that are printed.
+func (c *commer) write(column int, line []byte) {
+	if !c.columns[column] {
+		return
+	}
+	for _, shown := range c.columns[:column] {
+		if shown {
+			c.out.Write(c.separator)
+		}
+	}
+	c.out.Write(line)
+	c.out.WriteByte(c.lineEnd)
+}
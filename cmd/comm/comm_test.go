@@ -0,0 +1,59 @@
+package comm
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+
+	"github.com/skraio/unix-utilities/internal/assert"
+	"github.com/skraio/unix-utilities/internal/lineio"
+)
+
+func TestComm(t *testing.T) {
+	tests := []struct {
+		name       string
+		file1      string
+		file2      string
+		columns    [3]bool
+		separator  string
+		checkOrder int
+		want       string
+		disorder   bool
+		err        string
+	}{
+		{name: "All columns", file1: "a\nb\nd\ne", file2: "a\nc\nd\nf\n", columns: [3]bool{true, true, true}, want: "\t\ta\nb\n\tc\n\t\td\ne\n\tf\n"},
+		{name: "Common lines", file1: "a\nb\nd\n", file2: "a\nc\nd\n", columns: [3]bool{false, false, true}, want: "a\nd\n"},
+		{name: "Separator", file1: "a\nb\n", file2: "a\nc\n", columns: [3]bool{false, true, true}, separator: "::", want: "::a\nc\n"},
+		{name: "Unsorted", file1: "b\na\n", file2: "a\nc\n", columns: [3]bool{true, true, true}, want: "\ta\nb\na\n\tc\n", disorder: true},
+		{name: "Unsorted but paired", file1: "b\na\n", file2: "b\na\n", columns: [3]bool{true, true, true}, want: "\t\tb\n\t\ta\n"},
+		{name: "Unsorted last line", file1: "b\na\n", file2: "b\nc\nd\nf\n", columns: [3]bool{true, true, true}, want: "\t\tb\na\n\tc\n\td\n\tf\n", disorder: true},
+		{name: "Unsorted before paired lines", file1: "b\na\nz\n", file2: "b\nz\n", columns: [3]bool{true, true, true}, want: "\t\tb\na\n\t\tz\n"},
+		{name: "Strict order", file1: "b\na\n", file2: "b\na\n", columns: [3]bool{true, true, true}, checkOrder: checkStrict, want: "\t\tb\n", err: "file 1 is not in sorted order"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out strings.Builder
+			c := &commer{
+				out:        bufio.NewWriter(&out),
+				lineEnd:    '\n',
+				separator:  []byte("\t"),
+				columns:    tt.columns,
+				checkOrder: tt.checkOrder,
+			}
+			if tt.separator != "" {
+				c.separator = []byte(tt.separator)
+			}
+			err := c.comm([2]*lineio.Reader{
+				lineio.NewReader(strings.NewReader(tt.file1)),
+				lineio.NewReader(strings.NewReader(tt.file2)),
+			})
+			c.out.Flush()
+			assert.Equal(t, out.String(), tt.want)
+			assert.Equal(t, c.disorder[0], tt.disorder)
+			if tt.err != "" {
+				assert.Equal(t, err.Error(), tt.err)
+			}
+		})
+	}
+}
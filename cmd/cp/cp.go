@@ -0,0 +1,127 @@
+// Package cp provides functionality for copying files and directories.
+package cp
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/skraio/unix-utilities/cmdflags"
+	"github.com/skraio/unix-utilities/internal/exit"
+	"github.com/skraio/unix-utilities/internal/filecopy"
+	"github.com/spf13/cobra"
+)
+
+// cpFlags holds flags for cp command.
+type cpFlags struct {
+	recursive   bool
+	recursiveR  bool
+	archive     bool
+	preserve    bool
+	noClobber   bool
+	update      bool
+	force       bool
+	dereference bool
+	verbose     bool
+	reflink     string
+}
+
+var pFlags cpFlags
+
+// flags definition for cp command.
+var flags = []cmdflags.Flag{
+	{Value: &pFlags.recursive, Name: "recursive", ShortHand: "r", DefaultValue: false, Description: "copy directories recursively"},
+	{Value: &pFlags.recursiveR, Name: "recursive-R", ShortHand: "R", DefaultValue: false, Description: "same as -r"},
+	{Value: &pFlags.archive, Name: "archive", ShortHand: "a", DefaultValue: false, Description: "copy directories recursively, keeping links and preserving all attributes"},
+	{Value: &pFlags.preserve, Name: "preserve", ShortHand: "p", DefaultValue: false, Description: "preserve mode, ownership, timestamps and extended attributes"},
+	{Value: &pFlags.noClobber, Name: "no-clobber", ShortHand: "n", DefaultValue: false, Description: "do not overwrite an existing file"},
+	{Value: &pFlags.update, Name: "update", ShortHand: "u", DefaultValue: false, Description: "copy only when the source file is newer than the destination file or when the destination file is missing"},
+	{Value: &pFlags.force, Name: "force", ShortHand: "f", DefaultValue: false, Description: "remove a destination file that cannot be opened and try again"},
+	{Value: &pFlags.dereference, Name: "dereference", ShortHand: "L", DefaultValue: false, Description: "always follow symbolic links in source"},
+	{Value: &pFlags.verbose, Name: "verbose", ShortHand: "v", DefaultValue: false, Description: "explain what is being done"},
+}
+
+// stringFlags definition for cp command.
+var stringFlags = []cmdflags.StringFlag{
+	{Value: &pFlags.reflink, Name: "reflink", ShortHand: "", DefaultValue: "auto", Description: "control clone/CoW copies: auto, always or never"},
+}
+
+// Cmd represents the 'cp' command configuration using Cobra.
+var Cmd = &cobra.Command{
+	Use:           "cp [-f flags] source... dest",
+	Short:         "Copy files and directories",
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return exit.Status(executeCp(args))
+	},
+}
+
+// init initializes the 'cp' command by setting up flags.
+func init() {
+	cmdflags.ParseFlags(flags, Cmd)
+	cmdflags.ParseStringFlags(stringFlags, Cmd)
+	Cmd.Flags().Lookup("reflink").NoOptDefVal = "always"
+	Cmd.Flags().Lookup("recursive-R").Hidden = true
+
+	Cmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		fmt.Fprintf(os.Stderr, "cp: %v\n", err)
+		return exit.Status(1)
+	})
+}
+
+// executeCp executes the cp command with given arguments and returns its
+// exit status.
+func executeCp(args []string) int {
+	opts, err := options()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "cp: %v\n", err)
+		return 1
+	}
+
+	pairs, err := filecopy.Targets(args)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "cp: %v\n", err)
+		return 1
+	}
+
+	status := 0
+	for _, p := range pairs {
+		if !filecopy.Copy(p[0], p[1], opts) {
+			status = 1
+		}
+	}
+	return status
+}
+
+// options returns the copy options selected by the flags.
+func options() (filecopy.Options, error) {
+	opts := filecopy.Options{
+		Recursive:   pFlags.recursive || pFlags.recursiveR || pFlags.archive,
+		Preserve:    pFlags.preserve || pFlags.archive,
+		NoClobber:   pFlags.noClobber,
+		Update:      pFlags.update,
+		Force:       pFlags.force,
+		Dereference: pFlags.dereference || !pFlags.archive && !pFlags.recursive && !pFlags.recursiveR,
+		Report: func(err error) {
+			fmt.Fprintf(os.Stderr, "cp: %v\n", err)
+		},
+	}
+
+	switch pFlags.reflink {
+	case "never":
+		opts.Reflink = filecopy.ReflinkNever
+	case "auto":
+		opts.Reflink = filecopy.ReflinkAuto
+	case "always":
+		opts.Reflink = filecopy.ReflinkAlways
+	default:
+		return opts, fmt.Errorf("invalid argument '%s' for '--reflink'", pFlags.reflink)
+	}
+
+	if pFlags.verbose {
+		opts.Verbose = func(src, dst string) {
+			fmt.Printf("'%s' -> '%s'\n", src, dst)
+		}
+	}
+	return opts, nil
+}
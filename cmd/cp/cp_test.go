@@ -0,0 +1,118 @@
+package cp
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/skraio/unix-utilities/internal/assert"
+)
+
+// writeFile creates a file with the given contents and mode, creating its
+// directory as needed.
+func writeFile(t *testing.T, path, content string, mode os.FileMode) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), mode); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chmod(path, mode); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// readFile returns the contents of a file.
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+func TestCopy(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "src")
+	writeFile(t, filepath.Join(src, "a.txt"), "alpha", 0640)
+	writeFile(t, filepath.Join(src, "sub/b.txt"), "beta", 0600)
+	if err := os.Symlink("a.txt", filepath.Join(src, "link")); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	if err := os.Chtimes(filepath.Join(src, "a.txt"), old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("Single file into directory", func(t *testing.T) {
+		pFlags = cpFlags{reflink: "never"}
+		dest := filepath.Join(dir, "single")
+		if err := os.Mkdir(dest, 0755); err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, executeCp([]string{filepath.Join(src, "a.txt"), dest}), 0)
+		assert.Equal(t, readFile(t, filepath.Join(dest, "a.txt")), "alpha")
+	})
+
+	t.Run("Directory without recursion", func(t *testing.T) {
+		pFlags = cpFlags{reflink: "never"}
+		assert.Equal(t, executeCp([]string{src, filepath.Join(dir, "norec")}), 1)
+		_, err := os.Stat(filepath.Join(dir, "norec"))
+		assert.Equal(t, os.IsNotExist(err), true)
+	})
+
+	t.Run("Archive", func(t *testing.T) {
+		pFlags = cpFlags{archive: true, reflink: "auto"}
+		dest := filepath.Join(dir, "archive")
+		assert.Equal(t, executeCp([]string{src, dest}), 0)
+
+		assert.Equal(t, readFile(t, filepath.Join(dest, "sub/b.txt")), "beta")
+		target, err := os.Readlink(filepath.Join(dest, "link"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, target, "a.txt")
+
+		info, err := os.Stat(filepath.Join(dest, "a.txt"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, info.Mode().Perm(), os.FileMode(0640))
+		assert.Equal(t, info.ModTime().Equal(old), true)
+	})
+
+	t.Run("No clobber and partial failure", func(t *testing.T) {
+		pFlags = cpFlags{noClobber: true, reflink: "never"}
+		dest := filepath.Join(dir, "keep")
+		writeFile(t, filepath.Join(dest, "a.txt"), "kept", 0644)
+		args := []string{filepath.Join(src, "a.txt"), filepath.Join(src, "missing"), filepath.Join(src, "sub/b.txt"), dest}
+		assert.Equal(t, executeCp(args), 1)
+		assert.Equal(t, readFile(t, filepath.Join(dest, "a.txt")), "kept")
+		assert.Equal(t, readFile(t, filepath.Join(dest, "b.txt")), "beta")
+	})
+
+	t.Run("Sparse file", func(t *testing.T) {
+		pFlags = cpFlags{reflink: "never"}
+		sparse := filepath.Join(dir, "sparse")
+		f, err := os.Create(sparse)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := f.WriteAt([]byte("end"), 1<<20); err != nil {
+			t.Fatal(err)
+		}
+		f.Close()
+
+		dest := filepath.Join(dir, "sparse.copy")
+		assert.Equal(t, executeCp([]string{sparse, dest}), 0)
+		assert.Equal(t, readFile(t, dest), readFile(t, sparse))
+	})
+
+	t.Run("Invalid reflink", func(t *testing.T) {
+		pFlags = cpFlags{reflink: "sometimes"}
+		assert.Equal(t, executeCp([]string{filepath.Join(src, "a.txt"), filepath.Join(dir, "x")}), 1)
+	})
+}
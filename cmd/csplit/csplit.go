@@ -0,0 +1,127 @@
+// Package csplit provides functionality for splitting a file into pieces
+// ending at lines given by number or matching regular expressions.
+package csplit
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/skraio/unix-utilities/cmdflags"
+	"github.com/skraio/unix-utilities/internal/exit"
+	"github.com/skraio/unix-utilities/internal/fileinput"
+	"github.com/skraio/unix-utilities/internal/lineio"
+	"github.com/spf13/cobra"
+)
+
+// csplitFlags holds flags for csplit command.
+type csplitFlags struct {
+	keep   bool
+	elide  bool
+	quiet  bool
+	silent bool
+	prefix string
+	suffix string
+	digits string
+}
+
+var pFlags csplitFlags
+
+// flags definition for csplit command.
+var flags = []cmdflags.Flag{
+	{Value: &pFlags.keep, Name: "keep-files", ShortHand: "k", DefaultValue: false, Description: "do not remove output files on errors"},
+	{Value: &pFlags.elide, Name: "elide-empty-files", ShortHand: "z", DefaultValue: false, Description: "remove empty output files"},
+	{Value: &pFlags.quiet, Name: "quiet", ShortHand: "q", DefaultValue: false, Description: "do not print counts of output file sizes"},
+	{Value: &pFlags.silent, Name: "silent", ShortHand: "s", DefaultValue: false, Description: "same as --quiet"},
+}
+
+// stringFlags definition for csplit command.
+var stringFlags = []cmdflags.StringFlag{
+	{Value: &pFlags.prefix, Name: "prefix", ShortHand: "f", DefaultValue: "xx", Description: "use PREFIX instead of 'xx'"},
+	{Value: &pFlags.suffix, Name: "suffix-format", ShortHand: "b", DefaultValue: "", Description: "use sprintf FORMAT instead of %02d"},
+	{Value: &pFlags.digits, Name: "digits", ShortHand: "n", DefaultValue: "2", Description: "use specified number of digits instead of 2"},
+}
+
+// Cmd represents the 'csplit' command configuration using Cobra.
+var Cmd = &cobra.Command{
+	Use:           "csplit [-f flags] file pattern...",
+	Short:         "Split a file into pieces determined by context lines",
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return exit.Status(executeCsplit(args))
+	},
+}
+
+// init initializes the 'csplit' command by setting up flags.
+func init() {
+	cmdflags.ParseFlags(flags, Cmd)
+	cmdflags.ParseStringFlags(stringFlags, Cmd)
+
+	Cmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		fmt.Fprintf(os.Stderr, "csplit: %v\n", err)
+		return exit.Status(1)
+	})
+}
+
+// executeCsplit executes the csplit command with given arguments and
+// returns its exit status.
+func executeCsplit(args []string) int {
+	switch len(args) {
+	case 0:
+		return exit.Fail("csplit", errors.New("missing operand"))
+	case 1:
+		return exit.Fail("csplit", fmt.Errorf("missing operand after '%s'", args[0]))
+	}
+
+	digits, err := strconv.Atoi(pFlags.digits)
+	switch {
+	case err != nil:
+		return exit.Fail("csplit", fmt.Errorf("invalid number: '%s'", pFlags.digits))
+	case digits < 0:
+		return exit.Fail("csplit", fmt.Errorf("invalid number: '%s': Numerical result out of range", pFlags.digits))
+	}
+	suffix := func(n int) string { return fmt.Sprintf("%0*d", digits, n) }
+	if pFlags.suffix != "" {
+		format, err := suffixFormat(pFlags.suffix)
+		if err != nil {
+			return exit.Fail("csplit", err)
+		}
+		suffix = func(n int) string { return fmt.Sprintf(format, n) }
+	}
+
+	patterns, err := parsePatterns(args[1:])
+	if err != nil {
+		return exit.Fail("csplit", err)
+	}
+
+	in, err := fileinput.Open(args[0])
+	if err != nil {
+		return exit.Fail("csplit", fmt.Errorf("cannot open '%s' for reading: %v", args[0], exit.Unwrap(err)))
+	}
+	defer fileinput.Close(in)
+
+	s := &splitter{
+		in:  &input{r: lineio.NewReader(in), first: 1},
+		out: &outputs{prefix: pFlags.prefix, suffix: suffix, quiet: pFlags.quiet || pFlags.silent},
+	}
+	err = s.run(patterns)
+	if err == errDisappeared {
+		return exit.Fail("csplit", err)
+	}
+	if err != nil {
+		warn(err)
+		s.out.close()
+		if !pFlags.keep {
+			s.out.removeAll()
+		}
+		return 1
+	}
+	return 0
+}
+
+// warn prints an error.
+func warn(err error) {
+	exit.Fail("csplit", err)
+}
@@ -0,0 +1,177 @@
+package csplit
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/skraio/unix-utilities/internal/assert"
+	"github.com/skraio/unix-utilities/internal/lineio"
+)
+
+func TestSuffixFormat(t *testing.T) {
+	tests := []struct {
+		arg  string
+		want string
+		err  string
+	}{
+		{arg: "%03d.txt", want: "%03d.txt"},
+		{arg: "%%%'5i", want: "%%%5d"},
+		{arg: "%#x", want: "%#x"},
+		{arg: "x", err: "missing % conversion specification in suffix"},
+		{arg: "%d%d", err: "too many % conversion specifications in suffix"},
+		{arg: "%s", err: "invalid conversion specifier in suffix: s"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.arg, func(t *testing.T) {
+			format, err := suffixFormat(tt.arg)
+			if tt.err != "" {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				assert.Equal(t, err.Error(), tt.err)
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, format, tt.want)
+		})
+	}
+}
+
+func TestParsePatterns(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		err  string
+	}{
+		{
+			name: "Repeated patterns",
+			args: []string{"/a/+1", "{*}", "%b%-2", "3", "{2}"},
+		},
+		{
+			name: "Repeat without pattern",
+			args: []string{"1", "{2}", "{3}"},
+			err:  "'{3}': invalid pattern",
+		},
+		{
+			name: "Decreasing line numbers",
+			args: []string{"4", "2"},
+			err:  "line number '2' is smaller than preceding line number, 4",
+		},
+		{
+			name: "Bad offset",
+			args: []string{"/a/x"},
+			err:  "'/a/x': integer expected after delimiter",
+		},
+		{
+			name: "Bad repeat count",
+			args: []string{"/a/", "{x}"},
+			err:  "'{x'}: integer required between '{' and '}'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			patterns, err := parsePatterns(tt.args)
+			if tt.err != "" {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				assert.Equal(t, err.Error(), tt.err)
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, len(patterns), 3)
+			assert.Equal(t, patterns[0].forever, true)
+			assert.Equal(t, patterns[1].offset, int64(-2))
+			assert.Equal(t, patterns[2].repeat, int64(2))
+		})
+	}
+}
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name  string
+		args  []string
+		want  []string
+		err   string
+		input string
+	}{
+		{
+			name:  "Regular expressions",
+			args:  []string{"/a/+1", "{*}"},
+			input: "a\nb\na\nb\nc\n",
+			want:  []string{"a\n", "b\na\n", "b\nc\n"},
+		},
+		{
+			name:  "Line numbers and skipped piece",
+			args:  []string{"2", "%c%"},
+			input: "a\nb\na\nb\nc\n",
+			want:  []string{"a\n", "c\n"},
+		},
+		{
+			name:  "Negative offset",
+			args:  []string{"/c/-2"},
+			input: "a\nb\na\nb\nc\n",
+			want:  []string{"a\nb\n", "a\nb\nc\n"},
+		},
+		{
+			name:  "Match not found",
+			args:  []string{"/z/"},
+			input: "a\n",
+			want:  []string{"a\n"},
+			err:   "'/z/': match not found",
+		},
+		{
+			name:  "Line number out of range",
+			args:  []string{"1", "{2}"},
+			input: "a\nb\n",
+			want:  []string{"", "a\n", "b\n"},
+			err:   "'1': line number out of range on repetition 2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			patterns, err := parsePatterns(tt.args)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			s := &splitter{
+				in: &input{r: lineio.NewReader(strings.NewReader(tt.input)), first: 1},
+				out: &outputs{
+					prefix: filepath.Join(dir, "xx"),
+					suffix: func(n int) string { return fmt.Sprintf("%02d", n) },
+					quiet:  true,
+				},
+			}
+			err = s.run(patterns)
+			s.out.close()
+			if tt.err == "" && err != nil {
+				t.Fatal(err)
+			}
+			if tt.err != "" {
+				assert.Equal(t, fmt.Sprint(err), tt.err)
+			}
+
+			pieces := []string{}
+			for _, name := range s.out.created {
+				data, err := os.ReadFile(name)
+				if err != nil {
+					t.Fatal(err)
+				}
+				pieces = append(pieces, string(data))
+			}
+			assert.EqualStr(t, pieces, tt.want)
+		})
+	}
+}
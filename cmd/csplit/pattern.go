@@ -0,0 +1,167 @@
+package csplit
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/skraio/unix-utilities/internal/posixre"
+)
+
+// pattern is an operand telling where a piece of the input ends.
+type pattern struct {
+	// arg is the operand, named in messages.
+	arg string
+
+	// re matches the line the piece ends at, offset lines before or after
+	// it. The piece ends before line number line when re is nil.
+	re     *regexp.Regexp
+	offset int64
+	line   int64
+
+	// skip discards the piece instead of writing it to a file.
+	skip bool
+
+	// repeat is the number of times the pattern is used again, and
+	// forever uses it until the input ends.
+	repeat  int64
+	forever bool
+}
+
+// parsePatterns parses the pattern operands, each of which may be followed
+// by a repeat count in braces.
+func parsePatterns(args []string) ([]*pattern, error) {
+	patterns := []*pattern{}
+	lastLine := int64(0)
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		var p *pattern
+		var err error
+		if strings.HasPrefix(arg, "/") || strings.HasPrefix(arg, "%") {
+			p, err = parseRegexp(arg)
+		} else {
+			p, err = parseLine(arg, lastLine)
+			if p != nil {
+				lastLine = p.line
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if i+1 < len(args) && strings.HasPrefix(args[i+1], "{") {
+			i++
+			if err := p.parseRepeat(args[i]); err != nil {
+				return nil, err
+			}
+		}
+		patterns = append(patterns, p)
+	}
+	return patterns, nil
+}
+
+// parseRegexp parses a pattern of the form /REGEXP/[OFFSET] or
+// %REGEXP%[OFFSET], the latter skipping the piece it ends.
+func parseRegexp(arg string) (*pattern, error) {
+	delim := arg[0]
+	closing := strings.LastIndexByte(arg[1:], delim) + 1
+	if closing == 0 {
+		return nil, fmt.Errorf("%s: closing delimiter '%c' missing", arg, delim)
+	}
+
+	expr, err := posixre.TranslateBRE(arg[1:closing])
+	var re *regexp.Regexp
+	if err == nil {
+		re, err = regexp.Compile(expr)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("'%s': invalid regular expression: %v", arg, err)
+	}
+
+	p := &pattern{arg: arg, re: re, skip: delim == '%'}
+	if offset := arg[closing+1:]; offset != "" {
+		if p.offset, err = strconv.ParseInt(offset, 10, 64); err != nil {
+			return nil, fmt.Errorf("'%s': integer expected after delimiter", arg)
+		}
+	}
+	return p, nil
+}
+
+// parseLine parses a line number pattern, which must not be below the line
+// number of the previous one, last.
+func parseLine(arg string, last int64) (*pattern, error) {
+	line, err := strconv.ParseUint(arg, 10, 63)
+	switch {
+	case err != nil:
+		return nil, fmt.Errorf("'%s': invalid pattern", arg)
+	case line == 0:
+		return nil, fmt.Errorf("%s: line number must be greater than zero", arg)
+	case int64(line) < last:
+		return nil, fmt.Errorf("line number '%s' is smaller than preceding line number, %d", arg, last)
+	case int64(line) == last:
+		warn(fmt.Errorf("warning: line number '%s' is the same as preceding line number", arg))
+	}
+	return &pattern{arg: arg, line: int64(line)}, nil
+}
+
+// parseRepeat parses a repeat count of the form {INTEGER} or {*}.
+func (p *pattern) parseRepeat(arg string) error {
+	count, found := strings.CutSuffix(arg[1:], "}")
+	if !found {
+		return fmt.Errorf("'%s': '}' is required in repeat count", arg)
+	}
+	if count == "*" {
+		p.forever = true
+		return nil
+	}
+	repeat, err := strconv.ParseUint(count, 10, 63)
+	if err != nil {
+		return fmt.Errorf("'{%s'}: integer required between '{' and '}'", count)
+	}
+	p.repeat = int64(repeat)
+	return nil
+}
+
+// suffixFormat converts the printf format given to -b, which must have a
+// single integer conversion, to the format of the fmt package.
+func suffixFormat(arg string) (string, error) {
+	var b strings.Builder
+	conversions := 0
+	for i := 0; i < len(arg); i++ {
+		b.WriteByte(arg[i])
+		if arg[i] != '%' {
+			continue
+		}
+		if i+1 < len(arg) && arg[i+1] == '%' {
+			b.WriteByte('%')
+			i++
+			continue
+		}
+
+		if conversions++; conversions > 1 {
+			return "", fmt.Errorf("too many %% conversion specifications in suffix")
+		}
+		for i++; i < len(arg) && strings.IndexByte("-+ #0'123456789.", arg[i]) >= 0; i++ {
+			if arg[i] != '\'' {
+				b.WriteByte(arg[i])
+			}
+		}
+		if i == len(arg) {
+			return "", fmt.Errorf("missing conversion specifier in suffix")
+		}
+		switch c := arg[i]; c {
+		case 'd', 'i', 'u':
+			b.WriteByte('d')
+		case 'o', 'x', 'X':
+			b.WriteByte(c)
+		default:
+			return "", fmt.Errorf("invalid conversion specifier in suffix: %c", c)
+		}
+	}
+
+	if conversions == 0 {
+		return "", fmt.Errorf("missing %% conversion specification in suffix")
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,325 @@
+package csplit
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/skraio/unix-utilities/internal/exit"
+	"github.com/skraio/unix-utilities/internal/lineio"
+)
+
+// errDisappeared is returned when a piece is to start after the end of the
+// input.
+var errDisappeared = errors.New("input disappeared")
+
+// input holds the lines of the input that were read but not yet written to
+// a piece or skipped.
+type input struct {
+	r     *lineio.Reader
+	lines [][]byte
+	eof   bool
+
+	// first is the number of the first line held.
+	first int64
+
+	// current is the number of the last line consumed or searched.
+	current int64
+}
+
+// line returns line number n, reading up to it, or nil past the end of the
+// input.
+func (in *input) line(n int64) ([]byte, error) {
+	for !in.eof && n >= in.first+int64(len(in.lines)) {
+		line, err := in.r.ReadLine()
+		if err == io.EOF {
+			in.eof = true
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		in.lines = append(in.lines, append([]byte(nil), line...))
+	}
+	if i := n - in.first; i >= 0 && i < int64(len(in.lines)) {
+		return in.lines[i], nil
+	}
+	return nil, nil
+}
+
+// remove consumes the first line held, reading it if needed, and returns
+// it, or nil at the end of the input.
+func (in *input) remove() ([]byte, error) {
+	line, err := in.line(in.first)
+	if line == nil {
+		return nil, err
+	}
+	in.current = max(in.current, in.first)
+	in.lines = in.lines[1:]
+	in.first++
+	return line, nil
+}
+
+// firstLine returns the number of the first line not consumed.
+func (in *input) firstLine() (int64, error) {
+	line, err := in.line(in.first)
+	if err == nil && line == nil {
+		err = errDisappeared
+	}
+	return in.first, err
+}
+
+// exhausted reports whether all lines of the input were consumed.
+func (in *input) exhausted() (bool, error) {
+	line, err := in.line(in.first)
+	return line == nil, err
+}
+
+// outputs creates the files holding the pieces.
+type outputs struct {
+	prefix string
+	suffix func(n int) string
+	quiet  bool
+
+	created []string
+	file    *os.File
+	w       *bufio.Writer
+	size    int64
+}
+
+// create creates the file of the next piece.
+func (o *outputs) create() error {
+	name := o.prefix + o.suffix(len(o.created))
+	f, err := os.Create(name)
+	if err != nil {
+		return fmt.Errorf("%s: %v", name, exit.Unwrap(err))
+	}
+	o.created = append(o.created, name)
+	o.file, o.w, o.size = f, bufio.NewWriter(f), 0
+	return nil
+}
+
+// write writes a line to the current piece.
+func (o *outputs) write(line []byte) error {
+	o.size += int64(len(line))
+	_, err := o.w.Write(line)
+	return err
+}
+
+// close closes the current piece, if any, and prints its size. Empty
+// pieces are removed with -z, their name being used for the next one.
+func (o *outputs) close() error {
+	if o.file == nil {
+		return nil
+	}
+	err := o.w.Flush()
+	if closeErr := o.file.Close(); err == nil {
+		err = closeErr
+	}
+	o.file = nil
+	if err != nil {
+		return fmt.Errorf("%s: %v", o.created[len(o.created)-1], exit.Unwrap(err))
+	}
+
+	if o.size == 0 && pFlags.elide {
+		last := len(o.created) - 1
+		err := os.Remove(o.created[last])
+		o.created = o.created[:last]
+		return err
+	}
+	if !o.quiet {
+		fmt.Println(o.size)
+	}
+	return nil
+}
+
+// removeAll removes the files created.
+func (o *outputs) removeAll() {
+	for _, name := range o.created {
+		if err := os.Remove(name); err != nil {
+			warn(err)
+		}
+	}
+	o.created = nil
+}
+
+// splitter splits the input into pieces.
+type splitter struct {
+	in  *input
+	out *outputs
+}
+
+// run splits the input at the patterns, the rest of the input making the
+// last piece.
+func (s *splitter) run(patterns []*pattern) error {
+	for _, p := range patterns {
+		for i := int64(0); p.forever || i <= p.repeat; i++ {
+			var err error
+			done := false
+			if p.re != nil {
+				done, err = s.regexp(p, i)
+			} else {
+				err = s.lines(p, i)
+			}
+			if done || err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := s.out.create(); err != nil {
+		return err
+	}
+	if err := s.rest(); err != nil {
+		return err
+	}
+	return s.out.close()
+}
+
+// lines writes the piece ending before line number p.line times the
+// repetition plus one. It must not end past the last line.
+func (s *splitter) lines(p *pattern, repetition int64) error {
+	if err := s.out.create(); err != nil {
+		return err
+	}
+	end := p.line * (repetition + 1)
+	first, err := s.in.firstLine()
+	if err != nil {
+		return err
+	}
+	for n := first; n < end; n++ {
+		line, err := s.in.remove()
+		if err != nil {
+			return err
+		}
+		if line == nil {
+			return outOfRange(p, repetition)
+		}
+		if err := s.out.write(line); err != nil {
+			return err
+		}
+	}
+	if err := s.out.close(); err != nil {
+		return err
+	}
+
+	if exhausted, err := s.in.exhausted(); err != nil || exhausted {
+		if err == nil {
+			err = outOfRange(p, repetition)
+		}
+		return err
+	}
+	return nil
+}
+
+// regexp writes or skips the piece ending at the line p.offset lines after
+// the next one matching p.re. It reports whether the input ended, which is
+// only not an error when the pattern repeats forever.
+func (s *splitter) regexp(p *pattern, repetition int64) (bool, error) {
+	if !p.skip {
+		if err := s.out.create(); err != nil {
+			return false, err
+		}
+	}
+
+	for {
+		s.in.current++
+		line, err := s.in.line(s.in.current)
+		if err != nil {
+			return false, err
+		}
+		if line == nil {
+			if !p.skip {
+				if err := s.rest(); err != nil {
+					return false, err
+				}
+			}
+			if p.forever {
+				return true, s.out.close()
+			}
+			return false, notFound(p, repetition)
+		}
+		if p.re.Match(s.in.r.TrimDelim(line)) {
+			break
+		}
+
+		// Lines before a match are only held for negative offsets.
+		if p.offset >= 0 {
+			if line, err = s.in.remove(); err != nil {
+				return false, err
+			}
+			if !p.skip {
+				if err := s.out.write(line); err != nil {
+					return false, err
+				}
+			}
+		}
+	}
+
+	end := s.in.current + p.offset
+	first, err := s.in.firstLine()
+	if err != nil {
+		return false, err
+	}
+	if first > end {
+		return false, fmt.Errorf("'%s': line number out of range", p.arg)
+	}
+	for n := first; n < end; n++ {
+		line, err := s.in.remove()
+		if err != nil {
+			return false, err
+		}
+		if line == nil {
+			return false, fmt.Errorf("'%s': line number out of range", p.arg)
+		}
+		if !p.skip {
+			if err := s.out.write(line); err != nil {
+				return false, err
+			}
+		}
+	}
+
+	if !p.skip {
+		if err := s.out.close(); err != nil {
+			return false, err
+		}
+	}
+	if p.offset > 0 {
+		s.in.current = end
+	}
+	return false, nil
+}
+
+// rest writes the rest of the input to the current piece.
+func (s *splitter) rest() error {
+	for {
+		line, err := s.in.remove()
+		if line == nil || err != nil {
+			return err
+		}
+		if err := s.out.write(line); err != nil {
+			return err
+		}
+	}
+}
+
+// outOfRange returns the error for a line number pattern past the last
+// line.
+func outOfRange(p *pattern, repetition int64) error {
+	return fmt.Errorf("'%s': line number out of range%s", p.arg, onRepetition(repetition))
+}
+
+// notFound returns the error for a regular expression not matching.
+func notFound(p *pattern, repetition int64) error {
+	return fmt.Errorf("'%s': match not found%s", p.arg, onRepetition(repetition))
+}
+
+// onRepetition returns the end of a message about a repeated pattern.
+func onRepetition(repetition int64) string {
+	if repetition == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" on repetition %d", repetition)
+}
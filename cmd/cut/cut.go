@@ -0,0 +1,184 @@
+// Package cut provides functionality for removing sections from each line of
+// files.
+package cut
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/skraio/unix-utilities/cmdflags"
+	"github.com/skraio/unix-utilities/internal/exit"
+	"github.com/skraio/unix-utilities/internal/fileinput"
+	"github.com/skraio/unix-utilities/internal/lineio"
+	"github.com/spf13/cobra"
+)
+
+// cutFlags holds flags for cut command.
+type cutFlags struct {
+	complement    bool
+	onlyDelimited bool
+	zero          bool
+	bytes         string
+	chars         string
+	fields        string
+	delimiter     string
+	outDelimiter  string
+}
+
+var pFlags cutFlags
+
+// flags definition for cut command.
+var flags = []cmdflags.Flag{
+	{Value: &pFlags.complement, Name: "complement", ShortHand: "", DefaultValue: false, Description: "complement the set of selected bytes, characters or fields"},
+	{Value: &pFlags.onlyDelimited, Name: "only-delimited", ShortHand: "s", DefaultValue: false, Description: "do not print lines not containing delimiters"},
+	{Value: &pFlags.zero, Name: "zero-terminated", ShortHand: "z", DefaultValue: false, Description: "line delimiter is NUL, not newline"},
+}
+
+// stringFlags definition for cut command.
+var stringFlags = []cmdflags.StringFlag{
+	{Value: &pFlags.bytes, Name: "bytes", ShortHand: "b", DefaultValue: "", Description: "select only these bytes"},
+	{Value: &pFlags.chars, Name: "characters", ShortHand: "c", DefaultValue: "", Description: "select only these UTF-8 characters"},
+	{Value: &pFlags.fields, Name: "fields", ShortHand: "f", DefaultValue: "", Description: "select only these fields"},
+	{Value: &pFlags.delimiter, Name: "delimiter", ShortHand: "d", DefaultValue: "\t", Description: "use DELIM instead of TAB for field delimiter"},
+	{Value: &pFlags.outDelimiter, Name: "output-delimiter", ShortHand: "", DefaultValue: "", Description: "use STRING as the output delimiter"},
+}
+
+// Cmd represents the 'cut' command configuration using Cobra.
+var Cmd = &cobra.Command{
+	Use:           "cut [-f flags] [file]...",
+	Short:         "Remove sections from each line of files",
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c, err := newCutter(cmd.Flags().Changed("output-delimiter"))
+		if err != nil {
+			return exit.Status(exit.Fail("cut", err))
+		}
+		return exit.Status(c.executeCut(fileinput.Args(args)))
+	},
+}
+
+// init initializes the 'cut' command by setting up flags.
+func init() {
+	cmdflags.ParseFlags(flags, Cmd)
+	cmdflags.ParseStringFlags(stringFlags, Cmd)
+
+	Cmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		fmt.Fprintf(os.Stderr, "cut: %v\n", err)
+		return exit.Status(1)
+	})
+}
+
+// cutter selects parts of lines according to the flags.
+type cutter struct {
+	list     positions
+	mode     byte
+	delim    byte
+	outDelim []byte
+	lineEnd  byte
+}
+
+// newCutter builds the cutter from the flags. hasOutDelim tells whether
+// --output-delimiter was given.
+func newCutter(hasOutDelim bool) (*cutter, error) {
+	c := &cutter{lineEnd: '\n'}
+	if pFlags.zero {
+		c.lineEnd = 0
+	}
+
+	lists := 0
+	arg := ""
+	for _, l := range []struct {
+		mode byte
+		arg  string
+	}{{'b', pFlags.bytes}, {'c', pFlags.chars}, {'f', pFlags.fields}} {
+		if l.arg != "" {
+			lists++
+			c.mode, arg = l.mode, l.arg
+		}
+	}
+	switch {
+	case lists == 0:
+		return nil, errors.New("you must specify a list of bytes, characters, or fields")
+	case lists > 1:
+		return nil, errors.New("only one list may be specified")
+	}
+
+	if len(pFlags.delimiter) != 1 {
+		return nil, errors.New("the delimiter must be a single character")
+	}
+	c.delim = pFlags.delimiter[0]
+
+	list, err := parseList(arg, pFlags.complement)
+	if err != nil {
+		return nil, err
+	}
+	c.list = list
+
+	switch {
+	case hasOutDelim:
+		c.outDelim = []byte(pFlags.outDelimiter)
+	case c.mode == 'f':
+		c.outDelim = []byte{c.delim}
+	}
+
+	return c, nil
+}
+
+// executeCut executes the cut command with given arguments and returns its
+// exit status. An input that cannot be read is reported and the others are
+// still cut.
+func (c *cutter) executeCut(args []string) int {
+	out := bufio.NewWriter(os.Stdout)
+	defer out.Flush()
+
+	status := 0
+	for _, name := range args {
+		if err := c.cutFile(out, name); err != nil {
+			out.Flush()
+			status = exit.Fail("cut", err)
+		}
+	}
+	return status
+}
+
+// cutFile prints the selected parts of each line of the named input.
+func (c *cutter) cutFile(w *bufio.Writer, name string) error {
+	f, err := fileinput.Open(name)
+	if err != nil {
+		return err
+	}
+	defer fileinput.Close(f)
+
+	lr := lineio.NewReaderDelim(f, c.lineEnd)
+	for {
+		line, err := lr.ReadLine()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if out, ok := c.cutLine(lr.TrimDelim(line)); ok {
+			w.Write(out)
+			w.WriteByte(c.lineEnd)
+		}
+	}
+}
+
+// cutLine returns the selected part of line and whether it is printed.
+func (c *cutter) cutLine(line []byte) ([]byte, bool) {
+	switch c.mode {
+	case 'b':
+		return c.list.cutBytes(line, c.outDelim), true
+	case 'c':
+		return c.list.cutChars(line, c.outDelim), true
+	}
+
+	out, delimited := c.list.cutFields(line, c.delim, c.outDelim)
+	return out, delimited || !pFlags.onlyDelimited
+}
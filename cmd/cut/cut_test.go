@@ -0,0 +1,115 @@
+package cut
+
+import (
+	"testing"
+
+	"github.com/skraio/unix-utilities/internal/assert"
+)
+
+func TestCutLine(t *testing.T) {
+	tests := []struct {
+		name       string
+		list       string
+		complement bool
+		mode       byte
+		outDelim   string
+		line       string
+		want       string
+	}{
+		{
+			name: "Byte ranges",
+			list: "1-3,6-",
+			mode: 'b',
+			line: "Without just",
+			want: "Witut just",
+		},
+		{
+			name:     "Byte ranges with output delimiter",
+			list:     "1-2,5",
+			mode:     'b',
+			outDelim: ":",
+			line:     "Without",
+			want:     "Wi:o",
+		},
+		{
+			name: "Multibyte characters",
+			list: "2-3",
+			mode: 'c',
+			line: "żółw",
+			want: "ół",
+		},
+		{
+			name:     "Fields",
+			list:     "3,1",
+			mode:     'f',
+			outDelim: ",",
+			line:     "bird,call,world,home",
+			want:     "bird,world",
+		},
+		{
+			name:       "Complement fields",
+			list:       "2-3",
+			complement: true,
+			mode:       'f',
+			outDelim:   ",",
+			line:       "bird,call,world,home",
+			want:       "bird,home",
+		},
+		{
+			name:     "Line without delimiter",
+			list:     "2",
+			mode:     'f',
+			outDelim: ",",
+			line:     "nest",
+			want:     "nest",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			list, err := parseList(tt.list, tt.complement)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			c := &cutter{list: list, mode: tt.mode, delim: ',', outDelim: []byte(tt.outDelim)}
+			ans, _ := c.cutLine([]byte(tt.line))
+
+			assert.Equal(t, string(ans), tt.want)
+		})
+	}
+}
+
+func TestParseListErrors(t *testing.T) {
+	for _, list := range []string{"", "0", "3-1", "a"} {
+		t.Run(list, func(t *testing.T) {
+			if _, err := parseList(list, false); err == nil {
+				t.Errorf("parseList(%q) succeeded; want error", list)
+			}
+		})
+	}
+}
+
+func TestNewCutterLists(t *testing.T) {
+	tests := []struct {
+		name string
+		f    cutFlags
+		want string
+	}{
+		{name: "None", f: cutFlags{delimiter: "\t"}, want: "you must specify a list of bytes, characters, or fields"},
+		{name: "Two", f: cutFlags{fields: "1", bytes: "1", delimiter: "\t"}, want: "only one list may be specified"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pFlags = tt.f
+			defer func() { pFlags = cutFlags{} }()
+
+			_, err := newCutter(false)
+			if err == nil {
+				t.Fatal("newCutter succeeded; want error")
+			}
+			assert.Equal(t, err.Error(), tt.want)
+		})
+	}
+}
@@ -0,0 +1,181 @@
+package cut
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// span is an inclusive range of 1-based positions.
+type span struct {
+	lo, hi int
+}
+
+// positions is a sorted list of non-overlapping spans selected by a LIST
+// argument.
+type positions []span
+
+// parseList parses a LIST of the form N, N-, N-M or -M separated by commas.
+// With complement, the positions not listed are selected instead.
+func parseList(list string, complement bool) (positions, error) {
+	if list == "" {
+		return nil, errors.New("missing list of positions")
+	}
+
+	spans := positions{}
+	for _, item := range strings.Split(list, ",") {
+		lo, hi, isRange := strings.Cut(item, "-")
+
+		s := span{lo: 1, hi: math.MaxInt}
+		var err error
+		if lo != "" {
+			if s.lo, err = strconv.Atoi(lo); err != nil || s.lo < 1 {
+				return nil, fmt.Errorf("invalid byte, character or field list %q", list)
+			}
+		}
+		switch {
+		case !isRange:
+			s.hi = s.lo
+		case hi != "":
+			if s.hi, err = strconv.Atoi(hi); err != nil || s.hi < 1 {
+				return nil, fmt.Errorf("invalid byte, character or field list %q", list)
+			}
+		case lo == "":
+			return nil, fmt.Errorf("invalid range with no endpoint: -")
+		}
+		if s.lo > s.hi {
+			return nil, fmt.Errorf("invalid decreasing range %q", item)
+		}
+		spans = append(spans, s)
+	}
+
+	slices.SortFunc(spans, func(a, b span) int { return a.lo - b.lo })
+
+	merged := positions{spans[0]}
+	for _, s := range spans[1:] {
+		last := &merged[len(merged)-1]
+		if s.lo <= last.hi {
+			last.hi = max(last.hi, s.hi)
+			continue
+		}
+		merged = append(merged, s)
+	}
+
+	if complement {
+		return merged.complement(), nil
+	}
+	return merged, nil
+}
+
+// complement returns the spans of the positions not in p.
+func (p positions) complement() positions {
+	result := positions{}
+	next := 1
+	for _, s := range p {
+		if s.lo > next {
+			result = append(result, span{next, s.lo - 1})
+		}
+		if s.hi == math.MaxInt {
+			return result
+		}
+		next = s.hi + 1
+	}
+	return append(result, span{next, math.MaxInt})
+}
+
+// cutBytes returns the selected bytes of line, writing outDelim between
+// non-adjacent spans when it is not nil.
+func (p positions) cutBytes(line []byte, outDelim []byte) []byte {
+	result := []byte{}
+	for i, s := range p {
+		if s.lo > len(line) {
+			break
+		}
+		if i > 0 && outDelim != nil {
+			result = append(result, outDelim...)
+		}
+		result = append(result, line[s.lo-1:min(s.hi, len(line))]...)
+	}
+	return result
+}
+
+// cutChars returns the selected UTF-8 characters of line, writing outDelim
+// between non-adjacent spans when it is not nil.
+func (p positions) cutChars(line []byte, outDelim []byte) []byte {
+	result := []byte{}
+	pos, offset := 1, 0
+	for i, s := range p {
+		for pos < s.lo && offset < len(line) {
+			_, size := utf8.DecodeRune(line[offset:])
+			offset += size
+			pos++
+		}
+		if offset >= len(line) {
+			break
+		}
+
+		start := offset
+		for pos <= s.hi && offset < len(line) {
+			_, size := utf8.DecodeRune(line[offset:])
+			offset += size
+			pos++
+		}
+
+		if i > 0 && outDelim != nil {
+			result = append(result, outDelim...)
+		}
+		result = append(result, line[start:offset]...)
+	}
+	return result
+}
+
+// cutFields returns the selected fields of line joined by outDelim. It
+// reports false when line contains no delimiter.
+func (p positions) cutFields(line []byte, delim byte, outDelim []byte) ([]byte, bool) {
+	if bytes.IndexByte(line, delim) < 0 {
+		return line, false
+	}
+
+	result := []byte{}
+	first := true
+	field, start := 1, 0
+	for start <= len(line) {
+		end := bytes.IndexByte(line[start:], delim)
+		if end < 0 {
+			end = len(line)
+		} else {
+			end += start
+		}
+
+		if p.contains(field) {
+			if !first {
+				result = append(result, outDelim...)
+			}
+			result = append(result, line[start:end]...)
+			first = false
+		}
+
+		field++
+		start = end + 1
+	}
+
+	return result, true
+}
+
+// contains reports whether position n is selected.
+func (p positions) contains(n int) bool {
+	for _, s := range p {
+		if n < s.lo {
+			return false
+		}
+		if n <= s.hi {
+			return true
+		}
+	}
+	return false
+}
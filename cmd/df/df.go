@@ -0,0 +1,243 @@
+// Package df provides functionality for reporting file system disk space
+// usage.
+package df
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/skraio/unix-utilities/cmdflags"
+	"github.com/skraio/unix-utilities/internal/exit"
+	"github.com/skraio/unix-utilities/internal/mountinfo"
+	"github.com/skraio/unix-utilities/internal/units"
+	"github.com/spf13/cobra"
+)
+
+// dfFlags holds flags for df command.
+type dfFlags struct {
+	all          bool
+	human        bool
+	si           bool
+	inodes       bool
+	printType    bool
+	json         bool
+	output       string
+	types        []string
+	excludeTypes []string
+}
+
+var pFlags dfFlags
+
+// flags definition for df command.
+var flags = []cmdflags.Flag{
+	{Value: &pFlags.all, Name: "all", ShortHand: "a", DefaultValue: false, Description: "include pseudo, duplicate, inaccessible file systems"},
+	{Value: &pFlags.human, Name: "human-readable", ShortHand: "h", DefaultValue: false, Description: "print sizes in powers of 1024 (e.g., 1023M)"},
+	{Value: &pFlags.si, Name: "si", ShortHand: "H", DefaultValue: false, Description: "print sizes in powers of 1000 (e.g., 1.1G)"},
+	{Value: &pFlags.inodes, Name: "inodes", ShortHand: "i", DefaultValue: false, Description: "list inode information instead of block usage"},
+	{Value: &pFlags.printType, Name: "print-type", ShortHand: "T", DefaultValue: false, Description: "print file system type"},
+	{Value: &pFlags.json, Name: "json", ShortHand: "", DefaultValue: false, Description: "print the selected fields as JSON"},
+}
+
+// stringFlags definition for df command.
+var stringFlags = []cmdflags.StringFlag{
+	{Value: &pFlags.output, Name: "output", ShortHand: "", DefaultValue: "", Description: "use the output format defined by FIELD_LIST, or print all fields if FIELD_LIST is omitted"},
+}
+
+// Cmd represents the 'df' command configuration using Cobra.
+var Cmd = &cobra.Command{
+	Use:           "df [-f flags] [file]...",
+	Short:         "Report file system disk space usage",
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return exit.Status(executeDf(args, cmd.Flags().Changed("output")))
+	},
+}
+
+// init initializes the 'df' command by setting up flags.
+func init() {
+	cmdflags.ParseFlags(flags, Cmd)
+	cmdflags.ParseStringFlags(stringFlags, Cmd)
+	Cmd.Flags().Lookup("output").NoOptDefVal = strings.Join(columnNames(), ",")
+	Cmd.Flags().StringArrayVarP(&pFlags.types, "type", "t", nil, "limit listing to file systems of type TYPE")
+	Cmd.Flags().StringArrayVarP(&pFlags.excludeTypes, "exclude-type", "x", nil, "limit listing to file systems not of type TYPE")
+	Cmd.PersistentFlags().BoolP("help", "", false, "help for this command")
+
+	Cmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		fmt.Fprintf(os.Stderr, "df: %v\n", err)
+		return exit.Status(1)
+	})
+}
+
+// columnNames returns the names of all --output fields.
+func columnNames() []string {
+	names := []string{}
+	for _, c := range columns {
+		names = append(names, c.name)
+	}
+	return names
+}
+
+// executeDf executes the df command with given arguments and returns its
+// exit status.
+func executeDf(args []string, hasOutput bool) int {
+	t, err := newTable(hasOutput)
+	if err != nil {
+		return exit.Fail("df", err)
+	}
+
+	mounts, err := mountinfo.Read()
+	if err != nil {
+		return exit.Fail("df", err)
+	}
+
+	status := 0
+	rows := []*row{}
+	if len(args) == 0 {
+		rows = mountRows(mounts)
+	} else {
+		for _, arg := range args {
+			r, err := fileRow(mounts, arg)
+			if err != nil {
+				status = exit.Fail("df", err)
+				continue
+			}
+			rows = append(rows, r)
+		}
+	}
+
+	rows = slices.DeleteFunc(rows, func(r *row) bool { return !typeSelected(r.mount.FSType) })
+	if len(rows) == 0 {
+		if status != 0 {
+			return status
+		}
+		return exit.Fail("df", errors.New("no file systems processed"))
+	}
+
+	if pFlags.json {
+		err = t.writeJSON(os.Stdout, rows)
+	} else {
+		err = t.writeText(os.Stdout, rows)
+	}
+	if err != nil {
+		return exit.Fail("df", err)
+	}
+	return status
+}
+
+// newTable builds the table from the flags.
+func newTable(hasOutput bool) (*table, error) {
+	t := &table{columns: defaultColumns(pFlags.printType, pFlags.inodes)}
+	if hasOutput {
+		if pFlags.inodes || pFlags.printType {
+			return nil, errors.New("options --output and -i or -T are mutually exclusive")
+		}
+		selected, err := parseOutput(pFlags.output)
+		if err != nil {
+			return nil, err
+		}
+		t.columns = selected
+	}
+
+	switch {
+	case pFlags.si:
+		t.format = &units.Format{Base: 1000, RoundUp: true}
+	case pFlags.human:
+		t.format = &units.Format{RoundUp: true}
+	}
+	return t, nil
+}
+
+// typeSelected reports whether the -t and -x options select a file system
+// type.
+func typeSelected(fstype string) bool {
+	if slices.Contains(pFlags.excludeTypes, fstype) {
+		return false
+	}
+	return len(pFlags.types) == 0 || slices.Contains(pFlags.types, fstype)
+}
+
+// mountRows returns a row for every mounted file system. Unless -a is
+// given, file systems without any blocks, mounts hidden by a later mount on
+// the same mount point and all but the mount with the shortest mount point
+// of each device are left out.
+func mountRows(mounts []*mountinfo.Mount) []*row {
+	rows := []*row{}
+	byDev := map[string]*row{}
+	byTarget := map[string]*row{}
+	for _, m := range mounts {
+		u, err := statfs(m.Target)
+		if err != nil {
+			if pFlags.all {
+				exit.Fail("df", err)
+			}
+			continue
+		}
+
+		r := &row{mount: m, usage: u}
+		if pFlags.all {
+			rows = append(rows, r)
+			continue
+		}
+		if u.size == 0 || !typeSelected(m.FSType) {
+			continue
+		}
+		if prev, ok := byTarget[m.Target]; ok {
+			*prev = *r
+			byDev[m.Dev] = prev
+			continue
+		}
+		if prev, ok := byDev[m.Dev]; ok {
+			if len(m.Target) < len(prev.mount.Target) {
+				delete(byTarget, prev.mount.Target)
+				*prev = *r
+				byTarget[m.Target] = prev
+			}
+			continue
+		}
+		byDev[m.Dev] = r
+		byTarget[m.Target] = r
+		rows = append(rows, r)
+	}
+	return rows
+}
+
+// fileRow returns the row of the file system containing the named file,
+// that is, the mount with the longest mount point containing it.
+func fileRow(mounts []*mountinfo.Mount, name string) (*row, error) {
+	if _, err := os.Stat(name); err != nil {
+		return nil, err
+	}
+	path, err := filepath.Abs(name)
+	if err == nil {
+		path, err = filepath.EvalSymlinks(path)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var found *mountinfo.Mount
+	for _, m := range mounts {
+		if within(path, m.Target) && (found == nil || len(m.Target) >= len(found.Target)) {
+			found = m
+		}
+	}
+	if found == nil {
+		return nil, fmt.Errorf("%s: cannot find mount point", name)
+	}
+
+	u, err := statfs(name)
+	if err != nil {
+		return nil, err
+	}
+	return &row{mount: found, file: name, usage: u}, nil
+}
+
+// within reports whether path is dir or below it.
+func within(path, dir string) bool {
+	return dir == "/" || path == dir || strings.HasPrefix(path, dir+"/")
+}
@@ -0,0 +1,99 @@
+package df
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/skraio/unix-utilities/internal/assert"
+	"github.com/skraio/unix-utilities/internal/mountinfo"
+	"github.com/skraio/unix-utilities/internal/units"
+)
+
+func TestTable(t *testing.T) {
+	rows := []*row{
+		{
+			mount: &mountinfo.Mount{Target: "/", FSType: "ext4", Source: "/dev/vda1"},
+			usage: usage{size: 10 << 30, used: 3 << 30, avail: 7<<30 - 1, inodes: 1000, iused: 250, iavail: 750},
+		},
+		{
+			mount: &mountinfo.Mount{Target: "/proc", FSType: "proc", Source: "proc"},
+			file:  "/proc/self",
+		},
+	}
+
+	tests := []struct {
+		name string
+		t    table
+		json bool
+		want string
+	}{
+		{
+			name: "Default columns",
+			t:    table{columns: defaultColumns(false, false)},
+			want: "" +
+				"Filesystem 1K-blocks    Used Available Use% Mounted on\n" +
+				"/dev/vda1   10485760 3145728   7340032  31% /\n" +
+				"proc               0       0         0    - /proc\n",
+		},
+		{
+			name: "Human readable with types",
+			t:    table{columns: defaultColumns(true, false), format: &units.Format{RoundUp: true}},
+			want: "" +
+				"Filesystem Type Size Used Avail Use% Mounted on\n" +
+				"/dev/vda1  ext4  10G 3.0G  7.0G  31% /\n" +
+				"proc       proc    0    0     0    - /proc\n",
+		},
+		{
+			name: "Inodes",
+			t:    table{columns: defaultColumns(false, true)},
+			want: "" +
+				"Filesystem Inodes IUsed IFree IUse% Mounted on\n" +
+				"/dev/vda1    1000   250   750   25% /\n" +
+				"proc            0     0     0     - /proc\n",
+		},
+		{
+			name: "JSON",
+			t:    table{columns: mustParseOutput(t, "source,avail,pcent,file")},
+			json: true,
+			want: "[\n" +
+				`  {"source": "/dev/vda1", "avail": 7516192767, "pcent": 31, "file": null},` + "\n" +
+				`  {"source": "proc", "avail": 0, "pcent": null, "file": "/proc/self"}` + "\n" +
+				"]\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			var err error
+			if tt.json {
+				err = tt.t.writeJSON(&out, rows)
+			} else {
+				err = tt.t.writeText(&out, rows)
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assert.Equal(t, out.String(), tt.want)
+		})
+	}
+}
+
+// mustParseOutput parses an --output list, failing the test on errors.
+func mustParseOutput(t *testing.T, list string) []column {
+	t.Helper()
+
+	columns, err := parseOutput(list)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return columns
+}
+
+func TestTypeOfOperands(t *testing.T) {
+	pFlags = dfFlags{types: []string{"no-such-type"}}
+	defer func() { pFlags = dfFlags{} }()
+
+	assert.Equal(t, executeDf([]string{"."}, false), 1)
+}
@@ -0,0 +1,301 @@
+package df
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/skraio/unix-utilities/internal/fileinfo"
+	"github.com/skraio/unix-utilities/internal/mountinfo"
+	"github.com/skraio/unix-utilities/internal/units"
+)
+
+// usage holds the space, in bytes, and inode counts of a file system.
+type usage struct {
+	size   int64
+	used   int64
+	avail  int64
+	inodes int64
+	iused  int64
+	iavail int64
+}
+
+// statfs returns the usage of the file system containing path.
+func statfs(path string) (usage, error) {
+	st, err := fileinfo.Statfs(path)
+	if err != nil {
+		return usage{}, err
+	}
+
+	bsize := st.FragSize
+	return usage{
+		size:   int64(st.Blocks) * bsize,
+		used:   int64(st.Blocks-st.BlocksFree) * bsize,
+		avail:  int64(st.BlocksAvail) * bsize,
+		inodes: int64(st.Files),
+		iused:  int64(st.Files - st.FilesFree),
+		iavail: int64(st.FilesFree),
+	}, nil
+}
+
+// row is a line of output: a file system and the operand it was found for.
+type row struct {
+	mount *mountinfo.Mount
+	file  string
+	usage usage
+}
+
+// column is a field that can be selected with --output.
+type column struct {
+	name    string
+	header  string
+	numeric bool
+}
+
+// columns lists the fields of --output in their default order.
+var columns = []column{
+	{name: "source", header: "Filesystem"},
+	{name: "fstype", header: "Type"},
+	{name: "itotal", header: "Inodes", numeric: true},
+	{name: "iused", header: "IUsed", numeric: true},
+	{name: "iavail", header: "IFree", numeric: true},
+	{name: "ipcent", header: "IUse%", numeric: true},
+	{name: "size", header: "1K-blocks", numeric: true},
+	{name: "used", header: "Used", numeric: true},
+	{name: "avail", header: "Avail", numeric: true},
+	{name: "pcent", header: "Use%", numeric: true},
+	{name: "file", header: "File"},
+	{name: "target", header: "Mounted on"},
+}
+
+// parseOutput returns the columns named in a --output list.
+func parseOutput(list string) ([]column, error) {
+	if list == "" {
+		return columns, nil
+	}
+
+	selected := []column{}
+	for _, name := range strings.Split(list, ",") {
+		found := false
+		for _, c := range columns {
+			if c.name == name {
+				selected = append(selected, c)
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("'%s': not a valid field for --output", name)
+		}
+	}
+	return selected, nil
+}
+
+// defaultColumns returns the columns shown without --output.
+func defaultColumns(showType, inodes bool) []column {
+	names := []string{"source", "size", "used", "avail", "pcent", "target"}
+	if inodes {
+		names = []string{"source", "itotal", "iused", "iavail", "ipcent", "target"}
+	}
+	if showType {
+		names = append(names[:1], append([]string{"fstype"}, names[1:]...)...)
+	}
+
+	selected, _ := parseOutput(strings.Join(names, ","))
+	return selected
+}
+
+// percent returns the used share of used and avail as a percentage rounded
+// up, or -1 when both are zero.
+func percent(used, avail int64) int64 {
+	total := used + avail
+	if total == 0 {
+		return -1
+	}
+	return (used*100 + total - 1) / total
+}
+
+// table formats rows as aligned text or JSON.
+type table struct {
+	columns []column
+	format  *units.Format
+}
+
+// value returns the text of a column for a row.
+func (t *table) value(c column, r *row) string {
+	size := func(n int64) string {
+		if t.format != nil {
+			return t.format.Size(n)
+		}
+		return strconv.FormatInt((n+1023)/1024, 10)
+	}
+	count := func(n int64) string {
+		if t.format != nil {
+			return t.format.Size(n)
+		}
+		return strconv.FormatInt(n, 10)
+	}
+	pcent := func(used, avail int64) string {
+		if p := percent(used, avail); p >= 0 {
+			return strconv.FormatInt(p, 10) + "%"
+		}
+		return "-"
+	}
+
+	u := r.usage
+	switch c.name {
+	case "source":
+		return r.mount.Source
+	case "fstype":
+		return r.mount.FSType
+	case "itotal":
+		return count(u.inodes)
+	case "iused":
+		return count(u.iused)
+	case "iavail":
+		return count(u.iavail)
+	case "ipcent":
+		return pcent(u.iused, u.iavail)
+	case "size":
+		return size(u.size)
+	case "used":
+		return size(u.used)
+	case "avail":
+		return size(u.avail)
+	case "pcent":
+		return pcent(u.used, u.avail)
+	case "file":
+		if r.file == "" {
+			return "-"
+		}
+		return r.file
+	}
+	return r.mount.Target
+}
+
+// header returns the heading of a column.
+func (t *table) header(c column) string {
+	switch {
+	case c.name == "size" && t.format != nil:
+		return "Size"
+	case c.name == "avail" && t.format == nil:
+		return "Available"
+	}
+	return c.header
+}
+
+// writeText writes the rows as a table, aligning text columns to the left
+// and numbers to the right.
+func (t *table) writeText(w io.Writer, rows []*row) error {
+	cells := [][]string{{}}
+	for _, c := range t.columns {
+		cells[0] = append(cells[0], t.header(c))
+	}
+	for _, r := range rows {
+		line := []string{}
+		for _, c := range t.columns {
+			line = append(line, t.value(c, r))
+		}
+		cells = append(cells, line)
+	}
+
+	widths := make([]int, len(t.columns))
+	for _, line := range cells {
+		for i, cell := range line {
+			widths[i] = max(widths[i], len(cell))
+		}
+	}
+
+	for _, line := range cells {
+		var b strings.Builder
+		for i, cell := range line {
+			if i > 0 {
+				b.WriteByte(' ')
+			}
+			pad := strings.Repeat(" ", widths[i]-len(cell))
+			switch {
+			case t.columns[i].numeric:
+				b.WriteString(pad + cell)
+			case i == len(line)-1:
+				b.WriteString(cell)
+			default:
+				b.WriteString(cell + pad)
+			}
+		}
+		b.WriteByte('\n')
+		if _, err := io.WriteString(w, b.String()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// jsonValue returns the value of a column for JSON output, where sizes are
+// in bytes and percentages are numbers, or null when unknown.
+func (t *table) jsonValue(c column, r *row) any {
+	u := r.usage
+	switch c.name {
+	case "itotal":
+		return u.inodes
+	case "iused":
+		return u.iused
+	case "iavail":
+		return u.iavail
+	case "size":
+		return u.size
+	case "used":
+		return u.used
+	case "avail":
+		return u.avail
+	case "ipcent", "pcent":
+		p := percent(u.used, u.avail)
+		if c.name == "ipcent" {
+			p = percent(u.iused, u.iavail)
+		}
+		if p < 0 {
+			return nil
+		}
+		return p
+	case "file":
+		if r.file == "" {
+			return nil
+		}
+	}
+	return t.value(c, r)
+}
+
+// writeJSON writes the rows as a JSON array of objects whose keys are the
+// column names, in column order.
+func (t *table) writeJSON(w io.Writer, rows []*row) error {
+	var b strings.Builder
+	b.WriteString("[")
+	for i, r := range rows {
+		if i > 0 {
+			b.WriteString(",")
+		}
+		b.WriteString("\n  {")
+		for j, c := range t.columns {
+			if j > 0 {
+				b.WriteString(", ")
+			}
+			key, _ := json.Marshal(c.name)
+			value, err := json.Marshal(t.jsonValue(c, r))
+			if err != nil {
+				return err
+			}
+			b.Write(key)
+			b.WriteString(": ")
+			b.Write(value)
+		}
+		b.WriteString("}")
+	}
+	if len(rows) > 0 {
+		b.WriteString("\n")
+	}
+	b.WriteString("]\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
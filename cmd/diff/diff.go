@@ -0,0 +1,428 @@
+// Package diff provides functionality for comparing files line by line, in
+// the normal, unified, context or side-by-side formats, and for comparing
+// directories.
+package diff
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/skraio/unix-utilities/cmdflags"
+	"github.com/skraio/unix-utilities/internal/exit"
+	"github.com/skraio/unix-utilities/internal/fileinput"
+	"github.com/spf13/cobra"
+)
+
+// diffFlags holds flags for diff command.
+type diffFlags struct {
+	unified           bool
+	context           bool
+	sideBySide        bool
+	suppressCommon    bool
+	expandTabs        bool
+	recursive         bool
+	newFile           bool
+	brief             bool
+	reportIdentical   bool
+	text              bool
+	ignoreCase        bool
+	ignoreAllSpace    bool
+	ignoreSpaceChange bool
+	ignoreBlankLines  bool
+	unifiedLines      string
+	contextLines      string
+	width             string
+	color             string
+}
+
+var pFlags diffFlags
+
+// flags definition for diff command. The options -u and -c, which have no
+// long names of their own, are named by their letter.
+var flags = []cmdflags.Flag{
+	{Value: &pFlags.unified, Name: "u", ShortHand: "u", DefaultValue: false, Description: "output 3 lines of unified context"},
+	{Value: &pFlags.context, Name: "c", ShortHand: "c", DefaultValue: false, Description: "output 3 lines of copied context"},
+	{Value: &pFlags.sideBySide, Name: "side-by-side", ShortHand: "y", DefaultValue: false, Description: "output in two columns"},
+	{Value: &pFlags.suppressCommon, Name: "suppress-common-lines", ShortHand: "", DefaultValue: false, Description: "do not output common lines in two columns"},
+	{Value: &pFlags.expandTabs, Name: "expand-tabs", ShortHand: "t", DefaultValue: false, Description: "expand tabs to spaces in output"},
+	{Value: &pFlags.recursive, Name: "recursive", ShortHand: "r", DefaultValue: false, Description: "recursively compare any subdirectories found"},
+	{Value: &pFlags.newFile, Name: "new-file", ShortHand: "N", DefaultValue: false, Description: "treat absent files as empty"},
+	{Value: &pFlags.brief, Name: "brief", ShortHand: "q", DefaultValue: false, Description: "report only when files differ"},
+	{Value: &pFlags.reportIdentical, Name: "report-identical-files", ShortHand: "s", DefaultValue: false, Description: "report when two files are the same"},
+	{Value: &pFlags.text, Name: "text", ShortHand: "a", DefaultValue: false, Description: "treat all files as text"},
+	{Value: &pFlags.ignoreCase, Name: "ignore-case", ShortHand: "i", DefaultValue: false, Description: "ignore case differences in file contents"},
+	{Value: &pFlags.ignoreAllSpace, Name: "ignore-all-space", ShortHand: "w", DefaultValue: false, Description: "ignore all white space"},
+	{Value: &pFlags.ignoreSpaceChange, Name: "ignore-space-change", ShortHand: "b", DefaultValue: false, Description: "ignore changes in the amount of white space"},
+	{Value: &pFlags.ignoreBlankLines, Name: "ignore-blank-lines", ShortHand: "B", DefaultValue: false, Description: "ignore changes where lines are all blank"},
+}
+
+// stringFlags definition for diff command.
+var stringFlags = []cmdflags.StringFlag{
+	{Value: &pFlags.unifiedLines, Name: "unified", ShortHand: "U", DefaultValue: "", Description: "output NUM lines of unified context"},
+	{Value: &pFlags.contextLines, Name: "context", ShortHand: "C", DefaultValue: "", Description: "output NUM lines of copied context"},
+	{Value: &pFlags.width, Name: "width", ShortHand: "W", DefaultValue: "130", Description: "output at most NUM print columns"},
+	{Value: &pFlags.color, Name: "color", ShortHand: "", DefaultValue: "never", Description: "color output; WHEN is 'never', 'always', or 'auto'"},
+}
+
+// Cmd represents the 'diff' command configuration using Cobra. Flag parsing
+// is done in RunE so that the options can be repeated in the lines
+// introducing the files compared within directories.
+var Cmd = &cobra.Command{
+	Use:                "diff [-f flags] file1 file2",
+	Short:              "Compare files line by line",
+	DisableFlagParsing: true,
+	SilenceErrors:      true,
+	SilenceUsage:       true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := cmd.Flags().Parse(args); err != nil {
+			fmt.Fprintf(os.Stderr, "diff: %v\n", err)
+			return exit.Status(2)
+		}
+		if help, _ := cmd.Flags().GetBool("help"); help {
+			return cmd.Help()
+		}
+		operands := cmd.Flags().Args()
+		switches := args[:len(args)-len(operands)]
+		if n := len(switches); n > 0 && switches[n-1] == "--" {
+			switches = switches[:n-1]
+		}
+		return exit.Status(executeDiff(switches, operands))
+	},
+}
+
+// init initializes the 'diff' command by setting up flags.
+func init() {
+	cmdflags.ParseFlags(flags, Cmd)
+	cmdflags.ParseStringFlags(stringFlags, Cmd)
+	Cmd.Flags().Lookup("color").NoOptDefVal = "auto"
+}
+
+// outputFormat is a way of showing the differences between files.
+type outputFormat int
+
+const (
+	normalFormat outputFormat = iota
+	unifiedFormat
+	contextFormat
+	sideBySideFormat
+)
+
+// options holds the settings of a comparison resolved from the flags.
+type options struct {
+	format  outputFormat
+	context int
+
+	// halfWidth is the width of the columns of the side-by-side format,
+	// and column2 where the second one starts.
+	halfWidth, column2 int
+	expandTabs         bool
+	suppressCommon     bool
+
+	recursive, newFile, brief, reportIdentical, text bool
+
+	ignoreCase, ignoreAllSpace, ignoreSpaceChange, ignoreBlankLines bool
+
+	color bool
+
+	// switches are the options as given, repeated when comparing files
+	// found in directories.
+	switches string
+}
+
+// newOptions resolves the flags into options.
+func newOptions(switches []string) (*options, error) {
+	o := &options{
+		context:           3,
+		expandTabs:        pFlags.expandTabs,
+		suppressCommon:    pFlags.suppressCommon,
+		recursive:         pFlags.recursive,
+		newFile:           pFlags.newFile,
+		brief:             pFlags.brief,
+		reportIdentical:   pFlags.reportIdentical,
+		text:              pFlags.text,
+		ignoreCase:        pFlags.ignoreCase,
+		ignoreAllSpace:    pFlags.ignoreAllSpace,
+		ignoreSpaceChange: pFlags.ignoreSpaceChange,
+		ignoreBlankLines:  pFlags.ignoreBlankLines,
+	}
+	for _, s := range switches {
+		o.switches += " " + shellQuote(s)
+	}
+
+	formats := map[outputFormat]bool{
+		unifiedFormat:    pFlags.unified || pFlags.unifiedLines != "",
+		contextFormat:    pFlags.context || pFlags.contextLines != "",
+		sideBySideFormat: pFlags.sideBySide,
+	}
+	for format, given := range formats {
+		if !given {
+			continue
+		}
+		if o.format != normalFormat {
+			return nil, errors.New("conflicting output style options")
+		}
+		o.format = format
+	}
+
+	for _, lines := range []string{pFlags.unifiedLines, pFlags.contextLines} {
+		if lines == "" {
+			continue
+		}
+		n, err := strconv.Atoi(lines)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid context length '%s'", lines)
+		}
+		o.context = n
+	}
+
+	width, err := strconv.Atoi(pFlags.width)
+	if err != nil || width <= 0 {
+		return nil, fmt.Errorf("invalid width '%s'", pFlags.width)
+	}
+	o.setWidth(width)
+
+	if o.color, err = useColor(pFlags.color); err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+// setWidth lays out the columns of the side-by-side format in a line of at
+// most width columns, starting the second column at a tab stop.
+func (o *options) setWidth(width int) {
+	tab := tabSize
+	if o.expandTabs {
+		tab = 1
+	}
+	offset := (width + tab + 3) / (2 * tab) * tab
+	o.halfWidth = max(0, min(offset-3, width-offset))
+	o.column2 = width
+	if o.halfWidth > 0 {
+		o.column2 = offset
+	}
+}
+
+// ignoring reports whether some differences between lines are ignored, so
+// that files that are not the same may still compare equal.
+func (o *options) ignoring() bool {
+	return o.ignoreCase || o.ignoreAllSpace || o.ignoreSpaceChange || o.ignoreBlankLines
+}
+
+// useColor resolves the --color argument.
+func useColor(when string) (bool, error) {
+	switch when {
+	case "always", "yes", "force":
+		return true, nil
+	case "never", "no", "none":
+		return false, nil
+	case "auto", "tty", "if-tty":
+		fi, err := os.Stdout.Stat()
+		return err == nil && fi.Mode()&os.ModeCharDevice != 0 && os.Getenv("TERM") != "dumb", nil
+	}
+	return false, fmt.Errorf("invalid color '%s'", when)
+}
+
+// shellQuote quotes an argument for the shell when it has characters that
+// would need it.
+func shellQuote(arg string) string {
+	if arg != "" && strings.Trim(arg, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789%+,-./:@_^") == "" {
+		return arg
+	}
+	return "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+}
+
+// executeDiff executes the diff command with given arguments and returns
+// its exit status: 0 when the files are the same, 1 when they differ and 2
+// on trouble.
+func executeDiff(switches, args []string) int {
+	o, err := newOptions(switches)
+	if err != nil {
+		return fail(err)
+	}
+	switch {
+	case len(args) == 0:
+		return fail(errors.New("missing operand after 'diff'"))
+	case len(args) == 1:
+		return fail(fmt.Errorf("missing operand after '%s'", args[0]))
+	case len(args) > 2:
+		return fail(fmt.Errorf("extra operand '%s'", args[2]))
+	}
+
+	c := &comparison{o: o, w: bufio.NewWriter(os.Stdout)}
+	status := c.compareOperands(args[0], args[1])
+	if err := c.w.Flush(); err != nil {
+		return fail(err)
+	}
+	return status
+}
+
+// comparison compares files and directories, writing the differences
+// found.
+type comparison struct {
+	o *options
+	w *bufio.Writer
+}
+
+// compareOperands compares the files named on the command line. A file
+// compared with a directory is compared with the file of the same name in
+// it.
+func (c *comparison) compareOperands(a, b string) int {
+	dirA, err := c.isDir(a)
+	if err != nil {
+		return c.warn(err)
+	}
+	dirB, err := c.isDir(b)
+	if err != nil {
+		return c.warn(err)
+	}
+
+	switch {
+	case dirA && dirB:
+		return c.compareDirs(a, b)
+	case dirA:
+		if b == fileinput.Stdin {
+			return c.warn(errors.New("cannot compare '-' to a directory"))
+		}
+		a = join(a, filepath.Base(b))
+	case dirB:
+		if a == fileinput.Stdin {
+			return c.warn(errors.New("cannot compare '-' to a directory"))
+		}
+		b = join(b, filepath.Base(a))
+	}
+	return c.compareFiles(a, b, false)
+}
+
+// isDir reports whether the named operand is a directory. A missing file
+// is not one when absent files are taken as empty.
+func (c *comparison) isDir(name string) (bool, error) {
+	if name == fileinput.Stdin {
+		return false, nil
+	}
+	fi, err := os.Stat(name)
+	if errors.Is(err, fs.ErrNotExist) && c.o.newFile {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return fi.IsDir(), nil
+}
+
+// open reads the named file, which is taken as empty when missing and
+// absent files are allowed.
+func (c *comparison) open(name string) (*file, error) {
+	f, err := readFile(name)
+	if errors.Is(err, fs.ErrNotExist) && c.o.newFile {
+		return missingFile(name), nil
+	}
+	return f, err
+}
+
+// compareFiles compares two files and writes their differences. Files
+// found in directories are introduced by a line naming them.
+func (c *comparison) compareFiles(nameA, nameB string, nested bool) int {
+	a, err := c.open(nameA)
+	if err != nil {
+		return c.warn(err)
+	}
+	b, err := c.open(nameB)
+	if err != nil {
+		return c.warn(err)
+	}
+
+	var changes []change
+	same := bytes.Equal(a.data, b.data)
+	switch {
+	case same:
+	case !c.o.text && (a.binary() || b.binary()):
+		fmt.Fprintf(c.w, "Binary files %s and %s differ\n", a.name, b.name)
+		return 1
+	case c.o.brief && !c.o.ignoring():
+	default:
+		changes = diffLines(a, b, c.o)
+		same = true
+		for _, ch := range changes {
+			same = same && ch.ignored
+		}
+	}
+
+	switch {
+	case same:
+		if c.o.reportIdentical {
+			fmt.Fprintf(c.w, "Files %s and %s are identical\n", a.name, b.name)
+		}
+		return 0
+	case c.o.brief:
+		fmt.Fprintf(c.w, "Files %s and %s differ\n", a.name, b.name)
+		return 1
+	}
+
+	if nested {
+		fmt.Fprintf(c.w, "diff%s %s %s\n", c.o.switches, a.name, b.name)
+	}
+	p := &printer{w: c.w, o: c.o, a: a, b: b}
+	p.print(changes)
+	return 1
+}
+
+// diffLines returns the changes turning the lines of a into those of b.
+// With -B, changes made only of blank lines are marked as ignored.
+func diffLines(a, b *file, o *options) []change {
+	cl := newClassifier(o)
+	// Identical lines at both ends are not compared but for those next to
+	// the others, as far as context is shown, which lines that changed
+	// may move to.
+	horizon := 0
+	if o.format == unifiedFormat || o.format == contextFormat {
+		horizon = o.context
+	}
+	prefix, suffix := identicalEnds(a, b)
+	prefix, suffix = max(prefix-horizon, 0), max(suffix-horizon, 0)
+	changedA, changedB := changes(cl.classify(a), cl.classify(b), prefix, suffix)
+	script := editScript(changedA, changedB)
+	if !o.ignoreBlankLines {
+		return script
+	}
+
+	blank := func(lines [][]byte) bool {
+		for _, line := range lines {
+			if strings.TrimSuffix(cl.key(line), "\n") != "" {
+				return false
+			}
+		}
+		return true
+	}
+	for i, ch := range script {
+		script[i].ignored = blank(a.lines[ch.a0:ch.a1]) && blank(b.lines[ch.b0:ch.b1])
+	}
+	return script
+}
+
+// join returns the name of an entry of a directory.
+func join(dir, name string) string {
+	if strings.HasSuffix(dir, "/") {
+		return dir + name
+	}
+	return dir + "/" + name
+}
+
+// warn writes an error after the output written so far and returns the
+// exit status of trouble.
+func (c *comparison) warn(err error) int {
+	c.w.Flush()
+	return fail(err)
+}
+
+// fail prints an error and returns the exit status of trouble.
+func fail(err error) int {
+	exit.Fail("diff", err)
+	return 2
+}
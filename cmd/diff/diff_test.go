@@ -0,0 +1,167 @@
+package diff
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/skraio/unix-utilities/internal/assert"
+)
+
+func TestChanges(t *testing.T) {
+	tests := []struct {
+		a, b           string
+		wantA, wantB   string
+		prefix, suffix int
+	}{
+		{a: "abc", b: "abc", wantA: "...", wantB: "..."},
+		{a: "abc", b: "", wantA: "---", wantB: ""},
+		{a: "abcabba", b: "cbabac", wantA: "--.-...", wantB: "..+..+"},
+		{a: "xaay", b: "xay", wantA: "..-.", wantB: "...", prefix: 1, suffix: 1},
+		{a: "abab", b: "ab", wantA: "..--", wantB: ".."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+"/"+tt.b, func(t *testing.T) {
+			changedA, changedB := changes(classes(tt.a), classes(tt.b), tt.prefix, tt.suffix)
+			assert.Equal(t, marks(changedA, '-'), tt.wantA)
+			assert.Equal(t, marks(changedB, '+'), tt.wantB)
+		})
+	}
+}
+
+// classes returns the letters of s as classes of lines.
+func classes(s string) []int {
+	lines := make([]int, len(s))
+	for i := range s {
+		lines[i] = int(s[i])
+	}
+	return lines
+}
+
+// marks shows changed lines with a mark and others with a dot.
+func marks(changed []bool, mark byte) string {
+	b := make([]byte, len(changed))
+	for i, c := range changed {
+		b[i] = '.'
+		if c {
+			b[i] = mark
+		}
+	}
+	return string(b)
+}
+
+func TestPrint(t *testing.T) {
+	modTime := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	a := newFile("ta", modTime, []byte("a\nb\nc\nd\ne\nf\ng\nh\n"))
+	b := newFile("tb", modTime, []byte("a\nB\nc\nd\ne\nf\ng\nh\ni"))
+
+	tests := []struct {
+		name string
+		o    options
+		want string
+	}{
+		{
+			name: "Normal",
+			o:    options{format: normalFormat},
+			want: "2c2\n< b\n---\n> B\n8a9\n> i\n\\ No newline at end of file\n",
+		},
+		{
+			name: "Unified",
+			o:    options{format: unifiedFormat, context: 1},
+			want: "--- ta\t2024-01-02 03:04:05.000000000 +0000\n+++ tb\t2024-01-02 03:04:05.000000000 +0000\n" +
+				"@@ -1,3 +1,3 @@\n a\n-b\n+B\n c\n@@ -8 +8,2 @@\n h\n+i\n\\ No newline at end of file\n",
+		},
+		{
+			name: "Context",
+			o:    options{format: contextFormat, context: 1},
+			want: "*** ta\tTue Jan  2 03:04:05 2024\n--- tb\tTue Jan  2 03:04:05 2024\n" +
+				"***************\n*** 1,3 ****\n  a\n! b\n  c\n--- 1,3 ----\n  a\n! B\n  c\n" +
+				"***************\n*** 8 ****\n--- 8,9 ----\n  h\n+ i\n\\ No newline at end of file\n",
+		},
+		{
+			name: "Side by side",
+			o:    options{format: sideBySideFormat, suppressCommon: true},
+			want: "b\t      |\tB\n\t      >\ti",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.o.setWidth(30)
+			var out strings.Builder
+			p := &printer{w: bufio.NewWriter(&out), o: &tt.o, a: a, b: b}
+			p.print(diffLines(a, b, &tt.o))
+			p.w.Flush()
+			assert.Equal(t, out.String(), tt.want)
+		})
+	}
+}
+
+func TestIgnoring(t *testing.T) {
+	a := newFile("a", time.Time{}, []byte("A  b\n\nc\n"))
+	b := newFile("b", time.Time{}, []byte("a b \nc\n"))
+
+	tests := []struct {
+		name    string
+		o       options
+		changes int
+	}{
+		{name: "Nothing ignored", o: options{}, changes: 1},
+		{name: "Case and space change", o: options{ignoreCase: true, ignoreSpaceChange: true}, changes: 1},
+		{name: "Blank lines", o: options{ignoreCase: true, ignoreAllSpace: true, ignoreBlankLines: true}, changes: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changes := 0
+			for _, c := range diffLines(a, b, &tt.o) {
+				if !c.ignored {
+					changes++
+				}
+			}
+			assert.Equal(t, changes, tt.changes)
+		})
+	}
+}
+
+func TestSetWidth(t *testing.T) {
+	tests := []struct {
+		width, halfWidth, column2 int
+		expandTabs                bool
+	}{
+		{width: 130, halfWidth: 61, column2: 64},
+		{width: 30, halfWidth: 13, column2: 16},
+		{width: 30, halfWidth: 13, column2: 17, expandTabs: true},
+		{width: 3, halfWidth: 0, column2: 3},
+	}
+
+	for _, tt := range tests {
+		o := &options{expandTabs: tt.expandTabs}
+		o.setWidth(tt.width)
+		assert.Equal(t, o.halfWidth, tt.halfWidth)
+		assert.Equal(t, o.column2, tt.column2)
+	}
+}
+
+func TestKey(t *testing.T) {
+	tests := []struct {
+		name string
+		c    classifier
+		a, b string
+		same bool
+	}{
+		{name: "Case only", c: classifier{ignoreCase: true}, a: "Ab\n", b: "a b\n", same: false},
+		{name: "Case", c: classifier{ignoreCase: true}, a: "A b\n", b: "a B\n", same: true},
+		{name: "Space change", c: classifier{ignoreSpaceChange: true}, a: "a  b \n", b: "a b\n", same: true},
+		{name: "Space change kept", c: classifier{ignoreSpaceChange: true}, a: "ab\n", b: "a b\n", same: false},
+		{name: "All space", c: classifier{ignoreAllSpace: true}, a: "ab\n", b: " a b\n", same: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.c.key([]byte(tt.a)) == tt.c.key([]byte(tt.b)), tt.same)
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package diff
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sort"
+)
+
+// compareDirs compares the entries of two directories, in the order of
+// their names. A directory missing with -N is taken as empty.
+func (c *comparison) compareDirs(a, b string) int {
+	namesA, err := c.readNames(a)
+	if err != nil {
+		return c.warn(err)
+	}
+	namesB, err := c.readNames(b)
+	if err != nil {
+		return c.warn(err)
+	}
+
+	names := make([]string, 0, len(namesA)+len(namesB))
+	for name := range namesA {
+		names = append(names, name)
+	}
+	for name := range namesB {
+		if !namesA[name] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	status := 0
+	for _, name := range names {
+		var s int
+		switch {
+		case !namesB[name] && !c.o.newFile:
+			s = c.onlyIn(a, name)
+		case !namesA[name] && !c.o.newFile:
+			s = c.onlyIn(b, name)
+		default:
+			s = c.compareEntries(a, b, name, namesA[name], namesB[name])
+		}
+		status = max(status, s)
+	}
+	return status
+}
+
+// readNames returns the set of the names of the entries of a directory.
+func (c *comparison) readNames(dir string) (map[string]bool, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) && c.o.newFile {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[string]bool, len(entries))
+	for _, e := range entries {
+		names[e.Name()] = true
+	}
+	return names, nil
+}
+
+// onlyIn reports an entry found in one directory only.
+func (c *comparison) onlyIn(dir, name string) int {
+	fmt.Fprintf(c.w, "Only in %s: %s\n", dir, name)
+	return 1
+}
+
+// compareEntries compares the entries of the same name of two directories,
+// either of which may be missing with -N. Subdirectories are only compared
+// when comparing recursively.
+func (c *comparison) compareEntries(parentA, parentB, name string, inA, inB bool) int {
+	a, b := join(parentA, name), join(parentB, name)
+	fiA, err := stat(a, inA)
+	if err != nil {
+		return c.warn(err)
+	}
+	fiB, err := stat(b, inB)
+	if err != nil {
+		return c.warn(err)
+	}
+
+	dirA := fiA != nil && fiA.IsDir()
+	dirB := fiB != nil && fiB.IsDir()
+	switch {
+	case fiA == nil && fiB == nil:
+		return 0
+	case (dirA || fiA == nil) && (dirB || fiB == nil):
+		if c.o.recursive {
+			return c.compareDirs(a, b)
+		}
+		fmt.Fprintf(c.w, "Common subdirectories: %s and %s\n", a, b)
+		return 0
+	case fiA == nil || fiB == nil || fiA.Mode().IsRegular() && fiB.Mode().IsRegular():
+		return c.compareFiles(a, b, true)
+	}
+	fmt.Fprintf(c.w, "File %s is a %s while file %s is a %s\n", a, fileType(fiA), b, fileType(fiB))
+	return 1
+}
+
+// stat returns the information of an entry of a directory, or nil when the
+// directory has no such entry.
+func stat(name string, present bool) (fs.FileInfo, error) {
+	if !present {
+		return nil, nil
+	}
+	return os.Stat(name)
+}
+
+// fileType returns the description of the type of a file used in
+// messages.
+func fileType(fi fs.FileInfo) string {
+	mode := fi.Mode()
+	switch {
+	case mode.IsRegular() && fi.Size() == 0:
+		return "regular empty file"
+	case mode.IsRegular():
+		return "regular file"
+	case mode.IsDir():
+		return "directory"
+	case mode&fs.ModeSymlink != 0:
+		return "symbolic link"
+	case mode&fs.ModeNamedPipe != 0:
+		return "fifo"
+	case mode&fs.ModeSocket != 0:
+		return "socket"
+	case mode&fs.ModeCharDevice != 0:
+		return "character special file"
+	case mode&fs.ModeDevice != 0:
+		return "block special file"
+	}
+	return "weird file"
+}
@@ -0,0 +1,245 @@
+package diff
+
+import (
+	"bufio"
+	"fmt"
+	"strings"
+)
+
+// Terminal color codes, matching the GNU diff defaults.
+const (
+	headerColor = "\033[1m"
+	hunkColor   = "\033[36m"
+	deleteColor = "\033[31m"
+	addColor    = "\033[32m"
+	resetColor  = "\033[0m"
+)
+
+// Time layouts of the file headers of the unified and context formats.
+const (
+	unifiedTimeLayout = "2006-01-02 15:04:05.000000000 -0700"
+	contextTimeLayout = "Mon Jan _2 15:04:05 2006"
+)
+
+// noNewline marks a last line not ending with a newline.
+const noNewline = "\\ No newline at end of file\n"
+
+// printer writes the differences between two files in one of the output
+// formats.
+type printer struct {
+	w    *bufio.Writer
+	o    *options
+	a, b *file
+}
+
+// colorize writes s, which may end with a newline, in a color.
+func (p *printer) colorize(color, s string) {
+	if !p.o.color || color == "" {
+		p.w.WriteString(s)
+		return
+	}
+	text, newline := strings.CutSuffix(s, "\n")
+	p.w.WriteString(color + text + resetColor)
+	if newline {
+		p.w.WriteByte('\n')
+	}
+}
+
+// line writes line i of f after a prefix, followed by the marker of a
+// missing newline where needed.
+func (p *printer) line(color, prefix string, f *file, i int) {
+	line := string(f.lines[i])
+	if f.missingNewline(i) {
+		p.colorize(color, prefix+line+"\n")
+		p.w.WriteString(noNewline)
+		return
+	}
+	p.colorize(color, prefix+line)
+}
+
+// print writes the hunks of changes in the format selected.
+func (p *printer) print(changes []change) {
+	switch p.o.format {
+	case unifiedFormat:
+		p.header("---", "+++", unifiedTimeLayout)
+		for _, h := range hunks(changes, p.o.context) {
+			p.unifiedHunk(h)
+		}
+	case contextFormat:
+		p.header("***", "---", contextTimeLayout)
+		for _, h := range hunks(changes, p.o.context) {
+			p.contextHunk(h)
+		}
+	case sideBySideFormat:
+		p.sideBySide(changes)
+	default:
+		for _, h := range hunks(changes, 0) {
+			p.normalHunk(h[0])
+		}
+	}
+}
+
+// header writes the names and modification times of the files.
+func (p *printer) header(markA, markB, layout string) {
+	p.colorize(headerColor, fmt.Sprintf("%s %s\t%s\n", markA, p.a.name, p.a.modTime.Format(layout)))
+	p.colorize(headerColor, fmt.Sprintf("%s %s\t%s\n", markB, p.b.name, p.b.modTime.Format(layout)))
+}
+
+// normalHunk writes a change as an ed-like command followed by the lines
+// deleted and inserted.
+func (p *printer) normalHunk(c change) {
+	var command string
+	switch {
+	case c.a0 == c.a1:
+		command = fmt.Sprintf("%da%s", c.a0, normalRange(c.b0, c.b1))
+	case c.b0 == c.b1:
+		command = fmt.Sprintf("%sd%d", normalRange(c.a0, c.a1), c.b0)
+	default:
+		command = fmt.Sprintf("%sc%s", normalRange(c.a0, c.a1), normalRange(c.b0, c.b1))
+	}
+	p.colorize(hunkColor, command+"\n")
+
+	for i := c.a0; i < c.a1; i++ {
+		p.line(deleteColor, "< ", p.a, i)
+	}
+	if c.a0 < c.a1 && c.b0 < c.b1 {
+		p.w.WriteString("---\n")
+	}
+	for i := c.b0; i < c.b1; i++ {
+		p.line(addColor, "> ", p.b, i)
+	}
+}
+
+// normalRange returns the line numbers of lines [low, high) as shown by the
+// normal format.
+func normalRange(low, high int) string {
+	if high-low == 1 {
+		return fmt.Sprint(high)
+	}
+	return fmt.Sprintf("%d,%d", low+1, high)
+}
+
+// bounds returns the lines of both files a hunk shows, with context lines
+// around its changes.
+func (p *printer) bounds(h []change) (aLow, aHigh, bLow, bHigh int) {
+	first, last := h[0], h[len(h)-1]
+	before := min(first.a0, first.b0, p.o.context)
+	after := min(len(p.a.lines)-last.a1, len(p.b.lines)-last.b1, p.o.context)
+	return first.a0 - before, last.a1 + after, first.b0 - before, last.b1 + after
+}
+
+// unifiedHunk writes a hunk in the unified format.
+func (p *printer) unifiedHunk(h []change) {
+	aLow, aHigh, bLow, bHigh := p.bounds(h)
+	p.colorize(hunkColor, fmt.Sprintf("@@ -%s +%s @@\n", unifiedRange(aLow, aHigh), unifiedRange(bLow, bHigh)))
+
+	i := aLow
+	for _, c := range h {
+		for ; i < c.a0; i++ {
+			p.line("", " ", p.a, i)
+		}
+		for ; i < c.a1; i++ {
+			p.line(deleteColor, "-", p.a, i)
+		}
+		for j := c.b0; j < c.b1; j++ {
+			p.line(addColor, "+", p.b, j)
+		}
+	}
+	for ; i < aHigh; i++ {
+		p.line("", " ", p.a, i)
+	}
+}
+
+// unifiedRange returns the line numbers of lines [low, high) as shown by
+// the unified format.
+func unifiedRange(low, high int) string {
+	switch high - low {
+	case 0:
+		return fmt.Sprintf("%d,0", low)
+	case 1:
+		return fmt.Sprint(high)
+	}
+	return fmt.Sprintf("%d,%d", low+1, high-low)
+}
+
+// contextHunk writes a hunk in the context format. The lines of either
+// file are left out when none of them changed.
+func (p *printer) contextHunk(h []change) {
+	aLow, aHigh, bLow, bHigh := p.bounds(h)
+	deleted, inserted := false, false
+	for _, c := range h {
+		deleted = deleted || c.a0 < c.a1
+		inserted = inserted || c.b0 < c.b1
+	}
+
+	p.w.WriteString("***************\n")
+	p.colorize(hunkColor, fmt.Sprintf("*** %s ****\n", contextRange(aLow, aHigh)))
+	if deleted {
+		p.contextLines(h, p.a, aLow, aHigh, deleteColor, "- ", func(c change) (int, int) { return c.a0, c.a1 })
+	}
+	p.colorize(hunkColor, fmt.Sprintf("--- %s ----\n", contextRange(bLow, bHigh)))
+	if inserted {
+		p.contextLines(h, p.b, bLow, bHigh, addColor, "+ ", func(c change) (int, int) { return c.b0, c.b1 })
+	}
+}
+
+// contextLines writes the lines [low, high) of one of the files of a hunk
+// in the context format, span giving the lines of a change in the file.
+// Changes to both files are marked with '!'.
+func (p *printer) contextLines(h []change, f *file, low, high int, color, mark string, span func(change) (int, int)) {
+	i := low
+	for _, c := range h {
+		start, end := span(c)
+		for ; i < start; i++ {
+			p.line(color, "  ", f, i)
+		}
+		prefix := mark
+		if c.a0 < c.a1 && c.b0 < c.b1 {
+			prefix = "! "
+		}
+		for ; i < end; i++ {
+			p.line(color, prefix, f, i)
+		}
+	}
+	for ; i < high; i++ {
+		p.line(color, "  ", f, i)
+	}
+}
+
+// contextRange returns the line numbers of lines [low, high) as shown by
+// the context format.
+func contextRange(low, high int) string {
+	if high <= low+1 {
+		return fmt.Sprint(high)
+	}
+	return fmt.Sprintf("%d,%d", low+1, high)
+}
+
+// hunks groups changes into hunks, changes separated by at most twice the
+// number of context lines being shown together. Hunks made only of changes
+// ignored are left out.
+func hunks(changes []change, context int) [][]change {
+	var groups [][]change
+	for i := 0; i < len(changes); {
+		j := i + 1
+		for j < len(changes) {
+			// An ignored change only joins when within the context of the
+			// previous one.
+			gap := changes[j].a0 - changes[j-1].a1
+			if gap > 2*context || changes[j].ignored && gap >= context {
+				break
+			}
+			j++
+		}
+
+		group := changes[i:j]
+		for _, c := range group {
+			if !c.ignored {
+				groups = append(groups, group)
+				break
+			}
+		}
+		i = j
+	}
+	return groups
+}
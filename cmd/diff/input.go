@@ -0,0 +1,173 @@
+package diff
+
+import (
+	"bytes"
+	"io"
+	"io/fs"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/skraio/unix-utilities/internal/fileinput"
+)
+
+// binaryPeekSize is how much of the start of a file is looked at to tell
+// whether it holds text.
+const binaryPeekSize = 32 * 1024
+
+// file is a file being compared, split into lines.
+type file struct {
+	// name is the name shown in headers and messages.
+	name string
+
+	// modTime is the modification time shown in headers. It is the epoch
+	// for a missing file taken as empty.
+	modTime time.Time
+
+	data []byte
+
+	// lines hold the lines of the file, each with its newline but the last
+	// one when the file does not end with a newline.
+	lines [][]byte
+}
+
+// readFile reads the named file, "-" being the standard input.
+func readFile(name string) (*file, error) {
+	f, err := fileinput.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer fileinput.Close(f)
+
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return nil, &fs.PathError{Op: "read", Path: name, Err: err}
+	}
+	modTime := fi.ModTime()
+	if name == fileinput.Stdin {
+		modTime = time.Now()
+	}
+	return newFile(name, modTime, data), nil
+}
+
+// missingFile returns an empty file standing for one that does not exist.
+func missingFile(name string) *file {
+	return newFile(name, time.Unix(0, 0), nil)
+}
+
+// newFile returns a file holding data.
+func newFile(name string, modTime time.Time, data []byte) *file {
+	f := &file{name: name, modTime: modTime, data: data}
+	for len(data) > 0 {
+		end := bytes.IndexByte(data, '\n') + 1
+		if end == 0 {
+			end = len(data)
+		}
+		f.lines = append(f.lines, data[:end])
+		data = data[end:]
+	}
+	return f
+}
+
+// binary reports whether the file seems to hold binary data.
+func (f *file) binary() bool {
+	return bytes.IndexByte(f.data[:min(len(f.data), binaryPeekSize)], 0) >= 0
+}
+
+// missingNewline reports whether line i is the last one and does not end
+// with a newline.
+func (f *file) missingNewline(i int) bool {
+	return i == len(f.lines)-1 && !bytes.HasSuffix(f.lines[i], []byte{'\n'})
+}
+
+// identicalEnds returns the numbers of lines the same at the start and at
+// the end of both files.
+func identicalEnds(a, b *file) (int, int) {
+	prefix := 0
+	for prefix < len(a.lines) && prefix < len(b.lines) && bytes.Equal(a.lines[prefix], b.lines[prefix]) {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a.lines)-prefix && suffix < len(b.lines)-prefix &&
+		bytes.Equal(a.lines[len(a.lines)-1-suffix], b.lines[len(b.lines)-1-suffix]) {
+		suffix++
+	}
+	return prefix, suffix
+}
+
+// classifier numbers the lines of the files compared so that lines which
+// are the same, as far as the options ignoring differences go, get the same
+// number.
+type classifier struct {
+	ignoreCase, ignoreAllSpace, ignoreSpaceChange bool
+
+	classes map[string]int
+}
+
+// newClassifier returns a classifier for the comparison options.
+func newClassifier(o *options) *classifier {
+	return &classifier{
+		ignoreCase:        o.ignoreCase,
+		ignoreAllSpace:    o.ignoreAllSpace,
+		ignoreSpaceChange: o.ignoreSpaceChange,
+		classes:           make(map[string]int),
+	}
+}
+
+// classify returns the class numbers of the lines of f.
+func (c *classifier) classify(f *file) []int {
+	classes := make([]int, len(f.lines))
+	for i, line := range f.lines {
+		key := c.key(line)
+		if f.missingNewline(i) {
+			// A last line lacking its newline differs from the same line
+			// with one, unless white space is ignored.
+			if !c.ignoreAllSpace && !c.ignoreSpaceChange {
+				key += "\x00"
+			}
+		} else {
+			key = strings.TrimSuffix(key, "\n")
+		}
+		class, ok := c.classes[key]
+		if !ok {
+			class = len(c.classes)
+			c.classes[key] = class
+		}
+		classes[i] = class
+	}
+	return classes
+}
+
+// key returns the form of a line that is compared.
+func (c *classifier) key(line []byte) string {
+	if !c.ignoreCase && !c.ignoreAllSpace && !c.ignoreSpaceChange {
+		return string(line)
+	}
+
+	var b strings.Builder
+	s := string(line)
+	if c.ignoreSpaceChange {
+		s = strings.TrimRightFunc(s, unicode.IsSpace)
+	}
+	// Spaces are only dropped, or collapsed into one, when ignored.
+	space := false
+	for _, r := range s {
+		if unicode.IsSpace(r) && r != '\n' && (c.ignoreAllSpace || c.ignoreSpaceChange) {
+			space = true
+			continue
+		}
+		if space && c.ignoreSpaceChange && !c.ignoreAllSpace {
+			b.WriteByte(' ')
+		}
+		space = false
+		if c.ignoreCase {
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,420 @@
+package diff
+
+import "math"
+
+// differ finds the lines of two sequences that are not part of a longest
+// common subsequence, with the algorithm of Eugene W. Myers in linear space.
+type differ struct {
+	a, b []int
+
+	// changedA and changedB mark the changed lines, shifted by one so that
+	// the entries before the first line and after the last are false.
+	changedA, changedB []bool
+
+	// forward and backward hold the furthest reaching paths of each
+	// diagonal x-y searching from both ends, stored at offset+x-y.
+	forward, backward []int
+	offset            int
+}
+
+// changes returns which lines of a and b, given as numbers of equivalence
+// classes, are deleted from a and inserted in b. The prefix and suffix
+// lines at both ends, known to be the same, are left aside, and lines
+// unlikely to match any line of the other file are taken as changed before
+// searching for the common lines among the rest.
+func changes(a, b []int, prefix, suffix int) ([]bool, []bool) {
+	changedA := make([]bool, len(a)+2)
+	changedB := make([]bool, len(b)+2)
+
+	midA, midB := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+
+	discardsA, discardsB := discardConfusingLines(midA, midB)
+	keptA, indexA := undiscarded(midA, discardsA, changedA[1+prefix:])
+	keptB, indexB := undiscarded(midB, discardsB, changedB[1+prefix:])
+
+	d := &differ{
+		a:        keptA,
+		b:        keptB,
+		changedA: make([]bool, len(keptA)+2),
+		changedB: make([]bool, len(keptB)+2),
+		forward:  make([]int, len(keptA)+len(keptB)+3),
+		backward: make([]int, len(keptA)+len(keptB)+3),
+		offset:   len(keptB) + 1,
+	}
+	d.compare(0, len(keptA), 0, len(keptB))
+	for i, changed := range d.changedA[1 : len(keptA)+1] {
+		changedA[1+prefix+indexA[i]] = changed
+	}
+	for i, changed := range d.changedB[1 : len(keptB)+1] {
+		changedB[1+prefix+indexB[i]] = changed
+	}
+
+	regionA := changedA[prefix : len(a)-suffix+2]
+	regionB := changedB[prefix : len(b)-suffix+2]
+	shiftBoundaries(regionA, regionB, midA)
+	shiftBoundaries(regionB, regionA, midB)
+	return changedA[1 : len(a)+1], changedB[1 : len(b)+1]
+}
+
+// undiscarded returns the lines not discarded and their indexes, marking
+// the others as changed.
+func undiscarded(lines []int, discards []byte, changed []bool) ([]int, []int) {
+	var kept, indexes []int
+	for i, line := range lines {
+		if discards[i] != 0 {
+			changed[i] = true
+			continue
+		}
+		kept = append(kept, line)
+		indexes = append(indexes, i)
+	}
+	return kept, indexes
+}
+
+// discardConfusingLines marks the lines of each file that match no line of
+// the other with 1, and those matching many lines with 2. The latter are
+// only kept marked within runs of lines with the former at both ends.
+func discardConfusingLines(a, b []int) ([]byte, []byte) {
+	countA, countB := map[int]int{}, map[int]int{}
+	for _, class := range a {
+		countA[class]++
+	}
+	for _, class := range b {
+		countB[class]++
+	}
+	discardsA := markDiscards(a, countB)
+	discardsB := markDiscards(b, countA)
+	cancelProvisional(discardsA)
+	cancelProvisional(discardsB)
+	return discardsA, discardsB
+}
+
+// markDiscards marks the lines matching no line of the other file, counts
+// giving how many lines of each class it has, or more lines than about the
+// square root of the number of lines.
+func markDiscards(lines []int, counts map[int]int) []byte {
+	many := 5
+	for n := len(lines) / 64 >> 2; n > 0; n >>= 2 {
+		many *= 2
+	}
+	discards := make([]byte, len(lines))
+	for i, class := range lines {
+		switch n := counts[class]; {
+		case n == 0:
+			discards[i] = 1
+		case n > many:
+			discards[i] = 2
+		}
+	}
+	return discards
+}
+
+// cancelProvisional unmarks the lines matching many lines of the other file
+// but in the middle of runs of lines matching none, long stretches of them
+// being unmarked too.
+func cancelProvisional(discards []byte) {
+	for i := 0; i < len(discards); i++ {
+		if discards[i] == 2 {
+			discards[i] = 0
+			continue
+		}
+		if discards[i] == 0 {
+			continue
+		}
+
+		// Find the end of the run, not counting provisional lines at it.
+		j, provisional := i, 0
+		for ; j < len(discards) && discards[j] != 0; j++ {
+			if discards[j] == 2 {
+				provisional++
+			}
+		}
+		for j > i && discards[j-1] == 2 {
+			j--
+			discards[j] = 0
+			provisional--
+		}
+		length := j - i
+
+		if provisional*4 > length {
+			for ; j > i; j-- {
+				if discards[j-1] == 2 {
+					discards[j-1] = 0
+				}
+			}
+			continue
+		}
+
+		// Cancel the runs of provisional lines of at least about the
+		// square root of a quarter of the length.
+		minimum := 1
+		for n := length >> 4; n > 0; n >>= 2 {
+			minimum <<= 1
+		}
+		minimum++
+		consecutive := 0
+		for j := 0; j < length; j++ {
+			switch {
+			case discards[i+j] != 2:
+				consecutive = 0
+			case consecutive+1 == minimum:
+				consecutive++
+				j -= consecutive
+			case consecutive+1 > minimum:
+				consecutive++
+				discards[i+j] = 0
+			default:
+				consecutive++
+			}
+		}
+
+		// Cancel provisional lines from both ends until three lines
+		// matching nothing in a row, or one at least eight lines in.
+		cancelEnd(discards, length, func(j int) int { return i + j })
+		i += length - 1
+		last := i
+		cancelEnd(discards, length, func(j int) int { return last - j })
+	}
+}
+
+// cancelEnd cancels provisional lines from an end of a run of discardable
+// lines, at returning the index of the jth line from it.
+func cancelEnd(discards []byte, length int, at func(j int) int) {
+	consecutive := 0
+	for j := 0; j < length; j++ {
+		k := at(j)
+		if j >= 8 && discards[k] == 1 {
+			return
+		}
+		switch discards[k] {
+		case 2:
+			consecutive = 0
+			discards[k] = 0
+		case 0:
+			consecutive = 0
+		default:
+			consecutive++
+		}
+		if consecutive == 3 {
+			return
+		}
+	}
+}
+
+// compare marks the changed lines of a[aLow:aHigh] and b[bLow:bHigh].
+func (d *differ) compare(aLow, aHigh, bLow, bHigh int) {
+	for aLow < aHigh && bLow < bHigh && d.a[aLow] == d.b[bLow] {
+		aLow++
+		bLow++
+	}
+	for aLow < aHigh && bLow < bHigh && d.a[aHigh-1] == d.b[bHigh-1] {
+		aHigh--
+		bHigh--
+	}
+
+	switch {
+	case aLow == aHigh:
+		for i := bLow; i < bHigh; i++ {
+			d.changedB[i+1] = true
+		}
+	case bLow == bHigh:
+		for i := aLow; i < aHigh; i++ {
+			d.changedA[i+1] = true
+		}
+	default:
+		x, y := d.split(aLow, aHigh, bLow, bHigh)
+		d.compare(aLow, x, bLow, y)
+		d.compare(x, aHigh, y, bHigh)
+	}
+}
+
+// split returns the point where the paths of fewest edits searched from
+// both ends of a[aLow:aHigh] and b[bLow:bHigh] first meet, about halfway
+// through a shortest edit script.
+func (d *differ) split(aLow, aHigh, bLow, bHigh int) (int, int) {
+	// Diagonals are numbered x-y, those valid lying within minK and maxK.
+	minK, maxK := aLow-bHigh, aHigh-bLow
+	forwardMid, backwardMid := aLow-bLow, aHigh-bHigh
+	fMin, fMax := forwardMid, forwardMid
+	bMin, bMax := backwardMid, backwardMid
+	odd := (forwardMid-backwardMid)&1 != 0
+
+	f := func(k int) *int { return &d.forward[d.offset+k] }
+	b := func(k int) *int { return &d.backward[d.offset+k] }
+	*f(forwardMid) = aLow
+	*b(backwardMid) = aHigh
+
+	for {
+		// Extend the forward search by an edit on each diagonal, the
+		// diagonals just outside the range searched holding sentinels.
+		if fMin > minK {
+			fMin--
+			*f(fMin - 1) = -1
+		} else {
+			fMin++
+		}
+		if fMax < maxK {
+			fMax++
+			*f(fMax + 1) = -1
+		} else {
+			fMax--
+		}
+		for k := fMax; k >= fMin; k -= 2 {
+			low, high := *f(k - 1), *f(k + 1)
+			x := low + 1
+			if low < high {
+				x = high
+			}
+			y := x - k
+			for x < aHigh && y < bHigh && d.a[x] == d.b[y] {
+				x++
+				y++
+			}
+			*f(k) = x
+			if odd && bMin <= k && k <= bMax && *b(k) <= x {
+				return x, y
+			}
+		}
+
+		// Extend the backward search likewise.
+		if bMin > minK {
+			bMin--
+			*b(bMin - 1) = math.MaxInt
+		} else {
+			bMin++
+		}
+		if bMax < maxK {
+			bMax++
+			*b(bMax + 1) = math.MaxInt
+		} else {
+			bMax--
+		}
+		for k := bMax; k >= bMin; k -= 2 {
+			low, high := *b(k - 1), *b(k + 1)
+			x := high - 1
+			if low < high {
+				x = low
+			}
+			y := x - k
+			for aLow < x && bLow < y && d.a[x-1] == d.b[y-1] {
+				x--
+				y--
+			}
+			*b(k) = x
+			if !odd && fMin <= k && k <= fMax && x <= *f(k) {
+				return x, y
+			}
+		}
+	}
+}
+
+// shiftBoundaries moves each run of changed lines of a file, where equal
+// lines allow it, as far down as possible, or up to where it lines up with
+// a run of changes of the other file. Runs brought together are merged.
+// Both slices have a false entry before the first line and after the last.
+func shiftBoundaries(changed, other []bool, equivs []int) {
+	end := len(changed) - 1
+	equiv := func(i int) int { return equivs[i-1] }
+	i, j := 1, 1
+	for {
+		// Find the start of the next run, keeping j at the matching line
+		// of the other file.
+		for i < end && !changed[i] {
+			for other[j] {
+				j++
+			}
+			j++
+			i++
+		}
+		if i == end {
+			return
+		}
+
+		start := i
+		for changed[i] {
+			i++
+		}
+		for other[j] {
+			j++
+		}
+
+		var corresponding int
+		for {
+			length := i - start
+
+			for start > 1 && equiv(start-1) == equiv(i-1) {
+				start--
+				changed[start] = true
+				i--
+				changed[i] = false
+				for changed[start-1] {
+					start--
+				}
+				for j--; other[j]; j-- {
+				}
+			}
+
+			corresponding = end
+			if other[j-1] {
+				corresponding = i
+			}
+
+			for i != end && equiv(start) == equiv(i) {
+				changed[start] = false
+				start++
+				changed[i] = true
+				i++
+				for changed[i] {
+					i++
+				}
+				for j++; other[j]; j++ {
+					corresponding = i
+				}
+			}
+
+			if length == i-start {
+				break
+			}
+		}
+
+		for corresponding < i {
+			start--
+			changed[start] = true
+			i--
+			changed[i] = false
+			for j--; other[j]; j-- {
+			}
+		}
+	}
+}
+
+// change is a run of lines a[a0:a1] replaced by b[b0:b1].
+type change struct {
+	a0, a1, b0, b1 int
+
+	// ignored marks a change that does not count as a difference.
+	ignored bool
+}
+
+// editScript returns the runs of changed lines, in order.
+func editScript(changedA, changedB []bool) []change {
+	var script []change
+	i, j := 0, 0
+	for i < len(changedA) || j < len(changedB) {
+		if i < len(changedA) && changedA[i] || j < len(changedB) && changedB[j] {
+			c := change{a0: i, b0: j}
+			for i < len(changedA) && changedA[i] {
+				i++
+			}
+			for j < len(changedB) && changedB[j] {
+				j++
+			}
+			c.a1, c.b1 = i, j
+			script = append(script, c)
+			continue
+		}
+		i++
+		j++
+	}
+	return script
+}
@@ -0,0 +1,159 @@
+package diff
+
+import "strings"
+
+// tabSize is the distance between tab stops.
+const tabSize = 8
+
+// sideBySide writes the files in two columns, marking the lines that
+// differ.
+func (p *printer) sideBySide(changes []change) {
+	i, j := 0, 0
+	common := func(aEnd int) {
+		for ; i < aEnd; i, j = i+1, j+1 {
+			if !p.o.suppressCommon {
+				p.sideLine(p.a.lines[i], ' ', p.b.lines[j])
+			}
+		}
+	}
+
+	for _, c := range changes {
+		common(c.a0)
+		sep, left, right := '|', byte('<'), byte('>')
+		if c.ignored {
+			sep, left, right = ' ', '(', ')'
+		}
+		for ; i < c.a1 && j < c.b1; i, j = i+1, j+1 {
+			p.sideLine(p.a.lines[i], byte(sep), p.b.lines[j])
+		}
+		for ; i < c.a1; i++ {
+			p.sideLine(p.a.lines[i], left, nil)
+		}
+		for ; j < c.b1; j++ {
+			p.sideLine(nil, right, p.b.lines[j])
+		}
+	}
+	common(len(p.a.lines))
+}
+
+// sideLine writes a line of the side-by-side format, either line being
+// nil when the other has no counterpart. The separator is a space for
+// lines that are the same.
+func (p *printer) sideLine(left []byte, sep byte, right []byte) {
+	half, column2 := p.o.halfWidth, p.o.column2
+	newline := false
+	color := ""
+	switch sep {
+	case '<':
+		color = deleteColor
+	case '>':
+		color = addColor
+	}
+
+	var b strings.Builder
+	column := 0
+	if left != nil {
+		left, newline = cutNewline(left)
+		column = p.halfLine(&b, left, 0, half)
+	}
+	if sep != ' ' {
+		column = p.tabTo(&b, column, (half+column2-1)/2) + 1
+		if _, rightNewline := cutNewline(right); sep == '|' && newline != rightNewline {
+			sep = '\\'
+			if newline {
+				sep = '/'
+			}
+		}
+		b.WriteByte(sep)
+	}
+	if right != nil {
+		var rightNewline bool
+		right, rightNewline = cutNewline(right)
+		newline = newline || rightNewline
+		if len(right) > 0 {
+			column = p.tabTo(&b, column, column2)
+			p.halfLine(&b, right, column, half)
+		}
+	}
+
+	if newline {
+		b.WriteByte('\n')
+	}
+	p.colorize(color, b.String())
+}
+
+// cutNewline returns a line without its newline and whether it had one.
+func cutNewline(line []byte) ([]byte, bool) {
+	if n := len(line); n > 0 && line[n-1] == '\n' {
+		return line[:n-1], true
+	}
+	return line, false
+}
+
+// halfLine writes as much of a line as fits in a column of the given
+// width, indented by indent, and returns the position reached within it.
+func (p *printer) halfLine(b *strings.Builder, line []byte, indent, width int) int {
+	in, out := 0, 0
+	for _, r := range string(line) {
+		switch r {
+		case '\t':
+			spaces := tabSize - in%tabSize
+			if in == out {
+				stop := out + spaces
+				if p.o.expandTabs {
+					stop = min(stop, width)
+					for ; out < stop; out++ {
+						b.WriteByte(' ')
+					}
+				} else if stop < width {
+					out = stop
+					b.WriteRune(r)
+				}
+			}
+			in += spaces
+		case '\r':
+			b.WriteRune(r)
+			p.tabTo(b, 0, indent)
+			in, out = 0, 0
+		case '\b':
+			if in == 0 {
+				break
+			}
+			if in--; in < width {
+				if out <= in {
+					for ; out < in; out++ {
+						b.WriteByte(' ')
+					}
+				} else {
+					out = in
+					b.WriteRune(r)
+				}
+			}
+		case '\f', '\v':
+			if in < width {
+				b.WriteRune(r)
+			}
+		default:
+			if in++; in <= width {
+				out = in
+				b.WriteRune(r)
+			}
+		}
+	}
+	return out
+}
+
+// tabTo writes the tabs and spaces moving from one column to another and
+// returns the latter. Only spaces are used when expanding tabs.
+func (p *printer) tabTo(b *strings.Builder, from, to int) int {
+	if !p.o.expandTabs {
+		for stop := from + tabSize - from%tabSize; stop <= to; stop += tabSize {
+			b.WriteByte('\t')
+			from = stop
+		}
+	}
+	for ; from < to; from++ {
+		b.WriteByte(' ')
+	}
+	return to
+}
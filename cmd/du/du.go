@@ -0,0 +1,174 @@
+// Package du provides functionality for estimating the disk space used by
+// files and directories.
+package du
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/skraio/unix-utilities/cmdflags"
+	"github.com/skraio/unix-utilities/internal/exit"
+	"github.com/skraio/unix-utilities/internal/units"
+	"github.com/spf13/cobra"
+)
+
+// duFlags holds flags for du command.
+type duFlags struct {
+	summarize    bool
+	all          bool
+	human        bool
+	si           bool
+	total        bool
+	apparentSize bool
+	bytes        bool
+	kilobytes    bool
+	megabytes    bool
+	oneFS        bool
+	maxDepth     int
+	exclude      []string
+}
+
+var pFlags duFlags
+
+// flags definition for du command.
+var flags = []cmdflags.Flag{
+	{Value: &pFlags.summarize, Name: "summarize", ShortHand: "s", DefaultValue: false, Description: "display only a total for each argument"},
+	{Value: &pFlags.all, Name: "all", ShortHand: "a", DefaultValue: false, Description: "write counts for all files, not just directories"},
+	{Value: &pFlags.human, Name: "human-readable", ShortHand: "h", DefaultValue: false, Description: "print sizes in human readable format (e.g., 1K 234M 2G)"},
+	{Value: &pFlags.si, Name: "si", ShortHand: "", DefaultValue: false, Description: "like -h, but use powers of 1000 not 1024"},
+	{Value: &pFlags.total, Name: "total", ShortHand: "c", DefaultValue: false, Description: "produce a grand total"},
+	{Value: &pFlags.apparentSize, Name: "apparent-size", ShortHand: "", DefaultValue: false, Description: "print apparent sizes rather than disk usage"},
+	{Value: &pFlags.bytes, Name: "bytes", ShortHand: "b", DefaultValue: false, Description: "equivalent to '--apparent-size --block-size=1'"},
+	{Value: &pFlags.kilobytes, Name: "kilobytes", ShortHand: "k", DefaultValue: false, Description: "like --block-size=1K"},
+	{Value: &pFlags.megabytes, Name: "megabytes", ShortHand: "m", DefaultValue: false, Description: "like --block-size=1M"},
+	{Value: &pFlags.oneFS, Name: "one-file-system", ShortHand: "x", DefaultValue: false, Description: "skip directories on different file systems"},
+}
+
+// intFlags definition for du command.
+var intFlags = []cmdflags.IntFlag{
+	{Value: &pFlags.maxDepth, Name: "max-depth", ShortHand: "d", DefaultValue: -1, Description: "print the total for a directory only if it is N or fewer levels below the command line argument"},
+}
+
+// Cmd represents the 'du' command configuration using Cobra.
+var Cmd = &cobra.Command{
+	Use:           "du [-f flags] [file]...",
+	Short:         "Estimate file space usage",
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			args = []string{"."}
+		}
+		return exit.Status(executeDu(args))
+	},
+}
+
+// init initializes the 'du' command by setting up flags.
+func init() {
+	cmdflags.ParseFlags(flags, Cmd)
+	cmdflags.ParseIntFlags(intFlags, Cmd)
+	Cmd.Flags().StringArrayVarP(&pFlags.exclude, "exclude", "", nil, "exclude files that match PATTERN")
+	Cmd.PersistentFlags().BoolP("help", "", false, "help for this command")
+
+	Cmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		fmt.Fprintf(os.Stderr, "du: %v\n", err)
+		return exit.Status(1)
+	})
+}
+
+// executeDu executes the du command with given arguments and returns its
+// exit status.
+func executeDu(args []string) int {
+	if pFlags.summarize && pFlags.all {
+		fmt.Fprintln(os.Stderr, "du: cannot both summarize and show all entries")
+		return 1
+	}
+	for _, pattern := range pFlags.exclude {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			fmt.Fprintf(os.Stderr, "du: %s: %v\n", pattern, err)
+			return 1
+		}
+	}
+
+	c := newCounter()
+	w := &walker{
+		oneFS:    pFlags.oneFS,
+		excluded: excluded,
+		sem:      make(chan struct{}, runtime.NumCPU()),
+	}
+
+	status := 0
+	var total int64
+	for _, arg := range args {
+		root, err := w.walk(arg)
+		if err != nil {
+			c.report(err)
+			status = 1
+			continue
+		}
+
+		total += c.count(root, 0)
+		if c.failed {
+			status = 1
+		}
+	}
+
+	if pFlags.total {
+		c.print(total, "total")
+	}
+	c.out.Flush()
+
+	return status
+}
+
+// newCounter builds the counter from the flags.
+func newCounter() *counter {
+	c := &counter{
+		out:      bufio.NewWriter(os.Stdout),
+		apparent: pFlags.apparentSize || pFlags.bytes,
+		all:      pFlags.all,
+		maxDepth: pFlags.maxDepth,
+		seen:     map[[2]uint64]bool{},
+		unit:     1024,
+	}
+	if pFlags.summarize {
+		c.maxDepth = 0
+	}
+
+	switch {
+	case pFlags.si:
+		c.format = &units.Format{Base: 1000, RoundUp: true}
+	case pFlags.human:
+		c.format = &units.Format{RoundUp: true}
+	case pFlags.bytes:
+		c.unit = 1
+	case pFlags.megabytes:
+		c.unit = 1024 * 1024
+	}
+
+	return c
+}
+
+// excluded reports whether the base name or path of a file matches one of
+// the --exclude patterns.
+func excluded(path string) bool {
+	for _, pattern := range pFlags.exclude {
+		if ok, _ := filepath.Match(pattern, filepath.Base(path)); ok {
+			return true
+		}
+		if ok, _ := filepath.Match(pattern, path); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// report prints an error about a file.
+func (c *counter) report(err error) {
+	c.out.Flush()
+	exit.Fail("du", err)
+	c.failed = true
+}
@@ -0,0 +1,142 @@
+package du
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/skraio/unix-utilities/internal/assert"
+	"github.com/skraio/unix-utilities/internal/units"
+)
+
+func TestCount(t *testing.T) {
+	root := t.TempDir()
+	for name, size := range map[string]int{
+		"bird/call.txt":   100,
+		"bird/nest/home":  2000,
+		"world/career.md": 30,
+	} {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, bytes.Repeat([]byte("x"), size), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Link(filepath.Join(root, "bird/nest/home"), filepath.Join(root, "world/home")); err != nil {
+		t.Fatal(err)
+	}
+
+	dirSize := func(name string) int64 {
+		info, err := os.Lstat(filepath.Join(root, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return info.Size()
+	}
+	d := dirSize(".")
+	nest := dirSize("bird/nest")
+	bird := dirSize("bird") + 100 + nest + 2000
+	world := dirSize("world") + 30
+
+	tests := []struct {
+		name string
+		c    counter
+		want []string
+	}{
+		{
+			name: "Directories",
+			c:    counter{maxDepth: -1},
+			want: []string{
+				itoa(nest+2000) + "\t/bird/nest",
+				itoa(bird) + "\t/bird",
+				itoa(world) + "\t/world",
+				itoa(d+bird+world) + "\t",
+			},
+		},
+		{
+			name: "All files",
+			c:    counter{maxDepth: -1, all: true},
+			want: []string{
+				"100\t/bird/call.txt",
+				"2000\t/bird/nest/home",
+				itoa(nest+2000) + "\t/bird/nest",
+				itoa(bird) + "\t/bird",
+				"30\t/world/career.md",
+				itoa(world) + "\t/world",
+				itoa(d+bird+world) + "\t",
+			},
+		},
+		{
+			name: "Depth",
+			c:    counter{maxDepth: 1},
+			want: []string{
+				itoa(bird) + "\t/bird",
+				itoa(world) + "\t/world",
+				itoa(d+bird+world) + "\t",
+			},
+		},
+		{
+			name: "Summary",
+			c:    counter{maxDepth: 0},
+			want: []string{itoa(d+bird+world) + "\t"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &walker{excluded: func(string) bool { return false }, sem: make(chan struct{}, 4)}
+			n, err := w.walk(root)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var out bytes.Buffer
+			c := tt.c
+			c.out = bufio.NewWriter(&out)
+			c.apparent = true
+			c.unit = 1
+			c.seen = map[[2]uint64]bool{}
+			total := c.count(n, 0)
+			c.out.Flush()
+
+			assert.Equal(t, total, d+bird+world)
+			assert.Equal(t, strings.ReplaceAll(out.String(), root, ""), strings.Join(tt.want, "\n")+"\n")
+		})
+	}
+}
+
+func TestHumanSize(t *testing.T) {
+	tests := []struct {
+		name   string
+		format units.Format
+		size   int64
+		want   string
+	}{
+		{name: "Bytes", format: units.Format{RoundUp: true}, size: 1000, want: "1000"},
+		{name: "Exact kibibytes", format: units.Format{RoundUp: true}, size: 4096, want: "4.0K"},
+		{name: "Rounded up", format: units.Format{RoundUp: true}, size: 4097, want: "4.1K"},
+		{name: "Above ten", format: units.Format{RoundUp: true}, size: 100000, want: "98K"},
+		{name: "Next unit", format: units.Format{RoundUp: true}, size: 1024*1024 - 1, want: "1.0M"},
+		{name: "SI", format: units.Format{Base: 1000, RoundUp: true}, size: 119000, want: "119k"},
+		{name: "Truncated", format: units.Format{Min: 1024 * 1024}, size: 5*1024*1024 + 12345, want: "5M"},
+		{name: "Largest unit", format: units.Format{Max: 1024 * 1024}, size: 3 * 1024 * 1024 * 1024, want: "3072M"},
+		{name: "Largest unit rounded", format: units.Format{Max: 1024, RoundUp: true}, size: 1024*1024 - 1, want: "1024K"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.format.Size(tt.size), tt.want)
+		})
+	}
+}
+
+// itoa formats a size for the expected output.
+func itoa(n int64) string {
+	return strconv.FormatInt(n, 10)
+}
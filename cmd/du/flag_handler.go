@@ -0,0 +1,98 @@
+package du
+
+import (
+	"bufio"
+	"strconv"
+
+	"github.com/skraio/unix-utilities/internal/units"
+)
+
+// counter adds up the sizes of walked trees and prints them.
+type counter struct {
+	out      *bufio.Writer
+	apparent bool
+	all      bool
+	maxDepth int
+	format   *units.Format
+	unit     int64
+	// seen holds the device and inode of files with several hard links
+	// that were already counted.
+	seen   map[[2]uint64]bool
+	failed bool
+}
+
+// first reports whether n is counted for the first time, remembering the
+// directories and the files with several hard links that were.
+func (c *counter) first(n *node) bool {
+	if n.nlink > 1 || n.dir {
+		key := [2]uint64{n.dev, n.ino}
+		if c.seen[key] {
+			return false
+		}
+		c.seen[key] = true
+	}
+	return true
+}
+
+// size returns the size counted for n.
+func (c *counter) size(n *node) int64 {
+	if c.apparent {
+		return n.size
+	}
+	return n.blocks
+}
+
+// count returns the total size of the tree n at the given depth below the
+// command line argument, printing the sizes of its directories, and files
+// with -a, in post-order. A file that was already counted, such as a hard
+// link to one seen before or a directory given twice on the command line,
+// is skipped entirely.
+func (c *counter) count(n *node, depth int) int64 {
+	if n.err != nil && !n.dir {
+		c.report(n.err)
+		return 0
+	}
+	if !c.first(n) {
+		return 0
+	}
+
+	total := c.size(n)
+	if n.err != nil {
+		c.report(n.err)
+	}
+
+	for _, child := range n.children {
+		if child.dir || child.err != nil {
+			total += c.count(child, depth+1)
+			continue
+		}
+
+		if !c.first(child) {
+			continue
+		}
+		size := c.size(child)
+		total += size
+		if c.all && (c.maxDepth < 0 || depth+1 <= c.maxDepth) {
+			c.print(size, child.path)
+		}
+	}
+
+	if n.dir || depth == 0 {
+		if c.maxDepth < 0 || depth <= c.maxDepth {
+			c.print(total, n.path)
+		}
+	}
+	return total
+}
+
+// print prints a size followed by the name it belongs to.
+func (c *counter) print(size int64, name string) {
+	if c.format != nil {
+		c.out.WriteString(c.format.Size(size))
+	} else {
+		c.out.WriteString(strconv.FormatInt((size+c.unit-1)/c.unit, 10))
+	}
+	c.out.WriteByte('\t')
+	c.out.WriteString(name)
+	c.out.WriteByte('\n')
+}
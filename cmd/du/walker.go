@@ -0,0 +1,101 @@
+package du
+
+import (
+	"io/fs"
+	"os"
+	"sync"
+
+	"github.com/skraio/unix-utilities/internal/fileinfo"
+)
+
+// node is a file of the tree being measured.
+type node struct {
+	path     string
+	dir      bool
+	blocks   int64
+	size     int64
+	dev      uint64
+	ino      uint64
+	nlink    uint64
+	children []*node
+	err      error
+}
+
+// newNode returns the node of a file from its information.
+func newNode(path string, info fs.FileInfo) *node {
+	n := &node{path: path, dir: info.IsDir(), size: info.Size()}
+	if stat, ok := fileinfo.Sys(info); ok {
+		n.blocks = int64(stat.Blocks) * 512
+		n.dev = uint64(stat.Dev)
+		n.ino = uint64(stat.Ino)
+		n.nlink = uint64(stat.Nlink)
+	}
+	return n
+}
+
+// walker reads a directory tree concurrently into nodes.
+type walker struct {
+	oneFS    bool
+	excluded func(path string) bool
+	// sem bounds the number of directories read at the same time.
+	sem chan struct{}
+	wg  sync.WaitGroup
+}
+
+// walk returns the tree rooted at root, whose directories are all read
+// once it returns.
+func (w *walker) walk(root string) (*node, error) {
+	info, err := os.Lstat(root)
+	if err != nil {
+		return nil, err
+	}
+
+	n := newNode(root, info)
+	if n.dir {
+		w.wg.Add(1)
+		go w.walkDir(n, n.dev)
+		w.wg.Wait()
+	}
+	return n, nil
+}
+
+// walkDir reads the entries of the directory n and starts walking its
+// subdirectories, skipping those on another device than dev with oneFS.
+func (w *walker) walkDir(n *node, dev uint64) {
+	defer w.wg.Done()
+
+	w.sem <- struct{}{}
+	defer func() { <-w.sem }()
+
+	entries, err := os.ReadDir(n.path)
+	if err != nil {
+		n.err = err
+	}
+
+	for _, e := range entries {
+		path := n.path + "/" + e.Name()
+		if n.path[len(n.path)-1] == '/' {
+			path = n.path + e.Name()
+		}
+		if w.excluded(path) {
+			continue
+		}
+
+		info, err := e.Info()
+		if err != nil {
+			n.children = append(n.children, &node{path: path, err: err})
+			continue
+		}
+
+		child := newNode(path, info)
+		if child.dir && w.oneFS && child.dev != dev {
+			continue
+		}
+		n.children = append(n.children, child)
+
+		if child.dir {
+			w.wg.Add(1)
+			go w.walkDir(child, dev)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+// Package expand provides the expand and unexpand commands, which convert
+// tabs to spaces and spaces back to tabs, given a size or a list of tab
+// stops.
+package expand
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"unicode/utf8"
+
+	"github.com/skraio/unix-utilities/cmdflags"
+	"github.com/skraio/unix-utilities/internal/exit"
+	"github.com/skraio/unix-utilities/internal/fileinput"
+	"github.com/skraio/unix-utilities/internal/lineio"
+	"github.com/spf13/cobra"
+)
+
+// expandFlags holds flags for expand command.
+type expandFlags struct {
+	initial bool
+	tabs    string
+}
+
+var pFlags expandFlags
+
+// flags definition for expand command.
+var flags = []cmdflags.Flag{
+	{Value: &pFlags.initial, Name: "initial", ShortHand: "i", DefaultValue: false, Description: "do not convert tabs after non blanks"},
+}
+
+// stringFlags definition for expand command.
+var stringFlags = []cmdflags.StringFlag{
+	{Value: &pFlags.tabs, Name: "tabs", ShortHand: "t", DefaultValue: "", Description: "have tabs N characters apart, or use a comma separated LIST of tab positions, the last optionally given as /N or +N"},
+}
+
+// Cmd represents the 'expand' command configuration using Cobra.
+var Cmd = &cobra.Command{
+	Use:           "expand [-f flags] [file]...",
+	Short:         "Convert tabs to spaces",
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return exit.Status(executeExpand(args))
+	},
+}
+
+// init initializes the 'expand' command by setting up flags.
+func init() {
+	cmdflags.ParseFlags(flags, Cmd)
+	cmdflags.ParseStringFlags(stringFlags, Cmd)
+
+	Cmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		fmt.Fprintf(os.Stderr, "expand: %v\n", err)
+		return exit.Status(1)
+	})
+}
+
+// executeExpand executes the expand command with given arguments and
+// returns its exit status.
+func executeExpand(args []string) int {
+	ts, err := parseTabStops(pFlags.tabs)
+	if err != nil {
+		return exit.Fail("expand", err)
+	}
+	e := &expander{tabs: ts, initial: pFlags.initial}
+	return convertFiles("expand", args, e.expandLine)
+}
+
+// convertFunc converts a line, given without its newline, printing it to
+// out.
+type convertFunc func(out *bufio.Writer, line []byte)
+
+// convertFiles converts the lines of the named files with convert and
+// returns the exit status of the named command. Files that cannot be read
+// are reported and skipped.
+func convertFiles(command string, args []string, convert convertFunc) int {
+	out := bufio.NewWriter(os.Stdout)
+	defer out.Flush()
+
+	status := 0
+	for _, name := range fileinput.Args(args) {
+		if err := convertFile(out, name, convert); err != nil {
+			out.Flush()
+			status = exit.Fail(command, err)
+		}
+	}
+	return status
+}
+
+// convertFile converts the lines of the named file.
+func convertFile(out *bufio.Writer, name string, convert convertFunc) error {
+	f, err := fileinput.Open(name)
+	if err != nil {
+		return err
+	}
+	defer fileinput.Close(f)
+
+	lr := lineio.NewReader(f)
+	for {
+		line, err := lr.ReadLine()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		text := lr.TrimDelim(line)
+		convert(out, text)
+		out.Write(line[len(text):])
+	}
+}
+
+// expander converts tabs to spaces.
+type expander struct {
+	tabs *tabStops
+
+	// initial only converts the tabs before the first character that is
+	// not a blank.
+	initial bool
+}
+
+// expandLine prints line with its tabs replaced by the spaces up to the
+// next tab stop, or by a single space past the last stop.
+func (e *expander) expandLine(out *bufio.Writer, line []byte) {
+	column, index := 0, 0
+	convert := true
+	for len(line) > 0 {
+		_, size := utf8.DecodeRune(line)
+		char := line[:size]
+		line = line[size:]
+		if !convert {
+			out.Write(char)
+			continue
+		}
+
+		switch char[0] {
+		case '\t':
+			stop, ok := e.tabs.next(column, &index)
+			if !ok {
+				stop = column + 1
+			}
+			for ; column < stop; column++ {
+				out.WriteByte(' ')
+			}
+			continue
+		case '\b':
+			column = max(column-1, 0)
+			index = max(index-1, 0)
+		default:
+			column++
+		}
+		convert = !e.initial || lineio.IsBlank(char[0])
+		out.Write(char)
+	}
+}
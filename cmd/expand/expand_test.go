@@ -0,0 +1,94 @@
+package expand
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+
+	"github.com/skraio/unix-utilities/internal/assert"
+)
+
+func TestParseTabStopsErrors(t *testing.T) {
+	tests := []struct {
+		arg string
+		err string
+	}{
+		{arg: "0", err: "tab size cannot be 0"},
+		{arg: "2,1", err: "tab sizes must be ascending"},
+		{arg: "3/", err: "'/' specifier not at start of number: '/'"},
+		{arg: "a", err: "tab size contains invalid character(s): 'a'"},
+		{arg: "/2,3", err: "'/' specifier only allowed with the last value"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.arg, func(t *testing.T) {
+			_, err := parseTabStops(tt.arg)
+			assert.Equal(t, err.Error(), tt.err)
+		})
+	}
+}
+
+// convertInput runs convert over the lines of input.
+func convertInput(input string, convert convertFunc) string {
+	var out strings.Builder
+	w := bufio.NewWriter(&out)
+	for _, line := range strings.SplitAfter(input, "\n") {
+		text := strings.TrimSuffix(line, "\n")
+		convert(w, []byte(text))
+		w.WriteString(line[len(text):])
+	}
+	w.Flush()
+	return out.String()
+}
+
+func TestExpand(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		tabs    string
+		initial bool
+		want    string
+	}{
+		{name: "Default", input: "a\tb\n", want: "a       b\n"},
+		{name: "Size", input: "a\tb  c\n\tx\ty\n", tabs: "4", want: "a   b  c\n    x   y\n"},
+		{name: "Initial", input: "a\tb  c\n\tx\ty\n", tabs: "2,5", initial: true, want: "a\tb  c\n  x\ty\n"},
+		{name: "Repeat", input: "a\tb\tc\td\n", tabs: "2,/5", want: "a b  c    d\n"},
+		{name: "Increment", input: "a\tb\tc\td\n", tabs: "2,+5", want: "a b    c    d\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts, err := parseTabStops(tt.tabs)
+			if err != nil {
+				t.Fatal(err)
+			}
+			e := &expander{tabs: ts, initial: tt.initial}
+			assert.Equal(t, convertInput(tt.input, e.expandLine), tt.want)
+		})
+	}
+}
+
+func TestUnexpand(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		tabs  string
+		all   bool
+		want  string
+	}{
+		{name: "Initial", input: "        a       b\n", want: "\ta       b\n"},
+		{name: "All", input: "        a       b\n", all: true, want: "\ta\tb\n"},
+		{name: "Size", input: "   a     b\n", tabs: "3", all: true, want: "\ta\t\tb\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts, err := parseTabStops(tt.tabs)
+			if err != nil {
+				t.Fatal(err)
+			}
+			u := &unexpander{tabs: ts, all: tt.all}
+			assert.Equal(t, convertInput(tt.input, u.unexpandLine), tt.want)
+		})
+	}
+}
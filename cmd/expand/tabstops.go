@@ -0,0 +1,137 @@
+package expand
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// defaultTabSize is the distance between tab stops when none are given.
+const defaultTabSize = 8
+
+// tabStops holds the tab stops given to expand and unexpand: either a
+// size, with stops at each of its multiples, or a list of stops optionally
+// followed by stops every extend columns or every increment columns from
+// the last in the list.
+type tabStops struct {
+	size      int
+	list      []int
+	extend    int
+	increment int
+}
+
+// parseTabStops parses a list of tab stops separated by commas or blanks.
+// The last may be given as /N for stops at multiples of N beyond the list,
+// or as +N for stops every N columns after the last in the list.
+func parseTabStops(s string) (*tabStops, error) {
+	ts := &tabStops{}
+	value, start, have := 0, 0, false
+	extend, increment := false, false
+
+	// add ends the value being read.
+	add := func() error {
+		if !have {
+			return nil
+		}
+		have = false
+		switch {
+		case extend:
+			if ts.extend != 0 {
+				return errors.New("'/' specifier only allowed with the last value")
+			}
+			ts.extend = value
+		case increment:
+			if ts.increment != 0 {
+				return errors.New("'+' specifier only allowed with the last value")
+			}
+			ts.increment = value
+		default:
+			if ts.extend != 0 {
+				return errors.New("'/' specifier only allowed with the last value")
+			}
+			if ts.increment != 0 {
+				return errors.New("'+' specifier only allowed with the last value")
+			}
+			ts.list = append(ts.list, value)
+		}
+		return nil
+	}
+
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == ',' || c == ' ' || c == '\t':
+			if err := add(); err != nil {
+				return nil, err
+			}
+		case c == '/' || c == '+':
+			if have {
+				return nil, fmt.Errorf("'%c' specifier not at start of number: '%s'", c, s[i:])
+			}
+			extend, increment = c == '/', c == '+'
+		case c >= '0' && c <= '9':
+			if !have {
+				value, start, have = 0, i, true
+			}
+			if value > (math.MaxInt-int(c-'0'))/10 {
+				end := i
+				for end < len(s) && s[end] >= '0' && s[end] <= '9' {
+					end++
+				}
+				return nil, fmt.Errorf("tab stop is too large '%s'", s[start:end])
+			}
+			value = value*10 + int(c-'0')
+		default:
+			return nil, fmt.Errorf("tab size contains invalid character(s): '%s'", s[i:])
+		}
+	}
+	if err := add(); err != nil {
+		return nil, err
+	}
+
+	if ts.extend != 0 && ts.increment != 0 {
+		return nil, errors.New("'/' specifier is mutually exclusive with '+'")
+	}
+	for i, stop := range ts.list {
+		if stop == 0 {
+			return nil, errors.New("tab size cannot be 0")
+		}
+		if i > 0 && stop <= ts.list[i-1] {
+			return nil, errors.New("tab sizes must be ascending")
+		}
+	}
+
+	switch {
+	case len(ts.list) == 0:
+		ts.size = defaultTabSize
+		if ts.extend != 0 {
+			ts.size = ts.extend
+		} else if ts.increment != 0 {
+			ts.size = ts.increment
+		}
+	case len(ts.list) == 1 && ts.extend == 0 && ts.increment == 0:
+		ts.size = ts.list[0]
+	}
+	return ts, nil
+}
+
+// next returns the first tab stop after column, or false when there is
+// none. The index of the stop in the list is kept in *index, as columns
+// only grow along a line but for backspaces, which step it back.
+func (ts *tabStops) next(column int, index *int) (int, bool) {
+	if ts.size != 0 {
+		return column + ts.size - column%ts.size, true
+	}
+	for ; *index < len(ts.list); *index++ {
+		if stop := ts.list[*index]; column < stop {
+			return stop, true
+		}
+	}
+	if ts.extend != 0 {
+		return column + ts.extend - column%ts.extend, true
+	}
+	if ts.increment != 0 {
+		last := ts.list[len(ts.list)-1]
+		return column + ts.increment - (column-last)%ts.increment, true
+	}
+	return 0, false
+}
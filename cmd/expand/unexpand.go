@@ -0,0 +1,160 @@
+package expand
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"unicode/utf8"
+
+	"github.com/skraio/unix-utilities/cmdflags"
+	"github.com/skraio/unix-utilities/internal/exit"
+	"github.com/skraio/unix-utilities/internal/lineio"
+	"github.com/spf13/cobra"
+)
+
+// unexpandFlags holds flags for unexpand command.
+type unexpandFlags struct {
+	all       bool
+	firstOnly bool
+	tabs      string
+}
+
+var uFlags unexpandFlags
+
+// unexpandFlagList definition for unexpand command.
+var unexpandFlagList = []cmdflags.Flag{
+	{Value: &uFlags.all, Name: "all", ShortHand: "a", DefaultValue: false, Description: "convert all blanks, instead of just initial blanks"},
+	{Value: &uFlags.firstOnly, Name: "first-only", ShortHand: "", DefaultValue: false, Description: "convert only leading sequences of blanks (overrides -a)"},
+}
+
+// unexpandStringFlags definition for unexpand command.
+var unexpandStringFlags = []cmdflags.StringFlag{
+	{Value: &uFlags.tabs, Name: "tabs", ShortHand: "t", DefaultValue: "", Description: "have tabs N characters apart instead of 8 (enables -a), or use a comma separated LIST of tab positions"},
+}
+
+// UnexpandCmd represents the 'unexpand' command configuration using Cobra.
+var UnexpandCmd = &cobra.Command{
+	Use:           "unexpand [-f flags] [file]...",
+	Short:         "Convert spaces to tabs",
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return exit.Status(executeUnexpand(args))
+	},
+}
+
+// init initializes the 'unexpand' command by setting up flags.
+func init() {
+	cmdflags.ParseFlags(unexpandFlagList, UnexpandCmd)
+	cmdflags.ParseStringFlags(unexpandStringFlags, UnexpandCmd)
+
+	UnexpandCmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		fmt.Fprintf(os.Stderr, "unexpand: %v\n", err)
+		return exit.Status(1)
+	})
+}
+
+// executeUnexpand executes the unexpand command with given arguments and
+// returns its exit status.
+func executeUnexpand(args []string) int {
+	ts, err := parseTabStops(uFlags.tabs)
+	if err != nil {
+		return exit.Fail("unexpand", err)
+	}
+	u := &unexpander{
+		tabs: ts,
+		all:  !uFlags.firstOnly && (uFlags.all || uFlags.tabs != ""),
+	}
+	return convertFiles("unexpand", args, u.unexpandLine)
+}
+
+// unexpander converts blanks to tabs.
+type unexpander struct {
+	tabs *tabStops
+
+	// all converts the blanks all along the line, not only those before
+	// its first other character.
+	all bool
+}
+
+// unexpandLine prints line with the runs of blanks reaching a tab stop
+// replaced by tabs. Blanks past the last stop are left alone.
+func (u *unexpander) unexpandLine(out *bufio.Writer, line []byte) {
+	column, index := 0, 0
+	convert := true
+
+	// pending holds the blanks not known yet to reach a tab stop. When a
+	// single one is just before a stop, it is printed as is in case the
+	// stop is not reached by a tab.
+	var pending []byte
+	beforeStop := false
+	prevBlank := true
+
+	for len(line) > 0 || convert {
+		var char []byte
+		if len(line) > 0 {
+			_, size := utf8.DecodeRune(line)
+			char, line = line[:size], line[size:]
+		}
+		if !convert {
+			out.Write(char)
+			continue
+		}
+
+		blank := len(char) > 0 && lineio.IsBlank(char[0])
+		switch {
+		case blank:
+			stop, ok := u.tabs.next(column, &index)
+			if !ok {
+				convert = false
+				break
+			}
+			if char[0] == '\t' {
+				column = stop
+				if len(pending) > 0 {
+					pending[0] = '\t'
+				}
+			} else {
+				column++
+				if !prevBlank || column != stop {
+					if column == stop {
+						beforeStop = true
+					}
+					pending = append(pending, char[0])
+					prevBlank = true
+					continue
+				}
+				// The blanks reach the stop: a tab replaces them.
+				char = []byte{'\t'}
+				if len(pending) > 0 {
+					pending[0] = '\t'
+				}
+			}
+			if beforeStop {
+				pending = pending[:1]
+			} else {
+				pending = pending[:0]
+			}
+		case len(char) > 0 && char[0] == '\b':
+			column = max(column-1, 0)
+			index = max(index-1, 0)
+		default:
+			column++
+		}
+
+		if len(pending) > 0 {
+			if len(pending) > 1 && beforeStop {
+				pending[0] = '\t'
+			}
+			out.Write(pending)
+			pending = pending[:0]
+			beforeStop = false
+		}
+		prevBlank = blank
+		convert = convert && (u.all || blank)
+		if len(char) == 0 {
+			return
+		}
+		out.Write(char)
+	}
+}
@@ -0,0 +1,403 @@
+package find
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/skraio/unix-utilities/internal/fileinfo"
+	"github.com/skraio/unix-utilities/internal/filemode"
+)
+
+// expr is a node of the expression tree evaluated for every file.
+type expr interface {
+	eval(e *entry) bool
+}
+
+// andExpr is true when both operands are, evaluating right only if left is
+// true.
+type andExpr struct {
+	left, right expr
+}
+
+// eval implements expr.
+func (x *andExpr) eval(e *entry) bool {
+	return x.left.eval(e) && x.right.eval(e)
+}
+
+// orExpr is true when either operand is, evaluating right only if left is
+// false.
+type orExpr struct {
+	left, right expr
+}
+
+// eval implements expr.
+func (x *orExpr) eval(e *entry) bool {
+	return x.left.eval(e) || x.right.eval(e)
+}
+
+// notExpr negates its operand.
+type notExpr struct {
+	x expr
+}
+
+// eval implements expr.
+func (x *notExpr) eval(e *entry) bool {
+	return !x.x.eval(e)
+}
+
+// predicate is a test or action evaluated on its own.
+type predicate func(e *entry) bool
+
+// eval implements expr.
+func (p predicate) eval(e *entry) bool {
+	return p(e)
+}
+
+// parser builds the expression tree from the command line, collecting the
+// global options along the way.
+type parser struct {
+	args      []string
+	pos       int
+	f         *finder
+	hasAction bool
+}
+
+// parseExpression parses the expression arguments into f. An expression
+// without actions prints the files for which it is true.
+func parseExpression(args []string, f *finder) (expr, error) {
+	p := &parser{args: args, f: f}
+	if len(args) == 0 {
+		return predicate(f.print), nil
+	}
+
+	x, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(args) {
+		if args[p.pos] == ")" {
+			return nil, fmt.Errorf("invalid expression; you have too many ')'")
+		}
+		return nil, fmt.Errorf("unexpected argument '%s'", args[p.pos])
+	}
+
+	if !p.hasAction {
+		x = &andExpr{x, predicate(f.print)}
+	}
+	return x, nil
+}
+
+// peek returns the next argument, or the empty string at the end.
+func (p *parser) peek() string {
+	if p.pos < len(p.args) {
+		return p.args[p.pos]
+	}
+	return ""
+}
+
+// parseOr parses operands joined by -o.
+func (p *parser) parseOr() (expr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for tok := p.peek(); tok == "-o" || tok == "-or"; tok = p.peek() {
+		p.pos++
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &orExpr{left, right}
+	}
+	return left, nil
+}
+
+// parseAnd parses operands joined by -a or simply juxtaposed.
+func (p *parser) parseAnd() (expr, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		tok := p.peek()
+		if tok == "" || tok == ")" || tok == "-o" || tok == "-or" {
+			return left, nil
+		}
+		if tok == "-a" || tok == "-and" {
+			p.pos++
+		}
+
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = &andExpr{left, right}
+	}
+}
+
+// parseNot parses an operand optionally preceded by negations.
+func (p *parser) parseNot() (expr, error) {
+	if tok := p.peek(); tok == "!" || tok == "-not" {
+		p.pos++
+		x, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &notExpr{x}, nil
+	}
+	return p.parsePrimary()
+}
+
+// parsePrimary parses a parenthesized expression or a single primary.
+func (p *parser) parsePrimary() (expr, error) {
+	tok := p.peek()
+	if tok == "" {
+		return nil, fmt.Errorf("invalid expression")
+	}
+	p.pos++
+
+	if tok == "(" {
+		x, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ")" {
+			return nil, fmt.Errorf("invalid expression; I was expecting to find a ')' somewhere but did not see one")
+		}
+		p.pos++
+		return x, nil
+	}
+
+	switch tok {
+	case "-true":
+		return predicate(func(*entry) bool { return true }), nil
+	case "-false":
+		return predicate(func(*entry) bool { return false }), nil
+	case "-depth":
+		p.f.depthFirst = true
+		return predicate(func(*entry) bool { return true }), nil
+	case "-empty":
+		return predicate(isEmpty), nil
+	case "-prune":
+		return predicate(func(e *entry) bool {
+			e.prune = true
+			return true
+		}), nil
+	case "-print":
+		p.hasAction = true
+		return predicate(p.f.print), nil
+	case "-print0":
+		p.hasAction = true
+		return predicate(p.f.print0), nil
+	case "-delete":
+		p.hasAction = true
+		p.f.depthFirst = true
+		return predicate(p.f.delete), nil
+	case "-exec":
+		p.hasAction = true
+		return p.parseExec()
+	}
+
+	arg, ok := "", false
+	if p.pos < len(p.args) {
+		arg, ok = p.args[p.pos], true
+		p.pos++
+	}
+
+	switch tok {
+	case "-name", "-iname", "-path", "-ipath", "-wholename", "-iwholename",
+		"-regex", "-iregex", "-type", "-size", "-mtime", "-mmin", "-newer",
+		"-perm", "-user", "-group", "-maxdepth", "-mindepth", "-printf":
+		if !ok {
+			return nil, fmt.Errorf("missing argument to '%s'", tok)
+		}
+	default:
+		return nil, fmt.Errorf("unknown predicate '%s'", tok)
+	}
+
+	switch tok {
+	case "-name", "-iname":
+		re, err := globRegexp(arg, false, tok == "-iname")
+		if err != nil {
+			return nil, err
+		}
+		return predicate(func(e *entry) bool { return re.MatchString(baseName(e.path)) }), nil
+
+	case "-path", "-ipath", "-wholename", "-iwholename":
+		re, err := globRegexp(arg, true, tok[1] == 'i')
+		if err != nil {
+			return nil, err
+		}
+		return predicate(func(e *entry) bool { return re.MatchString(e.path) }), nil
+
+	case "-regex", "-iregex":
+		if tok == "-iregex" {
+			arg = "(?i)" + arg
+		}
+		re, err := regexp.Compile("^(?:" + arg + ")$")
+		if err != nil {
+			return nil, err
+		}
+		return predicate(func(e *entry) bool { return re.MatchString(e.path) }), nil
+
+	case "-type":
+		types := map[byte]bool{}
+		for _, t := range strings.Split(arg, ",") {
+			if len(t) != 1 || !strings.Contains("bcdpfls", t) {
+				return nil, fmt.Errorf("unknown argument to -type: %s", t)
+			}
+			types[t[0]] = true
+		}
+		return predicate(func(e *entry) bool { return types[typeChar(e.info.Mode())] }), nil
+
+	case "-size":
+		n, unit, err := parseSize(arg)
+		if err != nil {
+			return nil, err
+		}
+		return predicate(func(e *entry) bool {
+			return n.match((e.info.Size() + unit - 1) / unit)
+		}), nil
+
+	case "-mtime", "-mmin":
+		n, err := parseNumber(arg)
+		if err != nil {
+			return nil, fmt.Errorf("invalid argument '%s' to '%s'", arg, tok)
+		}
+		unit := 24 * time.Hour
+		if tok == "-mmin" {
+			unit = time.Minute
+		}
+		now := p.f.now
+		return predicate(func(e *entry) bool {
+			return n.matchAge(now.Sub(e.info.ModTime()), unit)
+		}), nil
+
+	case "-newer":
+		ref, err := os.Stat(arg)
+		if err != nil {
+			return nil, err
+		}
+		return predicate(func(e *entry) bool { return e.info.ModTime().After(ref.ModTime()) }), nil
+
+	case "-perm":
+		return parsePerm(arg)
+
+	case "-user":
+		uid, err := fileinfo.UserID(arg)
+		if err != nil {
+			return nil, fmt.Errorf("'%s' is not the name of a known user", arg)
+		}
+		return predicate(func(e *entry) bool {
+			stat, ok := fileinfo.Sys(e.info)
+			return ok && stat.Uid == uid
+		}), nil
+
+	case "-group":
+		gid, err := fileinfo.GroupID(arg)
+		if err != nil {
+			return nil, fmt.Errorf("'%s' is not the name of an existing group", arg)
+		}
+		return predicate(func(e *entry) bool {
+			stat, ok := fileinfo.Sys(e.info)
+			return ok && stat.Gid == gid
+		}), nil
+
+	case "-maxdepth", "-mindepth":
+		n, err := strconv.Atoi(arg)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid argument '%s' to '%s'", arg, tok)
+		}
+		if tok == "-maxdepth" {
+			p.f.maxDepth = n
+		} else {
+			p.f.minDepth = n
+		}
+		return predicate(func(*entry) bool { return true }), nil
+	}
+
+	// -printf
+	p.hasAction = true
+	format := parsePrintf(arg)
+	return predicate(func(e *entry) bool { return p.f.printf(e, format) }), nil
+}
+
+// parseExec parses the command of -exec up to the terminating ';' or '{} +'.
+func (p *parser) parseExec() (expr, error) {
+	start := p.pos
+	for ; p.pos < len(p.args); p.pos++ {
+		arg := p.args[p.pos]
+		if arg == ";" {
+			argv := p.args[start:p.pos]
+			p.pos++
+			if len(argv) == 0 {
+				return nil, fmt.Errorf("missing argument to '-exec'")
+			}
+			return predicate(func(e *entry) bool { return p.f.exec(argv, e) }), nil
+		}
+		if arg == "+" && p.pos > start+1 && p.args[p.pos-1] == "{}" {
+			b := &batch{f: p.f, argv: p.args[start : p.pos-1]}
+			p.f.batches = append(p.f.batches, b)
+			p.pos++
+			return predicate(b.add), nil
+		}
+	}
+
+	return nil, fmt.Errorf("missing argument to '-exec'")
+}
+
+// parsePerm parses the argument of -perm: an octal or symbolic mode, the
+// latter applied to no permissions, that must match exactly, or be
+// included entirely with a '-' prefix, or partially with a '/' prefix.
+func parsePerm(arg string) (expr, error) {
+	kind := byte(0)
+	if arg != "" && (arg[0] == '-' || arg[0] == '/') {
+		kind, arg = arg[0], arg[1:]
+	}
+
+	m, err := filemode.Parse(arg)
+	if err != nil {
+		return nil, fmt.Errorf("invalid mode '%s'", arg)
+	}
+	mode := filemode.Bits(m.Apply(0, 0))
+
+	return predicate(func(e *entry) bool {
+		perm := filemode.Bits(e.info.Mode())
+		switch kind {
+		case '-':
+			return perm&mode == mode
+		case '/':
+			return mode == 0 || perm&mode != 0
+		}
+		return perm == mode
+	}), nil
+}
+
+// baseName returns the last element of path as find sees it, keeping "/"
+// for the root.
+func baseName(path string) string {
+	if trimmed := strings.TrimRight(path, "/"); trimmed != "" {
+		return filepath.Base(trimmed)
+	}
+	return path
+}
+
+// dirName returns the leading directories of path as %h prints them: path
+// up to its last slash, not counting trailing ones unless there are no
+// others, or "." when it has no slash at all.
+func dirName(path string) string {
+	if i := strings.LastIndexByte(strings.TrimRight(path, "/"), '/'); i >= 0 {
+		return path[:i]
+	}
+	if i := strings.LastIndexByte(path, '/'); i >= 0 {
+		return path[:i]
+	}
+	return "."
+}
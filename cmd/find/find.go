@@ -0,0 +1,265 @@
+// Package find provides functionality for searching directory trees for
+// files matching an expression and acting on them.
+package find
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/skraio/unix-utilities/internal/exit"
+	"github.com/spf13/cobra"
+)
+
+// batchLimit is the number of bytes of arguments collected by -exec ... {} +
+// before the command is run.
+const batchLimit = 128 * 1024
+
+// Cmd represents the 'find' command configuration using Cobra.
+var Cmd = &cobra.Command{
+	Use:   "find [-H] [-L] [-P] [path...] [expression]",
+	Short: "Search for files in a directory hierarchy",
+	Long: `Search for files in a directory hierarchy.
+
+Tests: -name -iname -path -ipath -regex -iregex -type -size -mtime -mmin
+-newer -perm -user -group -empty -true -false
+Options: -maxdepth -mindepth -depth
+Actions: -print -print0 -printf -delete -prune -exec COMMAND ; -exec COMMAND {} +
+Operators: ( EXPR ) ! EXPR EXPR -a EXPR EXPR -o EXPR`,
+	DisableFlagParsing: true,
+	SilenceErrors:      true,
+	SilenceUsage:       true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) > 0 && (args[0] == "--help" || args[0] == "-help") {
+			return cmd.Help()
+		}
+		return exit.Status(executeFind(args))
+	},
+}
+
+// finder walks the starting points, evaluating the expression on every
+// file.
+type finder struct {
+	out    *bufio.Writer
+	expr   expr
+	follow bool
+	// followRoots follows symbolic links given as starting points only.
+	followRoots bool
+	depthFirst  bool
+	minDepth    int
+	maxDepth    int
+	now         time.Time
+	batches     []*batch
+	status      int
+}
+
+// executeFind executes the find command with given arguments and returns
+// its exit status.
+func executeFind(args []string) int {
+	f := &finder{out: bufio.NewWriter(os.Stdout), maxDepth: -1, now: time.Now()}
+	defer f.out.Flush()
+
+	for len(args) > 0 && (args[0] == "-H" || args[0] == "-L" || args[0] == "-P") {
+		f.follow = args[0] == "-L"
+		f.followRoots = args[0] == "-H"
+		args = args[1:]
+	}
+
+	roots := []string{}
+	for len(args) > 0 && !isExpressionStart(args[0]) {
+		roots = append(roots, args[0])
+		args = args[1:]
+	}
+	if len(roots) == 0 {
+		roots = append(roots, ".")
+	}
+
+	x, err := parseExpression(args, f)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "find: %v\n", err)
+		return 1
+	}
+	f.expr = x
+
+	for _, root := range roots {
+		f.visit(root, root, 0, nil)
+	}
+	for _, b := range f.batches {
+		b.run()
+	}
+
+	return f.status
+}
+
+// isExpressionStart reports whether arg starts the expression rather than
+// being a starting point.
+func isExpressionStart(arg string) bool {
+	return (len(arg) > 1 && arg[0] == '-') || arg == "(" || arg == "!" || arg == ")"
+}
+
+// visit evaluates the expression on path and descends into it if it is a
+// directory. ancestors holds the directories above path, to detect loops
+// when following symbolic links.
+func (f *finder) visit(path, root string, depth int, ancestors []fs.FileInfo) {
+	follow := f.follow || (f.followRoots && depth == 0)
+	stat := os.Lstat
+	if follow {
+		stat = os.Stat
+	}
+	info, err := stat(path)
+	if err != nil && follow {
+		// A dangling link is reported as the link itself.
+		info, err = os.Lstat(path)
+	}
+	if err != nil {
+		f.report(err)
+		return
+	}
+
+	e := &entry{path: path, root: root, depth: depth, info: info}
+	if !f.depthFirst && depth >= f.minDepth {
+		f.expr.eval(e)
+	}
+
+	if info.IsDir() && !e.prune && (f.maxDepth < 0 || depth < f.maxDepth) {
+		f.descend(e, ancestors)
+	}
+
+	if f.depthFirst && depth >= f.minDepth {
+		f.expr.eval(e)
+	}
+}
+
+// descend visits the entries of the directory e.
+func (f *finder) descend(e *entry, ancestors []fs.FileInfo) {
+	for _, a := range ancestors {
+		if os.SameFile(a, e.info) {
+			f.report(fmt.Errorf("File system loop detected; '%s' is part of a file system loop", e.path))
+			return
+		}
+	}
+
+	entries, err := os.ReadDir(e.path)
+	if err != nil {
+		f.report(err)
+	}
+
+	ancestors = append(ancestors, e.info)
+	for _, d := range entries {
+		child := e.path + "/" + d.Name()
+		if strings.HasSuffix(e.path, "/") {
+			child = e.path + d.Name()
+		}
+		f.visit(child, e.root, e.depth+1, ancestors)
+	}
+}
+
+// report prints an error about a file and makes find exit unsuccessfully.
+func (f *finder) report(err error) {
+	f.out.Flush()
+	f.status = exit.Fail("find", err)
+}
+
+// print implements -print.
+func (f *finder) print(e *entry) bool {
+	f.out.WriteString(e.path)
+	f.out.WriteByte('\n')
+	return true
+}
+
+// print0 implements -print0.
+func (f *finder) print0(e *entry) bool {
+	f.out.WriteString(e.path)
+	f.out.WriteByte(0)
+	return true
+}
+
+// printf implements -printf.
+func (f *finder) printf(e *entry, format []printfItem) bool {
+	for _, item := range format {
+		if item.verb == 0 {
+			f.out.WriteString(item.literal)
+			continue
+		}
+
+		value := formatItem(item, e)
+		if item.width != "" {
+			value = fmt.Sprintf("%"+item.width+"s", value)
+		}
+		f.out.WriteString(value)
+	}
+	return true
+}
+
+// delete implements -delete.
+func (f *finder) delete(e *entry) bool {
+	if e.path == "." {
+		return true
+	}
+	if err := os.Remove(e.path); err != nil {
+		f.report(err)
+		return false
+	}
+	return true
+}
+
+// exec implements -exec COMMAND ;, reporting whether the command succeeded.
+func (f *finder) exec(argv []string, e *entry) bool {
+	args := make([]string, len(argv))
+	for i, arg := range argv {
+		args[i] = strings.ReplaceAll(arg, "{}", e.path)
+	}
+	return f.run(args) == nil
+}
+
+// run runs a command with find's standard streams.
+func (f *finder) run(args []string) error {
+	f.out.Flush()
+
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	err := cmd.Run()
+
+	var ee *exec.ExitError
+	if err != nil && !errors.As(err, &ee) {
+		f.report(err)
+	}
+	return err
+}
+
+// batch collects the files of -exec COMMAND {} + to run the command on as
+// many of them at once as possible.
+type batch struct {
+	f     *finder
+	argv  []string
+	paths []string
+	size  int
+}
+
+// add adds a file to the batch, running the command when it is full.
+func (b *batch) add(e *entry) bool {
+	if b.size+len(e.path)+1 > batchLimit {
+		b.run()
+	}
+	b.paths = append(b.paths, e.path)
+	b.size += len(e.path) + 1
+	return true
+}
+
+// run runs the command on the collected files.
+func (b *batch) run() {
+	if len(b.paths) == 0 {
+		return
+	}
+
+	args := append(append([]string{}, b.argv...), b.paths...)
+	if err := b.f.run(args); err != nil {
+		b.f.status = 1
+	}
+	b.paths, b.size = nil, 0
+}
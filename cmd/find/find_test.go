@@ -0,0 +1,272 @@
+package find
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/skraio/unix-utilities/internal/assert"
+)
+
+func TestGlobRegexp(t *testing.T) {
+	tests := []struct {
+		name       string
+		pattern    string
+		matchSlash bool
+		foldCase   bool
+		input      string
+		want       bool
+	}{
+		{name: "Star", pattern: "*.go", input: "find.go", want: true},
+		{name: "Star does not match slash", pattern: "*.go", input: "cmd/find.go", want: false},
+		{name: "Star matches slash in paths", pattern: "*/find.go", matchSlash: true, input: "./cmd/find.go", want: true},
+		{name: "Question mark", pattern: "?.txt", input: "ab.txt", want: false},
+		{name: "Bracket", pattern: "[a-c]x", input: "bx", want: true},
+		{name: "Negated bracket", pattern: "[!a-c]x", input: "bx", want: false},
+		{name: "Escaped star", pattern: `a\*`, input: "ab", want: false},
+		{name: "Folded case", pattern: "*.TXT", foldCase: true, input: "nest.txt", want: true},
+		{name: "Dot is literal", pattern: "a.b", input: "axb", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			re, err := globRegexp(tt.pattern, tt.matchSlash, tt.foldCase)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assert.Equal(t, re.MatchString(tt.input), tt.want)
+		})
+	}
+}
+
+func TestNumArg(t *testing.T) {
+	tests := []struct {
+		arg  string
+		age  time.Duration
+		want bool
+	}{
+		{arg: "0", age: 3 * time.Hour, want: true},
+		{arg: "1", age: 30 * time.Hour, want: true},
+		{arg: "+1", age: 47 * time.Hour, want: false},
+		{arg: "+1", age: 49 * time.Hour, want: true},
+		{arg: "-2", age: 47 * time.Hour, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.arg, func(t *testing.T) {
+			n, err := parseNumber(tt.arg)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assert.Equal(t, n.matchAge(tt.age, 24*time.Hour), tt.want)
+		})
+	}
+}
+
+func TestDirName(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{path: "a", want: "."},
+		{path: ".", want: "."},
+		{path: "./a", want: "."},
+		{path: "./a/b", want: "./a"},
+		{path: "a//b", want: "a/"},
+		{path: "a/b/", want: "a"},
+		{path: "a/", want: "a"},
+		{path: "a//", want: "a/"},
+		{path: "/", want: ""},
+		{path: "//", want: "/"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			assert.Equal(t, dirName(tt.path), tt.want)
+		})
+	}
+}
+
+func TestFormatTime(t *testing.T) {
+	mtime := time.Date(2024, time.March, 5, 7, 8, 9, 123456789, time.UTC)
+	tests := []struct {
+		field byte
+		want  string
+	}{
+		{field: '@', want: "1709622489.1234567890"},
+		{field: 'S', want: "09.1234567890"},
+		{field: 'T', want: "07:08:09.1234567890"},
+		{field: '+', want: "2024-03-05+07:08:09.1234567890"},
+		{field: 'Y', want: "2024"},
+		{field: 'd', want: "05"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.field), func(t *testing.T) {
+			assert.Equal(t, formatTime(mtime, tt.field), tt.want)
+		})
+	}
+}
+
+func TestFind(t *testing.T) {
+	root := t.TempDir()
+	for name, content := range map[string]string{
+		"bird/call.txt":      "Without just one nest",
+		"bird/nest/home.TXT": "",
+		"world/career.md":    "Life is your career",
+		"empty.txt":          "",
+	} {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Mkdir(filepath.Join(root, "void"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		expr string
+		want string
+	}{
+		{
+			name: "Everything",
+			expr: "",
+			want: ". ./bird ./bird/call.txt ./bird/nest ./bird/nest/home.TXT ./empty.txt ./void ./world ./world/career.md",
+		},
+		{
+			name: "Name and type",
+			expr: "-type f -name *.txt",
+			want: "./bird/call.txt ./empty.txt",
+		},
+		{
+			name: "Or with parentheses",
+			expr: "( -iname *.txt -o -name *.md ) -print",
+			want: "./bird/call.txt ./bird/nest/home.TXT ./empty.txt ./world/career.md",
+		},
+		{
+			name: "Negation and empty",
+			expr: "-empty ! -type d",
+			want: "./bird/nest/home.TXT ./empty.txt",
+		},
+		{
+			name: "Prune",
+			expr: "-name bird -prune -o -type f -print",
+			want: "./empty.txt ./world/career.md",
+		},
+		{
+			name: "Depth limits",
+			expr: "-mindepth 2 -maxdepth 2",
+			want: "./bird/call.txt ./bird/nest ./world/career.md",
+		},
+		{
+			name: "Path and size",
+			expr: "-path ./bird/* -type f -size +0c",
+			want: "./bird/call.txt",
+		},
+		{
+			name: "Regex",
+			expr: "-regex .*/[a-c][a-z]*\\.[a-z]+",
+			want: "./bird/call.txt ./world/career.md",
+		},
+		{
+			name: "Printf",
+			expr: "-type f -name c* -printf %f:%s:%d:%y:%m",
+			want: "call.txt:21:2:f:644career.md:19:2:f:644",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			f := &finder{out: bufio.NewWriter(&out), maxDepth: -1, now: time.Now()}
+
+			x, err := parseExpression(strings.Fields(tt.expr), f)
+			if err != nil {
+				t.Fatal(err)
+			}
+			f.expr = x
+
+			wd, _ := os.Getwd()
+			os.Chdir(root)
+			f.visit(".", ".", 0, nil)
+			os.Chdir(wd)
+			f.out.Flush()
+
+			assert.Equal(t, strings.Join(strings.Fields(out.String()), " "), tt.want)
+			assert.Equal(t, f.status, 0)
+		})
+	}
+}
+
+func TestFollowLinks(t *testing.T) {
+	root := t.TempDir()
+	for _, dir := range []string{"bird", "world"} {
+		if err := os.Mkdir(filepath.Join(root, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(root, "world/career.md"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("world", filepath.Join(root, "link")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("../bird", filepath.Join(root, "world/nest")); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		f    finder
+		want string
+	}{
+		{name: "Never", f: finder{}, want: "link:l"},
+		{name: "Starting points", f: finder{followRoots: true}, want: "link:d link/career.md:f link/nest:l"},
+		{name: "Always", f: finder{follow: true}, want: "link:d link/career.md:f link/nest:d"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			f := tt.f
+			f.out = bufio.NewWriter(&out)
+			f.maxDepth = -1
+
+			x, err := parseExpression(strings.Fields("-printf %p:%y\\n"), &f)
+			if err != nil {
+				t.Fatal(err)
+			}
+			f.expr = x
+
+			wd, _ := os.Getwd()
+			os.Chdir(root)
+			f.visit("link", "link", 0, nil)
+			os.Chdir(wd)
+			f.out.Flush()
+
+			assert.Equal(t, strings.Join(strings.Fields(out.String()), " "), tt.want)
+		})
+	}
+}
+
+func TestParseExpressionErrors(t *testing.T) {
+	for _, expr := range []string{"-bogus", "-name", "( -print", "-print )", "-type x", "-exec echo {}", "-o -print"} {
+		t.Run(expr, func(t *testing.T) {
+			f := &finder{maxDepth: -1}
+			if _, err := parseExpression(strings.Fields(expr), f); err == nil {
+				t.Errorf("parseExpression(%q) succeeded; want error", expr)
+			}
+		})
+	}
+}
@@ -0,0 +1,363 @@
+package find
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/skraio/unix-utilities/internal/fileinfo"
+	"github.com/skraio/unix-utilities/internal/filemode"
+)
+
+// entry is a file visited by the walk.
+type entry struct {
+	path  string
+	root  string
+	depth int
+	info  fs.FileInfo
+	prune bool
+}
+
+// numArg is a numeric argument of a test, matching values greater than n
+// when cmp is positive, less than n when it is negative and n otherwise.
+type numArg struct {
+	cmp int
+	n   int64
+}
+
+// parseNumber parses a numeric argument with an optional '+' or '-' prefix.
+func parseNumber(arg string) (numArg, error) {
+	a := numArg{}
+	switch {
+	case strings.HasPrefix(arg, "+"):
+		a.cmp, arg = 1, arg[1:]
+	case strings.HasPrefix(arg, "-"):
+		a.cmp, arg = -1, arg[1:]
+	}
+
+	n, err := strconv.ParseInt(arg, 10, 64)
+	if err != nil || n < 0 {
+		return numArg{}, fmt.Errorf("invalid number '%s'", arg)
+	}
+	a.n = n
+	return a, nil
+}
+
+// match reports whether v matches the argument.
+func (a numArg) match(v int64) bool {
+	switch {
+	case a.cmp > 0:
+		return v > a.n
+	case a.cmp < 0:
+		return v < a.n
+	}
+	return v == a.n
+}
+
+// matchAge reports whether an age, counted in whole units with any
+// fraction discarded, matches the argument.
+func (a numArg) matchAge(age, unit time.Duration) bool {
+	n := int64(age / unit)
+	if age < 0 && age%unit != 0 {
+		n--
+	}
+	return a.match(n)
+}
+
+// sizeUnits maps the suffixes of -size to their size in bytes.
+var sizeUnits = map[byte]int64{
+	'b': 512,
+	'c': 1,
+	'w': 2,
+	'k': 1024,
+	'M': 1024 * 1024,
+	'G': 1024 * 1024 * 1024,
+}
+
+// parseSize parses the argument of -size, returning the number and the unit
+// sizes are rounded up to.
+func parseSize(arg string) (numArg, int64, error) {
+	unit := int64(512)
+	if arg != "" {
+		if u, ok := sizeUnits[arg[len(arg)-1]]; ok {
+			unit, arg = u, arg[:len(arg)-1]
+		}
+	}
+
+	n, err := parseNumber(arg)
+	if err != nil {
+		return numArg{}, 0, fmt.Errorf("invalid -size type '%s'", arg)
+	}
+	return n, unit, nil
+}
+
+// typeChar returns the letter -type and %y use for the type of a file.
+func typeChar(mode fs.FileMode) byte {
+	switch {
+	case mode.IsDir():
+		return 'd'
+	case mode&fs.ModeSymlink != 0:
+		return 'l'
+	case mode&fs.ModeNamedPipe != 0:
+		return 'p'
+	case mode&fs.ModeSocket != 0:
+		return 's'
+	case mode&fs.ModeCharDevice != 0:
+		return 'c'
+	case mode&fs.ModeDevice != 0:
+		return 'b'
+	}
+	return 'f'
+}
+
+// isEmpty reports whether the entry is an empty regular file or directory.
+func isEmpty(e *entry) bool {
+	switch {
+	case e.info.Mode().IsRegular():
+		return e.info.Size() == 0
+	case !e.info.IsDir():
+		return false
+	}
+
+	d, err := os.Open(e.path)
+	if err != nil {
+		return false
+	}
+	defer d.Close()
+
+	_, err = d.Readdirnames(1)
+	return err == io.EOF
+}
+
+// globRegexp compiles a shell pattern into a regular expression matching
+// whole strings. With matchSlash, wildcards also match '/'.
+func globRegexp(pattern string, matchSlash, foldCase bool) (*regexp.Regexp, error) {
+	wild := "[^/]"
+	if matchSlash {
+		wild = "."
+	}
+
+	var b strings.Builder
+	b.WriteString("(?s)^")
+	if foldCase {
+		b.WriteString("(?i)")
+	}
+
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; c {
+		case '*':
+			b.WriteString(wild + "*")
+		case '?':
+			b.WriteString(wild)
+		case '\\':
+			if i+1 < len(pattern) {
+				i++
+			}
+			b.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		case '[':
+			end := i + 1
+			if end < len(pattern) && (pattern[end] == '!' || pattern[end] == '^') {
+				end++
+			}
+			if end < len(pattern) && pattern[end] == ']' {
+				end++
+			}
+			for end < len(pattern) && pattern[end] != ']' {
+				end++
+			}
+			if end == len(pattern) {
+				b.WriteString(`\[`)
+				continue
+			}
+
+			class := pattern[i+1 : end]
+			if class[0] == '!' {
+				class = "^" + class[1:]
+			}
+			b.WriteString("[" + strings.ReplaceAll(class, `\`, `\\`) + "]")
+			i = end
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+
+	b.WriteString("$")
+	return regexp.Compile(b.String())
+}
+
+// printfItem is a literal string or a directive of a -printf format.
+type printfItem struct {
+	literal string
+	verb    byte
+	sub     byte
+	width   string
+}
+
+// printfEscapes maps the backslash escapes of -printf to their characters.
+var printfEscapes = map[byte]string{
+	'a': "\a", 'b': "\b", 'f': "\f", 'n': "\n", 'r': "\r", 't': "\t",
+	'v': "\v", '0': "\x00", '\\': "\\",
+}
+
+// parsePrintf splits a -printf format into literals and directives.
+func parsePrintf(format string) []printfItem {
+	items := []printfItem{}
+	lit := strings.Builder{}
+	flush := func() {
+		if lit.Len() > 0 {
+			items = append(items, printfItem{literal: lit.String()})
+			lit.Reset()
+		}
+	}
+
+	for i := 0; i < len(format); i++ {
+		c := format[i]
+		switch {
+		case c == '\\' && i+1 < len(format):
+			i++
+			if s, ok := printfEscapes[format[i]]; ok {
+				lit.WriteString(s)
+			} else {
+				lit.WriteString(format[i-1 : i+1])
+			}
+
+		case c == '%' && i+1 < len(format):
+			start := i + 1
+			j := start
+			for j < len(format) && strings.IndexByte("-0123456789", format[j]) >= 0 {
+				j++
+			}
+			if j == len(format) {
+				lit.WriteString(format[i:])
+				i = j
+				continue
+			}
+			if format[j] == '%' {
+				lit.WriteByte('%')
+				i = j
+				continue
+			}
+
+			flush()
+			item := printfItem{verb: format[j], width: format[start:j]}
+			if item.verb == 'T' && j+1 < len(format) {
+				j++
+				item.sub = format[j]
+			}
+			items = append(items, item)
+			i = j
+
+		default:
+			lit.WriteByte(c)
+		}
+	}
+
+	flush()
+	return items
+}
+
+// formatItem returns the value of a -printf directive for an entry.
+func formatItem(item printfItem, e *entry) string {
+	stat, hasStat := fileinfo.Sys(e.info)
+	statValue := func(v func() string) string {
+		if hasStat {
+			return v()
+		}
+		return "?"
+	}
+
+	switch item.verb {
+	case 'p':
+		return e.path
+	case 'f':
+		return baseName(e.path)
+	case 'h':
+		return dirName(e.path)
+	case 'P':
+		return strings.TrimPrefix(strings.TrimPrefix(e.path, e.root), "/")
+	case 'H':
+		return e.root
+	case 'd':
+		return strconv.Itoa(e.depth)
+	case 's':
+		return strconv.FormatInt(e.info.Size(), 10)
+	case 'b':
+		return statValue(func() string { return strconv.FormatInt(int64(stat.Blocks), 10) })
+	case 'k':
+		return statValue(func() string { return strconv.FormatInt((int64(stat.Blocks)+1)/2, 10) })
+	case 'm':
+		return strconv.FormatUint(uint64(filemode.Bits(e.info.Mode())), 8)
+	case 'M':
+		return e.info.Mode().String()
+	case 'u':
+		return statValue(func() string { return fileinfo.OwnerName(stat) })
+	case 'g':
+		return statValue(func() string { return fileinfo.GroupOwnerName(stat) })
+	case 'U':
+		return statValue(func() string { return strconv.FormatUint(uint64(stat.Uid), 10) })
+	case 'G':
+		return statValue(func() string { return strconv.FormatUint(uint64(stat.Gid), 10) })
+	case 'n':
+		return statValue(func() string { return strconv.FormatUint(uint64(stat.Nlink), 10) })
+	case 'i':
+		return statValue(func() string { return strconv.FormatUint(uint64(stat.Ino), 10) })
+	case 'y':
+		return string(typeChar(e.info.Mode()))
+	case 'l':
+		if e.info.Mode()&fs.ModeSymlink != 0 {
+			target, _ := os.Readlink(e.path)
+			return target
+		}
+		return ""
+	case 't':
+		t := e.info.ModTime()
+		return t.Format("Mon Jan _2 15:04:05") + fraction(t) + t.Format(" 2006")
+	case 'T':
+		return formatTime(e.info.ModTime(), item.sub)
+	}
+
+	return "%" + item.width + string(item.verb)
+}
+
+// timeLayouts maps the %T field letters to time layouts.
+var timeLayouts = map[byte]string{
+	'a': "Mon",
+	'b': "Jan",
+	'd': "02",
+	'D': "01/02/06",
+	'F': "2006-01-02",
+	'H': "15",
+	'M': "04",
+	'S': "05",
+	'T': "15:04:05",
+	'y': "06",
+	'Y': "2006",
+	'm': "01",
+	'+': "2006-01-02+15:04:05",
+}
+
+// formatTime formats a field of a modification time for %T. The fields
+// ending in seconds have a fraction.
+func formatTime(t time.Time, field byte) string {
+	switch field {
+	case '@':
+		return strconv.FormatInt(t.Unix(), 10) + fraction(t)
+	case 'S', 'T', '+':
+		return t.Format(timeLayouts[field]) + fraction(t)
+	}
+	if layout, ok := timeLayouts[field]; ok {
+		return t.Format(layout)
+	}
+	return "%T" + string(field)
+}
+
+// fraction formats the fractional part of the seconds of t as GNU find
+// does, in nanoseconds followed by a 0.
+func fraction(t time.Time) string {
+	return fmt.Sprintf(".%09d0", t.Nanosecond())
+}
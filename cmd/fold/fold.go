@@ -0,0 +1,217 @@
+// Package fold provides functionality for wrapping the lines of files to
+// fit in a given width.
+package fold
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"unicode/utf8"
+
+	"github.com/skraio/unix-utilities/cmdflags"
+	"github.com/skraio/unix-utilities/internal/exit"
+	"github.com/skraio/unix-utilities/internal/fileinput"
+	"github.com/skraio/unix-utilities/internal/lineio"
+	"github.com/spf13/cobra"
+)
+
+// tabWidth is the distance between the tab stops used to count columns.
+const tabWidth = 8
+
+// foldFlags holds flags for fold command.
+type foldFlags struct {
+	bytes  bool
+	spaces bool
+	width  int
+}
+
+var pFlags foldFlags
+
+// flags definition for fold command.
+var flags = []cmdflags.Flag{
+	{Value: &pFlags.bytes, Name: "bytes", ShortHand: "b", DefaultValue: false, Description: "count bytes rather than columns"},
+	{Value: &pFlags.spaces, Name: "spaces", ShortHand: "s", DefaultValue: false, Description: "break at spaces"},
+}
+
+// intFlags definition for fold command.
+var intFlags = []cmdflags.IntFlag{
+	{Value: &pFlags.width, Name: "width", ShortHand: "w", DefaultValue: 80, Description: "use WIDTH columns instead of 80"},
+}
+
+// Cmd represents the 'fold' command configuration using Cobra.
+var Cmd = &cobra.Command{
+	Use:           "fold [-f flags] [file]...",
+	Short:         "Wrap each input line to fit in specified width",
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return exit.Status(executeFold(args))
+	},
+}
+
+// init initializes the 'fold' command by setting up flags.
+func init() {
+	cmdflags.ParseFlags(flags, Cmd)
+	cmdflags.ParseIntFlags(intFlags, Cmd)
+
+	Cmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		fmt.Fprintf(os.Stderr, "fold: %v\n", err)
+		return exit.Status(1)
+	})
+}
+
+// executeFold executes the fold command with given arguments and returns
+// its exit status. Files that cannot be read are reported and skipped.
+func executeFold(args []string) int {
+	switch {
+	case pFlags.width < 0:
+		return exit.Fail("fold", fmt.Errorf("invalid number of columns: '%d'", pFlags.width))
+	case pFlags.width == 0:
+		return exit.Fail("fold", fmt.Errorf("invalid number of columns: '%d': numerical result out of range", pFlags.width))
+	}
+
+	out := bufio.NewWriter(os.Stdout)
+	defer out.Flush()
+	f := &folder{out: out, width: pFlags.width, bytes: pFlags.bytes, spaces: pFlags.spaces}
+
+	status := 0
+	for _, name := range fileinput.Args(args) {
+		if err := f.foldFile(name); err != nil {
+			out.Flush()
+			status = exit.Fail("fold", err)
+		}
+	}
+	return status
+}
+
+// folder breaks lines longer than a width.
+type folder struct {
+	out   *bufio.Writer
+	width int
+
+	// bytes counts a column for every byte, rather than for every
+	// character and with tabs, backspaces and carriage returns moving the
+	// column as they do on a terminal.
+	bytes bool
+
+	// spaces breaks lines after their last blank that fits, when they have
+	// one.
+	spaces bool
+}
+
+// foldFile folds the lines of the named file.
+func (f *folder) foldFile(name string) error {
+	file, err := fileinput.Open(name)
+	if err != nil {
+		return err
+	}
+	defer fileinput.Close(file)
+	return f.fold(file)
+}
+
+// fold folds the lines read from r.
+func (f *folder) fold(r io.Reader) error {
+	lr := lineio.NewReader(r)
+	var pending []byte
+	for {
+		line, err := lr.ReadLine()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		text := lr.TrimDelim(line)
+		pending = f.foldLine(pending[:0], text)
+		pending = append(pending, line[len(text):]...)
+		if _, err := f.out.Write(pending); err != nil {
+			return err
+		}
+	}
+}
+
+// foldLine prints the pieces of line that fill the width, each followed by
+// a newline, and returns the last piece, appended to pending, to be
+// printed with the end of the line.
+func (f *folder) foldLine(pending, line []byte) []byte {
+	column := 0
+	for len(line) > 0 {
+		size := f.charSize(line)
+		char := line[:size]
+		for {
+			next := f.advance(column, char)
+			if next <= f.width {
+				column = next
+				break
+			}
+			if f.spaces {
+				if i := lastBlank(pending); i >= 0 {
+					f.out.Write(pending[:i+1])
+					f.out.WriteByte('\n')
+					pending = append(pending[:0], pending[i+1:]...)
+					column = f.columns(pending)
+					continue
+				}
+			}
+			// A character wider than the width is given a line of its
+			// own.
+			if len(pending) == 0 {
+				column = next
+				break
+			}
+			f.out.Write(pending)
+			f.out.WriteByte('\n')
+			pending, column = pending[:0], 0
+		}
+		pending = append(pending, char...)
+		line = line[size:]
+	}
+	return pending
+}
+
+// charSize returns the size of the character b starts with.
+func (f *folder) charSize(b []byte) int {
+	if f.bytes {
+		return 1
+	}
+	_, size := utf8.DecodeRune(b)
+	return size
+}
+
+// advance returns the column after char, printed at column.
+func (f *folder) advance(column int, char []byte) int {
+	if f.bytes {
+		return column + 1
+	}
+	switch char[0] {
+	case '\b':
+		return max(column-1, 0)
+	case '\r':
+		return 0
+	case '\t':
+		return column + tabWidth - column%tabWidth
+	}
+	return column + 1
+}
+
+// columns returns the column reached printing b.
+func (f *folder) columns(b []byte) int {
+	column := 0
+	for len(b) > 0 {
+		size := f.charSize(b)
+		column = f.advance(column, b[:size])
+		b = b[size:]
+	}
+	return column
+}
+
+// lastBlank returns the index of the last space or tab in b, or -1.
+func lastBlank(b []byte) int {
+	for i := len(b) - 1; i >= 0; i-- {
+		if lineio.IsBlank(b[i]) {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,40 @@
+package fold
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+
+	"github.com/skraio/unix-utilities/internal/assert"
+)
+
+func TestFold(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		width  int
+		bytes  bool
+		spaces bool
+		want   string
+	}{
+		{name: "Width", input: "abcdefghij klm nop\n", width: 5, want: "abcde\nfghij\n klm \nnop\n"},
+		{name: "Spaces", input: "abc def ghi jkl\n", width: 6, spaces: true, want: "abc \ndef \nghi \njkl\n"},
+		{name: "Tabs", input: "a\tb\n", width: 4, want: "a\n\t\nb\n"},
+		{name: "Bytes", input: "a\tb\n", width: 2, bytes: true, want: "a\t\nb\n"},
+		{name: "Multibyte characters", input: "ééééé\n", width: 2, want: "éé\néé\né\n"},
+		{name: "Short lines", input: "ab\ncd", width: 2, want: "ab\ncd"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out strings.Builder
+			w := bufio.NewWriter(&out)
+			f := &folder{out: w, width: tt.width, bytes: tt.bytes, spaces: tt.spaces}
+			if err := f.fold(strings.NewReader(tt.input)); err != nil {
+				t.Fatal(err)
+			}
+			w.Flush()
+			assert.Equal(t, out.String(), tt.want)
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package grep
+
+// acNode is a state of the Aho-Corasick automaton.
+type acNode struct {
+	next map[byte]int32
+	fail int32
+	// out holds the lengths of the patterns ending in this state, including
+	// those reachable through failure links.
+	out []int
+}
+
+// ahoCorasick finds occurrences of many fixed strings in a single pass over
+// the input.
+type ahoCorasick struct {
+	nodes    []acNode
+	foldCase bool
+}
+
+// newAhoCorasick builds the automaton for the given patterns. With foldCase,
+// ASCII letters match regardless of case.
+func newAhoCorasick(patterns []string, foldCase bool) *ahoCorasick {
+	ac := &ahoCorasick{nodes: []acNode{{next: map[byte]int32{}}}, foldCase: foldCase}
+
+	for _, p := range patterns {
+		state := int32(0)
+		for i := 0; i < len(p); i++ {
+			c := ac.fold(p[i])
+			next, ok := ac.nodes[state].next[c]
+			if !ok {
+				next = int32(len(ac.nodes))
+				ac.nodes = append(ac.nodes, acNode{next: map[byte]int32{}})
+				ac.nodes[state].next[c] = next
+			}
+			state = next
+		}
+		ac.nodes[state].out = append(ac.nodes[state].out, len(p))
+	}
+
+	ac.buildFailureLinks()
+	return ac
+}
+
+// buildFailureLinks computes the failure link of every state in breadth-first
+// order and merges the outputs along them.
+func (ac *ahoCorasick) buildFailureLinks() {
+	queue := []int32{}
+	for _, child := range ac.nodes[0].next {
+		queue = append(queue, child)
+	}
+
+	for len(queue) > 0 {
+		state := queue[0]
+		queue = queue[1:]
+
+		for c, child := range ac.nodes[state].next {
+			fail := ac.nodes[state].fail
+			for {
+				if next, ok := ac.nodes[fail].next[c]; ok {
+					ac.nodes[child].fail = next
+					break
+				}
+				if fail == 0 {
+					ac.nodes[child].fail = 0
+					break
+				}
+				fail = ac.nodes[fail].fail
+			}
+
+			ac.nodes[child].out = append(ac.nodes[child].out, ac.nodes[ac.nodes[child].fail].out...)
+			queue = append(queue, child)
+		}
+	}
+}
+
+// fold maps c to its lower case when case folding is enabled.
+func (ac *ahoCorasick) fold(c byte) byte {
+	if ac.foldCase && 'A' <= c && c <= 'Z' {
+		return c + 'a' - 'A'
+	}
+	return c
+}
+
+// findAll returns the start and end offsets of every occurrence of a pattern
+// in text, ordered by start and, for equal starts, longest first.
+func (ac *ahoCorasick) findAll(text []byte) [][2]int {
+	matches := [][2]int{}
+	state := int32(0)
+	for i := 0; i < len(text); i++ {
+		c := ac.fold(text[i])
+		for {
+			if next, ok := ac.nodes[state].next[c]; ok {
+				state = next
+				break
+			}
+			if state == 0 {
+				break
+			}
+			state = ac.nodes[state].fail
+		}
+
+		for _, n := range ac.nodes[state].out {
+			matches = append(matches, [2]int{i + 1 - n, i + 1})
+		}
+	}
+
+	sortMatches(matches)
+	return matches
+}
@@ -0,0 +1,199 @@
+package grep
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/skraio/unix-utilities/internal/posixre"
+)
+
+// matcher finds the matches of the patterns in a line.
+type matcher struct {
+	re *regexp.Regexp
+	ac *ahoCorasick
+	// lines holds the patterns when -F and -x are combined.
+	lines map[string]bool
+	// empty is set when -F is given an empty pattern, which matches any line.
+	empty    bool
+	word     bool
+	foldCase bool
+}
+
+// newMatcher compiles the patterns according to the matching flags.
+func newMatcher(patterns []string, f grepFlags) (*matcher, error) {
+	m := &matcher{word: f.word && !f.lineRegexp, foldCase: f.ignoreCase}
+
+	if f.fixed && (!f.ignoreCase || isASCII(patterns)) {
+		if f.lineRegexp {
+			m.lines = map[string]bool{}
+			for _, p := range patterns {
+				m.lines[m.foldKey(p)] = true
+			}
+			return m, nil
+		}
+
+		nonEmpty := []string{}
+		for _, p := range patterns {
+			if p == "" {
+				m.empty = true
+				continue
+			}
+			nonEmpty = append(nonEmpty, p)
+		}
+		m.ac = newAhoCorasick(nonEmpty, f.ignoreCase)
+		return m, nil
+	}
+
+	alternatives := make([]string, len(patterns))
+	for i, p := range patterns {
+		var err error
+		switch {
+		case f.fixed:
+			alternatives[i] = regexp.QuoteMeta(p)
+		case f.extended:
+			alternatives[i], err = posixre.TranslateERE(p)
+		default:
+			alternatives[i], err = posixre.TranslateBRE(p)
+		}
+		if err != nil {
+			return nil, err
+		}
+		alternatives[i] = "(?:" + alternatives[i] + ")"
+	}
+
+	expr := strings.Join(alternatives, "|")
+	switch {
+	case f.lineRegexp:
+		expr = "^(?:" + expr + ")$"
+	case m.word:
+		expr = `(?:^|[^\pL\pN_])(` + expr + ")"
+	}
+	if f.ignoreCase {
+		expr = "(?i)" + expr
+	}
+
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, err
+	}
+	m.re = re
+
+	return m, nil
+}
+
+// isMatch reports whether line contains a match.
+func (m *matcher) isMatch(line []byte) bool {
+	switch {
+	case m.lines != nil:
+		return m.lines[m.foldKey(string(line))]
+	case m.re != nil && !m.word:
+		return m.re.Match(line)
+	case m.empty && !m.word:
+		return true
+	}
+	return len(m.matches(line)) > 0
+}
+
+// matches returns the start and end offsets of the non-overlapping matches in
+// line, leftmost first.
+func (m *matcher) matches(line []byte) [][2]int {
+	switch {
+	case m.lines != nil:
+		if m.lines[m.foldKey(string(line))] {
+			return [][2]int{{0, len(line)}}
+		}
+		return nil
+	case m.re != nil && m.word:
+		result := [][2]int{}
+		for _, loc := range m.re.FindAllSubmatchIndex(line, -1) {
+			if isWordEnd(line, loc[3]) {
+				result = append(result, [2]int{loc[2], loc[3]})
+			}
+		}
+		return result
+	case m.re != nil:
+		result := [][2]int{}
+		for _, loc := range m.re.FindAllIndex(line, -1) {
+			result = append(result, [2]int{loc[0], loc[1]})
+		}
+		return result
+	}
+
+	result := [][2]int{}
+	end := 0
+	for _, c := range m.ac.findAll(line) {
+		if c[0] < end {
+			continue
+		}
+		if m.word && (!isWordStart(line, c[0]) || !isWordEnd(line, c[1])) {
+			continue
+		}
+		result = append(result, c)
+		end = c[1]
+	}
+	if len(result) == 0 && m.empty && !m.word {
+		result = append(result, [2]int{0, 0})
+	}
+
+	return result
+}
+
+// foldKey returns the key under which s is stored in the -x pattern set.
+func (m *matcher) foldKey(s string) string {
+	if m.foldCase {
+		return strings.ToLower(s)
+	}
+	return s
+}
+
+// sortMatches orders matches by start offset and, for equal starts, puts the
+// longest match first.
+func sortMatches(matches [][2]int) {
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i][0] != matches[j][0] {
+			return matches[i][0] < matches[j][0]
+		}
+		return matches[i][1] > matches[j][1]
+	})
+}
+
+// isASCII reports whether all patterns consist of ASCII characters only.
+func isASCII(patterns []string) bool {
+	for _, p := range patterns {
+		for i := 0; i < len(p); i++ {
+			if p[i] >= utf8.RuneSelf {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// isWordChar reports whether r is a word constituent: a letter, a digit or
+// the underscore.
+func isWordChar(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// isWordStart reports whether a match starting at offset i of line is not
+// preceded by a word constituent.
+func isWordStart(line []byte, i int) bool {
+	if i == 0 {
+		return true
+	}
+	r, _ := utf8.DecodeLastRune(line[:i])
+	return !isWordChar(r)
+}
+
+// isWordEnd reports whether a match ending at offset i of line is not
+// followed by a word constituent.
+func isWordEnd(line []byte, i int) bool {
+	if i == len(line) {
+		return true
+	}
+	r, _ := utf8.DecodeRune(line[i:])
+	return !isWordChar(r)
+}
@@ -0,0 +1,488 @@
+// Package grep provides functionality for printing lines that match
+// patterns.
+package grep
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/skraio/unix-utilities/cmdflags"
+	"github.com/skraio/unix-utilities/internal/exit"
+	"github.com/skraio/unix-utilities/internal/fileinput"
+	"github.com/skraio/unix-utilities/internal/lineio"
+	"github.com/spf13/cobra"
+)
+
+// Terminal color codes, matching the GNU grep defaults.
+const (
+	MatchColor    = "\033[01;31m\033[K"
+	FileColor     = "\033[35m\033[K"
+	LineColor     = "\033[32m\033[K"
+	SeparateColor = "\033[36m\033[K"
+	Reset         = "\033[m\033[K"
+)
+
+// Exit statuses of the grep command.
+const (
+	statusMatch   = 0
+	statusNoMatch = 1
+	statusError   = 2
+)
+
+// binaryPeekSize is the amount of input inspected to detect binary files.
+const binaryPeekSize = 32 * 1024
+
+// grepFlags holds flags for grep command.
+type grepFlags struct {
+	extended          bool
+	fixed             bool
+	ignoreCase        bool
+	invert            bool
+	word              bool
+	lineRegexp        bool
+	count             bool
+	filesWithMatches  bool
+	filesWithoutMatch bool
+	lineNumber        bool
+	onlyMatching      bool
+	recursive         bool
+	quiet             bool
+	noMessages        bool
+	withFilename      bool
+	noFilename        bool
+	text              bool
+	ignoreBinary      bool
+	after             int
+	before            int
+	context           int
+	patternFile       string
+	color             string
+	patterns          []string
+}
+
+var pFlags grepFlags
+
+// flags definition for grep command.
+var flags = []cmdflags.Flag{
+	{Value: &pFlags.extended, Name: "extended-regexp", ShortHand: "E", DefaultValue: false, Description: "PATTERNS are extended regular expressions"},
+	{Value: &pFlags.fixed, Name: "fixed-strings", ShortHand: "F", DefaultValue: false, Description: "PATTERNS are strings"},
+	{Value: &pFlags.ignoreCase, Name: "ignore-case", ShortHand: "i", DefaultValue: false, Description: "ignore case distinctions in patterns and data"},
+	{Value: &pFlags.invert, Name: "invert-match", ShortHand: "v", DefaultValue: false, Description: "select non-matching lines"},
+	{Value: &pFlags.word, Name: "word-regexp", ShortHand: "w", DefaultValue: false, Description: "match only whole words"},
+	{Value: &pFlags.lineRegexp, Name: "line-regexp", ShortHand: "x", DefaultValue: false, Description: "match only whole lines"},
+	{Value: &pFlags.count, Name: "count", ShortHand: "c", DefaultValue: false, Description: "print only a count of selected lines per file"},
+	{Value: &pFlags.filesWithMatches, Name: "files-with-matches", ShortHand: "l", DefaultValue: false, Description: "print only names of files with selected lines"},
+	{Value: &pFlags.filesWithoutMatch, Name: "files-without-match", ShortHand: "L", DefaultValue: false, Description: "print only names of files with no selected lines"},
+	{Value: &pFlags.lineNumber, Name: "line-number", ShortHand: "n", DefaultValue: false, Description: "print line number with output lines"},
+	{Value: &pFlags.onlyMatching, Name: "only-matching", ShortHand: "o", DefaultValue: false, Description: "show only nonempty parts of lines that match"},
+	{Value: &pFlags.recursive, Name: "recursive", ShortHand: "r", DefaultValue: false, Description: "search directories recursively"},
+	{Value: &pFlags.quiet, Name: "quiet", ShortHand: "q", DefaultValue: false, Description: "suppress all normal output"},
+	{Value: &pFlags.noMessages, Name: "no-messages", ShortHand: "s", DefaultValue: false, Description: "suppress error messages"},
+	{Value: &pFlags.withFilename, Name: "with-filename", ShortHand: "H", DefaultValue: false, Description: "print file name with output lines"},
+	{Value: &pFlags.noFilename, Name: "no-filename", ShortHand: "h", DefaultValue: false, Description: "suppress the file name prefix on output"},
+	{Value: &pFlags.text, Name: "text", ShortHand: "a", DefaultValue: false, Description: "process a binary file as if it were text"},
+	{Value: &pFlags.ignoreBinary, Name: "ignore-binary", ShortHand: "I", DefaultValue: false, Description: "assume binary files do not match"},
+}
+
+// stringFlags definition for grep command.
+var stringFlags = []cmdflags.StringFlag{
+	{Value: &pFlags.patternFile, Name: "file", ShortHand: "f", DefaultValue: "", Description: "take PATTERNS from file"},
+	{Value: &pFlags.color, Name: "color", ShortHand: "", DefaultValue: "never", Description: "use markers to highlight the matching strings; WHEN is 'always', 'never', or 'auto'"},
+}
+
+// intFlags definition for grep command.
+var intFlags = []cmdflags.IntFlag{
+	{Value: &pFlags.after, Name: "after-context", ShortHand: "A", DefaultValue: -1, Description: "print NUM lines of trailing context"},
+	{Value: &pFlags.before, Name: "before-context", ShortHand: "B", DefaultValue: -1, Description: "print NUM lines of leading context"},
+	{Value: &pFlags.context, Name: "context", ShortHand: "C", DefaultValue: 0, Description: "print NUM lines of output context"},
+}
+
+// Cmd represents the 'grep' command configuration using Cobra.
+var Cmd = &cobra.Command{
+	Use:           "grep [-f flags] PATTERNS [file]...",
+	Short:         "Print lines that match patterns",
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return exit.Status(executeGrep(args))
+	},
+}
+
+// init initializes the 'grep' command by setting up flags.
+func init() {
+	cmdflags.ParseFlags(flags, Cmd)
+	cmdflags.ParseStringFlags(stringFlags, Cmd)
+	cmdflags.ParseIntFlags(intFlags, Cmd)
+	Cmd.Flags().StringArrayVarP(&pFlags.patterns, "regexp", "e", nil, "use PATTERNS for matching")
+	Cmd.Flags().Lookup("color").NoOptDefVal = "auto"
+	Cmd.PersistentFlags().BoolP("help", "", false, "help for this command")
+
+	Cmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		fmt.Fprintf(os.Stderr, "grep: %v\n", err)
+		return exit.Status(statusError)
+	})
+}
+
+// searcher holds the state shared by the searches of all files.
+type searcher struct {
+	m            *matcher
+	out          *bufio.Writer
+	withFilename bool
+	color        bool
+	before       int
+	after        int
+	// lastFile and lastLine locate the last printed line, used to decide
+	// where "--" group separators go.
+	lastFile string
+	lastLine int
+	printed  bool
+	failed   bool
+}
+
+// executeGrep executes the grep command with given arguments and returns its
+// exit status.
+func executeGrep(args []string) int {
+	patterns, args, err := collectPatterns(args)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "grep: %v\n", err)
+		return statusError
+	}
+
+	m, err := newMatcher(patterns, pFlags)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "grep: %v\n", err)
+		return statusError
+	}
+
+	color, err := useColor(pFlags.color)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "grep: %v\n", err)
+		return statusError
+	}
+
+	implicit := len(args) == 0 && pFlags.recursive
+	if implicit {
+		args = []string{"."}
+	}
+	args = fileinput.Args(args)
+
+	s := &searcher{
+		m:            m,
+		out:          bufio.NewWriter(os.Stdout),
+		withFilename: (len(args) > 1 || pFlags.recursive || pFlags.withFilename) && !pFlags.noFilename,
+		color:        color,
+		before:       contextLength(pFlags.before, pFlags.context),
+		after:        contextLength(pFlags.after, pFlags.context),
+	}
+	defer s.out.Flush()
+
+	matched := false
+	for _, name := range args {
+		if s.searchPath(name, implicit) {
+			matched = true
+			if pFlags.quiet {
+				break
+			}
+		}
+	}
+
+	switch {
+	case matched && pFlags.quiet:
+		return statusMatch
+	case s.failed:
+		return statusError
+	case matched:
+		return statusMatch
+	}
+	return statusNoMatch
+}
+
+// collectPatterns gathers the patterns from -e, -f and, when neither is
+// given, the first operand, returning the remaining operands.
+func collectPatterns(args []string) ([]string, []string, error) {
+	patterns := []string{}
+	for _, p := range pFlags.patterns {
+		patterns = append(patterns, strings.Split(p, "\n")...)
+	}
+
+	if pFlags.patternFile != "" {
+		data, err := os.ReadFile(pFlags.patternFile)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(data) > 0 {
+			patterns = append(patterns, strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")...)
+		}
+	}
+
+	if len(pFlags.patterns) == 0 && pFlags.patternFile == "" {
+		if len(args) == 0 {
+			return nil, nil, fmt.Errorf("no pattern given")
+		}
+		patterns = strings.Split(args[0], "\n")
+		args = args[1:]
+	}
+
+	return patterns, args, nil
+}
+
+// contextLength returns the number of context lines given a specific -A or
+// -B value, which is negative when unset, and the -C value.
+func contextLength(specific, context int) int {
+	if specific >= 0 {
+		return specific
+	}
+	return context
+}
+
+// useColor resolves the --color argument.
+func useColor(when string) (bool, error) {
+	switch when {
+	case "always", "yes", "force":
+		return true, nil
+	case "never", "no", "none":
+		return false, nil
+	case "auto", "tty", "if-tty":
+		fi, err := os.Stdout.Stat()
+		return err == nil && fi.Mode()&os.ModeCharDevice != 0 && os.Getenv("TERM") != "dumb", nil
+	}
+	return false, fmt.Errorf("invalid argument %q for --color", when)
+}
+
+// searchPath searches the named operand, descending into directories when
+// searching recursively. It reports whether any line was selected.
+func (s *searcher) searchPath(name string, implicit bool) bool {
+	if name == fileinput.Stdin {
+		return s.searchFile(os.Stdin, "(standard input)")
+	}
+
+	fi, err := os.Stat(name)
+	if err != nil {
+		s.warn(err)
+		return false
+	}
+
+	if !fi.IsDir() {
+		return s.searchNamed(name, name)
+	}
+	if !pFlags.recursive {
+		s.warn(fmt.Errorf("%s: Is a directory", name))
+		return false
+	}
+
+	matched := false
+	filepath.WalkDir(name, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			s.warn(err)
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		display := path
+		if name == "." && !implicit {
+			display = "./" + path
+		}
+		if s.searchNamed(path, display) {
+			matched = true
+			if pFlags.quiet {
+				return filepath.SkipAll
+			}
+		}
+		return nil
+	})
+
+	return matched
+}
+
+// searchNamed opens and searches a single file.
+func (s *searcher) searchNamed(path, display string) bool {
+	file, err := os.Open(path)
+	if err != nil {
+		s.warn(err)
+		return false
+	}
+	defer file.Close()
+
+	return s.searchFile(file, display)
+}
+
+// searchFile searches the lines of r and prints the results under the given
+// name. It reports whether any line was selected.
+func (s *searcher) searchFile(r io.Reader, name string) bool {
+	br := bufio.NewReaderSize(r, binaryPeekSize)
+	head, _ := br.Peek(binaryPeekSize)
+	binary := !pFlags.text && bytes.IndexByte(head, 0) >= 0
+	if binary && pFlags.ignoreBinary {
+		return false
+	}
+
+	// Only the names or counts are printed in these modes, and a binary
+	// file needs no more than one match unless they are counted.
+	listing := pFlags.quiet || pFlags.count || pFlags.filesWithMatches || pFlags.filesWithoutMatch || binary
+	stopEarly := pFlags.quiet || pFlags.filesWithMatches || pFlags.filesWithoutMatch || binary && !pFlags.count
+
+	type contextLine struct {
+		number int
+		text   []byte
+	}
+	pending := []contextLine{}
+	afterLeft := 0
+	count := 0
+
+	lr := lineio.NewReader(br)
+	for number := 1; ; number++ {
+		line, err := lr.ReadLine()
+		if err != nil {
+			if err != io.EOF {
+				s.warn(fmt.Errorf("%s: %w", name, err))
+			}
+			break
+		}
+		text := lr.TrimDelim(line)
+
+		if s.m.isMatch(text) == pFlags.invert {
+			if listing {
+				continue
+			}
+			if afterLeft > 0 {
+				afterLeft--
+				s.printLine(name, number, text, '-')
+			} else if s.before > 0 {
+				if len(pending) == s.before {
+					pending = pending[1:]
+				}
+				pending = append(pending, contextLine{number, bytes.Clone(text)})
+			}
+			continue
+		}
+
+		count++
+		if stopEarly {
+			break
+		}
+		if listing {
+			continue
+		}
+
+		for _, c := range pending {
+			s.printLine(name, c.number, c.text, '-')
+		}
+		pending = pending[:0]
+		s.printLine(name, number, text, ':')
+		afterLeft = s.after
+	}
+
+	switch {
+	case pFlags.quiet:
+	case pFlags.count:
+		if s.withFilename {
+			s.printFilename(name, ':')
+		}
+		fmt.Fprintln(s.out, count)
+	case pFlags.filesWithMatches && count > 0, pFlags.filesWithoutMatch && count == 0:
+		s.printFilename(name, 0)
+		fmt.Fprintln(s.out)
+	case binary && count > 0:
+		s.out.Flush()
+		fmt.Fprintf(os.Stderr, "grep: %s: binary file matches\n", name)
+	}
+
+	return count > 0
+}
+
+// printLine prints a selected line, marked by ':', or a context line, marked
+// by '-', preceded by a group separator when it does not follow the last
+// printed line.
+func (s *searcher) printLine(name string, number int, text []byte, sep byte) {
+	if s.before > 0 || s.after > 0 {
+		if s.printed && (s.lastFile != name || s.lastLine+1 != number) {
+			s.colorize(SeparateColor, "--")
+			fmt.Fprintln(s.out)
+		}
+		s.lastFile, s.lastLine, s.printed = name, number, true
+	}
+
+	if !pFlags.onlyMatching {
+		s.printPrefix(name, number, sep)
+		if s.color && sep == ':' && !pFlags.invert {
+			s.printHighlighted(text)
+		} else {
+			s.out.Write(text)
+		}
+		fmt.Fprintln(s.out)
+		return
+	}
+
+	if sep != ':' || pFlags.invert {
+		return
+	}
+	for _, loc := range s.m.matches(text) {
+		if loc[0] == loc[1] {
+			continue
+		}
+		s.printPrefix(name, number, sep)
+		s.colorize(MatchColor, string(text[loc[0]:loc[1]]))
+		fmt.Fprintln(s.out)
+	}
+}
+
+// printPrefix prints the file name and line number preceding an output line.
+func (s *searcher) printPrefix(name string, number int, sep byte) {
+	if s.withFilename {
+		s.printFilename(name, sep)
+	}
+	if pFlags.lineNumber {
+		s.colorize(LineColor, strconv.Itoa(number))
+		s.colorize(SeparateColor, string(sep))
+	}
+}
+
+// printFilename prints the file name followed by sep, unless sep is zero.
+func (s *searcher) printFilename(name string, sep byte) {
+	s.colorize(FileColor, name)
+	if sep != 0 {
+		s.colorize(SeparateColor, string(sep))
+	}
+}
+
+// printHighlighted prints text with every match highlighted.
+func (s *searcher) printHighlighted(text []byte) {
+	last := 0
+	for _, loc := range s.m.matches(text) {
+		if loc[0] == loc[1] {
+			continue
+		}
+		s.out.Write(text[last:loc[0]])
+		s.colorize(MatchColor, string(text[loc[0]:loc[1]]))
+		last = loc[1]
+	}
+	s.out.Write(text[last:])
+}
+
+// colorize prints text wrapped in the given color when coloring is enabled.
+func (s *searcher) colorize(color, text string) {
+	if s.color {
+		s.out.WriteString(color + text + Reset)
+		return
+	}
+	s.out.WriteString(text)
+}
+
+// warn reports an error unless -s is given and records that one occurred.
+func (s *searcher) warn(err error) {
+	s.failed = true
+	if pFlags.noMessages {
+		return
+	}
+	s.out.Flush()
+	exit.Fail("grep", err)
+}
@@ -0,0 +1,121 @@
+package grep
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/skraio/unix-utilities/internal/assert"
+)
+
+func TestAhoCorasick(t *testing.T) {
+	tests := []struct {
+		name     string
+		patterns []string
+		foldCase bool
+		text     string
+		want     [][2]int
+	}{
+		{
+			name:     "Overlapping patterns",
+			patterns: []string{"he", "she", "his", "hers"},
+			text:     "ushers",
+			want:     [][2]int{{1, 4}, {2, 6}, {2, 4}},
+		},
+		{
+			name:     "Folded case",
+			patterns: []string{"nest"},
+			foldCase: true,
+			text:     "Without just one NEST",
+			want:     [][2]int{{17, 21}},
+		},
+		{
+			name:     "No match",
+			patterns: []string{"bird"},
+			text:     "Life is your career",
+			want:     [][2]int{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ac := newAhoCorasick(tt.patterns, tt.foldCase)
+			ans := ac.findAll([]byte(tt.text))
+
+			assert.Equal(t, len(ans), len(tt.want))
+			for i := range ans {
+				assert.Equal(t, ans[i], tt.want[i])
+			}
+		})
+	}
+}
+
+func TestMatches(t *testing.T) {
+	tests := []struct {
+		name     string
+		patterns []string
+		flags    grepFlags
+		line     string
+		want     [][2]int
+	}{
+		{
+			name:     "Word regexp",
+			patterns: []string{"foo"},
+			flags:    grepFlags{word: true},
+			line:     "foobar foo foo",
+			want:     [][2]int{{7, 10}, {11, 14}},
+		},
+		{
+			name:     "Fixed word strings",
+			patterns: []string{"-x"},
+			flags:    grepFlags{fixed: true, word: true},
+			line:     "a-x b -x",
+			want:     [][2]int{{6, 8}},
+		},
+		{
+			name:     "Leftmost longest fixed strings",
+			patterns: []string{"bird", "bi", "call"},
+			flags:    grepFlags{fixed: true},
+			line:     "A bird can call",
+			want:     [][2]int{{2, 6}, {11, 15}},
+		},
+		{
+			name:     "Whole line",
+			patterns: []string{"life.*"},
+			flags:    grepFlags{lineRegexp: true, ignoreCase: true},
+			line:     "Life is your career",
+			want:     [][2]int{{0, 19}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := newMatcher(tt.patterns, tt.flags)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ans := m.matches([]byte(tt.line))
+
+			assert.Equal(t, len(ans), len(tt.want))
+			for i := range ans {
+				assert.Equal(t, ans[i], tt.want[i])
+			}
+		})
+	}
+}
+
+func TestCountBinary(t *testing.T) {
+	pFlags = grepFlags{count: true}
+	defer func() { pFlags = grepFlags{} }()
+	m, err := newMatcher([]string{"ab"}, pFlags)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	s := &searcher{m: m, out: bufio.NewWriter(&buf)}
+	assert.Equal(t, s.searchFile(strings.NewReader("ab\x00\nab\nab\n"), "-"), true)
+	s.out.Flush()
+	assert.Equal(t, buf.String(), "3\n")
+}
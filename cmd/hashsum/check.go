@@ -0,0 +1,191 @@
+package hashsum
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"io/fs"
+	"os"
+	"strings"
+
+	"github.com/skraio/unix-utilities/internal/checksum"
+	"github.com/skraio/unix-utilities/internal/exit"
+	"github.com/skraio/unix-utilities/internal/fileinput"
+)
+
+// checker verifies checksum lists, recording failures in its exit status.
+type checker struct {
+	a      algorithm
+	f      *sumFlags
+	status int
+}
+
+// entry is a line of a checksum list: a file to verify, or the warning
+// about a line that could not be parsed.
+type entry struct {
+	line checksum.Line
+	size int
+	warn string
+}
+
+// checkList verifies the files listed in the named checksum list. The
+// files are hashed in parallel and reported in the order of the list.
+func (c *checker) checkList(name string) {
+	display := fileinput.DisplayName(name)
+	if strings.Contains(display, " ") {
+		display = "'" + display + "'"
+	}
+
+	entries, improper, err := c.readList(name, display)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %s: %v\n", c.a.name, name, exit.Unwrap(err))
+		c.status = 1
+		return
+	}
+
+	files, sizes := []string{}, []int{}
+	for _, e := range entries {
+		if e.warn == "" {
+			files = append(files, e.line.Name)
+			sizes = append(sizes, e.size)
+		}
+	}
+	if len(files) == 0 {
+		for _, e := range entries {
+			fmt.Fprintln(os.Stderr, e.warn)
+		}
+		fmt.Fprintf(os.Stderr, "%s: %s: no properly formatted checksum lines found\n", c.a.name, display)
+		c.status = 1
+		return
+	}
+
+	var unreadable, mismatched, verified int
+	next := 0
+	report := func(r checksum.Result) {
+		for ; entries[next].warn != ""; next++ {
+			fmt.Fprintln(os.Stderr, entries[next].warn)
+		}
+		e := entries[next]
+		next++
+
+		if r.Err != nil {
+			if c.f.ignoreMissing && errors.Is(r.Err, fs.ErrNotExist) {
+				return
+			}
+			fmt.Fprintf(os.Stderr, "%s: %s: %v\n", c.a.name, r.Name, exit.Unwrap(r.Err))
+			c.result(r.Name, "FAILED open or read")
+			unreadable++
+			return
+		}
+
+		verified++
+		if bytes.Equal(r.Sum, e.line.Sum) {
+			if !c.f.quiet {
+				c.result(r.Name, "OK")
+			}
+			return
+		}
+		c.result(r.Name, "FAILED")
+		mismatched++
+	}
+	checksum.Files(files, func(i int) hash.Hash { return c.a.newHash(sizes[i]) }, report)
+	for ; next < len(entries); next++ {
+		fmt.Fprintln(os.Stderr, entries[next].warn)
+	}
+
+	if !c.f.status {
+		c.warn(improper, "line is improperly formatted", "lines are improperly formatted")
+		c.warn(unreadable, "listed file could not be read", "listed files could not be read")
+		c.warn(mismatched, "computed checksum did NOT match", "computed checksums did NOT match")
+	}
+	noneVerified := c.f.ignoreMissing && verified == 0 && unreadable == 0
+	if noneVerified {
+		fmt.Fprintf(os.Stderr, "%s: %s: no file was verified\n", c.a.name, display)
+	}
+
+	if unreadable > 0 || mismatched > 0 || (c.f.strict && improper > 0) || noneVerified {
+		c.status = 1
+	}
+}
+
+// readList reads a checksum list, returning its entries and the number of
+// lines that could not be parsed.
+func (c *checker) readList(name, display string) ([]entry, int, error) {
+	f, err := fileinput.Open(name)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer fileinput.Close(f)
+
+	entries := []entry{}
+	improper := 0
+	r := bufio.NewReader(f)
+	for number := 1; ; number++ {
+		text, err := r.ReadString('\n')
+		if text == "" && err != nil {
+			if err == io.EOF {
+				return entries, improper, nil
+			}
+			return nil, 0, err
+		}
+		text = strings.TrimSuffix(text, "\n")
+		if strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		line, ok := checksum.ParseLine(text)
+		size, valid := c.size(line)
+		if ok && valid {
+			entries = append(entries, entry{line: line, size: size})
+			continue
+		}
+
+		improper++
+		if c.f.warn {
+			entries = append(entries, entry{warn: fmt.Sprintf("%s: %s: %d: improperly formatted %s checksum line",
+				c.a.name, display, number, c.a.tag)})
+		}
+	}
+}
+
+// size returns the digest size a checksum line is checked with, and
+// whether the line suits the algorithm: its tag, if any, must name it, and
+// its digest must have the right size.
+func (c *checker) size(line checksum.Line) (int, bool) {
+	size := c.a.size
+	if c.a.variable && c.f.length == 0 {
+		size = len(line.Sum)
+		if size > 64 {
+			return 0, false
+		}
+	}
+	if line.Tag != "" && line.Tag != c.a.tagFor(size) {
+		return 0, false
+	}
+	return size, len(line.Sum) == size
+}
+
+// result prints the outcome of verifying a file, unless --status is given.
+func (c *checker) result(name, outcome string) {
+	if c.f.status {
+		return
+	}
+	name, escaped := checksum.EscapeName(name)
+	if escaped {
+		fmt.Print("\\")
+	}
+	fmt.Printf("%s: %s\n", name, outcome)
+}
+
+// warn prints a warning about count lines or files, if there are any.
+func (c *checker) warn(count int, one, many string) {
+	switch {
+	case count == 1:
+		fmt.Fprintf(os.Stderr, "%s: WARNING: 1 %s\n", c.a.name, one)
+	case count > 1:
+		fmt.Fprintf(os.Stderr, "%s: WARNING: %d %s\n", c.a.name, count, many)
+	}
+}
@@ -0,0 +1,204 @@
+// Package hashsum provides the md5sum, sha1sum, sha256sum, sha512sum and
+// b2sum commands, which print or check message digests of files.
+package hashsum
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"errors"
+	"fmt"
+	"hash"
+	"os"
+
+	"github.com/skraio/unix-utilities/cmdflags"
+	"github.com/skraio/unix-utilities/internal/checksum"
+	"github.com/skraio/unix-utilities/internal/exit"
+	"github.com/skraio/unix-utilities/internal/fileinput"
+	"github.com/spf13/cobra"
+)
+
+// algorithm is a digest computed by one of the commands.
+type algorithm struct {
+	name string
+	tag  string
+
+	// size is the digest size in bytes. It is chosen with --length, and
+	// when checking by each line, if the algorithm is variable.
+	size     int
+	variable bool
+	newHash  func(size int) hash.Hash
+}
+
+// sumFlags holds flags for the hashing commands.
+type sumFlags struct {
+	binary        bool
+	text          bool
+	check         bool
+	tag           bool
+	quiet         bool
+	status        bool
+	strict        bool
+	ignoreMissing bool
+	warn          bool
+	zero          bool
+	length        int
+}
+
+// The algorithms of the commands.
+var (
+	md5Algorithm = algorithm{name: "md5sum", tag: "MD5", size: md5.Size,
+		newHash: func(int) hash.Hash { return md5.New() }}
+	sha1Algorithm = algorithm{name: "sha1sum", tag: "SHA1", size: sha1.Size,
+		newHash: func(int) hash.Hash { return sha1.New() }}
+	sha256Algorithm = algorithm{name: "sha256sum", tag: "SHA256", size: sha256.Size,
+		newHash: func(int) hash.Hash { return sha256.New() }}
+	sha512Algorithm = algorithm{name: "sha512sum", tag: "SHA512", size: sha512.Size,
+		newHash: func(int) hash.Hash { return sha512.New() }}
+	b2Algorithm = algorithm{name: "b2sum", tag: "BLAKE2b", size: 64, variable: true,
+		newHash: checksum.NewBlake2b}
+)
+
+// The commands, one per algorithm.
+var (
+	MD5Cmd    = newCmd(md5Algorithm)
+	SHA1Cmd   = newCmd(sha1Algorithm)
+	SHA256Cmd = newCmd(sha256Algorithm)
+	SHA512Cmd = newCmd(sha512Algorithm)
+	B2Cmd     = newCmd(b2Algorithm)
+)
+
+// newCmd returns the command computing the digests of an algorithm.
+func newCmd(a algorithm) *cobra.Command {
+	f := &sumFlags{}
+	flags := []cmdflags.Flag{
+		{Value: &f.binary, Name: "binary", ShortHand: "b", DefaultValue: false, Description: "read in binary mode"},
+		{Value: &f.check, Name: "check", ShortHand: "c", DefaultValue: false, Description: "read checksums from the files and check them"},
+		{Value: &f.tag, Name: "tag", ShortHand: "", DefaultValue: false, Description: "create a BSD-style checksum"},
+		{Value: &f.text, Name: "text", ShortHand: "t", DefaultValue: false, Description: "read in text mode (default)"},
+		{Value: &f.zero, Name: "zero", ShortHand: "z", DefaultValue: false, Description: "end each output line with NUL, not newline, and disable file name escaping"},
+		{Value: &f.ignoreMissing, Name: "ignore-missing", ShortHand: "", DefaultValue: false, Description: "don't fail or report status for missing files"},
+		{Value: &f.quiet, Name: "quiet", ShortHand: "", DefaultValue: false, Description: "don't print OK for each successfully verified file"},
+		{Value: &f.status, Name: "status", ShortHand: "", DefaultValue: false, Description: "don't output anything, status code shows success"},
+		{Value: &f.strict, Name: "strict", ShortHand: "", DefaultValue: false, Description: "exit non-zero for improperly formatted checksum lines"},
+		{Value: &f.warn, Name: "warn", ShortHand: "w", DefaultValue: false, Description: "warn about improperly formatted checksum lines"},
+	}
+
+	cmd := &cobra.Command{
+		Use:           a.name + " [-f flags] [file]...",
+		Short:         "Compute and check " + a.tag + " message digests",
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return exit.Status(execute(a, f, cmd, fileinput.Args(args)))
+		},
+	}
+	cmdflags.ParseFlags(flags, cmd)
+	if a.variable {
+		cmdflags.ParseIntFlags([]cmdflags.IntFlag{
+			{Value: &f.length, Name: "length", ShortHand: "l", DefaultValue: 0, Description: "digest length in bits; must not exceed the maximum and must be a multiple of 8"},
+		}, cmd)
+	}
+
+	cmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", a.name, err)
+		return exit.Status(1)
+	})
+	return cmd
+}
+
+// execute runs a hashing command with given arguments and returns its exit
+// status.
+func execute(a algorithm, f *sumFlags, cmd *cobra.Command, args []string) int {
+	if a.variable {
+		size, err := digestSize(f.length)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", a.name, err)
+			return 1
+		}
+		a.size = size
+	}
+
+	if err := checkFlags(f, cmd); err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", a.name, err)
+		return 1
+	}
+
+	if f.check {
+		c := &checker{a: a, f: f}
+		for _, name := range args {
+			c.checkList(name)
+		}
+		return c.status
+	}
+
+	status := 0
+	end := "\n"
+	if f.zero {
+		end = "\x00"
+	}
+	checksum.Files(args, func(int) hash.Hash { return a.newHash(a.size) }, func(r checksum.Result) {
+		if r.Err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %s: %v\n", a.name, r.Name, exit.Unwrap(r.Err))
+			status = 1
+			return
+		}
+
+		name, escaped := r.Name, false
+		if !f.zero {
+			name, escaped = checksum.EscapeName(name)
+		}
+		if escaped {
+			fmt.Print("\\")
+		}
+		if f.tag {
+			fmt.Printf("%s (%s) = %x%s", a.tagFor(a.size), name, r.Sum, end)
+		} else if f.binary {
+			fmt.Printf("%x *%s%s", r.Sum, name, end)
+		} else {
+			fmt.Printf("%x  %s%s", r.Sum, name, end)
+		}
+	})
+	return status
+}
+
+// digestSize returns the size in bytes of a digest of the given length in
+// bits, the largest one for 0.
+func digestSize(length int) (int, error) {
+	switch {
+	case length == 0:
+		return 64, nil
+	case length < 0 || length > 512:
+		return 0, fmt.Errorf("invalid length: '%d'", length)
+	case length%8 != 0:
+		return 0, fmt.Errorf("length is not a multiple of 8")
+	}
+	return length / 8, nil
+}
+
+// tagFor returns the BSD-style tag of a digest of size bytes, which names
+// the length when it is not the largest.
+func (a algorithm) tagFor(size int) string {
+	if !a.variable || size == 64 {
+		return a.tag
+	}
+	return fmt.Sprintf("%s-%d", a.tag, size*8)
+}
+
+// checkFlags rejects the flags that only make sense with --check, or
+// without it.
+func checkFlags(f *sumFlags, cmd *cobra.Command) error {
+	if f.check {
+		if f.tag {
+			return errors.New("the --tag option is meaningless when verifying checksums")
+		}
+		return nil
+	}
+	for _, name := range []string{"ignore-missing", "quiet", "status", "strict", "warn"} {
+		if cmd.Flags().Changed(name) {
+			return fmt.Errorf("the --%s option is meaningful only when verifying checksums", name)
+		}
+	}
+	return nil
+}
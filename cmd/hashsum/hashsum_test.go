@@ -0,0 +1,76 @@
+package hashsum
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/skraio/unix-utilities/internal/assert"
+)
+
+func TestDigestSize(t *testing.T) {
+	tests := []struct {
+		length  int
+		want    int
+		wantErr bool
+	}{
+		{length: 0, want: 64},
+		{length: 256, want: 32},
+		{length: 8, want: 1},
+		{length: 12, wantErr: true},
+		{length: 520, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := digestSize(tt.length)
+		assert.Equal(t, got, tt.want)
+		assert.Equal(t, err != nil, tt.wantErr)
+	}
+}
+
+func TestTagFor(t *testing.T) {
+	assert.Equal(t, b2Algorithm.tagFor(64), "BLAKE2b")
+	assert.Equal(t, b2Algorithm.tagFor(32), "BLAKE2b-256")
+	assert.Equal(t, md5Algorithm.tagFor(16), "MD5")
+}
+
+func TestCheckList(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "f")
+	if err := os.WriteFile(file, []byte("hi\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	sum := "764efa883dda1e11db47671c4a3bbd9e"
+
+	tests := []struct {
+		name  string
+		list  string
+		flags sumFlags
+		want  int
+	}{
+		{name: "ok", list: sum + "  " + file + "\n", want: 0},
+		{name: "tagged", list: "MD5 (" + file + ") = " + sum + "\n", want: 0},
+		{name: "mismatch", list: "00000000000000000000000000000000  " + file + "\n", want: 1},
+		{name: "missing", list: sum + "  " + file + ".missing\n", want: 1},
+		{name: "ignore missing", list: sum + "  " + file + "\n" + sum + "  " + file + ".missing\n", flags: sumFlags{ignoreMissing: true}, want: 0},
+		{name: "nothing verified", list: sum + "  " + file + ".missing\n", flags: sumFlags{ignoreMissing: true}, want: 1},
+		{name: "improper", list: sum + "  " + file + "\nbogus\n", want: 0},
+		{name: "strict", list: sum + "  " + file + "\nbogus\n", flags: sumFlags{strict: true}, want: 1},
+		{name: "wrong tag", list: "SHA1 (" + file + ") = " + sum + "\n", want: 1},
+		{name: "short sum", list: sum[:30] + "  " + file + "\n", want: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			list := filepath.Join(dir, "list")
+			if err := os.WriteFile(list, []byte(tt.list), 0644); err != nil {
+				t.Fatal(err)
+			}
+			f := tt.flags
+			f.status = true
+			c := &checker{a: md5Algorithm, f: &f}
+			c.checkList(list)
+			assert.Equal(t, c.status, tt.want)
+		})
+	}
+}
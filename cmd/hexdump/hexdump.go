@@ -0,0 +1,165 @@
+// Package hexdump provides functionality for displaying files in
+// hexadecimal, either as 2-byte words or in the canonical hex+ASCII form.
+package hexdump
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/skraio/unix-utilities/cmdflags"
+	"github.com/skraio/unix-utilities/internal/dump"
+	"github.com/skraio/unix-utilities/internal/exit"
+	"github.com/skraio/unix-utilities/internal/fileinput"
+	"github.com/skraio/unix-utilities/internal/units"
+	"github.com/spf13/cobra"
+)
+
+// lineWidth is the number of bytes shown on a line.
+const lineWidth = 16
+
+// hexdumpFlags holds flags for hexdump command.
+type hexdumpFlags struct {
+	canonical bool
+	verbose   bool
+	skip      string
+	length    string
+}
+
+var pFlags hexdumpFlags
+
+// flags definition for hexdump command.
+var flags = []cmdflags.Flag{
+	{Value: &pFlags.canonical, Name: "canonical", ShortHand: "C", DefaultValue: false, Description: "canonical hex+ASCII display"},
+	{Value: &pFlags.verbose, Name: "no-squeezing", ShortHand: "v", DefaultValue: false, Description: "output identical lines"},
+}
+
+// stringFlags definition for hexdump command.
+var stringFlags = []cmdflags.StringFlag{
+	{Value: &pFlags.skip, Name: "skip", ShortHand: "s", DefaultValue: "", Description: "skip offset bytes from the beginning"},
+	{Value: &pFlags.length, Name: "length", ShortHand: "n", DefaultValue: "", Description: "interpret only length bytes of input"},
+}
+
+// Cmd represents the 'hexdump' command configuration using Cobra.
+var Cmd = &cobra.Command{
+	Use:           "hexdump [-f flags] [file]...",
+	Short:         "Display file contents in hexadecimal",
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return exit.Status(executeHexdump(args))
+	},
+}
+
+// init initializes the 'hexdump' command by setting up flags.
+func init() {
+	cmdflags.ParseFlags(flags, Cmd)
+	cmdflags.ParseStringFlags(stringFlags, Cmd)
+
+	Cmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		fmt.Fprintf(os.Stderr, "hexdump: %v\n", err)
+		return exit.Status(1)
+	})
+}
+
+// executeHexdump executes the hexdump command with given arguments and
+// returns its exit status.
+func executeHexdump(args []string) int {
+	skip, err := parseBytes("offset", pFlags.skip)
+	if err != nil {
+		return exit.Fail("hexdump", err)
+	}
+	length, err := parseBytes("length", pFlags.length)
+	if err != nil {
+		return exit.Fail("hexdump", err)
+	}
+
+	status := 0
+	in := dump.NewFiles(fileinput.Args(args), func(name string, err error) {
+		fmt.Fprintf(os.Stderr, "hexdump: %s: %v\n", name, exit.Unwrap(err))
+		status = 1
+	})
+	in.Skip(skip)
+	if !in.Opened() {
+		fmt.Fprintln(os.Stderr, "hexdump: all input file arguments failed")
+		return 1
+	}
+
+	var r io.Reader = in
+	if pFlags.length != "" {
+		r = io.LimitReader(in, length)
+	}
+	d := dump.Dumper{Width: lineWidth, Squeeze: dump.Repeats, Line: wordLine}
+	if pFlags.canonical {
+		d.Line = canonicalLine
+	}
+	if pFlags.verbose {
+		d.Squeeze = dump.Verbose
+	}
+	end, err := d.Dump(os.Stdout, r, skip)
+	if err != nil {
+		return exit.Fail("hexdump", err)
+	}
+
+	switch {
+	case end == skip:
+	case pFlags.canonical:
+		fmt.Printf("%08x\n", end)
+	default:
+		fmt.Printf("%07x\n", end)
+	}
+	return status
+}
+
+// canonicalLine writes a line in the canonical format: the offset, the
+// bytes in hexadecimal in two groups of eight, and the printable
+// characters between bars.
+func canonicalLine(w *bufio.Writer, offset int64, data []byte) {
+	fmt.Fprintf(w, "%08x", offset)
+	for i := 0; i < lineWidth; i++ {
+		if i%8 == 0 {
+			w.WriteByte(' ')
+		}
+		if i < len(data) {
+			fmt.Fprintf(w, " %02x", data[i])
+		} else {
+			w.WriteString("   ")
+		}
+	}
+
+	w.WriteString("  |")
+	for _, b := range data {
+		w.WriteByte(dump.Printable(b))
+	}
+	w.WriteString("|\n")
+}
+
+// wordLine writes a line in the default format: the offset and the data
+// as little-endian 2-byte words in hexadecimal. An odd last byte is
+// completed with a zero byte.
+func wordLine(w *bufio.Writer, offset int64, data []byte) {
+	fmt.Fprintf(w, "%07x", offset)
+	for i := 0; i < len(data); i += 2 {
+		word := [2]byte{data[i]}
+		if i+1 < len(data) {
+			word[1] = data[i+1]
+		}
+		fmt.Fprintf(w, " %04x", binary.LittleEndian.Uint16(word[:]))
+	}
+	w.WriteByte('\n')
+}
+
+// parseBytes parses a byte count given to an option, which is zero when
+// not given.
+func parseBytes(what, arg string) (int64, error) {
+	if arg == "" {
+		return 0, nil
+	}
+	n, err := units.Parse(arg)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s argument: '%s'", what, arg)
+	}
+	return n, nil
+}
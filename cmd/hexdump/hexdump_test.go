@@ -0,0 +1,36 @@
+package hexdump
+
+import (
+	"bufio"
+	"bytes"
+	"testing"
+
+	"github.com/skraio/unix-utilities/internal/assert"
+)
+
+func TestCanonicalLine(t *testing.T) {
+	tests := []struct {
+		data string
+		want string
+	}{
+		{"hello world\x00\x01\xff\x80a", "00000010  68 65 6c 6c 6f 20 77 6f  72 6c 64 00 01 ff 80 61  |hello world....a|\n"},
+		{"789", "00000010  37 38 39                                          |789|\n"},
+		{"0123456789", "00000010  30 31 32 33 34 35 36 37  38 39                    |0123456789|\n"},
+	}
+
+	for _, test := range tests {
+		var out bytes.Buffer
+		w := bufio.NewWriter(&out)
+		canonicalLine(w, 16, []byte(test.data))
+		w.Flush()
+		assert.Equal(t, out.String(), test.want)
+	}
+}
+
+func TestWordLine(t *testing.T) {
+	var out bytes.Buffer
+	w := bufio.NewWriter(&out)
+	wordLine(w, 32, []byte("hello"))
+	w.Flush()
+	assert.Equal(t, out.String(), "0000020 6568 6c6c 006f\n")
+}
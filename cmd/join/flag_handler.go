@@ -0,0 +1,351 @@
+package join
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/skraio/unix-utilities/internal/lineio"
+)
+
+// Order checking modes.
+const (
+	checkDefault = iota
+	checkStrict
+	checkNone
+)
+
+// outField is an item of the -o format: the join field when file is zero,
+// otherwise field number field of file 1 or 2, counted from zero.
+type outField struct {
+	file  int
+	field int
+}
+
+// record is a line split into fields.
+type record struct {
+	raw    []byte
+	fields [][]byte
+}
+
+// input reads records from one of the two files, checking that the keys
+// appear in order.
+type input struct {
+	lr       *lineio.Reader
+	name     string
+	number   int
+	key      int
+	next     *record
+	prevKey  []byte
+	disorder string
+}
+
+// joiner joins the lines of two files on a common field.
+type joiner struct {
+	out         *bufio.Writer
+	lineEnd     byte
+	tab         int
+	outSep      []byte
+	unpaired    [3]bool
+	printPaired bool
+	format      []outField
+	autoFormat  bool
+	empty       []byte
+	ignoreCase  bool
+	checkOrder  int
+	header      bool
+
+	sawUnpaired bool
+	failed      bool
+}
+
+// parseFormat parses the -o argument, a list of 0 and FILENUM.FIELD items
+// separated by commas or blanks.
+func parseFormat(format string) ([]outField, error) {
+	items := strings.FieldsFunc(format, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t'
+	})
+
+	fields := []outField{}
+	for _, item := range items {
+		if item == "0" {
+			fields = append(fields, outField{})
+			continue
+		}
+
+		file, field, ok := strings.Cut(item, ".")
+		n, err := strconv.Atoi(field)
+		if !ok || (file != "1" && file != "2") || err != nil || n < 1 {
+			return nil, fmt.Errorf("invalid field specifier: %q", item)
+		}
+		fields = append(fields, outField{file: int(file[0] - '0'), field: n - 1})
+	}
+
+	return fields, nil
+}
+
+// split splits line into fields, either at every tab character or at runs
+// of blanks ignoring leading blanks.
+func (j *joiner) split(line []byte) *record {
+	rec := &record{raw: bytes.Clone(line)}
+	if j.tab >= 0 {
+		rec.fields = bytes.Split(rec.raw, []byte{byte(j.tab)})
+		return rec
+	}
+
+	rec.fields = bytes.FieldsFunc(rec.raw, func(r rune) bool {
+		return r == ' ' || r == '\t'
+	})
+	return rec
+}
+
+// keyOf returns the join field of rec, or nil when it has too few fields.
+func keyOf(rec *record, key int) []byte {
+	if key < len(rec.fields) {
+		return rec.fields[key]
+	}
+	return nil
+}
+
+// compareKeys compares two join fields, ignoring case if requested.
+func (j *joiner) compareKeys(a, b []byte) int {
+	if j.ignoreCase {
+		return bytes.Compare(bytes.ToLower(a), bytes.ToLower(b))
+	}
+	return bytes.Compare(a, b)
+}
+
+// read returns the next record of in, or nil at the end of the input.
+func (j *joiner) read(in *input) (*record, error) {
+	if rec := in.next; rec != nil {
+		in.next = nil
+		return rec, nil
+	}
+
+	line, err := in.lr.ReadLine()
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	in.number++
+	line = in.lr.TrimDelim(line)
+	rec := j.split(line)
+
+	key := keyOf(rec, in.key)
+	if j.checkOrder != checkNone && in.prevKey != nil && in.disorder == "" && j.compareKeys(in.prevKey, key) > 0 {
+		in.disorder = fmt.Sprintf("%s:%d: is not sorted: %s", in.name, in.number, line)
+		if j.checkOrder == checkStrict {
+			return nil, fmt.Errorf("%s", in.disorder)
+		}
+	}
+	in.prevKey = append(in.prevKey[:0], key...)
+
+	return rec, nil
+}
+
+// readGroup returns the next run of records of in sharing the same key.
+func (j *joiner) readGroup(in *input) ([]*record, error) {
+	first, err := j.read(in)
+	if err != nil || first == nil {
+		return nil, err
+	}
+
+	group := []*record{first}
+	for {
+		rec, err := j.read(in)
+		if err != nil {
+			return nil, err
+		}
+		if rec == nil {
+			return group, nil
+		}
+		if j.compareKeys(keyOf(first, in.key), keyOf(rec, in.key)) != 0 {
+			in.next = rec
+			return group, nil
+		}
+		group = append(group, rec)
+	}
+}
+
+// join merges the two sorted inputs.
+func (j *joiner) join(in1, in2 *input) error {
+	if j.header {
+		h1, err := j.read(in1)
+		if err != nil {
+			return err
+		}
+		h2, err := j.read(in2)
+		if err != nil {
+			return err
+		}
+		in1.prevKey, in2.prevKey = nil, nil
+		if j.autoFormat && h1 != nil && h2 != nil {
+			j.format = autoFormat(h1, h2, in1.key, in2.key)
+		}
+		if h1 != nil || h2 != nil {
+			j.writeRecord(h1, h2, in1.key, in2.key)
+		}
+	}
+
+	g1, err := j.readGroup(in1)
+	if err != nil {
+		return err
+	}
+	g2, err := j.readGroup(in2)
+	if err != nil {
+		return err
+	}
+
+	for len(g1) > 0 && len(g2) > 0 {
+		if j.autoFormat && j.format == nil {
+			j.format = autoFormat(g1[0], g2[0], in1.key, in2.key)
+		}
+
+		switch c := j.compareKeys(keyOf(g1[0], in1.key), keyOf(g2[0], in2.key)); {
+		case c < 0:
+			j.writeUnpaired(1, g1, in1.key, in2.key)
+			g1, err = j.readGroup(in1)
+		case c > 0:
+			j.writeUnpaired(2, g2, in1.key, in2.key)
+			g2, err = j.readGroup(in2)
+		default:
+			if j.printPaired {
+				for _, r1 := range g1 {
+					for _, r2 := range g2 {
+						j.writeRecord(r1, r2, in1.key, in2.key)
+					}
+				}
+			}
+			if g1, err = j.readGroup(in1); err == nil {
+				g2, err = j.readGroup(in2)
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	for len(g1) > 0 {
+		j.writeUnpaired(1, g1, in1.key, in2.key)
+		if g1, err = j.readGroup(in1); err != nil {
+			return err
+		}
+	}
+	for len(g2) > 0 {
+		j.writeUnpaired(2, g2, in1.key, in2.key)
+		if g2, err = j.readGroup(in2); err != nil {
+			return err
+		}
+	}
+
+	if j.checkOrder == checkDefault && j.sawUnpaired {
+		for _, in := range []*input{in1, in2} {
+			if in.disorder != "" {
+				j.out.Flush()
+				fmt.Fprintf(os.Stderr, "join: %s\n", in.disorder)
+				j.failed = true
+			}
+		}
+		if j.failed {
+			fmt.Fprintln(os.Stderr, "join: input is not in sorted order")
+		}
+	}
+
+	return nil
+}
+
+// autoFormat builds the -o auto format from the number of fields in the
+// first line of each file.
+func autoFormat(r1, r2 *record, key1, key2 int) []outField {
+	format := []outField{{}}
+	for i := range r1.fields {
+		if i != key1 {
+			format = append(format, outField{file: 1, field: i})
+		}
+	}
+	for i := range r2.fields {
+		if i != key2 {
+			format = append(format, outField{file: 2, field: i})
+		}
+	}
+	return format
+}
+
+// writeUnpaired records a group of records of file that have no match in the
+// other file and prints them if requested by -a or -v.
+func (j *joiner) writeUnpaired(file int, group []*record, key1, key2 int) {
+	j.sawUnpaired = true
+	if !j.unpaired[file] {
+		return
+	}
+
+	for _, rec := range group {
+		if file == 1 {
+			j.writeRecord(rec, nil, key1, key2)
+		} else {
+			j.writeRecord(nil, rec, key1, key2)
+		}
+	}
+}
+
+// writeRecord prints the joined line of r1 and r2, either of which may be
+// nil for unpaired lines.
+func (j *joiner) writeRecord(r1, r2 *record, key1, key2 int) {
+	fields := [][]byte{}
+	if j.format != nil {
+		for _, f := range j.format {
+			fields = append(fields, j.formatField(f, r1, r2, key1, key2))
+		}
+	} else {
+		var key []byte
+		if r1 != nil {
+			key = keyOf(r1, key1)
+		} else {
+			key = keyOf(r2, key2)
+		}
+		fields = append(fields, key)
+		fields = appendOthers(fields, r1, key1)
+		fields = appendOthers(fields, r2, key2)
+	}
+
+	j.out.Write(bytes.Join(fields, j.outSep))
+	j.out.WriteByte(j.lineEnd)
+}
+
+// formatField returns the value of a -o item, or the -e string when the
+// field is missing.
+func (j *joiner) formatField(f outField, r1, r2 *record, key1, key2 int) []byte {
+	rec, field := r1, f.field
+	switch {
+	case f.file == 0 && r1 != nil:
+		field = key1
+	case f.file == 0:
+		rec, field = r2, key2
+	case f.file == 2:
+		rec = r2
+	}
+
+	if rec == nil || field >= len(rec.fields) {
+		return j.empty
+	}
+	return rec.fields[field]
+}
+
+// appendOthers appends the fields of rec other than the join field.
+func appendOthers(fields [][]byte, rec *record, key int) [][]byte {
+	if rec == nil {
+		return fields
+	}
+	for i, f := range rec.fields {
+		if i != key {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
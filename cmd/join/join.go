@@ -0,0 +1,183 @@
+// Package join provides functionality for joining lines of two files on a
+// common field.
+package join
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/skraio/unix-utilities/cmdflags"
+	"github.com/skraio/unix-utilities/internal/exit"
+	"github.com/skraio/unix-utilities/internal/fileinput"
+	"github.com/skraio/unix-utilities/internal/lineio"
+	"github.com/spf13/cobra"
+)
+
+// joinFlags holds flags for join command.
+type joinFlags struct {
+	ignoreCase   bool
+	checkOrder   bool
+	noCheckOrder bool
+	header       bool
+	zero         bool
+	field1       int
+	field2       int
+	field        int
+	unpaired     []int
+	only         []int
+	format       string
+	empty        string
+	separator    string
+}
+
+var pFlags joinFlags
+
+// flags definition for join command.
+var flags = []cmdflags.Flag{
+	{Value: &pFlags.ignoreCase, Name: "ignore-case", ShortHand: "i", DefaultValue: false, Description: "ignore differences in case when comparing fields"},
+	{Value: &pFlags.checkOrder, Name: "check-order", ShortHand: "", DefaultValue: false, Description: "check that the input is correctly sorted, even if all input lines are pairable"},
+	{Value: &pFlags.noCheckOrder, Name: "nocheck-order", ShortHand: "", DefaultValue: false, Description: "do not check that the input is correctly sorted"},
+	{Value: &pFlags.header, Name: "header", ShortHand: "", DefaultValue: false, Description: "treat the first line in each file as field headers, print them without trying to pair them"},
+	{Value: &pFlags.zero, Name: "zero-terminated", ShortHand: "z", DefaultValue: false, Description: "line delimiter is NUL, not newline"},
+}
+
+// intFlags definition for join command.
+var intFlags = []cmdflags.IntFlag{
+	{Value: &pFlags.field1, Name: "field1", ShortHand: "1", DefaultValue: 1, Description: "join on this FIELD of file 1"},
+	{Value: &pFlags.field2, Name: "field2", ShortHand: "2", DefaultValue: 1, Description: "join on this FIELD of file 2"},
+	{Value: &pFlags.field, Name: "field", ShortHand: "j", DefaultValue: 0, Description: "equivalent to '-1 FIELD -2 FIELD'"},
+}
+
+// stringFlags definition for join command.
+var stringFlags = []cmdflags.StringFlag{
+	{Value: &pFlags.format, Name: "format", ShortHand: "o", DefaultValue: "", Description: "obey FORMAT while constructing output line, or 'auto'"},
+	{Value: &pFlags.empty, Name: "empty", ShortHand: "e", DefaultValue: "", Description: "replace missing input fields with EMPTY"},
+	{Value: &pFlags.separator, Name: "separator", ShortHand: "t", DefaultValue: "", Description: "use CHAR as input and output field separator"},
+}
+
+// Cmd represents the 'join' command configuration using Cobra.
+var Cmd = &cobra.Command{
+	Use:           "join [-f flags] file1 file2",
+	Short:         "Join lines of two files on a common field",
+	Args:          cobra.ExactArgs(2),
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return exit.Status(executeJoin(cmd, args))
+	},
+}
+
+// init initializes the 'join' command by setting up flags.
+func init() {
+	cmdflags.ParseFlags(flags, Cmd)
+	cmdflags.ParseIntFlags(intFlags, Cmd)
+	cmdflags.ParseStringFlags(stringFlags, Cmd)
+	Cmd.Flags().IntSliceVarP(&pFlags.unpaired, "unpaired", "a", nil, "also print unpairable lines from file FILENUM, where FILENUM is 1 or 2")
+	Cmd.Flags().IntSliceVarP(&pFlags.only, "only-unpaired", "v", nil, "like -a FILENUM, but suppress joined output lines")
+
+	Cmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		fmt.Fprintf(os.Stderr, "join: %v\n", err)
+		return exit.Status(1)
+	})
+}
+
+// executeJoin executes the join command with given arguments and returns its
+// exit status.
+func executeJoin(cmd *cobra.Command, args []string) int {
+	j, err := newJoiner(cmd)
+	if err != nil {
+		return exit.Fail("join", err)
+	}
+
+	if args[0] == fileinput.Stdin && args[1] == fileinput.Stdin {
+		return exit.Fail("join", errors.New("both files cannot be standard input"))
+	}
+
+	var inputs [2]*input
+	keys := [2]int{pFlags.field1 - 1, pFlags.field2 - 1}
+	for i, name := range args {
+		f, err := fileinput.Open(name)
+		if err != nil {
+			return exit.Fail("join", err)
+		}
+		defer fileinput.Close(f)
+
+		inputs[i] = &input{
+			lr:   lineio.NewReaderDelim(f, j.lineEnd),
+			name: name,
+			key:  keys[i],
+		}
+	}
+
+	j.out = bufio.NewWriter(os.Stdout)
+	err = j.join(inputs[0], inputs[1])
+	j.out.Flush()
+	if err != nil {
+		return exit.Fail("join", err)
+	}
+
+	if j.failed {
+		return 1
+	}
+	return 0
+}
+
+// newJoiner builds the joiner from the flags.
+func newJoiner(cmd *cobra.Command) (*joiner, error) {
+	j := &joiner{
+		lineEnd:     '\n',
+		tab:         -1,
+		outSep:      []byte{' '},
+		printPaired: len(pFlags.only) == 0,
+		empty:       []byte(pFlags.empty),
+		ignoreCase:  pFlags.ignoreCase,
+		header:      pFlags.header,
+	}
+	if pFlags.zero {
+		j.lineEnd = 0
+	}
+
+	if cmd.Flags().Changed("field") {
+		pFlags.field1, pFlags.field2 = pFlags.field, pFlags.field
+	}
+	if pFlags.field1 < 1 || pFlags.field2 < 1 {
+		return nil, errors.New("invalid field number")
+	}
+
+	for _, n := range append(pFlags.unpaired, pFlags.only...) {
+		if n != 1 && n != 2 {
+			return nil, fmt.Errorf("invalid file number: %d", n)
+		}
+		j.unpaired[n] = true
+	}
+
+	if cmd.Flags().Changed("separator") {
+		if len(pFlags.separator) != 1 {
+			return nil, fmt.Errorf("multi-character tab %q", pFlags.separator)
+		}
+		j.tab = int(pFlags.separator[0])
+		j.outSep = []byte(pFlags.separator)
+	}
+
+	switch {
+	case pFlags.format == "auto":
+		j.autoFormat = true
+	case pFlags.format != "":
+		format, err := parseFormat(pFlags.format)
+		if err != nil {
+			return nil, err
+		}
+		j.format = format
+	}
+
+	switch {
+	case pFlags.checkOrder:
+		j.checkOrder = checkStrict
+	case pFlags.noCheckOrder:
+		j.checkOrder = checkNone
+	}
+
+	return j, nil
+}
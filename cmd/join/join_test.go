@@ -0,0 +1,102 @@
+package join
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/skraio/unix-utilities/internal/assert"
+	"github.com/skraio/unix-utilities/internal/lineio"
+)
+
+func TestJoin(t *testing.T) {
+	file1 := "1 bird\n2 call\n3 world\n"
+	file2 := "1 nest\n3 home\n3 career\n4 just\n"
+	tests := []struct {
+		name string
+		j    joiner
+		want string
+	}{
+		{
+			name: "Paired lines only",
+			j:    joiner{printPaired: true},
+			want: "1 bird nest\n3 world home\n3 world career\n",
+		},
+		{
+			name: "Unpaired lines of both files",
+			j:    joiner{printPaired: true, unpaired: [3]bool{false, true, true}},
+			want: "1 bird nest\n2 call\n3 world home\n3 world career\n4 just\n",
+		},
+		{
+			name: "Only unpaired lines of file 2",
+			j:    joiner{unpaired: [3]bool{false, false, true}},
+			want: "4 just\n",
+		},
+		{
+			name: "Format with empty fields",
+			j: joiner{
+				printPaired: true,
+				unpaired:    [3]bool{false, true, false},
+				format:      []outField{{file: 2, field: 1}, {}, {file: 1, field: 1}},
+				empty:       []byte("-"),
+			},
+			want: "nest 1 bird\n- 2 call\nhome 3 world\ncareer 3 world\n",
+		},
+		{
+			name: "Auto format",
+			j:    joiner{printPaired: true, unpaired: [3]bool{false, false, true}, autoFormat: true, empty: []byte("-")},
+			want: "1 bird nest\n3 world home\n3 world career\n4 - just\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			j := tt.j
+			j.out = bufio.NewWriter(&out)
+			j.lineEnd = '\n'
+			j.tab = -1
+			j.outSep = []byte{' '}
+
+			in1 := &input{lr: lineio.NewReader(strings.NewReader(file1)), name: "a"}
+			in2 := &input{lr: lineio.NewReader(strings.NewReader(file2)), name: "b"}
+			if err := j.join(in1, in2); err != nil {
+				t.Fatal(err)
+			}
+			j.out.Flush()
+
+			assert.Equal(t, out.String(), tt.want)
+		})
+	}
+}
+
+func TestJoinCheckOrder(t *testing.T) {
+	j := &joiner{printPaired: true, lineEnd: '\n', tab: ':', outSep: []byte(":"), checkOrder: checkStrict}
+	j.out = bufio.NewWriter(&bytes.Buffer{})
+
+	in1 := &input{lr: lineio.NewReader(strings.NewReader("b:1\na:2\n")), name: "a"}
+	in2 := &input{lr: lineio.NewReader(strings.NewReader("a:3\n")), name: "b"}
+	err := j.join(in1, in2)
+	if err == nil {
+		t.Fatal("join succeeded on unsorted input; want error")
+	}
+
+	assert.Equal(t, err.Error(), "a:2: is not sorted: a:2")
+}
+
+func TestParseFormat(t *testing.T) {
+	ans, err := parseFormat("0,2.1 1.3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, len(ans), 3)
+	assert.Equal(t, ans[1], outField{file: 2, field: 0})
+	assert.Equal(t, ans[2], outField{file: 1, field: 2})
+
+	for _, format := range []string{"3.1", "1.0", "1"} {
+		if _, err := parseFormat(format); err == nil {
+			t.Errorf("parseFormat(%q) succeeded; want error", format)
+		}
+	}
+}
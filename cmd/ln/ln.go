@@ -0,0 +1,151 @@
+// Package ln provides functionality for making links between files.
+package ln
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/skraio/unix-utilities/cmdflags"
+	"github.com/skraio/unix-utilities/internal/exit"
+	"github.com/skraio/unix-utilities/internal/filecopy"
+	"github.com/spf13/cobra"
+)
+
+// lnFlags holds flags for ln command.
+type lnFlags struct {
+	symbolic bool
+	force    bool
+	relative bool
+	verbose  bool
+}
+
+var pFlags lnFlags
+
+// flags definition for ln command.
+var flags = []cmdflags.Flag{
+	{Value: &pFlags.symbolic, Name: "symbolic", ShortHand: "s", DefaultValue: false, Description: "make symbolic links instead of hard links"},
+	{Value: &pFlags.force, Name: "force", ShortHand: "f", DefaultValue: false, Description: "remove existing destination files"},
+	{Value: &pFlags.relative, Name: "relative", ShortHand: "r", DefaultValue: false, Description: "create symbolic links relative to link location"},
+	{Value: &pFlags.verbose, Name: "verbose", ShortHand: "v", DefaultValue: false, Description: "print name of each linked file"},
+}
+
+// Cmd represents the 'ln' command configuration using Cobra.
+var Cmd = &cobra.Command{
+	Use:           "ln [-f flags] target... [link_name|directory]",
+	Short:         "Make links between files",
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return exit.Status(executeLn(args))
+	},
+}
+
+// init initializes the 'ln' command by setting up flags.
+func init() {
+	cmdflags.ParseFlags(flags, Cmd)
+
+	Cmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		fmt.Fprintf(os.Stderr, "ln: %v\n", err)
+		return exit.Status(1)
+	})
+}
+
+// executeLn executes the ln command with given arguments and returns its
+// exit status.
+func executeLn(args []string) int {
+	switch {
+	case len(args) == 0:
+		fmt.Fprintln(os.Stderr, "ln: missing file operand")
+		return 1
+	case pFlags.relative && !pFlags.symbolic:
+		fmt.Fprintln(os.Stderr, "ln: cannot do --relative without --symbolic")
+		return 1
+	case len(args) == 1:
+		args = append(args, ".")
+	}
+
+	pairs, err := filecopy.Targets(args)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ln: %v\n", err)
+		return 1
+	}
+
+	status := 0
+	for _, p := range pairs {
+		if err := link(p[0], p[1]); err != nil {
+			fmt.Fprintf(os.Stderr, "ln: %v\n", err)
+			status = 1
+		}
+	}
+	return status
+}
+
+// link makes name a link to target.
+func link(target, name string) error {
+	kind, arrow := "hard link", "=>"
+	if pFlags.symbolic {
+		kind, arrow = "symbolic link", "->"
+	}
+	failed := func(err error) error {
+		err = exit.Unwrap(err)
+		if pFlags.symbolic {
+			return fmt.Errorf("failed to create %s '%s': %v", kind, name, err)
+		}
+		return fmt.Errorf("failed to create %s '%s' %s '%s': %v", kind, name, arrow, target, err)
+	}
+
+	if pFlags.relative {
+		rel, err := relativeTarget(target, name)
+		if err != nil {
+			return failed(err)
+		}
+		target = rel
+	}
+
+	if pFlags.force {
+		if info, err := os.Lstat(name); err == nil {
+			if !pFlags.symbolic {
+				if targetInfo, err := os.Lstat(target); err == nil && os.SameFile(info, targetInfo) {
+					return fmt.Errorf("'%s' and '%s' are the same file", target, name)
+				}
+			}
+			if info.IsDir() {
+				return failed(fs.ErrExist)
+			}
+			if err := os.Remove(name); err != nil {
+				return failed(err)
+			}
+		}
+	}
+
+	var err error
+	if pFlags.symbolic {
+		err = os.Symlink(target, name)
+	} else {
+		err = os.Link(target, name)
+	}
+	if err != nil {
+		return failed(err)
+	}
+
+	if pFlags.verbose {
+		fmt.Printf("'%s' %s '%s'\n", name, arrow, target)
+	}
+	return nil
+}
+
+// relativeTarget returns target as a path relative to the directory of the
+// link name.
+func relativeTarget(target, name string) (string, error) {
+	absTarget, err := filepath.Abs(target)
+	if err != nil {
+		return "", err
+	}
+	absName, err := filepath.Abs(name)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Rel(filepath.Dir(absName), absTarget)
+}
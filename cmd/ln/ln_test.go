@@ -0,0 +1,56 @@
+package ln
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/skraio/unix-utilities/internal/assert"
+)
+
+func TestLink(t *testing.T) {
+	dir := t.TempDir()
+	target := filepath.Join(dir, "target")
+	if err := os.WriteFile(target, []byte("data"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	sub := filepath.Join(dir, "sub")
+	if err := os.Mkdir(sub, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("Hard link", func(t *testing.T) {
+		pFlags = lnFlags{}
+		assert.Equal(t, executeLn([]string{target, filepath.Join(dir, "hard")}), 0)
+		info, err := os.Stat(filepath.Join(dir, "hard"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		targetInfo, err := os.Stat(target)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, os.SameFile(info, targetInfo), true)
+	})
+
+	t.Run("Existing link name", func(t *testing.T) {
+		pFlags = lnFlags{symbolic: true}
+		assert.Equal(t, executeLn([]string{target, filepath.Join(dir, "hard")}), 1)
+	})
+
+	t.Run("Forced relative symbolic link", func(t *testing.T) {
+		pFlags = lnFlags{symbolic: true, relative: true, force: true}
+		assert.Equal(t, executeLn([]string{target, sub}), 0)
+		assert.Equal(t, executeLn([]string{target, sub}), 0)
+		link, err := os.Readlink(filepath.Join(sub, "target"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, link, "../target")
+	})
+
+	t.Run("Relative hard link", func(t *testing.T) {
+		pFlags = lnFlags{relative: true}
+		assert.Equal(t, executeLn([]string{target, filepath.Join(dir, "other")}), 1)
+	})
+}
@@ -9,9 +9,9 @@ import (
 	"os"
 	"sort"
 	"strings"
-	"text/tabwriter"
 
 	"github.com/skraio/unix-utilities/cmdflags"
+	"github.com/skraio/unix-utilities/internal/listing"
 	"github.com/spf13/cobra"
 )
 
@@ -164,14 +164,12 @@ func printList(output []OutputEntry, dir string, n int) {
 		return
 	}
 
-	w := tabwriter.NewWriter(os.Stdout, 0, 0, 1, ' ', tabwriter.AlignRight)
+	w := listing.NewWriter(os.Stdout)
 	defer w.Flush()
 
 	for _, o := range output {
 		a := o.fileAttributes
-		fmt.Fprintf(w, "%s\t\t%s\t\t%s\t\t%s\t\t%s\t\t%s",
-			a.fileMode, a.ulink, a.uid, a.fileSize, a.modTime, o.fileName)
-		fmt.Fprintln(w)
+		listing.Write(w, listing.Columns{Mode: a.fileMode, Links: a.ulink, Owner: a.uid, Size: a.fileSize, Time: a.modTime}, o.fileName)
 	}
 
 	if n > 1 {
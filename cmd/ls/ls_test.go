@@ -83,6 +83,11 @@ func TestHumanReadableSize(t *testing.T) {
 			size: 5*1024*1024 + 12345,
 			want: "5M",
 		},
+		{
+			name: "Gibibytes",
+			size: 3 * 1024 * 1024 * 1024,
+			want: "3072M",
+		},
 	}
 
 	for _, tt := range tests {
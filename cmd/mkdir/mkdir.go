@@ -0,0 +1,136 @@
+// Package mkdir provides functionality for creating directories.
+package mkdir
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/skraio/unix-utilities/cmdflags"
+	"github.com/skraio/unix-utilities/internal/exit"
+	"github.com/skraio/unix-utilities/internal/filemode"
+	"github.com/spf13/cobra"
+)
+
+// mkdirFlags holds flags for mkdir command.
+type mkdirFlags struct {
+	parents bool
+	verbose bool
+	mode    string
+}
+
+var pFlags mkdirFlags
+
+// flags definition for mkdir command.
+var flags = []cmdflags.Flag{
+	{Value: &pFlags.parents, Name: "parents", ShortHand: "p", DefaultValue: false, Description: "no error if existing, make parent directories as needed"},
+	{Value: &pFlags.verbose, Name: "verbose", ShortHand: "v", DefaultValue: false, Description: "print a message for each created directory"},
+}
+
+// stringFlags definition for mkdir command.
+var stringFlags = []cmdflags.StringFlag{
+	{Value: &pFlags.mode, Name: "mode", ShortHand: "m", DefaultValue: "", Description: "set file mode (as in chmod), not a=rwx - umask"},
+}
+
+// Cmd represents the 'mkdir' command configuration using Cobra.
+var Cmd = &cobra.Command{
+	Use:           "mkdir [-f flags] directory...",
+	Short:         "Make directories",
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return exit.Status(executeMkdir(args, cmd.Flags().Changed("mode")))
+	},
+}
+
+// init initializes the 'mkdir' command by setting up flags.
+func init() {
+	cmdflags.ParseFlags(flags, Cmd)
+	cmdflags.ParseStringFlags(stringFlags, Cmd)
+
+	Cmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		fmt.Fprintf(os.Stderr, "mkdir: %v\n", err)
+		return exit.Status(1)
+	})
+}
+
+// executeMkdir executes the mkdir command with given arguments and returns
+// its exit status.
+func executeMkdir(args []string, hasMode bool) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, "mkdir: missing operand")
+		return 1
+	}
+
+	var mode *fs.FileMode
+	if hasMode {
+		m, err := parseMode(pFlags.mode)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "mkdir: %v\n", err)
+			return 1
+		}
+		mode = &m
+	}
+
+	status := 0
+	for _, dir := range args {
+		if err := makeDir(dir, mode, pFlags.parents); err != nil {
+			fmt.Fprintf(os.Stderr, "mkdir: %v\n", err)
+			status = 1
+		}
+	}
+	return status
+}
+
+// parseMode parses a mode as chmod does, applied to a=rwx. As in chmod, the
+// umask limits the clauses that name no class.
+func parseMode(s string) (fs.FileMode, error) {
+	m, err := filemode.Parse(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid mode '%s'", s)
+	}
+	return m.Apply(fs.ModeDir|0777, filemode.Umask()) &^ fs.ModeDir, nil
+}
+
+// makeDir creates a directory with the given mode, or the default one when
+// mode is nil. With parents, missing parent directories are created first
+// and an existing directory is not an error.
+func makeDir(dir string, mode *fs.FileMode, parents bool) error {
+	if parents {
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			return nil
+		}
+		if parent := filepath.Dir(dir); parent != dir {
+			if err := makeDir(parent, nil, true); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := os.Mkdir(dir, 0777); err != nil {
+		if parents && errors.Is(err, fs.ErrExist) {
+			if info, statErr := os.Stat(dir); statErr == nil && info.IsDir() {
+				return nil
+			}
+		}
+		return pathError("cannot create directory", dir, err)
+	}
+	if pFlags.verbose {
+		fmt.Printf("mkdir: created directory '%s'\n", dir)
+	}
+
+	// The mode is set after creation so that the umask does not apply.
+	if mode != nil {
+		if err := os.Chmod(dir, *mode); err != nil {
+			return pathError("cannot set permissions of", dir, err)
+		}
+	}
+	return nil
+}
+
+// pathError returns an error about an operation on path.
+func pathError(op, path string, err error) error {
+	return fmt.Errorf("%s '%s': %v", op, path, exit.Unwrap(err))
+}
@@ -0,0 +1,53 @@
+package mkdir
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/skraio/unix-utilities/internal/assert"
+)
+
+func TestParseMode(t *testing.T) {
+	tests := []struct {
+		mode    string
+		want    fs.FileMode
+		wantErr bool
+	}{
+		{mode: "755", want: 0755},
+		{mode: "0700", want: 0700},
+		{mode: "2775", want: fs.ModeSetgid | 0775},
+		{mode: "1777", want: fs.ModeSticky | 0777},
+		{mode: "u+rwx,go-w", want: 0755},
+		{mode: "g-w", want: 0757},
+		{mode: "a=rx,u+w", want: 0755},
+		{mode: "g+s", want: fs.ModeSetgid | 0777},
+		{mode: "u+q", wantErr: true},
+		{mode: "17777", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			got, err := parseMode(tt.mode)
+			assert.Equal(t, err != nil, tt.wantErr)
+			assert.Equal(t, got, tt.want)
+		})
+	}
+}
+
+func TestMakeDir(t *testing.T) {
+	dir := t.TempDir()
+	mode := fs.FileMode(0750)
+
+	assert.Equal(t, makeDir(filepath.Join(dir, "a/b/c"), &mode, false) != nil, true)
+	assert.Equal(t, makeDir(filepath.Join(dir, "a/b/c"), &mode, true), nil)
+	assert.Equal(t, makeDir(filepath.Join(dir, "a/b/c"), &mode, true), nil)
+	assert.Equal(t, makeDir(filepath.Join(dir, "a"), nil, false) != nil, true)
+
+	info, err := os.Stat(filepath.Join(dir, "a/b/c"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, info.Mode().Perm(), mode)
+}
@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/skraio/unix-utilities/internal/exit"
+	"github.com/spf13/cobra"
+)
+
+// multicallFlags holds the flags of the root command, which manage the
+// links through which the commands run under their own names.
+type multicallFlags struct {
+	install  string
+	symlink  bool
+	hardlink bool
+	list     bool
+}
+
+var mFlags multicallFlags
+
+// initMulticall sets up the flags of the root command and its action,
+// which refers to the commands added to it.
+func initMulticall() {
+	rootCmd.RunE = func(cmd *cobra.Command, args []string) error {
+		return exit.Status(executeRoot(cmd))
+	}
+	rootCmd.Flags().StringVar(&mFlags.install, "install", "", "create links named after the commands to this executable in `DIR`")
+	rootCmd.Flags().BoolVar(&mFlags.symlink, "symlink", false, "install symbolic links")
+	rootCmd.Flags().BoolVar(&mFlags.hardlink, "hardlink", false, "install hard links (the default)")
+	rootCmd.Flags().BoolVar(&mFlags.list, "list", false, "list the commands")
+	rootCmd.MarkFlagsMutuallyExclusive("symlink", "hardlink")
+}
+
+// executeRoot runs the root command, which lists the commands, installs
+// links to them or else shows its usage, and returns its exit status.
+func executeRoot(cmd *cobra.Command) int {
+	switch {
+	case mFlags.list:
+		for _, c := range applets() {
+			fmt.Println(c.Name())
+		}
+		return 0
+	case mFlags.install != "":
+		return install(mFlags.install, mFlags.symlink)
+	}
+	cmd.Usage()
+	return 0
+}
+
+// applets returns the commands run by name, without those cobra adds.
+func applets() []*cobra.Command {
+	var cmds []*cobra.Command
+	for _, c := range rootCmd.Commands() {
+		if c.IsAvailableCommand() && c.Name() != "help" && c.Name() != "completion" {
+			cmds = append(cmds, c)
+		}
+	}
+	return cmds
+}
+
+// applet returns the command named name, or nil when there is none.
+func applet(name string) *cobra.Command {
+	for _, c := range applets() {
+		if c.Name() == name {
+			return c
+		}
+	}
+	return nil
+}
+
+// install creates a link to this executable in dir for each command,
+// symbolic if asked and hard otherwise. Links already there are kept.
+func install(dir string, symlink bool) int {
+	exe, err := os.Executable()
+	if err == nil {
+		exe, err = filepath.EvalSymlinks(exe)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "unix-utils: cannot find the executable: %v\n", err)
+		return 1
+	}
+	exeInfo, err := os.Stat(exe)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "unix-utils: %v\n", err)
+		return 1
+	}
+
+	if _, err := os.Stat(dir); err != nil {
+		fmt.Fprintf(os.Stderr, "unix-utils: cannot install in '%s': %v\n", dir, exit.Unwrap(err))
+		return 1
+	}
+
+	status := 0
+	for _, c := range applets() {
+		link := filepath.Join(dir, c.Name())
+		if info, err := os.Stat(link); err == nil && os.SameFile(info, exeInfo) {
+			continue
+		}
+
+		if symlink {
+			err = os.Symlink(exe, link)
+		} else {
+			err = os.Link(exe, link)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "unix-utils: cannot create link '%s': %v\n", link, exit.Unwrap(err))
+			status = 1
+		}
+	}
+	return status
+}
@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/skraio/unix-utilities/internal/assert"
+)
+
+func TestApplet(t *testing.T) {
+	assert.Equal(t, applet("wc").Name(), "wc")
+	assert.Equal(t, applet("help") == nil, true)
+	assert.Equal(t, applet("unix-utils") == nil, true)
+}
+
+func TestInstall(t *testing.T) {
+	exe, err := os.Executable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	exeInfo, err := os.Stat(exe)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, symlink := range []bool{false, true} {
+		// Hard links need the directory on the file system of the test
+		// executable.
+		dir, err := os.MkdirTemp(filepath.Dir(exe), "links")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.RemoveAll(dir)
+		assert.Equal(t, install(dir, symlink), 0)
+
+		for _, c := range applets() {
+			info, err := os.Stat(filepath.Join(dir, c.Name()))
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, os.SameFile(info, exeInfo), true)
+		}
+
+		// Links already there are kept.
+		assert.Equal(t, install(dir, symlink), 0)
+	}
+}
+
+// capture returns what run prints on the standard output, with its status.
+func capture(t *testing.T, run func() int) (string, int) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	status := run()
+	os.Stdout = stdout
+	w.Close()
+	out, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(out), status
+}
+
+func TestBuiltinRunsAfresh(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "f")
+	if err := os.WriteFile(name, []byte("a b\nc\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// wc sets all its counts itself when given none, which must not carry
+	// over to the next run.
+	run := builtin("wc")
+	words, _ := capture(t, func() int { return run([]string{"-w", name}) })
+	all, _ := capture(t, func() int { return run([]string{name}) })
+	again, status := capture(t, func() int { return run([]string{"-w", name}) })
+
+	assert.Equal(t, status, 0)
+	assert.Equal(t, again, words)
+	assert.Equal(t, strings.Count(all, "|"), 2*strings.Count(words, "|"))
+}
@@ -0,0 +1,82 @@
+// Package mv provides functionality for moving and renaming files.
+package mv
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/skraio/unix-utilities/cmdflags"
+	"github.com/skraio/unix-utilities/internal/exit"
+	"github.com/skraio/unix-utilities/internal/filecopy"
+	"github.com/spf13/cobra"
+)
+
+// mvFlags holds flags for mv command.
+type mvFlags struct {
+	force     bool
+	noClobber bool
+	update    bool
+	verbose   bool
+}
+
+var pFlags mvFlags
+
+// flags definition for mv command.
+var flags = []cmdflags.Flag{
+	{Value: &pFlags.force, Name: "force", ShortHand: "f", DefaultValue: false, Description: "do not prompt before overwriting"},
+	{Value: &pFlags.noClobber, Name: "no-clobber", ShortHand: "n", DefaultValue: false, Description: "do not overwrite an existing file"},
+	{Value: &pFlags.update, Name: "update", ShortHand: "u", DefaultValue: false, Description: "move only when the source file is newer than the destination file or when the destination file is missing"},
+	{Value: &pFlags.verbose, Name: "verbose", ShortHand: "v", DefaultValue: false, Description: "explain what is being done"},
+}
+
+// Cmd represents the 'mv' command configuration using Cobra.
+var Cmd = &cobra.Command{
+	Use:           "mv [-f flags] source... dest",
+	Short:         "Move (rename) files",
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return exit.Status(executeMv(args))
+	},
+}
+
+// init initializes the 'mv' command by setting up flags.
+func init() {
+	cmdflags.ParseFlags(flags, Cmd)
+
+	Cmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		fmt.Fprintf(os.Stderr, "mv: %v\n", err)
+		return exit.Status(1)
+	})
+}
+
+// executeMv executes the mv command with given arguments and returns its
+// exit status.
+func executeMv(args []string) int {
+	pairs, err := filecopy.Targets(args)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "mv: %v\n", err)
+		return 1
+	}
+
+	opts := filecopy.Options{
+		NoClobber: pFlags.noClobber && !pFlags.force,
+		Update:    pFlags.update,
+		Report: func(err error) {
+			fmt.Fprintf(os.Stderr, "mv: %v\n", err)
+		},
+	}
+	if pFlags.verbose {
+		opts.Verbose = func(src, dst string) {
+			fmt.Printf("renamed '%s' -> '%s'\n", src, dst)
+		}
+	}
+
+	status := 0
+	for _, p := range pairs {
+		if !filecopy.Move(p[0], p[1], opts) {
+			status = 1
+		}
+	}
+	return status
+}
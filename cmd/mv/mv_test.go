@@ -0,0 +1,60 @@
+package mv
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/skraio/unix-utilities/internal/assert"
+)
+
+func TestMove(t *testing.T) {
+	dir := t.TempDir()
+	create := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	exists := func(name string) bool {
+		_, err := os.Lstat(filepath.Join(dir, name))
+		return err == nil
+	}
+
+	sub := filepath.Join(dir, "sub")
+	if err := os.Mkdir(sub, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("Rename", func(t *testing.T) {
+		pFlags = mvFlags{}
+		assert.Equal(t, executeMv([]string{create("a", "1"), filepath.Join(dir, "b")}), 0)
+		assert.Equal(t, exists("a"), false)
+		assert.Equal(t, exists("b"), true)
+	})
+
+	t.Run("Into directory with a missing operand", func(t *testing.T) {
+		pFlags = mvFlags{}
+		args := []string{create("c", "2"), filepath.Join(dir, "missing"), sub}
+		assert.Equal(t, executeMv(args), 1)
+		assert.Equal(t, exists("sub/c"), true)
+	})
+
+	t.Run("No clobber", func(t *testing.T) {
+		pFlags = mvFlags{noClobber: true}
+		assert.Equal(t, executeMv([]string{create("d", "new"), filepath.Join(dir, "b")}), 0)
+		content, err := os.ReadFile(filepath.Join(dir, "b"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, string(content), "1")
+		assert.Equal(t, exists("d"), true)
+	})
+
+	t.Run("Into itself", func(t *testing.T) {
+		pFlags = mvFlags{}
+		assert.Equal(t, executeMv([]string{sub, filepath.Join(sub, "inner")}), 1)
+		assert.Equal(t, exists("sub"), true)
+	})
+}
@@ -0,0 +1,268 @@
+// Package nl provides functionality for numbering the lines of files, which
+// are divided into logical pages of header, body and footer sections.
+package nl
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/skraio/unix-utilities/cmdflags"
+	"github.com/skraio/unix-utilities/internal/exit"
+	"github.com/skraio/unix-utilities/internal/fileinput"
+	"github.com/skraio/unix-utilities/internal/lineio"
+	"github.com/skraio/unix-utilities/internal/linenum"
+	"github.com/spf13/cobra"
+)
+
+// The sections of a logical page.
+const (
+	header = iota
+	body
+	footer
+)
+
+// sectionNames are used in messages about the style of each section.
+var sectionNames = [3]string{"header", "body", "footer"}
+
+// nlFlags holds flags for nl command.
+type nlFlags struct {
+	noRenumber bool
+	styles     [3]string
+	delimiter  string
+	format     string
+	separator  string
+	increment  int
+	joinBlank  int
+	start      int
+	width      int
+}
+
+var pFlags nlFlags
+
+// flags definition for nl command.
+var flags = []cmdflags.Flag{
+	{Value: &pFlags.noRenumber, Name: "no-renumber", ShortHand: "p", DefaultValue: false, Description: "do not reset line numbers for each section"},
+}
+
+// stringFlags definition for nl command.
+var stringFlags = []cmdflags.StringFlag{
+	{Value: &pFlags.styles[body], Name: "body-numbering", ShortHand: "b", DefaultValue: "t", Description: "use STYLE for numbering body lines: a (all), t (non-empty), n (none) or pBRE (matching BRE)"},
+	{Value: &pFlags.delimiter, Name: "section-delimiter", ShortHand: "d", DefaultValue: `\:`, Description: "use CC for logical page delimiters"},
+	{Value: &pFlags.styles[footer], Name: "footer-numbering", ShortHand: "f", DefaultValue: "n", Description: "use STYLE for numbering footer lines"},
+	{Value: &pFlags.styles[header], Name: "header-numbering", ShortHand: "h", DefaultValue: "n", Description: "use STYLE for numbering header lines"},
+	{Value: &pFlags.format, Name: "number-format", ShortHand: "n", DefaultValue: "rn", Description: "insert line numbers according to FORMAT: ln, rn or rz"},
+	{Value: &pFlags.separator, Name: "number-separator", ShortHand: "s", DefaultValue: "\t", Description: "add STRING after (possible) line number"},
+}
+
+// intFlags definition for nl command.
+var intFlags = []cmdflags.IntFlag{
+	{Value: &pFlags.increment, Name: "line-increment", ShortHand: "i", DefaultValue: 1, Description: "line number increment at each line"},
+	{Value: &pFlags.joinBlank, Name: "join-blank-lines", ShortHand: "l", DefaultValue: 1, Description: "group of NUMBER empty lines counted as one"},
+	{Value: &pFlags.start, Name: "starting-line-number", ShortHand: "v", DefaultValue: 1, Description: "first line number for each section"},
+	{Value: &pFlags.width, Name: "number-width", ShortHand: "w", DefaultValue: 6, Description: "use NUMBER columns for line numbers"},
+}
+
+// Cmd represents the 'nl' command configuration using Cobra.
+var Cmd = &cobra.Command{
+	Use:           "nl [-f flags] [file]...",
+	Short:         "Number lines of files",
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return exit.Status(executeNl(args))
+	},
+}
+
+// init initializes the 'nl' command by setting up flags.
+func init() {
+	cmdflags.ParseFlags(flags, Cmd)
+	cmdflags.ParseStringFlags(stringFlags, Cmd)
+	cmdflags.ParseIntFlags(intFlags, Cmd)
+	Cmd.PersistentFlags().BoolP("help", "", false, "help for this command")
+
+	Cmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		fmt.Fprintf(os.Stderr, "nl: %v\n", err)
+		return exit.Status(1)
+	})
+}
+
+// executeNl executes the nl command with given arguments and returns its
+// exit status. Files that cannot be read are reported and skipped.
+func executeNl(args []string) int {
+	n, err := newNumberer()
+	if err != nil {
+		return exit.Fail("nl", err)
+	}
+
+	n.out = bufio.NewWriter(os.Stdout)
+	defer n.out.Flush()
+
+	status := 0
+	for _, name := range fileinput.Args(args) {
+		if err := n.numberFile(name); err != nil {
+			n.out.Flush()
+			status = exit.Fail("nl", err)
+		}
+	}
+	return status
+}
+
+// newNumberer builds the numberer from the flags.
+func newNumberer() (*numberer, error) {
+	n := &numberer{
+		width:     pFlags.width,
+		separator: pFlags.separator,
+		joinBlank: pFlags.joinBlank,
+		renumber:  !pFlags.noRenumber,
+		section:   body,
+		counter:   linenum.NewCounter(int64(pFlags.start), int64(pFlags.increment)),
+	}
+
+	for i, arg := range pFlags.styles {
+		style, err := linenum.ParseStyle(arg)
+		if errors.Is(err, linenum.ErrStyle) {
+			return nil, fmt.Errorf("invalid %s numbering style: '%s'", sectionNames[i], arg)
+		}
+		if err != nil {
+			return nil, err
+		}
+		n.styles[i] = style
+	}
+
+	format, err := linenum.ParseFormat(pFlags.format)
+	if err != nil {
+		return nil, err
+	}
+	n.format = format
+
+	if n.width < 1 {
+		return nil, fmt.Errorf("invalid line number field width: '%d': %v", n.width, errRange)
+	}
+	if n.joinBlank < 1 {
+		return nil, fmt.Errorf("invalid line number of blank lines: '%d': %v", n.joinBlank, errRange)
+	}
+
+	// A single character delimiter is completed with the default second
+	// one, while an empty one turns sections off.
+	n.delimiter = pFlags.delimiter
+	if len(n.delimiter) == 1 {
+		n.delimiter += ":"
+	}
+	n.unnumbered = strings.Repeat(" ", n.width+len(n.separator))
+	return n, nil
+}
+
+// errRange is the reason given for numeric arguments out of range.
+var errRange = errors.New("numerical result out of range")
+
+// numberer numbers lines, reading the inputs as one stream.
+type numberer struct {
+	out *bufio.Writer
+
+	// styles selects the lines numbered in each section.
+	styles    [3]linenum.Style
+	format    linenum.Format
+	width     int
+	separator string
+
+	// unnumbered is printed before lines without a number, in place of
+	// the number and its separator.
+	unnumbered string
+
+	// delimiter marks the start of a section, three times in a row for a
+	// header, twice for a body and once for a footer.
+	delimiter string
+
+	// joinBlank is the number of consecutive empty lines counted as one
+	// when numbering all lines, and blanks those seen since the last
+	// numbered line.
+	joinBlank int
+	blanks    int
+
+	renumber bool
+	section  int
+	counter  *linenum.Counter
+}
+
+// numberFile numbers the lines of the named file.
+func (n *numberer) numberFile(name string) error {
+	f, err := fileinput.Open(name)
+	if err != nil {
+		return err
+	}
+	defer fileinput.Close(f)
+	return n.number(f)
+}
+
+// number numbers the lines read from r.
+func (n *numberer) number(r io.Reader) error {
+	lr := lineio.NewReader(r)
+	for {
+		line, err := lr.ReadLine()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		n.line(lr.TrimDelim(line))
+	}
+}
+
+// line prints a line, numbered or not, or starts a new section when the
+// line is a delimiter.
+func (n *numberer) line(line []byte) {
+	if section, ok := n.sectionStart(line); ok {
+		n.section = section
+		if n.renumber {
+			n.counter.Reset()
+		}
+		n.out.WriteByte('\n')
+		return
+	}
+
+	if n.selects(line) {
+		n.out.WriteString(n.format.Pad(n.counter.Next(), n.width))
+		n.out.WriteString(n.separator)
+	} else {
+		n.out.WriteString(n.unnumbered)
+	}
+	n.out.Write(line)
+	n.out.WriteByte('\n')
+}
+
+// selects reports whether line is numbered in the current section. When
+// numbering all lines, only the last of each group of joined empty lines
+// is.
+func (n *numberer) selects(line []byte) bool {
+	style := n.styles[n.section]
+	if !style.Selects(line) {
+		return false
+	}
+	if style.IsAll() && n.joinBlank > 1 && len(line) == 0 {
+		n.blanks++
+		if n.blanks < n.joinBlank {
+			return false
+		}
+	}
+	n.blanks = 0
+	return true
+}
+
+// sectionStart returns the section started by line, when it is made of
+// the delimiter alone repeated one to three times.
+func (n *numberer) sectionStart(line []byte) (int, bool) {
+	d := len(n.delimiter)
+	if d == 0 || len(line)%d != 0 || len(line) > 3*d || len(line) == 0 {
+		return 0, false
+	}
+	if !bytes.Equal(line, bytes.Repeat([]byte(n.delimiter), len(line)/d)) {
+		return 0, false
+	}
+	return 3 - len(line)/d, true
+}
@@ -0,0 +1,111 @@
+package nl
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+
+	"github.com/skraio/unix-utilities/internal/assert"
+)
+
+// sections is an input with a body, a header, another body and a footer.
+const sections = "a\n\nb\n\\:\\:\\:\nh\n\\:\\:\nc\n\n\n\nd\n\\:\nf\n"
+
+func TestNumber(t *testing.T) {
+	defaults := nlFlags{
+		styles:    [3]string{"n", "t", "n"},
+		delimiter: `\:`,
+		format:    "rn",
+		separator: "\t",
+		increment: 1,
+		joinBlank: 1,
+		start:     1,
+		width:     6,
+	}
+
+	tests := []struct {
+		name  string
+		flags func(f *nlFlags)
+		input string
+		want  string
+	}{
+		{
+			name:  "Defaults",
+			flags: func(f *nlFlags) {},
+			input: sections,
+			want:  "     1\ta\n       \n     2\tb\n\n       h\n\n     1\tc\n       \n       \n       \n     2\td\n\n       f\n",
+		},
+		{
+			name: "All lines joining blanks",
+			flags: func(f *nlFlags) {
+				f.styles[1] = "a"
+				f.joinBlank = 2
+				f.format = "rz"
+				f.width = 3
+				f.separator = ":"
+				f.start = 5
+				f.increment = 2
+			},
+			input: sections,
+			want:  "005:a\n    \n007:b\n\n    h\n\n005:c\n    \n007:\n    \n009:d\n\n    f\n",
+		},
+		{
+			name: "Regular expression without renumbering",
+			flags: func(f *nlFlags) {
+				f.styles = [3]string{"a", "pb", "a"}
+				f.format = "ln"
+				f.noRenumber = true
+			},
+			input: sections,
+			want:  "       a\n       \n1     \tb\n\n2     \th\n\n       c\n       \n       \n       \n       d\n\n3     \tf\n",
+		},
+		{
+			name:  "Sections off",
+			flags: func(f *nlFlags) { f.delimiter = "" },
+			input: "a\n\\:\nb\n",
+			want:  "     1\ta\n     2\t\\:\n     3\tb\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pFlags = defaults
+			tt.flags(&pFlags)
+			defer func() { pFlags = nlFlags{} }()
+
+			n, err := newNumberer()
+			if err != nil {
+				t.Fatal(err)
+			}
+			var out strings.Builder
+			n.out = bufio.NewWriter(&out)
+			if err := n.number(strings.NewReader(tt.input)); err != nil {
+				t.Fatal(err)
+			}
+			n.out.Flush()
+			assert.Equal(t, out.String(), tt.want)
+		})
+	}
+}
+
+func TestNewNumbererErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		flags nlFlags
+		err   string
+	}{
+		{name: "Style", flags: nlFlags{styles: [3]string{"n", "x", "n"}, format: "rn", width: 6, joinBlank: 1}, err: "invalid body numbering style: 'x'"},
+		{name: "Format", flags: nlFlags{styles: [3]string{"n", "t", "n"}, format: "lz", width: 6, joinBlank: 1}, err: "invalid line numbering format: 'lz'"},
+		{name: "Width", flags: nlFlags{styles: [3]string{"n", "t", "n"}, format: "rn", width: 0, joinBlank: 1}, err: "invalid line number field width: '0': numerical result out of range"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pFlags = tt.flags
+			defer func() { pFlags = nlFlags{} }()
+
+			_, err := newNumberer()
+			assert.Equal(t, err.Error(), tt.err)
+		})
+	}
+}
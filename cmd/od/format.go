@@ -0,0 +1,281 @@
+package od
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/skraio/unix-utilities/internal/dump"
+)
+
+// addressFormat is the format of the offsets starting the lines.
+type addressFormat struct {
+	base  int
+	width int
+}
+
+// addressFormats are the address formats by radix letter. Addresses are
+// not shown with the radix n.
+var addressFormats = map[string]addressFormat{
+	"d": {base: 10, width: 7},
+	"o": {base: 8, width: 7},
+	"x": {base: 16, width: 6},
+	"n": {},
+}
+
+// spec is an output type: how each line of input is shown on a line of
+// output.
+type spec struct {
+	// kind is the letter of the type: a, c, d, f, o, u or x.
+	kind byte
+
+	// size is the number of bytes of each field.
+	size int
+
+	// trailer shows the printable characters of the line after its fields.
+	trailer bool
+
+	// width is the width of each field, not counting the space before it.
+	width int
+
+	// pad is the number of spaces spread between the fields of a line, so
+	// that the fields of all types line up.
+	pad int
+}
+
+// integerSizes maps the letters naming the sizes of C integer types to
+// their sizes.
+var integerSizes = map[byte]int{'C': 1, 'S': 2, 'I': 4, 'L': 8}
+
+// floatSizes maps the letters naming the sizes of C floating point types
+// to their sizes.
+var floatSizes = map[byte]int{'F': 4, 'D': 8, 'L': 16}
+
+// fieldWidths maps the integer types to the widths of their fields by
+// size, wide enough for any value.
+var fieldWidths = map[byte]map[int]int{
+	'd': {1: 4, 2: 6, 4: 11, 8: 20},
+	'o': {1: 3, 2: 6, 4: 11, 8: 22},
+	'u': {1: 3, 2: 5, 4: 10, 8: 20},
+	'x': {1: 2, 2: 4, 4: 8, 8: 16},
+	'f': {4: 15, 8: 24},
+}
+
+// parseTypes parses a type string: one or more type letters, each followed
+// by an optional size and an optional z for a trailer.
+func parseTypes(arg string) ([]*spec, error) {
+	specs := []*spec{}
+	s := arg
+	for len(s) > 0 {
+		sp := &spec{kind: s[0]}
+		s = s[1:]
+
+		switch sp.kind {
+		case 'a', 'c':
+			sp.size, sp.width = 1, 3
+		case 'd', 'o', 'u', 'x', 'f':
+			sizes, defaultSize, typeName := integerSizes, 4, "integral"
+			if sp.kind == 'f' {
+				sizes, defaultSize, typeName = floatSizes, 8, "floating point"
+			}
+
+			sp.size = defaultSize
+			end := 0
+			for end < len(s) && s[end] >= '0' && s[end] <= '9' {
+				end++
+			}
+			if end > 0 {
+				sp.size, _ = strconv.Atoi(s[:end])
+				s = s[end:]
+			} else if len(s) > 0 && sizes[s[0]] != 0 {
+				sp.size = sizes[s[0]]
+				s = s[1:]
+			}
+
+			width, ok := fieldWidths[sp.kind][sp.size]
+			if !ok {
+				return nil, fmt.Errorf("invalid type string '%s';\nthis system doesn't provide a %d-byte %s type", arg, sp.size, typeName)
+			}
+			sp.width = width
+		default:
+			return nil, fmt.Errorf("invalid character '%c' in type string '%s'", sp.kind, arg)
+		}
+
+		if strings.HasPrefix(s, "z") {
+			sp.trailer = true
+			s = s[1:]
+		}
+		specs = append(specs, sp)
+	}
+	return specs, nil
+}
+
+// unitSize returns the smallest number of bytes making whole fields of all
+// types: the largest of their sizes, which are powers of two.
+func unitSize(specs []*spec) int {
+	unit := 1
+	for _, s := range specs {
+		unit = max(unit, s.size)
+	}
+	return unit
+}
+
+// defaultWidth returns the number of bytes of a line when not given: 16,
+// or more if the fields of the types are larger.
+func defaultWidth(specs []*spec) int {
+	return max(16, unitSize(specs))
+}
+
+// layout lays out the lines of a dump.
+type layout struct {
+	specs []*spec
+	width int
+	radix addressFormat
+	buf   []byte
+}
+
+// newLayout returns the layout of lines of width bytes shown with the
+// given types, the fields of which are padded to line up.
+func newLayout(specs []*spec, width int, address addressFormat) *layout {
+	lineWidth := 0
+	for _, s := range specs {
+		lineWidth = max(lineWidth, (s.width+1)*(width/s.size))
+	}
+	for _, s := range specs {
+		s.pad = lineWidth - (s.width+1)*(width/s.size)
+	}
+	return &layout{specs: specs, width: width, radix: address, buf: make([]byte, width)}
+}
+
+// address formats an offset.
+func (l *layout) address(offset int64) string {
+	s := strconv.FormatInt(offset, l.radix.base)
+	return strings.Repeat("0", max(l.radix.width-len(s), 0)) + s
+}
+
+// line writes the line of data at offset, with one line of output per
+// type. The fields of a short last line are completed with zero bytes.
+func (l *layout) line(w *bufio.Writer, offset int64, data []byte) {
+	clear(l.buf)
+	copy(l.buf, data)
+
+	for i, s := range l.specs {
+		switch {
+		case l.radix.base == 0:
+		case i == 0:
+			w.WriteString(l.address(offset))
+		default:
+			w.WriteString(strings.Repeat(" ", l.radix.width))
+		}
+
+		fields := l.width / s.size
+		shown := (len(data) + s.size - 1) / s.size
+		remaining := s.pad
+		for j := 0; j < shown; j++ {
+			next := s.pad * (fields - j - 1) / fields
+			value := s.format(l.buf[j*s.size : (j+1)*s.size])
+			w.WriteString(strings.Repeat(" ", 1+max(remaining-next+s.width-len(value), 0)))
+			w.WriteString(value)
+			remaining = next
+		}
+
+		if s.trailer {
+			blank := (l.width - len(data)) / s.size
+			w.WriteString(strings.Repeat(" ", blank*(s.width+1)+remaining))
+			w.WriteString("  >")
+			for _, b := range data {
+				w.WriteByte(dump.Printable(b))
+			}
+			w.WriteString("<")
+		}
+		w.WriteByte('\n')
+	}
+}
+
+// names are the names of the ASCII control characters and space.
+var names = [...]string{
+	"nul", "soh", "stx", "etx", "eot", "enq", "ack", "bel",
+	"bs", "ht", "nl", "vt", "ff", "cr", "so", "si",
+	"dle", "dc1", "dc2", "dc3", "dc4", "nak", "syn", "etb",
+	"can", "em", "sub", "esc", "fs", "gs", "rs", "us", "sp",
+}
+
+// escapes are the backslash escapes of characters shown with type c.
+var escapes = map[byte]string{
+	0: `\0`, '\a': `\a`, '\b': `\b`, '\t': `\t`, '\n': `\n`, '\v': `\v`, '\f': `\f`, '\r': `\r`,
+}
+
+// format formats the field of the type held by b.
+func (s *spec) format(b []byte) string {
+	switch s.kind {
+	case 'a':
+		c := b[0] & 0x7f
+		switch {
+		case int(c) < len(names):
+			return names[c]
+		case c == 0x7f:
+			return "del"
+		}
+		return string(rune(c))
+	case 'c':
+		if e, ok := escapes[b[0]]; ok {
+			return e
+		}
+		if b[0] >= ' ' && b[0] <= '~' {
+			return string(rune(b[0]))
+		}
+		return fmt.Sprintf("%03o", b[0])
+	case 'f':
+		if s.size == 4 {
+			return formatFloat(float64(math.Float32frombits(binary.LittleEndian.Uint32(b))), 32)
+		}
+		return formatFloat(math.Float64frombits(binary.LittleEndian.Uint64(b)), 64)
+	}
+
+	var v uint64
+	for i := len(b) - 1; i >= 0; i-- {
+		v = v<<8 | uint64(b[i])
+	}
+	switch s.kind {
+	case 'd':
+		shift := 64 - 8*uint(s.size)
+		return strconv.FormatInt(int64(v<<shift)>>shift, 10)
+	case 'o':
+		return fmt.Sprintf("%0*o", s.width, v)
+	case 'x':
+		return fmt.Sprintf("%0*x", s.width, v)
+	}
+	return strconv.FormatUint(v, 10)
+}
+
+// formatFloat formats a floating point number of the given bit size with
+// as few digits as read back to the same number, but no fewer than the
+// type's guaranteed precision, like C's %g does.
+func formatFloat(v float64, bitSize int) string {
+	switch {
+	case math.IsNaN(v) && math.Signbit(v):
+		return "-nan"
+	case math.IsNaN(v):
+		return "nan"
+	case math.IsInf(v, 1):
+		return "inf"
+	case math.IsInf(v, -1):
+		return "-inf"
+	}
+
+	shortest := strconv.FormatFloat(v, 'e', -1, bitSize)
+	mantissa := strings.TrimPrefix(shortest[:strings.IndexByte(shortest, 'e')], "-")
+	digits := len(strings.Replace(mantissa, ".", "", 1))
+
+	precision, smallest := 15, 0x1p-1022
+	if bitSize == 32 {
+		precision, smallest = 6, 0x1p-126
+	}
+	if math.Abs(v) < smallest {
+		precision = 1
+	}
+	return strconv.FormatFloat(v, 'g', max(precision, digits), bitSize)
+}
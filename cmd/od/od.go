@@ -0,0 +1,225 @@
+// Package od provides functionality for dumping files in octal and other
+// formats, with one or more output types shown for each line of input.
+package od
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/skraio/unix-utilities/cmdflags"
+	"github.com/skraio/unix-utilities/internal/dump"
+	"github.com/skraio/unix-utilities/internal/exit"
+	"github.com/skraio/unix-utilities/internal/fileinput"
+	"github.com/skraio/unix-utilities/internal/units"
+	"github.com/spf13/cobra"
+)
+
+// odFlags holds flags for od command.
+type odFlags struct {
+	radix   string
+	skip    string
+	count   string
+	verbose bool
+	width   int
+	types   []string
+}
+
+var pFlags odFlags
+
+// flags definition for od command.
+var flags = []cmdflags.Flag{
+	{Value: &pFlags.verbose, Name: "output-duplicates", ShortHand: "v", DefaultValue: false, Description: "do not use * to mark line suppression"},
+}
+
+// stringFlags definition for od command.
+var stringFlags = []cmdflags.StringFlag{
+	{Value: &pFlags.radix, Name: "address-radix", ShortHand: "A", DefaultValue: "o", Description: "output format for file offsets; RADIX is one of [doxn]"},
+	{Value: &pFlags.skip, Name: "skip-bytes", ShortHand: "j", DefaultValue: "", Description: "skip BYTES input bytes first"},
+	{Value: &pFlags.count, Name: "read-bytes", ShortHand: "N", DefaultValue: "", Description: "limit dump to BYTES input bytes"},
+}
+
+// intFlags definition for od command.
+var intFlags = []cmdflags.IntFlag{
+	{Value: &pFlags.width, Name: "width", ShortHand: "w", DefaultValue: 16, Description: "output BYTES bytes per output line; 32 is implied when BYTES is not specified"},
+}
+
+// traditional lists the single-letter options standing for output types.
+// Having no long names, they are named by their letter.
+var traditional = []struct{ shortHand, types, description string }{
+	{"a", "a", "same as -t a, select named characters, ignoring high-order bit"},
+	{"b", "o1", "same as -t o1, select octal bytes"},
+	{"c", "c", "same as -t c, select printable characters or backslash escapes"},
+	{"d", "u2", "same as -t u2, select unsigned decimal 2-byte units"},
+	{"f", "fF", "same as -t fF, select floats"},
+	{"i", "dI", "same as -t dI, select decimal ints"},
+	{"l", "dL", "same as -t dL, select decimal longs"},
+	{"o", "o2", "same as -t o2, select octal 2-byte units"},
+	{"s", "d2", "same as -t d2, select decimal 2-byte units"},
+	{"x", "x2", "same as -t x2, select hexadecimal 2-byte units"},
+}
+
+// Cmd represents the 'od' command configuration using Cobra. Flag parsing
+// is done in RunE so that the optional width can be attached to -w.
+var Cmd = &cobra.Command{
+	Use:                "od [-f flags] [file]...",
+	Short:              "Dump files in octal and other formats",
+	DisableFlagParsing: true,
+	SilenceErrors:      true,
+	SilenceUsage:       true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := cmd.Flags().Parse(attachWidth(args)); err != nil {
+			fmt.Fprintf(os.Stderr, "od: %v\n", err)
+			return exit.Status(1)
+		}
+		if help, _ := cmd.Flags().GetBool("help"); help {
+			return cmd.Help()
+		}
+		return exit.Status(executeOd(cmd.Flags().Args(), cmd.Flags().Changed("width")))
+	},
+}
+
+// typeFlag is a flag adding output types to a list kept in the order of
+// the command line. The flags of traditional options add fixed types.
+type typeFlag struct {
+	types *[]string
+	fixed string
+}
+
+// String returns the default value of the flag.
+func (t typeFlag) String() string { return "" }
+
+// Set adds the types given.
+func (t typeFlag) Set(value string) error {
+	if t.fixed != "" {
+		value = t.fixed
+	}
+	*t.types = append(*t.types, value)
+	return nil
+}
+
+// Reset drops the types added, for the command to run again.
+func (t typeFlag) Reset() { *t.types = nil }
+
+// Type returns the type of the flag's value.
+func (t typeFlag) Type() string {
+	if t.fixed != "" {
+		return "bool"
+	}
+	return "string"
+}
+
+// init initializes the 'od' command by setting up flags.
+func init() {
+	cmdflags.ParseFlags(flags, Cmd)
+	cmdflags.ParseStringFlags(stringFlags, Cmd)
+	cmdflags.ParseIntFlags(intFlags, Cmd)
+	Cmd.Flags().Lookup("width").NoOptDefVal = "32"
+
+	Cmd.Flags().VarP(typeFlag{types: &pFlags.types}, "format", "t", "select output format or formats")
+	for _, t := range traditional {
+		flag := Cmd.Flags().VarPF(typeFlag{types: &pFlags.types, fixed: t.types}, t.shortHand, t.shortHand, t.description)
+		flag.NoOptDefVal = "true"
+	}
+
+}
+
+// attachWidth rewrites the options -wBYTES as --width=BYTES, which pflag
+// does not take for a flag with an optional value.
+func attachWidth(args []string) []string {
+	rewritten := make([]string, len(args))
+	copy(rewritten, args)
+	for i, arg := range args {
+		if arg == "--" {
+			break
+		}
+		if strings.HasPrefix(arg, "-w") && len(arg) > 2 && arg[2] != '=' {
+			rewritten[i] = "--width=" + arg[2:]
+		}
+	}
+	return rewritten
+}
+
+// executeOd executes the od command with given arguments and returns its
+// exit status. widthSet tells whether the width of lines was given.
+func executeOd(args []string, widthSet bool) int {
+	address, ok := addressFormats[pFlags.radix]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "od: invalid output address radix '%s'; it must be one character from [doxn]\n", pFlags.radix)
+		return 1
+	}
+
+	types := pFlags.types
+	if len(types) == 0 {
+		types = []string{"o2"}
+	}
+	specs := []*spec{}
+	for _, t := range types {
+		s, err := parseTypes(t)
+		if err != nil {
+			return exit.Fail("od", err)
+		}
+		specs = append(specs, s...)
+	}
+
+	skip, err := parseBytes("-j", pFlags.skip)
+	if err != nil {
+		return exit.Fail("od", err)
+	}
+	count, err := parseBytes("-N", pFlags.count)
+	if err != nil {
+		return exit.Fail("od", err)
+	}
+
+	width := defaultWidth(specs)
+	if widthSet {
+		width = pFlags.width
+		if unit := unitSize(specs); width < 1 || width%unit != 0 {
+			fmt.Fprintf(os.Stderr, "od: warning: invalid width %d; using %d instead\n", width, unit)
+			width = unit
+		}
+	}
+	l := newLayout(specs, width, address)
+
+	status := 0
+	in := dump.NewFiles(fileinput.Args(args), func(name string, err error) {
+		fmt.Fprintf(os.Stderr, "od: %s: %v\n", name, exit.Unwrap(err))
+		status = 1
+	})
+	if in.Skip(skip) > 0 {
+		fmt.Fprintln(os.Stderr, "od: cannot skip past end of combined input")
+		return 1
+	}
+
+	var r io.Reader = in
+	if pFlags.count != "" {
+		r = io.LimitReader(in, count)
+	}
+	d := dump.Dumper{Width: l.width, Squeeze: dump.Repeats, Line: l.line}
+	if pFlags.verbose {
+		d.Squeeze = dump.Verbose
+	}
+	end, err := d.Dump(os.Stdout, r, skip)
+	if err != nil {
+		return exit.Fail("od", err)
+	}
+
+	if address.base != 0 && in.Opened() {
+		fmt.Println(l.address(end))
+	}
+	return status
+}
+
+// parseBytes parses the byte count given to option, which is zero when
+// not given.
+func parseBytes(option, arg string) (int64, error) {
+	if arg == "" {
+		return 0, nil
+	}
+	n, err := units.Parse(arg)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s argument '%s'", option, arg)
+	}
+	return n, nil
+}
@@ -0,0 +1,88 @@
+package od
+
+import (
+	"bufio"
+	"bytes"
+	"testing"
+
+	"github.com/skraio/unix-utilities/internal/assert"
+)
+
+func TestParseTypes(t *testing.T) {
+	specs, err := parseTypes("x1zdSfFa")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, len(specs), 4)
+
+	want := []spec{
+		{kind: 'x', size: 1, width: 2, trailer: true},
+		{kind: 'd', size: 2, width: 6},
+		{kind: 'f', size: 4, width: 15},
+		{kind: 'a', size: 1, width: 3},
+	}
+	for i, s := range specs {
+		assert.Equal(t, *s, want[i])
+	}
+
+	for _, arg := range []string{"x3", "f2", "q", "z"} {
+		_, err := parseTypes(arg)
+		assert.Equal(t, err != nil, true)
+	}
+}
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		types string
+		data  string
+		want  string
+	}{
+		{"a", "\x00", "nul"},
+		{"a", " ", "sp"},
+		{"a", "\xff", "del"},
+		{"a", "\xc1", "A"},
+		{"c", "\n", `\n`},
+		{"c", "\x80", "200"},
+		{"c", "a", "a"},
+		{"d1", "\xff", "-1"},
+		{"d2", "\x00\x80", "-32768"},
+		{"u2", "\x00\x80", "32768"},
+		{"o2", "\x08\x00", "000010"},
+		{"x4", "\x01\x02\x03\x04", "04030201"},
+		{"f4", "\x00\x00\x80\x3f", "1"},
+		{"f8", "\x00\x00\x00\x00\x00\x00\x59\x40", "100"},
+	}
+
+	for _, test := range tests {
+		specs, err := parseTypes(test.types)
+		assert.Equal(t, err, nil)
+		assert.Equal(t, specs[0].format([]byte(test.data)), test.want)
+	}
+}
+
+func TestFormatFloat(t *testing.T) {
+	assert.Equal(t, formatFloat(1e6, 64), "1000000")
+	assert.Equal(t, formatFloat(1e15, 64), "1e+15")
+	assert.Equal(t, formatFloat(0.1, 64), "0.1")
+	assert.Equal(t, formatFloat(1.0/3, 64), "0.3333333333333333")
+	assert.Equal(t, formatFloat(float64(float32(0.1)), 32), "0.1")
+	assert.Equal(t, formatFloat(5e-324, 64), "5e-324")
+}
+
+func TestLayout(t *testing.T) {
+	specs := []*spec{}
+	for _, types := range []string{"d4", "cz"} {
+		s, err := parseTypes(types)
+		assert.Equal(t, err, nil)
+		specs = append(specs, s...)
+	}
+
+	var out bytes.Buffer
+	w := bufio.NewWriter(&out)
+	l := newLayout(specs, 8, addressFormats["x"])
+	l.line(w, 16, []byte("hello"))
+	w.Flush()
+
+	want := "000010      1819043176             111\n" +
+		"         h   e   l   l   o              >hello<\n"
+	assert.Equal(t, out.String(), want)
+	assert.Equal(t, l.address(8), "000008")
+}
@@ -0,0 +1,100 @@
+package paste
+
+import (
+	"errors"
+	"io"
+
+	"github.com/skraio/unix-utilities/internal/lineio"
+)
+
+// parseDelimiters parses the -d list, where \n, \t, \\ and \0 (an empty
+// delimiter) are escapes.
+func parseDelimiters(list string) ([]string, error) {
+	delims := []string{}
+	for i := 0; i < len(list); i++ {
+		if list[i] != '\\' {
+			delims = append(delims, string(list[i]))
+			continue
+		}
+
+		i++
+		if i == len(list) {
+			return nil, errors.New("delimiter list ends with an unescaped backslash")
+		}
+		switch list[i] {
+		case 'n':
+			delims = append(delims, "\n")
+		case 't':
+			delims = append(delims, "\t")
+		case '0':
+			delims = append(delims, "")
+		default:
+			delims = append(delims, string(list[i]))
+		}
+	}
+
+	if len(delims) == 0 {
+		delims = append(delims, "")
+	}
+	return delims, nil
+}
+
+// pasteParallel writes lines built from the corresponding lines of every
+// reader, separated by the delimiters in turn, until all readers are
+// exhausted.
+func pasteParallel(w io.Writer, readers []*lineio.Reader, delims []string, lineEnd byte) error {
+	done := make([]bool, len(readers))
+	for {
+		out := []byte{}
+		read := false
+		for i, lr := range readers {
+			if !done[i] {
+				line, err := lr.ReadLine()
+				switch {
+				case err == io.EOF:
+					done[i] = true
+				case err != nil:
+					return err
+				default:
+					out = append(out, lr.TrimDelim(line)...)
+					read = true
+				}
+			}
+			if i < len(readers)-1 {
+				out = append(out, delims[i%len(delims)]...)
+			}
+		}
+
+		if !read {
+			return nil
+		}
+		out = append(out, lineEnd)
+		if _, err := w.Write(out); err != nil {
+			return err
+		}
+	}
+}
+
+// pasteSerial writes all lines of r on a single line, separated by the
+// delimiters in turn.
+func pasteSerial(w io.Writer, lr *lineio.Reader, delims []string, lineEnd byte) error {
+	out := []byte{}
+	for n := 0; ; n++ {
+		line, err := lr.ReadLine()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		if n > 0 {
+			out = append(out, delims[(n-1)%len(delims)]...)
+		}
+		out = append(out, lr.TrimDelim(line)...)
+	}
+
+	out = append(out, lineEnd)
+	_, err := w.Write(out)
+	return err
+}
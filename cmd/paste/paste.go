@@ -0,0 +1,108 @@
+// Package paste provides functionality for merging lines of files.
+package paste
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+
+	"github.com/skraio/unix-utilities/cmdflags"
+	"github.com/skraio/unix-utilities/internal/exit"
+	"github.com/skraio/unix-utilities/internal/fileinput"
+	"github.com/skraio/unix-utilities/internal/lineio"
+	"github.com/spf13/cobra"
+)
+
+// pasteFlags holds flags for paste command.
+type pasteFlags struct {
+	serial     bool
+	zero       bool
+	delimiters string
+}
+
+var pFlags pasteFlags
+
+// flags definition for paste command.
+var flags = []cmdflags.Flag{
+	{Value: &pFlags.serial, Name: "serial", ShortHand: "s", DefaultValue: false, Description: "paste one file at a time instead of in parallel"},
+	{Value: &pFlags.zero, Name: "zero-terminated", ShortHand: "z", DefaultValue: false, Description: "line delimiter is NUL, not newline"},
+}
+
+// stringFlags definition for paste command.
+var stringFlags = []cmdflags.StringFlag{
+	{Value: &pFlags.delimiters, Name: "delimiters", ShortHand: "d", DefaultValue: `\t`, Description: "reuse characters from LIST instead of TABs"},
+}
+
+// Cmd represents the 'paste' command configuration using Cobra.
+var Cmd = &cobra.Command{
+	Use:           "paste [-f flags] [file]...",
+	Short:         "Merge lines of files",
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := executePaste(fileinput.Args(args)); err != nil {
+			return exit.Status(exit.Fail("paste", err))
+		}
+		return nil
+	},
+}
+
+// init initializes the 'paste' command by setting up flags.
+func init() {
+	cmdflags.ParseFlags(flags, Cmd)
+	cmdflags.ParseStringFlags(stringFlags, Cmd)
+
+	Cmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		fmt.Fprintf(os.Stderr, "paste: %v\n", err)
+		return exit.Status(1)
+	})
+}
+
+// executePaste executes the paste command with given arguments. Nothing is
+// printed when an input cannot be opened.
+func executePaste(args []string) error {
+	delims, err := parseDelimiters(pFlags.delimiters)
+	if err != nil {
+		return err
+	}
+
+	lineEnd := byte('\n')
+	if pFlags.zero {
+		lineEnd = 0
+	}
+
+	out := bufio.NewWriter(os.Stdout)
+	defer out.Flush()
+
+	// Every "-" operand shares the same reader, so that they take turns
+	// reading lines from the standard input.
+	var stdin *lineio.Reader
+	readers := []*lineio.Reader{}
+	for _, name := range args {
+		if name == fileinput.Stdin {
+			if stdin == nil {
+				stdin = lineio.NewReaderDelim(os.Stdin, lineEnd)
+			}
+			readers = append(readers, stdin)
+			continue
+		}
+
+		f, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		readers = append(readers, lineio.NewReaderDelim(f, lineEnd))
+	}
+
+	if !pFlags.serial {
+		return pasteParallel(out, readers, delims, lineEnd)
+	}
+
+	for _, lr := range readers {
+		if err := pasteSerial(out, lr, delims, lineEnd); err != nil {
+			return err
+		}
+	}
+	return nil
+}
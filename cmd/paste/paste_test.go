@@ -0,0 +1,93 @@
+package paste
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/skraio/unix-utilities/internal/assert"
+	"github.com/skraio/unix-utilities/internal/lineio"
+)
+
+func TestParseDelimiters(t *testing.T) {
+	tests := []struct {
+		name string
+		list string
+		want []string
+	}{
+		{
+			name: "Plain characters",
+			list: ",;",
+			want: []string{",", ";"},
+		},
+		{
+			name: "Escapes",
+			list: `\t\n\\\0`,
+			want: []string{"\t", "\n", "\\", ""},
+		},
+		{
+			name: "Empty list",
+			list: "",
+			want: []string{""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ans, err := parseDelimiters(tt.list)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assert.Equal(t, strings.Join(ans, "|"), strings.Join(tt.want, "|"))
+		})
+	}
+}
+
+func TestPaste(t *testing.T) {
+	inputs := []string{"bird\ncall\nworld\n", "1\n2\n", "home"}
+	tests := []struct {
+		name   string
+		serial bool
+		delims []string
+		want   string
+	}{
+		{
+			name:   "Parallel with cycling delimiters",
+			delims: []string{",", ";"},
+			want:   "bird,1;home\ncall,2;\nworld,;\n",
+		},
+		{
+			name:   "Serial",
+			serial: true,
+			delims: []string{"\t"},
+			want:   "bird\tcall\tworld\n1\t2\nhome\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			readers := []*lineio.Reader{}
+			for _, in := range inputs {
+				readers = append(readers, lineio.NewReader(strings.NewReader(in)))
+			}
+
+			var out bytes.Buffer
+			var err error
+			if tt.serial {
+				for _, lr := range readers {
+					if err = pasteSerial(&out, lr, tt.delims, '\n'); err != nil {
+						break
+					}
+				}
+			} else {
+				err = pasteParallel(&out, readers, tt.delims, '\n')
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assert.Equal(t, out.String(), tt.want)
+		})
+	}
+}
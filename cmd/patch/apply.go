@@ -0,0 +1,160 @@
+package patch
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// applier applies the hunks of a diff to the lines of a file in turn.
+type applier struct {
+	in, out [][]byte
+
+	// frozen is the number of lines of in already written to out, before
+	// which later hunks cannot apply.
+	frozen int
+
+	// offset is how many lines after the place given by the patch the last
+	// hunk was found.
+	offset int
+
+	// added is the number of lines the hunks applied so far added, negative
+	// when they removed some.
+	added int
+}
+
+// newApplier returns an applier for the contents of a file.
+func newApplier(data []byte) *applier {
+	var lines [][]byte
+	for len(data) > 0 {
+		end := bytes.IndexByte(data, '\n') + 1
+		if end == 0 {
+			end = len(data)
+		}
+		lines = append(lines, data[:end])
+		data = data[end:]
+	}
+	return &applier{in: lines}
+}
+
+// locate returns the number of the first line of the file the hunk
+// matches with up to fuzz context lines ignored at either end, looking
+// around where it is expected, or 0 when it matches nowhere.
+func (a *applier) locate(h *hunk, fuzz int) int {
+	guess := h.oldFirst + a.offset
+	lines := len(h.old)
+	if lines == 0 {
+		return guess
+	}
+
+	// A hunk with less context at one end than at the other can only
+	// match at that end of the file, unless fuzz allows it.
+	context := max(h.prefix, h.suffix)
+	prefixFuzz := fuzz + h.prefix - context
+	suffixFuzz := fuzz + h.suffix - context
+	maxWhere := len(a.in) - (lines - max(suffixFuzz, 0)) + 1
+	minWhere := a.frozen + 1
+	maxPositive := maxWhere - guess
+	maxNegative := min(guess-minWhere, guess-1)
+
+	if prefixFuzz < 0 && h.oldFirst <= 1 {
+		if suffixFuzz < 0 && (lines != len(a.in) || h.prefix < a.frozen) {
+			return 0
+		}
+		offset := 1 - guess
+		if a.frozen <= h.prefix && offset <= maxPositive && a.match(h, guess+offset, 0, max(suffixFuzz, 0)) {
+			return a.found(guess, offset)
+		}
+		return 0
+	}
+	prefixFuzz = max(prefixFuzz, 0)
+	if suffixFuzz < 0 {
+		offset := guess - (len(a.in) - lines + 1)
+		if offset <= maxNegative && a.match(h, guess-offset, prefixFuzz, 0) {
+			return a.found(guess, -offset)
+		}
+		return 0
+	}
+
+	// The places tried are those between the last line frozen and the
+	// end of the file, the nearest to the guess first.
+	for offset := 0; offset <= max(maxPositive, maxNegative); offset++ {
+		if offset <= maxPositive && guess+offset >= minWhere && a.match(h, guess+offset, prefixFuzz, suffixFuzz) {
+			return a.found(guess, offset)
+		}
+		if offset > 0 && offset <= maxNegative && guess-offset <= maxWhere && a.match(h, guess-offset, prefixFuzz, suffixFuzz) {
+			return a.found(guess, -offset)
+		}
+	}
+	return 0
+}
+
+// found records the offset a hunk was found at and returns its place.
+func (a *applier) found(guess, offset int) int {
+	a.offset += offset
+	return guess + offset
+}
+
+// match reports whether the lines the hunk replaces, but for the context
+// lines ignored at either end, are found at line number where.
+func (a *applier) match(h *hunk, where, prefixFuzz, suffixFuzz int) bool {
+	if where < 1 {
+		return false
+	}
+	for i := prefixFuzz; i < len(h.old)-suffixFuzz; i++ {
+		n := where - 1 + i
+		if n >= len(a.in) || !bytes.Equal(a.in[n], h.old[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// apply applies a hunk found at line number where, ignoring fuzz context
+// lines at its start, which are kept as they are in the file. The context
+// lines at its end are left to be copied from the file, where the next
+// hunk may start.
+func (a *applier) apply(h *hunk, where, fuzz int) {
+	context := max(h.prefix, h.suffix)
+	prefixFuzz := max(fuzz+h.prefix-context, 0)
+	if len(h.old) == 0 {
+		prefixFuzz = 0
+	}
+
+	start := min(max(where-1, a.frozen), len(a.in))
+	end := min(start+len(h.old)-h.suffix, len(a.in))
+	a.out = append(a.out, a.in[a.frozen:start]...)
+	a.out = append(a.out, a.in[start:min(start+prefixFuzz, end)]...)
+	a.out = append(a.out, h.new[prefixFuzz:len(h.new)-h.suffix]...)
+	a.frozen = end
+	a.added += len(h.new) - len(h.old)
+}
+
+// result returns the contents of the file with the hunks applied.
+func (a *applier) result() []byte {
+	return bytes.Join(append(a.out, a.in[a.frozen:]...), nil)
+}
+
+// succeeded returns the message telling a hunk found at line number where
+// applies elsewhere than given by the patch or with fuzz, or "" when it
+// applies as given. The line number told is that in the patched file.
+func (a *applier) succeeded(n, where, fuzz int) string {
+	if fuzz == 0 && a.offset == 0 {
+		return ""
+	}
+	msg := fmt.Sprintf("Hunk #%d succeeded at %d", n, where+a.added)
+	if fuzz > 0 {
+		msg += fmt.Sprintf(" with fuzz %d", fuzz)
+	}
+	if a.offset != 0 {
+		msg += fmt.Sprintf(" (offset %d line%s)", a.offset, plural(a.offset))
+	}
+	return msg + "."
+}
+
+// plural returns the ending of a plural noun for a count.
+func plural(n int) string {
+	if n == 1 {
+		return ""
+	}
+	return "s"
+}
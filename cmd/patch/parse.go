@@ -0,0 +1,267 @@
+package patch
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// hunk is a hunk of a unified diff.
+type hunk struct {
+	// oldFirst and newFirst are the numbers of the first lines of the hunk
+	// in both files, or those of the lines they come before for an empty
+	// range.
+	oldFirst, newFirst int
+
+	// old and new hold the lines the hunk replaces and those replacing
+	// them, each ending with its newline unless marked as missing it.
+	old, new [][]byte
+
+	// prefix and suffix are the numbers of context lines at the start and
+	// end of the hunk.
+	prefix, suffix int
+
+	// lines holds the lines of the hunk, marked with ' ', '-' or '+'.
+	lines []string
+}
+
+// filePatch holds the hunks of a diff between two files.
+type filePatch struct {
+	// header holds the lines of the patch leading up to the first hunk,
+	// among which the names of the files.
+	header []string
+
+	// line is the number of the line of the patch with the first hunk.
+	line int
+
+	// names holds the names of the files with their time stamps, and
+	// oldName and newName the names alone.
+	names            [2]string
+	oldName, newName string
+	hunks            []*hunk
+}
+
+// creates reports whether the patch creates its file.
+func (fp *filePatch) creates() bool {
+	return fp.missing(0) && len(fp.hunks) == 1 && len(fp.hunks[0].old) == 0
+}
+
+// deletes reports whether the patch deletes its file.
+func (fp *filePatch) deletes() bool {
+	return fp.missing(1) && len(fp.hunks) == 1 && len(fp.hunks[0].new) == 0
+}
+
+// missing reports whether the header tells the old file, for 0, or the
+// new one, for 1, does not exist: its name is /dev/null or its time stamp
+// is the epoch, as written by diff -N.
+func (fp *filePatch) missing(i int) bool {
+	name, stamp, _ := strings.Cut(fp.names[i], "\t")
+	return name == "/dev/null" || strings.HasPrefix(stamp, "1970-01-01 00:00:00") ||
+		strings.HasPrefix(stamp, "1969-12-31 ")
+}
+
+// reverse swaps the files the patch goes between.
+func (fp *filePatch) reverse() {
+	fp.names[0], fp.names[1] = fp.names[1], fp.names[0]
+	fp.oldName, fp.newName = fp.newName, fp.oldName
+	for _, h := range fp.hunks {
+		h.oldFirst, h.newFirst = h.newFirst, h.oldFirst
+		h.old, h.new = h.new, h.old
+		for i, line := range h.lines {
+			switch line[0] {
+			case '-':
+				h.lines[i] = "+" + line[1:]
+			case '+':
+				h.lines[i] = "-" + line[1:]
+			}
+		}
+	}
+}
+
+// parser reads the diffs between files of a patch.
+type parser struct {
+	lines []string
+	i     int
+}
+
+// newParser returns a parser for the text of a patch.
+func newParser(text []byte) *parser {
+	lines := strings.SplitAfter(string(text), "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return &parser{lines: lines}
+}
+
+// next returns the next diff between two files, or nil when no more are
+// found. Lines that are not part of a unified diff are skipped.
+func (p *parser) next() (*filePatch, error) {
+	start := p.i
+	for ; p.i+2 < len(p.lines); p.i++ {
+		if strings.HasPrefix(p.lines[p.i], "--- ") &&
+			strings.HasPrefix(p.lines[p.i+1], "+++ ") &&
+			strings.HasPrefix(p.lines[p.i+2], "@@ -") {
+			break
+		}
+	}
+	if p.i+2 >= len(p.lines) {
+		p.i = len(p.lines)
+		return nil, nil
+	}
+
+	fp := &filePatch{
+		header: p.lines[start : p.i+2],
+		line:   p.i + 3,
+		names:  [2]string{strings.TrimSuffix(p.lines[p.i][4:], "\n"), strings.TrimSuffix(p.lines[p.i+1][4:], "\n")},
+	}
+	fp.oldName, fp.newName = fileName(fp.names[0]), fileName(fp.names[1])
+	for p.i += 2; p.i < len(p.lines) && strings.HasPrefix(p.lines[p.i], "@@ -"); {
+		h, err := p.hunk()
+		if err != nil {
+			return nil, err
+		}
+		fp.hunks = append(fp.hunks, h)
+	}
+	return fp, nil
+}
+
+// fileName returns the name of a file given in the header of a unified
+// diff, which may be followed by a tab and a time stamp.
+func fileName(s string) string {
+	if name, _, found := strings.Cut(s, "\t"); found {
+		return name
+	}
+	return strings.TrimRight(s, " ")
+}
+
+// hunk parses the hunk starting at the current line.
+func (p *parser) hunk() (*hunk, error) {
+	header := p.lines[p.i]
+	oldFirst, oldCount, newFirst, newCount, ok := parseRanges(header)
+	if !ok {
+		return nil, p.malformed()
+	}
+	h := &hunk{oldFirst: oldFirst, newFirst: newFirst}
+	if oldCount == 0 {
+		h.oldFirst++
+	}
+	if newCount == 0 {
+		h.newFirst++
+	}
+
+	context := true
+	for p.i++; len(h.old) < oldCount || len(h.new) < newCount; p.i++ {
+		if p.i == len(p.lines) {
+			return nil, p.malformed()
+		}
+		line := p.lines[p.i]
+		if !strings.HasSuffix(line, "\n") {
+			line += "\n"
+		}
+		mark, text := byte(' '), []byte(line)
+		if line != "\n" {
+			mark, text = line[0], text[1:]
+		}
+		switch mark {
+		case ' ':
+			if len(h.old) == oldCount || len(h.new) == newCount {
+				return nil, p.malformed()
+			}
+			h.old = append(h.old, text)
+			h.new = append(h.new, text)
+			if context {
+				h.prefix++
+			}
+			h.suffix++
+		case '-':
+			if len(h.old) == oldCount {
+				return nil, p.malformed()
+			}
+			h.old = append(h.old, text)
+			context, h.suffix = false, 0
+		case '+':
+			if len(h.new) == newCount {
+				return nil, p.malformed()
+			}
+			h.new = append(h.new, text)
+			context, h.suffix = false, 0
+		case '\\':
+			p.noNewline(h)
+			continue
+		default:
+			return nil, p.malformed()
+		}
+		h.lines = append(h.lines, string(mark)+string(text))
+	}
+
+	// The last lines may lack their newline.
+	if p.i < len(p.lines) && strings.HasPrefix(p.lines[p.i], "\\") {
+		p.noNewline(h)
+		p.i++
+	}
+	if context {
+		h.suffix = 0
+	}
+	return h, nil
+}
+
+// noNewline removes the newline of the last line of a hunk read.
+func (p *parser) noNewline(h *hunk) {
+	if len(h.lines) == 0 {
+		return
+	}
+	trim := func(lines [][]byte) {
+		last := len(lines) - 1
+		lines[last] = bytes.TrimSuffix(lines[last], []byte{'\n'})
+	}
+	last := h.lines[len(h.lines)-1]
+	if last[0] != '+' {
+		trim(h.old)
+	}
+	if last[0] != '-' {
+		trim(h.new)
+	}
+	h.lines[len(h.lines)-1] = strings.TrimSuffix(last, "\n")
+}
+
+// malformed returns the error for the current line.
+func (p *parser) malformed() error {
+	line := ""
+	if p.i < len(p.lines) {
+		line = strings.TrimSuffix(p.lines[p.i], "\n")
+	}
+	return fmt.Errorf("malformed patch at line %d: %s", p.i+1, line)
+}
+
+// parseRanges parses a hunk header of the form @@ -FIRST[,COUNT]
+// +FIRST[,COUNT] @@, the count being 1 when left out.
+func parseRanges(header string) (oldFirst, oldCount, newFirst, newCount int, ok bool) {
+	fields := strings.Fields(header)
+	if len(fields) < 4 || fields[0] != "@@" || fields[3] != "@@" ||
+		!strings.HasPrefix(fields[1], "-") || !strings.HasPrefix(fields[2], "+") {
+		return 0, 0, 0, 0, false
+	}
+	if oldFirst, oldCount, ok = parseRange(fields[1][1:]); !ok {
+		return 0, 0, 0, 0, false
+	}
+	newFirst, newCount, ok = parseRange(fields[2][1:])
+	return oldFirst, oldCount, newFirst, newCount, ok
+}
+
+// parseRange parses a range of lines of the form FIRST[,COUNT].
+func parseRange(s string) (int, int, bool) {
+	first, count, found := strings.Cut(s, ",")
+	if !found {
+		count = "1"
+	}
+	f, err := strconv.Atoi(first)
+	if err != nil || f < 0 {
+		return 0, 0, false
+	}
+	c, err := strconv.Atoi(count)
+	if err != nil || c < 0 {
+		return 0, 0, false
+	}
+	return f, c, true
+}
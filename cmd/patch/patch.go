@@ -0,0 +1,555 @@
+// Package patch provides functionality for applying unified diffs to the
+// files they were made from, finding hunks that moved and, with fuzz,
+// hunks whose context changed.
+package patch
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/skraio/unix-utilities/cmdflags"
+	"github.com/skraio/unix-utilities/internal/exit"
+	"github.com/spf13/cobra"
+)
+
+// patchFlags holds flags for patch command.
+type patchFlags struct {
+	reverse bool
+	dryRun  bool
+	batch   bool
+	force   bool
+	quiet   bool
+	strip   string
+	fuzz    string
+	input   string
+	output  string
+}
+
+var pFlags patchFlags
+
+// flags definition for patch command.
+var flags = []cmdflags.Flag{
+	{Value: &pFlags.reverse, Name: "reverse", ShortHand: "R", DefaultValue: false, Description: "assume patches were created with old and new files swapped"},
+	{Value: &pFlags.dryRun, Name: "dry-run", ShortHand: "", DefaultValue: false, Description: "print the results of applying the patches without changing any files"},
+	{Value: &pFlags.batch, Name: "batch", ShortHand: "t", DefaultValue: false, Description: "ask no questions; assume reversed patches were applied already"},
+	{Value: &pFlags.force, Name: "force", ShortHand: "f", DefaultValue: false, Description: "like -t, but assume patches are not reversed"},
+	{Value: &pFlags.quiet, Name: "quiet", ShortHand: "s", DefaultValue: false, Description: "work silently, unless an error occurs"},
+}
+
+// stringFlags definition for patch command.
+var stringFlags = []cmdflags.StringFlag{
+	{Value: &pFlags.strip, Name: "strip", ShortHand: "p", DefaultValue: "", Description: "strip NUM leading components from file names"},
+	{Value: &pFlags.fuzz, Name: "fuzz", ShortHand: "F", DefaultValue: "2", Description: "set the fuzz factor to LINES for inexact matching"},
+	{Value: &pFlags.input, Name: "input", ShortHand: "i", DefaultValue: "", Description: "read patch from PATCHFILE instead of stdin"},
+	{Value: &pFlags.output, Name: "output", ShortHand: "o", DefaultValue: "", Description: "output patched files to FILE"},
+}
+
+// Cmd represents the 'patch' command configuration using Cobra.
+var Cmd = &cobra.Command{
+	Use:           "patch [-f flags] [origfile [patchfile]]",
+	Short:         "Apply a diff file to an original",
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return exit.Status(executePatch(args))
+	},
+}
+
+// init initializes the 'patch' command by setting up flags.
+func init() {
+	cmdflags.ParseFlags(flags, Cmd)
+	cmdflags.ParseStringFlags(stringFlags, Cmd)
+
+	Cmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		fmt.Fprintf(os.Stderr, "patch: %v\n", err)
+		return exit.Status(2)
+	})
+}
+
+// executePatch executes the patch command with given arguments and returns
+// its exit status: 0 when all hunks applied, 1 when some did not and 2 on
+// trouble.
+func executePatch(args []string) int {
+	if len(args) > 2 {
+		fmt.Fprintf(os.Stderr, "patch: %s: extra operand\n", args[2])
+		return 2
+	}
+
+	pt := &patcher{strip: -1, written: map[string]bool{}, rejected: map[string]bool{}, outputs: map[string]bool{}}
+	var err error
+	if pFlags.strip != "" {
+		if pt.strip, err = strconv.Atoi(pFlags.strip); err != nil || pt.strip < 0 {
+			return fatal(fmt.Errorf("strip count %s is not a number", pFlags.strip))
+		}
+	}
+	if pt.fuzz, err = strconv.Atoi(pFlags.fuzz); err != nil || pt.fuzz < 0 {
+		return fatal(fmt.Errorf("fuzz factor %s is not a number", pFlags.fuzz))
+	}
+	if len(args) > 0 {
+		pt.target = args[0]
+	}
+
+	patchFile := pFlags.input
+	if len(args) == 2 {
+		patchFile = args[1]
+	}
+	var text []byte
+	if patchFile == "" || patchFile == "-" {
+		text, err = io.ReadAll(os.Stdin)
+	} else {
+		text, err = os.ReadFile(patchFile)
+	}
+	if err != nil {
+		return fatal(fmt.Errorf("Can't open patch file %s : %v", patchFile, exit.Unwrap(err)))
+	}
+
+	p := newParser(text)
+	status, found := 0, false
+	for {
+		fp, err := p.next()
+		if err != nil {
+			return fatal(err)
+		}
+		if fp == nil {
+			break
+		}
+		found = true
+		s, err := pt.patch(fp)
+		if err != nil {
+			return fatal(err)
+		}
+		status = max(status, s)
+	}
+	if !found && len(text) > 0 {
+		return fatal(errors.New("Only garbage was found in the patch input."))
+	}
+	return status
+}
+
+// patcher applies the diffs of a patch.
+type patcher struct {
+	// target is the file to patch given as operand, or "" to find it from
+	// the names in the patch.
+	target string
+
+	// strip is the number of leading components removed from the names in
+	// the patch, or -1 to only keep the last one.
+	strip int
+
+	fuzz int
+
+	// written holds the files patched, and rejected the reject files
+	// written, to which the rejects of later diffs are appended.
+	written, rejected map[string]bool
+
+	// outputs holds the output files written, to which the diffs of the
+	// patch are appended.
+	outputs map[string]bool
+}
+
+// patch applies the hunks of a diff to its file and returns the exit
+// status: 1 when some hunks did not apply.
+func (pt *patcher) patch(fp *filePatch) (int, error) {
+	if pFlags.reverse {
+		fp.reverse()
+	}
+	name, fi := pt.file(fp)
+	if name == "" {
+		pt.cannotFind(fp)
+		return 1, nil
+	}
+
+	var data []byte
+	if fi != nil {
+		var err error
+		if data, err = os.ReadFile(name); err != nil {
+			return 0, fmt.Errorf("Can't open file %s : %v", name, exit.Unwrap(err))
+		}
+	}
+	switch {
+	case fp.creates() && len(data) > 0:
+		if !pt.assumeReversed(fp, fmt.Sprintf("The next patch%s would create the file %s,\nwhich already exists!", reversed(), name)) {
+			return pt.ignored(fp), nil
+		}
+	case fp.deletes() && fi == nil:
+		if !pt.assumeReversed(fp, fmt.Sprintf("The next patch%s would delete the file %s,\nwhich does not exist!", reversed(), name)) {
+			return pt.ignored(fp), nil
+		}
+	}
+
+	out := name
+	switch {
+	case pFlags.dryRun:
+		pt.say("checking file %s\n", name)
+	case pFlags.output != "":
+		out = pFlags.output
+		pt.say("patching file %s (read from %s)\n", out, name)
+	default:
+		pt.say("patching file %s\n", name)
+	}
+
+	a := newApplier(data)
+	var rejects strings.Builder
+	failed, skipped, mismatch := 0, false, false
+	for i, h := range fp.hunks {
+		where, fuzz := 0, 0
+		for !skipped {
+			where = a.locate(h, fuzz)
+			if fp.creates() && len(data) > 0 {
+				// A diff creating a file applies to no contents.
+				where = 0
+			}
+			if where == 0 && i == 0 && !pFlags.force {
+				where, skipped = pt.detectReversed(fp, a, fuzz)
+				mismatch = mismatch || where != 0
+			}
+			if where != 0 || skipped || fuzz >= min(pt.fuzz, max(h.prefix, h.suffix)) {
+				break
+			}
+			fuzz++
+		}
+
+		if where == 0 {
+			if !skipped {
+				pt.say("Hunk #%d FAILED at %d.\n", i+1, h.oldFirst+a.added)
+			}
+			writeReject(&rejects, h, a.added)
+			failed++
+			mismatch = true
+			continue
+		}
+		msg := a.succeeded(i+1, where, fuzz)
+		a.apply(h, where, fuzz)
+		if msg != "" {
+			pt.say("%s\n", msg)
+		}
+		mismatch = mismatch || fuzz > 0 || a.offset != 0
+	}
+
+	var err error
+	switch {
+	case pFlags.dryRun:
+	case out != name:
+		// The diffs of a patch are all written to the output file, but for
+		// those skipped.
+		var patched []byte
+		if !skipped {
+			patched = a.result()
+		}
+		err = pt.writeOnce(pt.outputs, out, patched)
+	case !skipped:
+		// The contents are kept when some hunks did not apply as given,
+		// but only before the file is first written, unless none applied.
+		backup := mismatch && (!pt.written[name] || failed == len(fp.hunks))
+		err = pt.write(fp, name, fi, data, a.result(), backup)
+	}
+	if err != nil {
+		return 0, err
+	}
+	if failed == 0 {
+		return 0, nil
+	}
+
+	verb := "FAILED"
+	if skipped {
+		verb = "ignored"
+	}
+	msg := fmt.Sprintf("%d out of %d hunk%s %s", failed, len(fp.hunks), plural(len(fp.hunks)), verb)
+	if !pFlags.dryRun {
+		name := out + ".rej"
+		text := fmt.Sprintf("--- %s\n+++ %s\n%s", pt.header(fp.names[0]), pt.header(fp.names[1]), rejects.String())
+		if err := pt.writeOnce(pt.rejected, name, []byte(text)); err != nil {
+			return 0, err
+		}
+		msg += " -- saving rejects to file " + name
+	}
+	fmt.Println(msg)
+	return 1, nil
+}
+
+// file returns the name of the file to patch and its information, which
+// is nil for a missing file the patch creates or deletes. The name is ""
+// when no file is found.
+func (pt *patcher) file(fp *filePatch) (string, fs.FileInfo) {
+	if pt.target != "" {
+		fi, _ := os.Stat(pt.target)
+		return pt.target, fi
+	}
+
+	var names, existing []string
+	for _, name := range []string{fp.oldName, fp.newName} {
+		if name == os.DevNull {
+			continue
+		}
+		if name = stripName(name, pt.strip); name == "" {
+			continue
+		}
+		names = append(names, name)
+		if fi, err := os.Stat(name); err == nil && fi.Mode().IsRegular() {
+			existing = append(existing, name)
+		}
+	}
+	if len(existing) > 0 {
+		name := bestName(existing)
+		fi, _ := os.Stat(name)
+		return name, fi
+	}
+	if (fp.creates() || fp.deletes()) && len(names) > 0 {
+		return bestName(names), nil
+	}
+	return "", nil
+}
+
+// header returns a file name of the header of a diff with its leading
+// components stripped, followed by the rest of the header.
+func (pt *patcher) header(s string) string {
+	name, rest, _ := strings.Cut(s, "\t")
+	if stripped := stripName(name, pt.strip); stripped != "" {
+		name = stripped
+	}
+	if rest == "" {
+		return name
+	}
+	return name + "\t" + rest
+}
+
+// stripName removes n leading components from a file name, or all but the
+// last one when n is negative. It returns "" when the name has too few.
+func stripName(name string, n int) string {
+	if n < 0 {
+		return filepath.Base(name)
+	}
+	for ; n > 0; n-- {
+		i := strings.IndexByte(name, '/')
+		if i < 0 {
+			return ""
+		}
+		name = strings.TrimLeft(name[i+1:], "/")
+	}
+	return name
+}
+
+// bestName returns the name with the fewest components, then the shortest
+// last component, then the shortest, the first one among equals.
+func bestName(names []string) string {
+	best := names[0]
+	for _, name := range names[1:] {
+		components := strings.Count(name, "/") - strings.Count(best, "/")
+		base := len(filepath.Base(name)) - len(filepath.Base(best))
+		if components < 0 || components == 0 && (base < 0 || base == 0 && len(name) < len(best)) {
+			best = name
+		}
+	}
+	return best
+}
+
+// detectReversed checks whether the first hunk of a diff, not found, would
+// be found with the files swapped. Asking no questions, the diff is then
+// reversed with -t and skipped otherwise. It returns where the hunk was
+// found and whether the diff is skipped.
+func (pt *patcher) detectReversed(fp *filePatch, a *applier, fuzz int) (int, bool) {
+	offset := a.offset
+	fp.reverse()
+	where := a.locate(fp.hunks[0], fuzz)
+	if where == 0 {
+		fp.reverse()
+		return 0, false
+	}
+
+	msg := "Reversed (or previously applied) patch detected!"
+	if pFlags.reverse {
+		msg = "Unreversed patch detected!"
+	}
+	if pt.assumeReversed(nil, msg) {
+		return where, false
+	}
+	fp.reverse()
+	a.offset = offset
+	return 0, true
+}
+
+// assumeReversed prints a message about a diff that seems reversed and
+// reports whether it is applied, which is only the case with -f, applying
+// it as it is, and with -t, applying it reversed. A diff given is then
+// reversed.
+func (pt *patcher) assumeReversed(fp *filePatch, msg string) bool {
+	assume, ignore := "Assume -R?", "Assuming -R."
+	if pFlags.reverse {
+		assume, ignore = "Ignore -R?", "Ignoring -R."
+	}
+	switch {
+	case pFlags.force:
+		fmt.Printf("%s  Applying it anyway.\n", msg)
+		return true
+	case pFlags.batch:
+		fmt.Printf("%s  %s\n", msg, ignore)
+		if fp != nil {
+			fp.reverse()
+		}
+		return true
+	}
+	fmt.Printf("%s  %s [n] \nApply anyway? [n] \n", msg, assume)
+	pt.say("Skipping patch.\n")
+	return false
+}
+
+// reversed returns the words telling that the diffs are applied reversed
+// with -R, to follow "The next patch".
+func reversed() string {
+	if pFlags.reverse {
+		return ", when reversed,"
+	}
+	return ""
+}
+
+// cannotFind reports that the file to patch was not found, showing the
+// lines of the patch leading up to the diff.
+func (pt *patcher) cannotFind(fp *filePatch) {
+	pt.say("can't find file to patch at input line %d\n", fp.line)
+	if pFlags.strip == "" {
+		pt.say("Perhaps you should have used the -p or --strip option?\n")
+	} else {
+		pt.say("Perhaps you used the wrong -p or --strip option?\n")
+	}
+	fmt.Println("The text leading up to this was:")
+	fmt.Println("--------------------------")
+	for _, line := range fp.header {
+		fmt.Printf("|%s", line)
+	}
+	fmt.Println("--------------------------")
+	if pFlags.batch || pFlags.force {
+		fmt.Println("No file to patch.  Skipping patch.")
+	} else {
+		fmt.Print("File to patch: \nSkip this patch? [y] \n")
+		pt.say("Skipping patch.\n")
+	}
+	pt.ignored(fp)
+}
+
+// ignored reports that the hunks of a diff were skipped and returns the
+// exit status.
+func (pt *patcher) ignored(fp *filePatch) int {
+	n := len(fp.hunks)
+	fmt.Printf("%d out of %d hunk%s ignored\n", n, n, plural(n))
+	return 1
+}
+
+// write writes the patched contents of a file, removing it when a diff
+// deleting it applied, after keeping the original contents in a file with
+// the suffix .orig if asked to.
+func (pt *patcher) write(fp *filePatch, name string, fi fs.FileInfo, original, patched []byte, backup bool) error {
+	perm := fs.FileMode(0666)
+	if fi != nil {
+		perm = fi.Mode().Perm()
+	}
+	if dir := filepath.Dir(name); fi == nil {
+		if err := os.MkdirAll(dir, 0777); err != nil {
+			return fmt.Errorf("Can't create directory %s : %v", dir, exit.Unwrap(err))
+		}
+	}
+	if backup {
+		backup := name + ".orig"
+		if err := os.WriteFile(backup, original, perm); err != nil {
+			return fmt.Errorf("Can't create file %s : %v", backup, exit.Unwrap(err))
+		}
+	}
+
+	pt.written[name] = true
+	if fp.deletes() && len(patched) == 0 {
+		if err := os.Remove(name); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("Can't remove file %s : %v", name, exit.Unwrap(err))
+		}
+		return nil
+	}
+	if err := os.WriteFile(name, patched, perm); err != nil {
+		return fmt.Errorf("Can't create file %s : %v", name, exit.Unwrap(err))
+	}
+	return nil
+}
+
+// writeOnce writes data to a file the first time it is written by the
+// command and appends to it afterwards, recording it in seen.
+func (pt *patcher) writeOnce(seen map[string]bool, name string, data []byte) error {
+	flag := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	if seen[name] {
+		flag = os.O_WRONLY | os.O_APPEND
+	}
+	f, err := os.OpenFile(name, flag, 0666)
+	if err == nil {
+		_, err = f.Write(data)
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("Can't create file %s : %v", name, exit.Unwrap(err))
+	}
+	seen[name] = true
+	return nil
+}
+
+// writeReject writes a hunk that did not apply as part of a unified diff,
+// its lines numbered as in the file patched so far, which added lines to
+// the file. Deleted lines come before added ones within each change.
+func writeReject(b *strings.Builder, h *hunk, added int) {
+	fmt.Fprintf(b, "@@ -%s +%s @@\n", rejectRange(h.oldFirst+added, len(h.old)), rejectRange(h.newFirst+added, len(h.new)))
+	for i := 0; i < len(h.lines); {
+		if h.lines[i][0] == ' ' {
+			rejectLine(b, h.lines[i])
+			i++
+			continue
+		}
+		end := i
+		for end < len(h.lines) && h.lines[end][0] != ' ' {
+			end++
+		}
+		for _, mark := range []byte{'-', '+'} {
+			for _, line := range h.lines[i:end] {
+				if line[0] == mark {
+					rejectLine(b, line)
+				}
+			}
+		}
+		i = end
+	}
+}
+
+// rejectRange returns a range of lines of a rejected hunk, in which an
+// empty range is numbered after the line before it.
+func rejectRange(first, count int) string {
+	switch count {
+	case 0:
+		return fmt.Sprintf("%d,0", first-1)
+	case 1:
+		return strconv.Itoa(first)
+	}
+	return fmt.Sprintf("%d,%d", first, count)
+}
+
+// rejectLine writes a line of a rejected hunk, marking a missing newline.
+func rejectLine(b *strings.Builder, line string) {
+	b.WriteString(line)
+	if !strings.HasSuffix(line, "\n") {
+		b.WriteString("\n\\ No newline at end of file\n")
+	}
+}
+
+// say prints a message unless working silently.
+func (pt *patcher) say(format string, args ...any) {
+	if !pFlags.quiet {
+		fmt.Printf(format, args...)
+	}
+}
+
+// fatal prints an error that stops the command and returns the exit
+// status of trouble.
+func fatal(err error) int {
+	fmt.Fprintf(os.Stderr, "patch: **** %v\n", err)
+	return 2
+}
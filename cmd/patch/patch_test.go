@@ -0,0 +1,111 @@
+package patch
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/skraio/unix-utilities/internal/assert"
+)
+
+const diff = `--- a/t	2024-01-02 03:04:05.000000000 +0000
++++ b/t	2024-01-02 03:04:05.000000000 +0000
+@@ -2,3 +2,3 @@
+ 2
+-3
++three
+ 4
+@@ -7,2 +7,3 @@
+ 7
+ 8
++9
+\ No newline at end of file
+`
+
+func TestParse(t *testing.T) {
+	p := newParser([]byte(diff))
+	fp, err := p.next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, fp.oldName, "a/t")
+	assert.Equal(t, fp.newName, "b/t")
+	assert.Equal(t, len(fp.hunks), 2)
+
+	h := fp.hunks[1]
+	assert.Equal(t, h.oldFirst, 7)
+	assert.Equal(t, h.prefix, 2)
+	assert.Equal(t, h.suffix, 0)
+	assert.Equal(t, string(h.new[2]), "9")
+
+	fp, err = p.next()
+	assert.Equal(t, fp == nil && err == nil, true)
+
+	_, err = newParser([]byte("--- a\n+++ b\n@@ -1,2 +1,2 @@\n-x\n")).next()
+	assert.Equal(t, err.Error(), "malformed patch at line 5: ")
+}
+
+func TestApply(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		fuzz   int
+		want   string
+		failed int
+	}{
+		{name: "As given", input: "1\n2\n3\n4\n5\n6\n7\n8\n", want: "1\n2\nthree\n4\n5\n6\n7\n8\n9"},
+		{name: "Offset", input: "0\n1\n2\n3\n4\n5\n6\n7\n8\n", want: "0\n1\n2\nthree\n4\n5\n6\n7\n8\n9"},
+		{name: "Fuzz", input: "1\nx\n3\n4\n5\n6\n7\n8\n", fuzz: 1, want: "1\nx\nthree\n4\n5\n6\n7\n8\n9"},
+		{name: "Failed", input: "1\n2\n3\n4\n5\n6\nx\n8\n", fuzz: 0, want: "1\n2\nthree\n4\n5\n6\nx\n8\n", failed: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fp, _ := newParser([]byte(diff)).next()
+			a := newApplier([]byte(tt.input))
+			failed := 0
+			for _, h := range fp.hunks {
+				where, fuzz := 0, 0
+				for ; fuzz <= tt.fuzz; fuzz++ {
+					if where = a.locate(h, fuzz); where != 0 {
+						break
+					}
+				}
+				if where == 0 {
+					failed++
+					continue
+				}
+				a.apply(h, where, fuzz)
+			}
+			assert.Equal(t, string(a.result()), tt.want)
+			assert.Equal(t, failed, tt.failed)
+		})
+	}
+}
+
+func TestReject(t *testing.T) {
+	fp, _ := newParser([]byte(diff)).next()
+	fp.reverse()
+	var b strings.Builder
+	writeReject(&b, fp.hunks[0], 2)
+	writeReject(&b, fp.hunks[1], 0)
+	assert.Equal(t, b.String(), "@@ -4,3 +4,3 @@\n 2\n-three\n+3\n 4\n"+
+		"@@ -7,3 +7,2 @@\n 7\n 8\n-9\n\\ No newline at end of file\n")
+}
+
+func TestStripName(t *testing.T) {
+	tests := []struct {
+		name  string
+		strip int
+		want  string
+	}{
+		{name: "a/b/c", strip: -1, want: "c"},
+		{name: "a/b/c", strip: 0, want: "a/b/c"},
+		{name: "a//b/c", strip: 1, want: "b/c"},
+		{name: "/a/b", strip: 1, want: "a/b"},
+		{name: "a/b", strip: 2, want: ""},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, stripName(tt.name, tt.strip), tt.want)
+	}
+}
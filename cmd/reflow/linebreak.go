@@ -0,0 +1,170 @@
+package reflow
+
+import (
+	"math"
+	"strings"
+	"unicode/utf8"
+)
+
+// The costs weighed in choosing where to break lines, which grow with the
+// square of the distances they measure.
+const (
+	maxCost = math.MaxInt64
+
+	// lineCost is that of each line, so fewer lines are preferred.
+	lineCost = 70 * 70
+
+	// sentenceBonus favors breaking after a sentence, nobreakCost
+	// penalizes breaking after a period that does not end one.
+	sentenceBonus = 50 * 50
+	nobreakCost   = 600 * 600
+
+	// parenBonus and punctBonus favor breaking before an opening
+	// parenthesis and after punctuation.
+	parenBonus = 40 * 40
+	punctBonus = 40 * 40
+)
+
+// shortCost is that of a line n columns short of the goal.
+func shortCost(n int) int64 {
+	return int64(n*10) * int64(n*10)
+}
+
+// raggedCost is that of a line n columns longer or shorter than the next.
+func raggedCost(n int) int64 {
+	return shortCost(n) / 2
+}
+
+// widowCost is that of leaving the first word of a sentence, of the given
+// length, at the end of a line.
+func widowCost(length int) int64 {
+	return 200 * 200 / int64(length+2)
+}
+
+// orphanCost is that of leaving the last word of a sentence, of the given
+// length, at the start of a line.
+func orphanCost(length int) int64 {
+	return 150 * 150 / int64(length+2)
+}
+
+// word is a word of a paragraph.
+type word struct {
+	text   []byte
+	length int
+
+	// space is the number of columns after the word, when not at the end
+	// of a line.
+	space int
+
+	// paren is set for words starting with an opening parenthesis or
+	// quote, punct for words ending with punctuation and period for those
+	// ending with a period, question or exclamation mark, maybe followed
+	// by closing parentheses and quotes. final is set for words ending a
+	// sentence.
+	paren  bool
+	punct  bool
+	period bool
+	final  bool
+
+	// The best line starting with the word, the cost of the lines from
+	// it to the end of the paragraph when it starts one, and the word
+	// starting the next line.
+	lineLength int
+	bestCost   int64
+	nextBreak  int
+}
+
+// newWord returns a word of the given text, which it copies.
+func newWord(text []byte) word {
+	w := word{text: append([]byte{}, text...), length: utf8.RuneCount(text)}
+	last := len(text) - 1
+	w.paren = strings.IndexByte("(['`\"", text[0]) >= 0
+	w.punct = isPunct(text[last])
+	for last > 0 && strings.IndexByte(")]'\"", text[last]) >= 0 {
+		last--
+	}
+	w.period = strings.IndexByte(".?!", text[last]) >= 0
+	return w
+}
+
+// isPunct reports whether c is an ASCII punctuation character.
+func isPunct(c byte) bool {
+	return c > ' ' && c < 0x7f && !(c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z')
+}
+
+// fmtParagraph chooses where to break the lines of the paragraph, those
+// that minimize the sum of their costs. Working back from the last word,
+// it finds for each the best line starting with it, given the best way to
+// break the words after that line.
+func (f *formatter) fmtParagraph() {
+	n := len(f.words)
+	// The sentinel after the last word is too long to share a line.
+	w := append(f.words, word{length: f.maxWidth})
+	for start := n - 1; start >= 0; start-- {
+		best := int64(maxCost)
+		length := f.otherIndent
+		if start == 0 {
+			length = f.firstIndent
+		}
+		length += w[start].length
+
+		// Each line holds at least one word, however long.
+		for i := start + 1; ; i++ {
+			cost := f.lineCost(w, i, length) + w[i].bestCost
+			if start == 0 && f.lastLineLength > 0 {
+				cost += raggedCost(length - f.lastLineLength)
+			}
+			if cost < best {
+				best = cost
+				w[start].nextBreak = i
+				w[start].lineLength = length
+			}
+			if i == n {
+				break
+			}
+			if length += w[i-1].space + w[i].length; length >= f.maxWidth {
+				break
+			}
+		}
+		w[start].bestCost = best + f.baseCost(w, start)
+	}
+	f.words = w[:n]
+}
+
+// lineCost returns the cost of a line of the given length ending before
+// word next, the last line of the paragraph costing nothing.
+func (f *formatter) lineCost(w []word, next, length int) int64 {
+	n := len(w) - 1
+	if next == n {
+		return 0
+	}
+	cost := shortCost(f.goalWidth - length)
+	if w[next].nextBreak != n {
+		cost += raggedCost(length - w[next].lineLength)
+	}
+	return cost
+}
+
+// baseCost returns the cost of starting a line with word i, depending on
+// the words around the break.
+func (f *formatter) baseCost(w []word, i int) int64 {
+	cost := int64(lineCost)
+	if i > 0 {
+		switch prev := w[i-1]; {
+		case prev.period && prev.final:
+			cost -= sentenceBonus
+		case prev.period:
+			cost += nobreakCost
+		case prev.punct:
+			cost -= punctBonus
+		case i > 1 && w[i-2].final:
+			cost += widowCost(prev.length)
+		}
+	}
+	if w[i].paren {
+		cost -= parenBonus
+	} else if w[i].final {
+		cost += orphanCost(w[i].length)
+	}
+	return cost
+}
@@ -0,0 +1,383 @@
+// Package reflow provides the fmt command, which fills and joins the lines
+// of paragraphs to make them of about the same width.
+package reflow
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"strconv"
+
+	"github.com/skraio/unix-utilities/cmdflags"
+	"github.com/skraio/unix-utilities/internal/exit"
+	"github.com/skraio/unix-utilities/internal/fileinput"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// defaultWidth is the maximum width of the lines when not given.
+	defaultWidth = 75
+
+	// leeway is the percentage of the maximum width the lines may be
+	// short of it without cost, unless a goal width is given.
+	leeway = 7
+
+	// maxChars and maxWords bound the text of a paragraph held at once.
+	// Longer paragraphs are printed in parts, split where the lines
+	// before and after suffer least.
+	maxChars = 5000
+	maxWords = 1000
+)
+
+// fmtFlags holds flags for fmt command.
+type fmtFlags struct {
+	split   bool
+	uniform bool
+	width   string
+	goal    string
+}
+
+var pFlags fmtFlags
+
+// flags definition for fmt command.
+var flags = []cmdflags.Flag{
+	{Value: &pFlags.split, Name: "split-only", ShortHand: "s", DefaultValue: false, Description: "split long lines, but do not refill"},
+	{Value: &pFlags.uniform, Name: "uniform-spacing", ShortHand: "u", DefaultValue: false, Description: "one space between words, two after sentences"},
+}
+
+// stringFlags definition for fmt command.
+var stringFlags = []cmdflags.StringFlag{
+	{Value: &pFlags.width, Name: "width", ShortHand: "w", DefaultValue: "", Description: "maximum line width (default of 75 columns)"},
+	{Value: &pFlags.goal, Name: "goal", ShortHand: "g", DefaultValue: "", Description: "goal width (default of 93% of width)"},
+}
+
+// Cmd represents the 'fmt' command configuration using Cobra.
+var Cmd = &cobra.Command{
+	Use:           "fmt [-f flags] [file]...",
+	Short:         "Simple optimal text formatter",
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return exit.Status(executeFmt(args))
+	},
+}
+
+// init initializes the 'fmt' command by setting up flags.
+func init() {
+	cmdflags.ParseFlags(flags, Cmd)
+	cmdflags.ParseStringFlags(stringFlags, Cmd)
+
+	Cmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		fmt.Fprintf(os.Stderr, "fmt: %v\n", err)
+		return exit.Status(1)
+	})
+}
+
+// executeFmt executes the fmt command with given arguments and returns its
+// exit status. Files that cannot be read are reported and skipped.
+func executeFmt(args []string) int {
+	f := &formatter{maxWidth: defaultWidth, split: pFlags.split, uniform: pFlags.uniform}
+	var err error
+	if pFlags.width != "" {
+		if f.maxWidth, err = parseWidth(pFlags.width, maxChars/2); err != nil {
+			return exit.Fail("fmt", err)
+		}
+	}
+	if pFlags.goal != "" {
+		if f.goalWidth, err = parseWidth(pFlags.goal, f.maxWidth); err != nil {
+			return exit.Fail("fmt", err)
+		}
+		if pFlags.width == "" {
+			f.maxWidth = f.goalWidth + 10
+		}
+	} else {
+		f.goalWidth = f.maxWidth * (2*(100-leeway) + 1) / 200
+	}
+
+	f.out = bufio.NewWriter(os.Stdout)
+	defer f.out.Flush()
+
+	status := 0
+	for _, name := range fileinput.Args(args) {
+		if err := f.formatFile(name); err != nil {
+			f.out.Flush()
+			status = exit.Fail("fmt", err)
+		}
+	}
+	return status
+}
+
+// parseWidth parses a width of at most limit columns.
+func parseWidth(s string, limit int) (int, error) {
+	n, err := strconv.Atoi(s)
+	if errors.Is(err, strconv.ErrRange) || err == nil && n > limit {
+		return 0, fmt.Errorf("invalid width: '%s': numerical result out of range", s)
+	}
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid width: '%s'", s)
+	}
+	return n, nil
+}
+
+// formatFile formats the paragraphs of the named file.
+func (f *formatter) formatFile(name string) error {
+	file, err := fileinput.Open(name)
+	if err != nil {
+		var pe *fs.PathError
+		if errors.As(err, &pe) {
+			return fmt.Errorf("cannot open '%s' for reading: %v", pe.Path, exit.Unwrap(pe))
+		}
+		return err
+	}
+	defer fileinput.Close(file)
+	return f.format(file)
+}
+
+// fileEnd stands for the end of the input among the bytes read.
+const fileEnd = -1
+
+// formatter fills paragraphs.
+type formatter struct {
+	in  *bufio.Reader
+	out *bufio.Writer
+	err error
+
+	maxWidth  int
+	goalWidth int
+
+	// split only breaks long lines, each line a paragraph of its own, and
+	// uniform leaves one space between words and two after sentences.
+	split   bool
+	uniform bool
+
+	// tabs is set once the input used tabs for spacing, which the output
+	// then also does.
+	tabs bool
+
+	inColumn  int
+	outColumn int
+
+	// The indentation of the first line of the paragraph and that of the
+	// other lines.
+	firstIndent int
+	otherIndent int
+
+	// lastLineLength is that of the last line printed of a paragraph
+	// printed in parts.
+	lastLineLength int
+
+	// words are those of the paragraph not printed yet, current the one
+	// being read and chars the number of bytes of all of them.
+	words   []word
+	current []byte
+	chars   int
+
+	nextChar int
+}
+
+// format formats the paragraphs read from r.
+func (f *formatter) format(r io.Reader) error {
+	f.in = bufio.NewReader(r)
+	f.err = nil
+	f.tabs = false
+	f.nextChar = f.getPrefix()
+	for f.getParagraph() {
+		f.fmtParagraph()
+		f.putParagraph(len(f.words))
+		f.words = f.words[:0]
+		f.chars = 0
+	}
+	return f.err
+}
+
+// getc returns the next byte of the input, or fileEnd.
+func (f *formatter) getc() int {
+	b, err := f.in.ReadByte()
+	if err != nil {
+		if err != io.EOF {
+			f.err = err
+		}
+		return fileEnd
+	}
+	return int(b)
+}
+
+// getSpace skips the blanks from c on, keeping track of the column, and
+// returns the byte after them.
+func (f *formatter) getSpace(c int) int {
+	for {
+		switch c {
+		case ' ':
+			f.inColumn++
+		case '\t':
+			f.tabs = true
+			f.inColumn = (f.inColumn/8 + 1) * 8
+		default:
+			return c
+		}
+		c = f.getc()
+	}
+}
+
+// getPrefix skips the indentation of a line and returns its first byte.
+func (f *formatter) getPrefix() int {
+	f.inColumn = 0
+	return f.getSpace(f.getc())
+}
+
+// getParagraph reads the next paragraph, printing the blank lines before
+// it, and reports whether there is one. The lines of a paragraph share
+// their indentation.
+func (f *formatter) getParagraph() bool {
+	f.lastLineLength = 0
+	c := f.nextChar
+	for c == '\n' || c == fileEnd {
+		if c == fileEnd {
+			f.nextChar = fileEnd
+			return false
+		}
+		f.out.WriteByte('\n')
+		c = f.getPrefix()
+	}
+
+	f.firstIndent = f.inColumn
+	f.otherIndent = f.firstIndent
+	c = f.getLine(c)
+	if !f.split {
+		for c != '\n' && c != fileEnd && f.inColumn == f.otherIndent {
+			c = f.getLine(c)
+		}
+	}
+	if n := len(f.words); n > 0 {
+		f.words[n-1].period = true
+		f.words[n-1].final = true
+	}
+	f.nextChar = c
+	return true
+}
+
+// getLine reads the words of a line, from c on, and returns the first
+// byte of the next line past its indentation.
+func (f *formatter) getLine(c int) int {
+	for c != '\n' && c != fileEnd {
+		f.current = f.current[:0]
+		for {
+			if f.chars == maxChars {
+				f.flushParagraph()
+			}
+			f.current = append(f.current, byte(c))
+			f.chars++
+			if c = f.getc(); c == fileEnd || isSpace(c) {
+				break
+			}
+		}
+
+		w := newWord(f.current)
+		f.inColumn += w.length
+		start := f.inColumn
+		c = f.getSpace(c)
+		w.space = f.inColumn - start
+		w.final = c == fileEnd || w.period && (c == '\n' || w.space > 1)
+		if c == '\n' || c == fileEnd || f.uniform {
+			w.space = 1
+			if w.final {
+				w.space = 2
+			}
+		}
+		if len(f.words) == maxWords-2 {
+			f.flushParagraph()
+		}
+		f.words = append(f.words, w)
+		f.current = f.current[:0]
+	}
+	return f.getPrefix()
+}
+
+// flushParagraph prints the first lines of a paragraph too long to be held
+// at once, up to the break that costs the least to the lines around it.
+func (f *formatter) flushParagraph() {
+	if len(f.words) == 0 {
+		f.out.Write(f.current)
+		f.chars -= len(f.current)
+		f.current = f.current[:0]
+		return
+	}
+
+	f.fmtParagraph()
+	n := len(f.words)
+	split, best := n, int64(maxCost)
+	w := f.words[:n+1]
+	for i := w[0].nextBreak; i != n; i = w[i].nextBreak {
+		if cost := w[i].bestCost - w[w[i].nextBreak].bestCost; cost < best {
+			split, best = i, cost
+		}
+		// Favor later breaks, which leave fewer lines to reformat.
+		if best <= maxCost-lineCost {
+			best += lineCost
+		}
+	}
+	f.putParagraph(split)
+
+	for _, printed := range f.words[:split] {
+		f.chars -= len(printed.text)
+	}
+	f.words = append(f.words[:0], f.words[split:]...)
+}
+
+// putParagraph prints the lines of the paragraph up to word finish.
+func (f *formatter) putParagraph(finish int) {
+	f.putLine(0, f.firstIndent)
+	for i := f.words[0].nextBreak; i != finish; i = f.words[i].nextBreak {
+		f.putLine(i, f.otherIndent)
+	}
+}
+
+// putLine prints the line starting with word i.
+func (f *formatter) putLine(i, indent int) {
+	f.outColumn = 0
+	f.putSpace(indent)
+	end := f.words[i].nextBreak - 1
+	for ; i != end; i++ {
+		f.putWord(i)
+		f.putSpace(f.words[i].space)
+	}
+	f.putWord(end)
+	f.lastLineLength = f.outColumn
+	f.out.WriteByte('\n')
+}
+
+// putWord prints word i.
+func (f *formatter) putWord(i int) {
+	f.out.Write(f.words[i].text)
+	f.outColumn += f.words[i].length
+}
+
+// putSpace prints space columns of blanks, using tabs where they fit when
+// the input did.
+func (f *formatter) putSpace(space int) {
+	target := f.outColumn + space
+	if f.tabs {
+		tabTarget := target / 8 * 8
+		if f.outColumn+1 < tabTarget {
+			for f.outColumn < tabTarget {
+				f.out.WriteByte('\t')
+				f.outColumn = (f.outColumn/8 + 1) * 8
+			}
+		}
+	}
+	for ; f.outColumn < target; f.outColumn++ {
+		f.out.WriteByte(' ')
+	}
+}
+
+// isSpace reports whether c is white space.
+func isSpace(c int) bool {
+	switch c {
+	case ' ', '\t', '\n', '\v', '\f', '\r':
+		return true
+	}
+	return false
+}
@@ -0,0 +1,69 @@
+package reflow
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+
+	"github.com/skraio/unix-utilities/internal/assert"
+)
+
+const text = "The quick brown fox jumps over the lazy dog.  It was fun.\n\n  indented   para with   spaces\n  second line\n"
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		width   int
+		split   bool
+		uniform bool
+		want    string
+	}{
+		{name: "Fill", input: text, width: 30, want: "The quick brown fox jumps\nover the lazy dog.  It was\nfun.\n\n  indented   para with\n  spaces second line\n"},
+		{name: "Uniform spacing", input: text, width: 30, uniform: true, want: "The quick brown fox jumps\nover the lazy dog.  It was\nfun.\n\n  indented para with spaces\n  second line\n"},
+		{name: "Split only", input: text, width: 20, split: true, want: "The quick brown fox\njumps over the lazy\ndog.  It was fun.\n\n  indented   para\n  with   spaces\n  second line\n"},
+		{name: "Tabs", input: "a\n\tb c d e f g\n\th i\n", width: 10, want: "a\n\tb\n\tc\n\td\n\te\n\tf\n\tg\n\th\n\ti\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out strings.Builder
+			f := &formatter{
+				out:       bufio.NewWriter(&out),
+				maxWidth:  tt.width,
+				goalWidth: tt.width * (2*(100-leeway) + 1) / 200,
+				split:     tt.split,
+				uniform:   tt.uniform,
+			}
+			if err := f.format(strings.NewReader(tt.input)); err != nil {
+				t.Fatal(err)
+			}
+			f.out.Flush()
+			assert.Equal(t, out.String(), tt.want)
+		})
+	}
+}
+
+func TestParseWidth(t *testing.T) {
+	tests := []struct {
+		arg  string
+		want int
+		err  string
+	}{
+		{arg: "40", want: 40},
+		{arg: "x", err: "invalid width: 'x'"},
+		{arg: "-1", err: "invalid width: '-1'"},
+		{arg: "3000", err: "invalid width: '3000': numerical result out of range"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.arg, func(t *testing.T) {
+			got, err := parseWidth(tt.arg, maxChars/2)
+			if tt.err != "" {
+				assert.Equal(t, err.Error(), tt.err)
+				return
+			}
+			assert.Equal(t, got, tt.want)
+		})
+	}
+}
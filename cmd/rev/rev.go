@@ -0,0 +1,102 @@
+// Package rev provides functionality for reversing the characters of each
+// line of files.
+package rev
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"unicode/utf8"
+
+	"github.com/skraio/unix-utilities/internal/exit"
+	"github.com/skraio/unix-utilities/internal/fileinput"
+	"github.com/skraio/unix-utilities/internal/lineio"
+	"github.com/spf13/cobra"
+)
+
+// Cmd represents the 'rev' command configuration using Cobra.
+var Cmd = &cobra.Command{
+	Use:           "rev [file]...",
+	Short:         "Reverse lines characterwise",
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return exit.Status(executeRev(args))
+	},
+}
+
+// init initializes the 'rev' command.
+func init() {
+	Cmd.Flags().SetInterspersed(false)
+
+	Cmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		fmt.Fprintf(os.Stderr, "rev: %v\n", err)
+		return exit.Status(1)
+	})
+}
+
+// executeRev executes the rev command with given arguments and returns its
+// exit status. Files that cannot be read are reported and skipped.
+func executeRev(args []string) int {
+	out := bufio.NewWriter(os.Stdout)
+	defer out.Flush()
+
+	status := 0
+	for _, name := range fileinput.Args(args) {
+		if err := reverseFile(out, name); err != nil {
+			out.Flush()
+			status = exit.Fail("rev", err)
+		}
+	}
+	return status
+}
+
+// reverseFile prints the lines of the named file reversed.
+func reverseFile(out *bufio.Writer, name string) error {
+	f, err := fileinput.Open(name)
+	if err != nil {
+		var pe *fs.PathError
+		if errors.As(err, &pe) {
+			return fmt.Errorf("cannot open %s: %v", pe.Path, exit.Unwrap(pe))
+		}
+		return err
+	}
+	defer fileinput.Close(f)
+	return reverseLines(out, f)
+}
+
+// reverseLines prints the lines read from r with their characters in
+// reverse order, each still followed by its newline.
+func reverseLines(out *bufio.Writer, r io.Reader) error {
+	lr := lineio.NewReader(r)
+	var reversed []byte
+	for {
+		line, err := lr.ReadLine()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		text := lr.TrimDelim(line)
+		reversed = appendReversed(reversed[:0], text)
+		reversed = append(reversed, line[len(text):]...)
+		if _, err := out.Write(reversed); err != nil {
+			return err
+		}
+	}
+}
+
+// appendReversed appends the characters of b to dst in reverse order.
+// Bytes that are not valid UTF-8 are taken as characters of their own.
+func appendReversed(dst, b []byte) []byte {
+	for len(b) > 0 {
+		_, size := utf8.DecodeLastRune(b)
+		dst = append(dst, b[len(b)-size:]...)
+		b = b[:len(b)-size]
+	}
+	return dst
+}
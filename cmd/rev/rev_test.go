@@ -0,0 +1,35 @@
+package rev
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+
+	"github.com/skraio/unix-utilities/internal/assert"
+)
+
+func TestReverseLines(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "Lines", input: "abc\nde\n", want: "cba\ned\n"},
+		{name: "No final newline", input: "abc\nde", want: "cba\ned"},
+		{name: "Multibyte characters", input: "héllo\n", want: "olléh\n"},
+		{name: "Invalid bytes", input: "a\xffb\n", want: "b\xffa\n"},
+		{name: "Empty lines", input: "\n\nx\n", want: "\n\nx\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out strings.Builder
+			w := bufio.NewWriter(&out)
+			if err := reverseLines(w, strings.NewReader(tt.input)); err != nil {
+				t.Fatal(err)
+			}
+			w.Flush()
+			assert.Equal(t, out.String(), tt.want)
+		})
+	}
+}
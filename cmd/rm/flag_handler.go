@@ -0,0 +1,177 @@
+package rm
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"github.com/skraio/unix-utilities/internal/exit"
+	"github.com/skraio/unix-utilities/internal/fileinfo"
+)
+
+// remover removes operands, recursively if asked, recording failures in
+// its exit status.
+type remover struct {
+	recursive     bool
+	force         bool
+	interactive   bool
+	dir           bool
+	preserveRoot  bool
+	oneFileSystem bool
+	in            *bufio.Reader
+	verbose       func(path string, isDir bool)
+	status        int
+}
+
+// report prints an error and makes rm exit unsuccessfully.
+func (r *remover) report(format string, a ...any) {
+	fmt.Fprintf(os.Stderr, "rm: "+format+"\n", a...)
+	r.status = 1
+}
+
+// fail reports that path cannot be removed.
+func (r *remover) fail(path string, err error) {
+	r.report("cannot remove '%s': %v", path, exit.Unwrap(err))
+}
+
+// removeOperand removes a command-line operand after the checks that only
+// apply to them.
+func (r *remover) removeOperand(path string) {
+	if base := filepath.Base(path); base == "." || base == ".." {
+		r.report("refusing to remove '.' or '..' directory: skipping '%s'", path)
+		return
+	}
+
+	info, err := os.Lstat(path)
+	if err != nil {
+		if !(r.force && errors.Is(err, fs.ErrNotExist)) {
+			r.fail(path, err)
+		}
+		return
+	}
+
+	if r.recursive && r.preserveRoot && info.IsDir() && isRoot(info) {
+		r.report("it is dangerous to operate recursively on '%s'", path)
+		r.report("use --no-preserve-root to override this failsafe")
+		return
+	}
+
+	r.remove(path, info, deviceOf(info))
+}
+
+// isRoot reports whether info describes the root directory.
+func isRoot(info fs.FileInfo) bool {
+	root, err := os.Lstat("/")
+	return err == nil && os.SameFile(info, root)
+}
+
+// deviceOf returns the device a file resides on.
+func deviceOf(info fs.FileInfo) uint64 {
+	if stat, ok := fileinfo.Sys(info); ok {
+		return uint64(stat.Dev)
+	}
+	return 0
+}
+
+// remove removes a file, or a directory with its contents, and reports
+// whether it is gone. dev is the device of the operand being removed.
+func (r *remover) remove(path string, info fs.FileInfo, dev uint64) bool {
+	if !info.IsDir() {
+		if r.interactive && !r.confirm("remove %s '%s'? ", describe(info), path) {
+			return false
+		}
+		return r.unlink(path, false)
+	}
+
+	if !r.recursive {
+		if !r.dir {
+			r.fail(path, syscall.EISDIR)
+			return false
+		}
+		if r.interactive && !r.confirm("remove directory '%s'? ", path) {
+			return false
+		}
+		return r.unlink(path, true)
+	}
+
+	if r.oneFileSystem && deviceOf(info) != dev {
+		r.report("skipping '%s', since it's on a different device", path)
+		return false
+	}
+
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		r.fail(path, err)
+		return false
+	}
+	if len(entries) > 0 && r.interactive && !r.confirm("descend into directory '%s'? ", path) {
+		return false
+	}
+
+	all := true
+	for _, e := range entries {
+		child := filepath.Join(path, e.Name())
+		childInfo, err := os.Lstat(child)
+		if err != nil {
+			r.fail(child, err)
+			all = false
+			continue
+		}
+		if !r.remove(child, childInfo, dev) {
+			all = false
+		}
+	}
+	if !all {
+		return false
+	}
+
+	if r.interactive && !r.confirm("remove directory '%s'? ", path) {
+		return false
+	}
+	return r.unlink(path, true)
+}
+
+// unlink removes a file or an empty directory.
+func (r *remover) unlink(path string, isDir bool) bool {
+	if err := os.Remove(path); err != nil {
+		if !(r.force && errors.Is(err, fs.ErrNotExist)) {
+			r.fail(path, err)
+		}
+		return false
+	}
+	if r.verbose != nil {
+		r.verbose(path, isDir)
+	}
+	return true
+}
+
+// confirm asks a question on the standard error and reports whether the
+// answer read from the standard input starts with 'y'.
+func (r *remover) confirm(format string, a ...any) bool {
+	fmt.Fprintf(os.Stderr, "rm: "+format, a...)
+	answer, _ := r.in.ReadString('\n')
+	return strings.HasPrefix(strings.ToLower(strings.TrimSpace(answer)), "y")
+}
+
+// describe returns the description of the type of a file used in prompts.
+func describe(info fs.FileInfo) string {
+	mode := info.Mode()
+	switch {
+	case mode.IsRegular() && info.Size() == 0:
+		return "regular empty file"
+	case mode.IsRegular():
+		return "regular file"
+	case mode&fs.ModeSymlink != 0:
+		return "symbolic link"
+	case mode&fs.ModeNamedPipe != 0:
+		return "fifo"
+	case mode&fs.ModeSocket != 0:
+		return "socket"
+	}
+	return "special file"
+}
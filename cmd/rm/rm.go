@@ -0,0 +1,98 @@
+// Package rm provides functionality for removing files and directories.
+package rm
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+
+	"github.com/skraio/unix-utilities/cmdflags"
+	"github.com/skraio/unix-utilities/internal/exit"
+	"github.com/spf13/cobra"
+)
+
+// rmFlags holds flags for rm command.
+type rmFlags struct {
+	recursive      bool
+	recursiveR     bool
+	force          bool
+	interactive    bool
+	dir            bool
+	verbose        bool
+	preserveRoot   bool
+	noPreserveRoot bool
+	oneFileSystem  bool
+}
+
+var pFlags rmFlags
+
+// flags definition for rm command.
+var flags = []cmdflags.Flag{
+	{Value: &pFlags.recursive, Name: "recursive", ShortHand: "r", DefaultValue: false, Description: "remove directories and their contents recursively"},
+	{Value: &pFlags.recursiveR, Name: "recursive-R", ShortHand: "R", DefaultValue: false, Description: "same as -r"},
+	{Value: &pFlags.force, Name: "force", ShortHand: "f", DefaultValue: false, Description: "ignore nonexistent files and arguments, never prompt"},
+	{Value: &pFlags.interactive, Name: "interactive", ShortHand: "i", DefaultValue: false, Description: "prompt before every removal"},
+	{Value: &pFlags.dir, Name: "dir", ShortHand: "d", DefaultValue: false, Description: "remove empty directories"},
+	{Value: &pFlags.verbose, Name: "verbose", ShortHand: "v", DefaultValue: false, Description: "explain what is being done"},
+	{Value: &pFlags.preserveRoot, Name: "preserve-root", ShortHand: "", DefaultValue: true, Description: "do not remove '/'"},
+	{Value: &pFlags.noPreserveRoot, Name: "no-preserve-root", ShortHand: "", DefaultValue: false, Description: "do not treat '/' specially"},
+	{Value: &pFlags.oneFileSystem, Name: "one-file-system", ShortHand: "", DefaultValue: false, Description: "when removing a hierarchy recursively, skip any directory that is on a different file system"},
+}
+
+// Cmd represents the 'rm' command configuration using Cobra.
+var Cmd = &cobra.Command{
+	Use:           "rm [-f flags] file...",
+	Short:         "Remove files or directories",
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return exit.Status(executeRm(args))
+	},
+}
+
+// init initializes the 'rm' command by setting up flags.
+func init() {
+	cmdflags.ParseFlags(flags, Cmd)
+	Cmd.Flags().Lookup("recursive-R").Hidden = true
+
+	Cmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		fmt.Fprintf(os.Stderr, "rm: %v\n", err)
+		return exit.Status(1)
+	})
+}
+
+// executeRm executes the rm command with given arguments and returns its
+// exit status.
+func executeRm(args []string) int {
+	if len(args) == 0 {
+		if pFlags.force {
+			return 0
+		}
+		fmt.Fprintln(os.Stderr, "rm: missing operand")
+		return 1
+	}
+
+	r := &remover{
+		recursive:     pFlags.recursive || pFlags.recursiveR,
+		force:         pFlags.force,
+		interactive:   pFlags.interactive && !pFlags.force,
+		dir:           pFlags.dir,
+		preserveRoot:  pFlags.preserveRoot && !pFlags.noPreserveRoot,
+		oneFileSystem: pFlags.oneFileSystem,
+		in:            bufio.NewReader(os.Stdin),
+	}
+	if pFlags.verbose {
+		r.verbose = func(path string, isDir bool) {
+			if isDir {
+				fmt.Printf("removed directory '%s'\n", path)
+			} else {
+				fmt.Printf("removed '%s'\n", path)
+			}
+		}
+	}
+
+	for _, arg := range args {
+		r.removeOperand(arg)
+	}
+	return r.status
+}
@@ -0,0 +1,104 @@
+package rm
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/skraio/unix-utilities/internal/assert"
+)
+
+func TestRemove(t *testing.T) {
+	setup := func(t *testing.T) string {
+		dir := t.TempDir()
+		for _, name := range []string{"tree/a", "tree/sub/b", "file"} {
+			path := filepath.Join(dir, name)
+			if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(path, nil, 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := os.Mkdir(filepath.Join(dir, "empty"), 0755); err != nil {
+			t.Fatal(err)
+		}
+		return dir
+	}
+	exists := func(dir, name string) bool {
+		_, err := os.Lstat(filepath.Join(dir, name))
+		return err == nil
+	}
+
+	tests := []struct {
+		name    string
+		r       remover
+		input   string
+		args    []string
+		status  int
+		removed []string
+		kept    []string
+	}{
+		{
+			name:    "Files only",
+			args:    []string{"file", "tree", "missing"},
+			status:  1,
+			removed: []string{"file"},
+			kept:    []string{"tree"},
+		},
+		{
+			name:    "Force ignores missing files",
+			r:       remover{force: true},
+			args:    []string{"file", "missing"},
+			removed: []string{"file"},
+		},
+		{
+			name:    "Empty directory",
+			r:       remover{dir: true},
+			args:    []string{"empty"},
+			removed: []string{"empty"},
+		},
+		{
+			name:    "Recursive",
+			r:       remover{recursive: true},
+			args:    []string{"tree", "empty"},
+			removed: []string{"tree", "empty"},
+		},
+		{
+			name:    "Interactive",
+			r:       remover{recursive: true, interactive: true},
+			input:   "y\ny\nn\n",
+			args:    []string{"tree"},
+			removed: []string{"tree/a"},
+			kept:    []string{"tree/sub/b", "tree"},
+		},
+		{
+			name:   "Dot",
+			r:      remover{recursive: true},
+			args:   []string{"tree/."},
+			status: 1,
+			kept:   []string{"tree/a"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := setup(t)
+			r := tt.r
+			r.in = bufio.NewReader(strings.NewReader(tt.input))
+			for _, arg := range tt.args {
+				r.removeOperand(dir + "/" + arg)
+			}
+
+			assert.Equal(t, r.status, tt.status)
+			for _, name := range tt.removed {
+				assert.Equal(t, exists(dir, name), false)
+			}
+			for _, name := range tt.kept {
+				assert.Equal(t, exists(dir, name), true)
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+// Package rmdir provides functionality for removing empty directories.
+package rmdir
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"github.com/skraio/unix-utilities/cmdflags"
+	"github.com/skraio/unix-utilities/internal/exit"
+	"github.com/spf13/cobra"
+)
+
+// rmdirFlags holds flags for rmdir command.
+type rmdirFlags struct {
+	parents        bool
+	ignoreNonEmpty bool
+	verbose        bool
+}
+
+var pFlags rmdirFlags
+
+// flags definition for rmdir command.
+var flags = []cmdflags.Flag{
+	{Value: &pFlags.parents, Name: "parents", ShortHand: "p", DefaultValue: false, Description: "remove DIRECTORY and its ancestors; e.g., 'rmdir -p a/b' is similar to 'rmdir a/b a'"},
+	{Value: &pFlags.ignoreNonEmpty, Name: "ignore-fail-on-non-empty", ShortHand: "", DefaultValue: false, Description: "ignore each failure that is solely because a directory is non-empty"},
+	{Value: &pFlags.verbose, Name: "verbose", ShortHand: "v", DefaultValue: false, Description: "output a diagnostic for every directory processed"},
+}
+
+// Cmd represents the 'rmdir' command configuration using Cobra.
+var Cmd = &cobra.Command{
+	Use:           "rmdir [-f flags] directory...",
+	Short:         "Remove empty directories",
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return exit.Status(executeRmdir(args))
+	},
+}
+
+// init initializes the 'rmdir' command by setting up flags.
+func init() {
+	cmdflags.ParseFlags(flags, Cmd)
+
+	Cmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		fmt.Fprintf(os.Stderr, "rmdir: %v\n", err)
+		return exit.Status(1)
+	})
+}
+
+// executeRmdir executes the rmdir command with given arguments and returns
+// its exit status.
+func executeRmdir(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, "rmdir: missing operand")
+		return 1
+	}
+
+	status := 0
+	for _, dir := range args {
+		if err := removeDir(dir, pFlags.parents); err != nil {
+			fmt.Fprintf(os.Stderr, "rmdir: %v\n", err)
+			status = 1
+		}
+	}
+	return status
+}
+
+// removeDir removes an empty directory and, with parents, each of its
+// ancestors named in the path in turn.
+func removeDir(dir string, parents bool) error {
+	for {
+		if pFlags.verbose {
+			fmt.Printf("rmdir: removing directory, '%s'\n", dir)
+		}
+		if err := remove(dir); err != nil {
+			if pFlags.ignoreNonEmpty && isNotEmpty(err) {
+				return nil
+			}
+			return fmt.Errorf("failed to remove '%s': %v", dir, exit.Unwrap(err))
+		}
+
+		if !parents {
+			return nil
+		}
+		parent := filepath.Dir(strings.TrimRight(dir, "/"))
+		if parent == "." || parent == "/" || parent == dir {
+			return nil
+		}
+		dir = parent
+	}
+}
+
+// remove removes an empty directory, refusing other kinds of files.
+func remove(dir string) error {
+	info, err := os.Lstat(dir)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return syscall.ENOTDIR
+	}
+	return syscall.Rmdir(dir)
+}
+
+// isNotEmpty reports whether err says a directory is not empty.
+func isNotEmpty(err error) bool {
+	return errors.Is(err, syscall.ENOTEMPTY) || errors.Is(err, syscall.EEXIST)
+}
@@ -0,0 +1,36 @@
+package rmdir
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/skraio/unix-utilities/internal/assert"
+)
+
+func TestRemoveDir(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "a/b/c"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "a/file"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	exists := func(name string) bool {
+		_, err := os.Stat(filepath.Join(dir, name))
+		return err == nil
+	}
+
+	pFlags = rmdirFlags{}
+	assert.Equal(t, removeDir(filepath.Join(dir, "a/file"), false) != nil, true)
+	assert.Equal(t, removeDir(filepath.Join(dir, "a"), false) != nil, true)
+
+	// Removing the parents stops at 'a', which is not empty.
+	assert.Equal(t, removeDir(filepath.Join(dir, "a/b/c"), true) != nil, true)
+	assert.Equal(t, exists("a/b"), false)
+	assert.Equal(t, exists("a"), true)
+
+	pFlags = rmdirFlags{ignoreNonEmpty: true}
+	assert.Equal(t, removeDir(filepath.Join(dir, "a"), false), nil)
+	assert.Equal(t, exists("a"), true)
+}
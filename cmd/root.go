@@ -3,13 +3,61 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 
+	"github.com/skraio/unix-utilities/cmd/basenc"
 	"github.com/skraio/unix-utilities/cmd/cat"
+	"github.com/skraio/unix-utilities/cmd/chgrp"
+	"github.com/skraio/unix-utilities/cmd/chmod"
+	"github.com/skraio/unix-utilities/cmd/chown"
+	"github.com/skraio/unix-utilities/cmd/cksum"
+	"github.com/skraio/unix-utilities/cmd/cmp"
+	"github.com/skraio/unix-utilities/cmd/column"
+	"github.com/skraio/unix-utilities/cmd/comm"
+	"github.com/skraio/unix-utilities/cmd/cp"
+	"github.com/skraio/unix-utilities/cmd/csplit"
+	"github.com/skraio/unix-utilities/cmd/cut"
+	"github.com/skraio/unix-utilities/cmd/df"
+	"github.com/skraio/unix-utilities/cmd/diff"
+	"github.com/skraio/unix-utilities/cmd/du"
+	"github.com/skraio/unix-utilities/cmd/expand"
+	"github.com/skraio/unix-utilities/cmd/find"
+	"github.com/skraio/unix-utilities/cmd/fold"
+	"github.com/skraio/unix-utilities/cmd/grep"
+	"github.com/skraio/unix-utilities/cmd/hashsum"
+	"github.com/skraio/unix-utilities/cmd/hexdump"
+	"github.com/skraio/unix-utilities/cmd/join"
+	"github.com/skraio/unix-utilities/cmd/ln"
 	"github.com/skraio/unix-utilities/cmd/ls"
+	"github.com/skraio/unix-utilities/cmd/mkdir"
+	"github.com/skraio/unix-utilities/cmd/mv"
+	"github.com/skraio/unix-utilities/cmd/nl"
+	"github.com/skraio/unix-utilities/cmd/od"
+	"github.com/skraio/unix-utilities/cmd/paste"
+	"github.com/skraio/unix-utilities/cmd/patch"
+	"github.com/skraio/unix-utilities/cmd/reflow"
+	"github.com/skraio/unix-utilities/cmd/rev"
+	"github.com/skraio/unix-utilities/cmd/rm"
+	"github.com/skraio/unix-utilities/cmd/rmdir"
+	"github.com/skraio/unix-utilities/cmd/search"
+	"github.com/skraio/unix-utilities/cmd/sort"
+	"github.com/skraio/unix-utilities/cmd/split"
+	"github.com/skraio/unix-utilities/cmd/stat"
+	"github.com/skraio/unix-utilities/cmd/tac"
+	"github.com/skraio/unix-utilities/cmd/tail"
+	"github.com/skraio/unix-utilities/cmd/touch"
+	"github.com/skraio/unix-utilities/cmd/tr"
+	"github.com/skraio/unix-utilities/cmd/trash"
+	"github.com/skraio/unix-utilities/cmd/uniq"
 	"github.com/skraio/unix-utilities/cmd/wc"
+	"github.com/skraio/unix-utilities/cmd/xargs"
+	"github.com/skraio/unix-utilities/cmd/xxd"
+	"github.com/skraio/unix-utilities/internal/exit"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 // rootCmd represents the root command of the application.
@@ -17,10 +65,9 @@ var rootCmd = &cobra.Command{
 	Use:   "unix-utils",
 	Short: "Unix Utility Commands",
 	Long:  "Implementation of various Unix utility commands in Go.",
-	Run: func(cmd *cobra.Command, args []string) {
-		// Default behavior to display usage when no command is specified.
-		cmd.Usage()
-	},
+
+	SilenceErrors: true,
+	SilenceUsage:  true,
 }
 
 // init initializes the root command and adds subcommands to it.
@@ -28,12 +75,113 @@ func init() {
 	rootCmd.AddCommand(wc.Cmd)
 	rootCmd.AddCommand(ls.Cmd)
 	rootCmd.AddCommand(cat.Cmd)
+	rootCmd.AddCommand(uniq.Cmd)
+	rootCmd.AddCommand(grep.Cmd)
+	rootCmd.AddCommand(tail.Cmd)
+	rootCmd.AddCommand(search.Cmd)
+	rootCmd.AddCommand(sort.Cmd)
+	rootCmd.AddCommand(cut.Cmd)
+	rootCmd.AddCommand(paste.Cmd)
+	rootCmd.AddCommand(join.Cmd)
+	rootCmd.AddCommand(tr.Cmd)
+	rootCmd.AddCommand(find.Cmd)
+	rootCmd.AddCommand(du.Cmd)
+	rootCmd.AddCommand(df.Cmd)
+	rootCmd.AddCommand(stat.Cmd)
+	rootCmd.AddCommand(cp.Cmd)
+	rootCmd.AddCommand(mv.Cmd)
+	rootCmd.AddCommand(rm.Cmd)
+	rootCmd.AddCommand(mkdir.Cmd)
+	rootCmd.AddCommand(rmdir.Cmd)
+	rootCmd.AddCommand(ln.Cmd)
+	rootCmd.AddCommand(touch.Cmd)
+	rootCmd.AddCommand(trash.Cmd)
+	rootCmd.AddCommand(chmod.Cmd)
+	rootCmd.AddCommand(chown.Cmd)
+	rootCmd.AddCommand(chgrp.Cmd)
+	rootCmd.AddCommand(hashsum.MD5Cmd)
+	rootCmd.AddCommand(hashsum.SHA1Cmd)
+	rootCmd.AddCommand(hashsum.SHA256Cmd)
+	rootCmd.AddCommand(hashsum.SHA512Cmd)
+	rootCmd.AddCommand(hashsum.B2Cmd)
+	rootCmd.AddCommand(cksum.Cmd)
+	rootCmd.AddCommand(basenc.Base64Cmd)
+	rootCmd.AddCommand(basenc.Base32Cmd)
+	rootCmd.AddCommand(basenc.Cmd)
+	rootCmd.AddCommand(od.Cmd)
+	rootCmd.AddCommand(hexdump.Cmd)
+	rootCmd.AddCommand(xxd.Cmd)
+	rootCmd.AddCommand(split.Cmd)
+	rootCmd.AddCommand(csplit.Cmd)
+	rootCmd.AddCommand(diff.Cmd)
+	rootCmd.AddCommand(patch.Cmd)
+	rootCmd.AddCommand(comm.Cmd)
+	rootCmd.AddCommand(cmp.Cmd)
+	rootCmd.AddCommand(nl.Cmd)
+	rootCmd.AddCommand(tac.Cmd)
+	rootCmd.AddCommand(rev.Cmd)
+	rootCmd.AddCommand(fold.Cmd)
+	rootCmd.AddCommand(reflow.Cmd)
+	rootCmd.AddCommand(expand.Cmd)
+	rootCmd.AddCommand(expand.UnexpandCmd)
+	rootCmd.AddCommand(column.Cmd)
+	rootCmd.AddCommand(xargs.Cmd)
+
+	xargs.Builtin = builtin
+	initMulticall()
+}
+
+// report prints err unless the command reporting it has already printed its
+// diagnostics, and returns the exit status for it.
+func report(err error) int {
+	var status *exit.Error
+	if err != nil && !errors.As(err, &status) {
+		fmt.Fprintln(os.Stderr, err)
+	}
+	return exit.Code(err)
+}
+
+// builtin returns a function running the named command in this process
+// with the given arguments and returning its exit status, or nil when
+// there is no such command.
+func builtin(name string) func(args []string) int {
+	c, _, err := rootCmd.Find([]string{name})
+	if err != nil || c == rootCmd {
+		return nil
+	}
+	return func(args []string) int {
+		resetFlags(c)
+		rootCmd.SetArgs(append([]string{name}, args...))
+		defer rootCmd.SetArgs(nil)
+		return report(rootCmd.Execute())
+	}
 }
 
-// Execute runs the root command, handling any errors.
+// resetFlags sets every flag of c back to its default, which parsing the
+// arguments of the next run does not do. Flags not given are reset too, as
+// commands may set the values behind them themselves.
+func resetFlags(c *cobra.Command) {
+	c.Flags().VisitAll(func(f *pflag.Flag) {
+		switch v := f.Value.(type) {
+		case interface{ Reset() }:
+			v.Reset()
+		case pflag.SliceValue:
+			v.Replace(nil)
+		default:
+			v.Set(f.DefValue)
+		}
+		f.Changed = false
+	})
+}
+
+// Execute runs the root command, handling any errors. Commands reporting an
+// exit status have already printed their diagnostics. Run through a link
+// named after one of the commands, the executable runs that command.
 func Execute() {
+	if name := filepath.Base(os.Args[0]); applet(name) != nil {
+		rootCmd.SetArgs(append([]string{name}, os.Args[1:]...))
+	}
 	if err := rootCmd.Execute(); err != nil {
-		fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
+		os.Exit(report(err))
 	}
 }
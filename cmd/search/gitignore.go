@@ -0,0 +1,212 @@
+package search
+
+import (
+	"bufio"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// ignoreRule is a single pattern of a .gitignore file.
+type ignoreRule struct {
+	re      *regexp.Regexp
+	negate  bool
+	dirOnly bool
+}
+
+// ignoreFile holds the rules of a .gitignore file, which apply to paths
+// relative to the directory containing it.
+type ignoreFile struct {
+	dir   string
+	rules []ignoreRule
+	// prefix is the path of dir relative to the directory containing the
+	// file, for a file above the search root, which dir is then.
+	prefix string
+}
+
+// ignoreStack is the chain of .gitignore files from the repository root
+// down to the directory being walked. Deeper files take precedence.
+type ignoreStack []*ignoreFile
+
+// readIgnoreFile parses the .gitignore file in dir, returning nil when there
+// is none.
+func readIgnoreFile(dir string) *ignoreFile {
+	f, err := os.Open(path.Join(dir, ".gitignore"))
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	ig := &ignoreFile{dir: dir}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if rule, ok := parseIgnoreRule(scanner.Text()); ok {
+			ig.rules = append(ig.rules, rule)
+		}
+	}
+
+	return ig
+}
+
+// parseIgnoreRule parses a line of a .gitignore file. It reports false for
+// blank lines, comments and invalid patterns.
+func parseIgnoreRule(line string) (ignoreRule, bool) {
+	line = strings.TrimRight(line, "\r")
+	if !strings.HasSuffix(line, `\ `) {
+		line = strings.TrimRight(line, " ")
+	}
+	if line == "" || strings.HasPrefix(line, "#") {
+		return ignoreRule{}, false
+	}
+
+	rule := ignoreRule{}
+	if strings.HasPrefix(line, "!") {
+		rule.negate = true
+		line = line[1:]
+	} else if strings.HasPrefix(line, `\!`) || strings.HasPrefix(line, `\#`) {
+		line = line[1:]
+	}
+
+	if strings.HasSuffix(line, "/") {
+		rule.dirOnly = true
+		line = strings.TrimSuffix(line, "/")
+	}
+
+	// A pattern containing a slash other than a trailing one is relative to
+	// the directory of the .gitignore file; otherwise it matches at any depth.
+	anchored := strings.Contains(line, "/")
+	line = strings.TrimPrefix(line, "/")
+	if line == "" {
+		return ignoreRule{}, false
+	}
+
+	expr := globToRegexp(line)
+	if !anchored {
+		expr = "(?:.*/)?" + expr
+	}
+
+	re, err := regexp.Compile("^" + expr + "$")
+	if err != nil {
+		return ignoreRule{}, false
+	}
+	rule.re = re
+
+	return rule, true
+}
+
+// globToRegexp translates a gitignore glob into a regular expression, where
+// "**" matches across directories and other wildcards stop at slashes.
+func globToRegexp(glob string) string {
+	var b strings.Builder
+	for i := 0; i < len(glob); i++ {
+		c := glob[i]
+		switch {
+		case strings.HasPrefix(glob[i:], "**/"):
+			b.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(glob[i:], "/**") && i+3 == len(glob):
+			b.WriteString("/.*")
+			i += 2
+		case strings.HasPrefix(glob[i:], "**"):
+			b.WriteString(".*")
+			i++
+		case c == '*':
+			b.WriteString("[^/]*")
+		case c == '?':
+			b.WriteString("[^/]")
+		case c == '[':
+			end := strings.IndexByte(glob[i+1:], ']')
+			if end < 0 {
+				b.WriteString(`\[`)
+				continue
+			}
+			class := glob[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			b.WriteString("[" + strings.ReplaceAll(class, `\`, `\\`) + "]")
+			i += end + 1
+		case c == '\\' && i+1 < len(glob):
+			i++
+			b.WriteString(regexp.QuoteMeta(string(glob[i])))
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+
+	return b.String()
+}
+
+// match reports whether the file at rel, relative to the .gitignore file, is
+// ignored, and whether any rule decided it.
+func (ig *ignoreFile) match(rel string, isDir bool) (ignored, decided bool) {
+	for i := len(ig.rules) - 1; i >= 0; i-- {
+		r := ig.rules[i]
+		if r.dirOnly && !isDir {
+			continue
+		}
+		if r.re.MatchString(rel) {
+			return !r.negate, true
+		}
+	}
+	return false, false
+}
+
+// parentIgnores returns the stack of .gitignore files in the directories
+// above root, up to the root of the repository containing it, which holds
+// .git. It is empty when root is not inside a repository.
+func parentIgnores(root string) ignoreStack {
+	abs, err := filepath.Abs(root)
+	if err != nil {
+		return nil
+	}
+
+	var stack ignoreStack
+	dir, prefix := filepath.ToSlash(abs), ""
+	for {
+		if _, err := os.Stat(path.Join(dir, ".git")); err == nil {
+			return stack
+		}
+		parent := path.Dir(dir)
+		if parent == dir {
+			return nil
+		}
+		dir, prefix = parent, path.Join(path.Base(dir), prefix)
+
+		if ig := readIgnoreFile(dir); ig != nil {
+			ig.dir, ig.prefix = root, prefix
+			stack = append(ignoreStack{ig}, stack...)
+		}
+	}
+}
+
+// push returns the stack extended with the .gitignore file of dir, if any.
+func (s ignoreStack) push(dir string) ignoreStack {
+	ig := readIgnoreFile(dir)
+	if ig == nil {
+		return s
+	}
+
+	stack := make(ignoreStack, len(s), len(s)+1)
+	copy(stack, s)
+	return append(stack, ig)
+}
+
+// ignored reports whether the file at p is excluded by the stack.
+func (s ignoreStack) ignored(p string, isDir bool) bool {
+	for i := len(s) - 1; i >= 0; i-- {
+		rel := strings.TrimPrefix(p, s[i].dir+"/")
+		if s[i].dir == "." {
+			rel = strings.TrimPrefix(p, "./")
+		}
+		if s[i].prefix != "" {
+			rel = s[i].prefix + "/" + rel
+		}
+		if ignored, decided := s[i].match(rel, isDir); decided {
+			return ignored
+		}
+	}
+	return false
+}
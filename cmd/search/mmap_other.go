@@ -0,0 +1,19 @@
+//go:build !unix
+
+package search
+
+import (
+	"io"
+	"os"
+)
+
+// mapFile reads size bytes of f, as memory maps are not available on this
+// platform.
+func mapFile(f *os.File, size int64) ([]byte, func(), error) {
+	data := make([]byte, size)
+	if _, err := io.ReadFull(f, data); err != nil {
+		return nil, nil, err
+	}
+
+	return data, func() {}, nil
+}
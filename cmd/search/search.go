@@ -0,0 +1,312 @@
+// Package search provides functionality for searching directory trees for a
+// pattern in parallel, skipping ignored, hidden and binary files.
+package search
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"runtime"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+
+	"github.com/skraio/unix-utilities/cmdflags"
+	"github.com/skraio/unix-utilities/internal/exit"
+	"github.com/spf13/cobra"
+)
+
+// Exit statuses of the search command.
+const (
+	statusMatch   = 0
+	statusNoMatch = 1
+	statusError   = 2
+)
+
+const (
+	// mmapThreshold is the size from which files are memory-mapped instead
+	// of read.
+	mmapThreshold = 1024 * 1024
+
+	// binaryPeekSize is the amount of a file inspected to detect binary data.
+	binaryPeekSize = 8 * 1024
+)
+
+// searchFlags holds flags for search command.
+type searchFlags struct {
+	ignoreCase       bool
+	fixed            bool
+	word             bool
+	hidden           bool
+	noIgnore         bool
+	filesWithMatches bool
+	count            bool
+	heading          bool
+	sortFiles        bool
+	text             bool
+	threads          int
+}
+
+var pFlags searchFlags
+
+// flags definition for search command.
+var flags = []cmdflags.Flag{
+	{Value: &pFlags.ignoreCase, Name: "ignore-case", ShortHand: "i", DefaultValue: false, Description: "search case insensitively"},
+	{Value: &pFlags.fixed, Name: "fixed-strings", ShortHand: "F", DefaultValue: false, Description: "treat the pattern as a literal string"},
+	{Value: &pFlags.word, Name: "word-regexp", ShortHand: "w", DefaultValue: false, Description: "only show matches surrounded by word boundaries"},
+	{Value: &pFlags.hidden, Name: "hidden", ShortHand: "", DefaultValue: false, Description: "search hidden files and directories"},
+	{Value: &pFlags.noIgnore, Name: "no-ignore", ShortHand: "", DefaultValue: false, Description: "don't respect .gitignore files"},
+	{Value: &pFlags.filesWithMatches, Name: "files-with-matches", ShortHand: "l", DefaultValue: false, Description: "print only the paths with at least one match"},
+	{Value: &pFlags.count, Name: "count", ShortHand: "c", DefaultValue: false, Description: "print only the number of matching lines per file"},
+	{Value: &pFlags.heading, Name: "heading", ShortHand: "", DefaultValue: false, Description: "print the file path above its matches"},
+	{Value: &pFlags.sortFiles, Name: "sort-files", ShortHand: "", DefaultValue: false, Description: "print results sorted by file path"},
+	{Value: &pFlags.text, Name: "text", ShortHand: "a", DefaultValue: false, Description: "search binary files as if they were text"},
+}
+
+// intFlags definition for search command.
+var intFlags = []cmdflags.IntFlag{
+	{Value: &pFlags.threads, Name: "threads", ShortHand: "j", DefaultValue: runtime.NumCPU(), Description: "number of files searched in parallel"},
+}
+
+// Cmd represents the 'search' command configuration using Cobra.
+var Cmd = &cobra.Command{
+	Use:           "search [-f flags] PATTERN [dir]",
+	Short:         "Recursively search a directory tree for a pattern",
+	Args:          cobra.RangeArgs(1, 2),
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return exit.Status(executeSearch(args))
+	},
+}
+
+// init initializes the 'search' command by setting up flags.
+func init() {
+	cmdflags.ParseFlags(flags, Cmd)
+	cmdflags.ParseIntFlags(intFlags, Cmd)
+
+	Cmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		fmt.Fprintf(os.Stderr, "search: %v\n", err)
+		return exit.Status(statusError)
+	})
+}
+
+// executeSearch executes the search command with given arguments and returns
+// its exit status.
+func executeSearch(args []string) int {
+	re, err := compilePattern(args[0], pFlags)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "search: %v\n", err)
+		return statusError
+	}
+
+	root := "."
+	if len(args) == 2 {
+		root = args[1]
+	}
+
+	s := newSearcher(re, os.Stdout, pFlags)
+	s.run(root)
+
+	switch {
+	case s.failed.Load():
+		return statusError
+	case s.matched.Load():
+		return statusMatch
+	}
+	return statusNoMatch
+}
+
+// compilePattern compiles the pattern according to the matching flags. The
+// multi-line flag lets a whole file be checked for a match at once.
+func compilePattern(pattern string, opts searchFlags) (*regexp.Regexp, error) {
+	if opts.fixed {
+		pattern = regexp.QuoteMeta(pattern)
+	}
+	if opts.word {
+		pattern = `\b(?:` + pattern + `)\b`
+	}
+
+	prefix := "(?m)"
+	if opts.ignoreCase {
+		prefix = "(?mi)"
+	}
+
+	return regexp.Compile(prefix + pattern)
+}
+
+// searcher searches files in parallel and writes the results of each file
+// in a single write, so the output of different files never interleaves.
+type searcher struct {
+	re      *regexp.Regexp
+	opts    searchFlags
+	out     io.Writer
+	mu      sync.Mutex
+	emitted bool
+	// results collects the output per file when sorting by path.
+	results map[string][]byte
+	matched atomic.Bool
+	failed  atomic.Bool
+}
+
+// newSearcher returns a searcher writing its results to out.
+func newSearcher(re *regexp.Regexp, out io.Writer, opts searchFlags) *searcher {
+	return &searcher{re: re, opts: opts, out: out, results: map[string][]byte{}}
+}
+
+// run searches every file below root.
+func (s *searcher) run(root string) {
+	files := make(chan string, 256)
+
+	var wg sync.WaitGroup
+	for i := 0; i < max(s.opts.threads, 1); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for p := range files {
+				s.searchFile(p)
+			}
+		}()
+	}
+
+	newWalker(files, s.opts, s.warn).walk(root)
+	close(files)
+	wg.Wait()
+
+	if s.opts.sortFiles {
+		paths := make([]string, 0, len(s.results))
+		for p := range s.results {
+			paths = append(paths, p)
+		}
+		sort.Strings(paths)
+
+		for _, p := range paths {
+			s.write(s.results[p])
+		}
+	}
+}
+
+// searchFile searches a single file and emits its results.
+func (s *searcher) searchFile(p string) {
+	data, release, err := readFile(p)
+	if err != nil {
+		s.warn(err)
+		return
+	}
+	defer release()
+
+	if !s.opts.text && bytes.IndexByte(data[:min(len(data), binaryPeekSize)], 0) >= 0 {
+		return
+	}
+	if !s.re.Match(data) {
+		return
+	}
+
+	var buf bytes.Buffer
+	if s.opts.heading && !s.opts.filesWithMatches && !s.opts.count {
+		buf.WriteString(p + "\n")
+	}
+
+	count := 0
+	for number, start := 1, 0; start < len(data); number++ {
+		end := bytes.IndexByte(data[start:], '\n')
+		if end < 0 {
+			end = len(data)
+		} else {
+			end += start
+		}
+		line := data[start:end]
+		start = end + 1
+
+		if !s.re.Match(line) {
+			continue
+		}
+
+		count++
+		if s.opts.filesWithMatches {
+			break
+		}
+		if s.opts.count {
+			continue
+		}
+
+		if !s.opts.heading {
+			buf.WriteString(p + ":")
+		}
+		buf.WriteString(strconv.Itoa(number) + ":")
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+
+	if count == 0 {
+		return
+	}
+	s.matched.Store(true)
+
+	switch {
+	case s.opts.filesWithMatches:
+		buf.WriteString(p + "\n")
+	case s.opts.count:
+		buf.WriteString(p + ":" + strconv.Itoa(count) + "\n")
+	}
+	s.emit(p, buf.Bytes())
+}
+
+// readFile returns the content of the file at p, memory-mapping large files.
+// The returned function releases the content.
+func readFile(p string) ([]byte, func(), error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if fi.Size() >= mmapThreshold {
+		return mapFile(f, fi.Size())
+	}
+
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return nil, nil, err
+	}
+	return data, func() {}, nil
+}
+
+// emit writes the results of a file, or keeps them when sorting by path.
+func (s *searcher) emit(p string, result []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.opts.sortFiles {
+		s.results[p] = bytes.Clone(result)
+		return
+	}
+	s.write(result)
+}
+
+// write writes the results of a file, separating them from the results of
+// the previous file when printing headings.
+func (s *searcher) write(result []byte) {
+	if s.opts.heading && s.emitted && !s.opts.filesWithMatches && !s.opts.count {
+		result = append([]byte{'\n'}, result...)
+	}
+	s.emitted = true
+	s.out.Write(result)
+}
+
+// warn reports an error and records that one occurred.
+func (s *searcher) warn(err error) {
+	s.failed.Store(true)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	exit.Fail("search", err)
+}
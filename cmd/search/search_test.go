@@ -0,0 +1,183 @@
+package search
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/skraio/unix-utilities/internal/assert"
+)
+
+func TestParseIgnoreRule(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    string
+		path    string
+		isDir   bool
+		want    bool
+		negated bool
+	}{
+		{
+			name: "Basename at any depth",
+			rule: "*.log",
+			path: "logs/app/server.log",
+			want: true,
+		},
+		{
+			name: "Anchored pattern",
+			rule: "/build",
+			path: "src/build",
+			want: false,
+		},
+		{
+			name:  "Directory only",
+			rule:  "out/",
+			path:  "out",
+			isDir: true,
+			want:  true,
+		},
+		{
+			name: "Directory only on a file",
+			rule: "out/",
+			path: "out",
+			want: false,
+		},
+		{
+			name: "Double star",
+			rule: "docs/**/*.md",
+			path: "docs/a/b/readme.md",
+			want: true,
+		},
+		{
+			name:    "Negation",
+			rule:    "!keep.log",
+			path:    "keep.log",
+			want:    true,
+			negated: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, ok := parseIgnoreRule(tt.rule)
+			if !ok {
+				t.Fatalf("rule %q not parsed", tt.rule)
+			}
+
+			ans := rule.re.MatchString(tt.path) && (!rule.dirOnly || tt.isDir)
+			assert.Equal(t, ans, tt.want)
+			assert.Equal(t, rule.negate, tt.negated)
+		})
+	}
+}
+
+func TestSearch(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		".git/HEAD":        "ref: refs/heads/main\n",
+		".gitignore":       "*.log\n!keep.log\nvendor/\n/lib/deep/skip.go\n",
+		"main.go":          "package main\n// needle here\n",
+		"lib/util.go":      "no match\nneedle again\n",
+		"app.log":          "needle\n",
+		"keep.log":         "needle\n",
+		"vendor/x.go":      "needle\n",
+		".hidden/h.go":     "needle\n",
+		"data.bin":         "needle\x00\n",
+		"lib/.gitignore":   "util.go\n",
+		"lib/deep/a.go":    "needle\n",
+		"lib/deep/a.log":   "needle\n",
+		"lib/deep/skip.go": "needle\n",
+		"lib/deep/util.go": "needle\n",
+	}
+	for name, content := range files {
+		writeFile(t, filepath.Join(root, name), content)
+	}
+
+	tests := []struct {
+		name string
+		dir  string
+		opts searchFlags
+		want string
+	}{
+		{
+			name: "Default filters",
+			opts: searchFlags{sortFiles: true},
+			want: "keep.log:1:needle\nlib/deep/a.go:1:needle\nmain.go:2:// needle here\n",
+		},
+		{
+			name: "No ignore files",
+			opts: searchFlags{sortFiles: true, noIgnore: true, filesWithMatches: true},
+			want: "app.log\nkeep.log\nlib/deep/a.go\nlib/deep/a.log\nlib/deep/skip.go\nlib/deep/util.go\nlib/util.go\nmain.go\nvendor/x.go\n",
+		},
+		{
+			name: "Ignore files above the root",
+			dir:  "lib/deep",
+			opts: searchFlags{sortFiles: true, filesWithMatches: true},
+			want: "lib/deep/a.go\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			re, err := compilePattern("needle", tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var out bytes.Buffer
+			s := newSearcher(re, &out, tt.opts)
+			s.run(filepath.Join(root, tt.dir))
+
+			ans := strings.ReplaceAll(out.String(), root+"/", "")
+			assert.Equal(t, ans, tt.want)
+		})
+	}
+}
+
+func BenchmarkSearch(b *testing.B) {
+	root := b.TempDir()
+	line := "Without just one nest a bird can call the world home\n"
+	for d := 0; d < 20; d++ {
+		for f := 0; f < 50; f++ {
+			content := strings.Repeat(line, 200)
+			if f%10 == 0 {
+				content += "Life is your career\n"
+			}
+			writeFile(b, filepath.Join(root, fmt.Sprintf("dir%d/file%d.txt", d, f)), content)
+		}
+	}
+
+	re, err := compilePattern("career", searchFlags{})
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	threadCounts := []int{1}
+	if n := runtime.NumCPU(); n > 1 {
+		threadCounts = append(threadCounts, n)
+	}
+
+	for _, threads := range threadCounts {
+		b.Run(fmt.Sprintf("threads=%d", threads), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				var out bytes.Buffer
+				newSearcher(re, &out, searchFlags{threads: threads}).run(root)
+			}
+		})
+	}
+}
+
+func writeFile(tb testing.TB, name, content string) {
+	tb.Helper()
+
+	if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+		tb.Fatal(err)
+	}
+	if err := os.WriteFile(name, []byte(content), 0644); err != nil {
+		tb.Fatal(err)
+	}
+}
@@ -0,0 +1,93 @@
+package search
+
+import (
+	"os"
+	"path"
+	"strings"
+	"sync"
+)
+
+// walker traverses a directory tree concurrently, sending the regular files
+// that are not hidden or ignored to a channel.
+type walker struct {
+	files    chan<- string
+	hidden   bool
+	noIgnore bool
+	// sem bounds the number of directories read at the same time.
+	sem     chan struct{}
+	wg      sync.WaitGroup
+	onError func(error)
+}
+
+// newWalker returns a walker reading up to concurrency directories at once.
+func newWalker(files chan<- string, opts searchFlags, onError func(error)) *walker {
+	return &walker{
+		files:    files,
+		hidden:   opts.hidden,
+		noIgnore: opts.noIgnore,
+		sem:      make(chan struct{}, max(opts.threads, 1)),
+		onError:  onError,
+	}
+}
+
+// walk sends the files below root and returns once the whole tree has been
+// traversed. A root that is not a directory is sent as is.
+func (w *walker) walk(root string) {
+	fi, err := os.Stat(root)
+	if err != nil {
+		w.onError(err)
+		return
+	}
+	if !fi.IsDir() {
+		w.files <- root
+		return
+	}
+
+	root = path.Clean(root)
+	var stack ignoreStack
+	if !w.noIgnore {
+		stack = parentIgnores(root)
+	}
+
+	w.wg.Add(1)
+	go w.walkDir(root, stack)
+	w.wg.Wait()
+}
+
+// walkDir sends the files of dir and starts walking its subdirectories, with
+// the .gitignore rules of the enclosing directories given by stack.
+func (w *walker) walkDir(dir string, stack ignoreStack) {
+	defer w.wg.Done()
+
+	if !w.noIgnore {
+		stack = stack.push(dir)
+	}
+
+	w.sem <- struct{}{}
+	entries, err := os.ReadDir(dir)
+	<-w.sem
+	if err != nil {
+		w.onError(err)
+		return
+	}
+
+	for _, e := range entries {
+		name := e.Name()
+		if name == ".git" || (!w.hidden && strings.HasPrefix(name, ".")) {
+			continue
+		}
+
+		p := path.Join(dir, name)
+		if !w.noIgnore && stack.ignored(p, e.IsDir()) {
+			continue
+		}
+
+		switch {
+		case e.IsDir():
+			w.wg.Add(1)
+			go w.walkDir(p, stack)
+		case e.Type().IsRegular():
+			w.files <- p
+		}
+	}
+}
@@ -0,0 +1,596 @@
+package sort
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/skraio/unix-utilities/internal/lineio"
+)
+
+// ordering holds the options controlling how two keys are compared.
+type ordering struct {
+	// mode is 0 for text comparison, or one of the n, g, h, M and V options.
+	mode        byte
+	reverse     bool
+	fold        bool
+	dictionary  bool
+	nonprinting bool
+}
+
+// key describes a sort key given by -k. Fields and characters are counted
+// from zero; eword is negative when the key extends to the end of the line
+// and echar is zero when it extends to the end of field eword.
+type key struct {
+	sword, schar int
+	eword, echar int
+	skipStart    bool
+	skipEnd      bool
+	ord          ordering
+}
+
+// comparator compares lines according to the keys and global options.
+type comparator struct {
+	keys []key
+	// tab is the field separator, or -1 to split fields at blanks.
+	tab int
+	// stable disables the last-resort comparison of whole lines.
+	stable  bool
+	reverse bool
+}
+
+// newComparator builds the comparator from the -k specifications and the
+// global options, which apply to keys that have no options of their own.
+func newComparator(specs []string, tab string, global ordering, blanks, stable bool) (*comparator, error) {
+	c := &comparator{tab: -1, stable: stable, reverse: global.reverse}
+
+	switch {
+	case tab == `\0`:
+		c.tab = 0
+	case len(tab) == 1:
+		c.tab = int(tab[0])
+	case tab != "":
+		return nil, fmt.Errorf("multi-character tab %q", tab)
+	}
+
+	for _, spec := range specs {
+		k, err := parseKey(spec, global, blanks)
+		if err != nil {
+			return nil, err
+		}
+		c.keys = append(c.keys, k)
+	}
+
+	if len(c.keys) == 0 {
+		c.keys = []key{{eword: -1, skipStart: blanks, skipEnd: blanks, ord: global}}
+	}
+
+	return c, nil
+}
+
+// parseKey parses a key specification of the form F[.C][OPTS][,F[.C][OPTS]].
+func parseKey(spec string, global ordering, blanks bool) (key, error) {
+	k := key{eword: -1}
+	invalid := fmt.Errorf("invalid key specification %q", spec)
+
+	start, end, hasEnd := strings.Cut(spec, ",")
+
+	field, char, opts, err := parsePosition(start)
+	if err != nil || field < 1 || char < 0 {
+		return key{}, invalid
+	}
+	if char == 0 {
+		char = 1
+	}
+	k.sword, k.schar = field-1, char-1
+	hasOpts, err := k.setOptions(opts, true)
+	if err != nil {
+		return key{}, invalid
+	}
+
+	if hasEnd {
+		field, char, opts, err := parsePosition(end)
+		if err != nil || field < 1 {
+			return key{}, invalid
+		}
+		k.eword, k.echar = field-1, char
+		endOpts, err := k.setOptions(opts, false)
+		if err != nil {
+			return key{}, invalid
+		}
+		hasOpts = hasOpts || endOpts
+	}
+
+	if !hasOpts {
+		k.ord = global
+		k.skipStart = blanks
+		k.skipEnd = blanks
+	}
+
+	return k, nil
+}
+
+// parsePosition splits a key position into its field, character and
+// trailing option letters. A missing character offset is returned as zero.
+func parsePosition(pos string) (int, int, string, error) {
+	i := 0
+	for i < len(pos) && '0' <= pos[i] && pos[i] <= '9' {
+		i++
+	}
+	field, err := strconv.Atoi(pos[:i])
+	if err != nil {
+		return 0, 0, "", err
+	}
+
+	char := 0
+	if i < len(pos) && pos[i] == '.' {
+		j := i + 1
+		for j < len(pos) && '0' <= pos[j] && pos[j] <= '9' {
+			j++
+		}
+		char, err = strconv.Atoi(pos[i+1 : j])
+		if err != nil {
+			return 0, 0, "", err
+		}
+		i = j
+	}
+
+	return field, char, pos[i:], nil
+}
+
+// setOptions applies the option letters of a key position and reports
+// whether any was given.
+func (k *key) setOptions(opts string, start bool) (bool, error) {
+	for _, o := range opts {
+		switch o {
+		case 'b':
+			if start {
+				k.skipStart = true
+			} else {
+				k.skipEnd = true
+			}
+		case 'd':
+			k.ord.dictionary = true
+		case 'f':
+			k.ord.fold = true
+		case 'i':
+			k.ord.nonprinting = true
+		case 'r':
+			k.ord.reverse = true
+		case 'n', 'g', 'h', 'M', 'V':
+			if k.ord.mode != 0 && k.ord.mode != byte(o) {
+				return false, fmt.Errorf("options '-%c%c' are incompatible", k.ord.mode, o)
+			}
+			k.ord.mode = byte(o)
+		default:
+			return false, fmt.Errorf("invalid option %q", o)
+		}
+	}
+	return opts != "", nil
+}
+
+// compare returns the order of lines a and b, falling back to comparing the
+// whole lines byte by byte when all keys are equal.
+func (c *comparator) compare(a, b []byte) int {
+	if r := c.compareKeys(a, b); r != 0 || c.stable {
+		return r
+	}
+
+	r := bytes.Compare(a, b)
+	if c.reverse {
+		return -r
+	}
+	return r
+}
+
+// compareKeys returns the order of lines a and b considering only the keys.
+func (c *comparator) compareKeys(a, b []byte) int {
+	for i := range c.keys {
+		k := &c.keys[i]
+		ka := a[c.begField(a, k):c.limField(a, k)]
+		kb := b[c.begField(b, k):c.limField(b, k)]
+
+		r := compareField(ka, kb, k.ord)
+		if r == 0 {
+			continue
+		}
+		if k.ord.reverse {
+			return -r
+		}
+		return r
+	}
+	return 0
+}
+
+// begField returns the offset at which key k starts in line.
+func (c *comparator) begField(line []byte, k *key) int {
+	ptr, lim := 0, len(line)
+	for sword := k.sword; ptr < lim && sword > 0; sword-- {
+		if c.tab >= 0 {
+			for ptr < lim && int(line[ptr]) != c.tab {
+				ptr++
+			}
+			if ptr < lim {
+				ptr++
+			}
+			continue
+		}
+		for ptr < lim && lineio.IsBlank(line[ptr]) {
+			ptr++
+		}
+		for ptr < lim && !lineio.IsBlank(line[ptr]) {
+			ptr++
+		}
+	}
+
+	if k.skipStart {
+		for ptr < lim && lineio.IsBlank(line[ptr]) {
+			ptr++
+		}
+	}
+
+	return min(lim, ptr+k.schar)
+}
+
+// limField returns the offset just past the end of key k in line.
+func (c *comparator) limField(line []byte, k *key) int {
+	ptr, lim := 0, len(line)
+	if k.eword < 0 {
+		return lim
+	}
+
+	eword := k.eword
+	if k.echar == 0 {
+		// The key extends over the whole end field.
+		eword++
+	}
+
+	for ; ptr < lim && eword > 0; eword-- {
+		if c.tab >= 0 {
+			for ptr < lim && int(line[ptr]) != c.tab {
+				ptr++
+			}
+			if ptr < lim && (eword > 1 || k.echar != 0) {
+				ptr++
+			}
+			continue
+		}
+		for ptr < lim && lineio.IsBlank(line[ptr]) {
+			ptr++
+		}
+		for ptr < lim && !lineio.IsBlank(line[ptr]) {
+			ptr++
+		}
+	}
+
+	if k.echar != 0 {
+		if k.skipEnd {
+			for ptr < lim && lineio.IsBlank(line[ptr]) {
+				ptr++
+			}
+		}
+		ptr = min(lim, ptr+k.echar)
+	}
+
+	return max(ptr, c.begField(line, k))
+}
+
+// compareField compares two extracted keys according to the ordering.
+func compareField(a, b []byte, ord ordering) int {
+	switch ord.mode {
+	case 'n':
+		return compareNumeric(a, b)
+	case 'g':
+		return compareGeneral(a, b)
+	case 'h':
+		return compareHuman(a, b)
+	case 'M':
+		return compareInts(monthIndex(a), monthIndex(b))
+	case 'V':
+		return compareVersion(a, b)
+	}
+	return compareText(a, b, ord)
+}
+
+// compareText compares keys byte by byte, skipping or folding characters as
+// requested by the -d, -i and -f options.
+func compareText(a, b []byte, ord ordering) int {
+	if !ord.fold && !ord.dictionary && !ord.nonprinting {
+		return bytes.Compare(a, b)
+	}
+
+	i, j := 0, 0
+	for {
+		for i < len(a) && ignored(a[i], ord) {
+			i++
+		}
+		for j < len(b) && ignored(b[j], ord) {
+			j++
+		}
+		if i == len(a) || j == len(b) {
+			return compareInts(len(a)-i, len(b)-j)
+		}
+
+		ca, cb := a[i], b[j]
+		if ord.fold {
+			ca, cb = toUpper(ca), toUpper(cb)
+		}
+		if ca != cb {
+			return compareInts(int(ca), int(cb))
+		}
+		i++
+		j++
+	}
+}
+
+// ignored reports whether c is skipped by the -d or -i options.
+func ignored(c byte, ord ordering) bool {
+	if ord.dictionary && !lineio.IsBlank(c) && !isAlnum(c) {
+		return true
+	}
+	return ord.nonprinting && (c < 0x20 || c >= 0x7f)
+}
+
+// compareNumeric compares the leading decimal numbers of a and b without
+// converting them, so numbers of any length are ordered exactly.
+func compareNumeric(a, b []byte) int {
+	aneg, aint, afrac := splitNumber(a)
+	bneg, bint, bfrac := splitNumber(b)
+
+	if aneg != bneg {
+		if aneg {
+			return -1
+		}
+		return 1
+	}
+
+	r := compareInts(len(aint), len(bint))
+	if r == 0 {
+		r = bytes.Compare(aint, bint)
+	}
+	if r == 0 {
+		r = bytes.Compare(afrac, bfrac)
+	}
+
+	if aneg {
+		return -r
+	}
+	return r
+}
+
+// splitNumber returns the sign, the integer digits without leading zeros and
+// the fraction digits without trailing zeros of the number at the start of
+// s. Negative zero is reported as positive.
+func splitNumber(s []byte) (bool, []byte, []byte) {
+	s = trimBlanks(s)
+
+	neg := false
+	if len(s) > 0 && s[0] == '-' {
+		neg = true
+		s = s[1:]
+	}
+
+	i := 0
+	for i < len(s) && isDigit(s[i]) {
+		i++
+	}
+	intPart := bytes.TrimLeft(s[:i], "0")
+
+	fracPart := []byte{}
+	if i < len(s) && s[i] == '.' {
+		j := i + 1
+		for j < len(s) && isDigit(s[j]) {
+			j++
+		}
+		fracPart = bytes.TrimRight(s[i+1:j], "0")
+	}
+
+	if len(intPart) == 0 && len(fracPart) == 0 {
+		neg = false
+	}
+
+	return neg, intPart, fracPart
+}
+
+// compareGeneral compares the leading floating-point numbers of a and b.
+// Keys that are not numbers sort first, followed by NaNs.
+func compareGeneral(a, b []byte) int {
+	fa, ra := parseFloatPrefix(a)
+	fb, rb := parseFloatPrefix(b)
+
+	if ra != rb || ra != 2 {
+		return compareInts(ra, rb)
+	}
+	switch {
+	case fa < fb:
+		return -1
+	case fa > fb:
+		return 1
+	}
+	return 0
+}
+
+// parseFloatPrefix parses the longest floating-point number at the start of
+// s. The rank is 0 for no number, 1 for NaN and 2 for any other number.
+func parseFloatPrefix(s []byte) (float64, int) {
+	str := string(trimBlanks(s))
+	if end := strings.IndexFunc(str, func(r rune) bool {
+		return !strings.ContainsRune("+-.0123456789eEpPxXaAfFiInNtTyY", r)
+	}); end >= 0 {
+		str = str[:end]
+	}
+
+	for end := len(str); end > 0; end-- {
+		f, err := strconv.ParseFloat(str[:end], 64)
+		if err != nil {
+			continue
+		}
+		if math.IsNaN(f) {
+			return f, 1
+		}
+		return f, 2
+	}
+	return 0, 0
+}
+
+// humanUnits lists the suffixes of human-readable sizes in increasing order.
+const humanUnits = "KMGTPEZYRQ"
+
+// compareHuman compares sizes such as 2K or 1G, first by sign and unit and
+// then by the number itself.
+func compareHuman(a, b []byte) int {
+	if r := compareInts(unitOrder(a), unitOrder(b)); r != 0 {
+		return r
+	}
+	return compareNumeric(a, b)
+}
+
+// unitOrder returns the rank of the size suffix following the number at the
+// start of s, negated for negative numbers.
+func unitOrder(s []byte) int {
+	neg, _, _ := splitNumber(s)
+
+	s = trimBlanks(s)
+	s = bytes.TrimPrefix(s, []byte("-"))
+	i := 0
+	for i < len(s) && (isDigit(s[i]) || s[i] == '.') {
+		i++
+	}
+
+	order := 0
+	if i < len(s) {
+		order = strings.IndexByte(humanUnits, toUpper(s[i])) + 1
+	}
+
+	if neg {
+		return -order
+	}
+	return order
+}
+
+// months lists the month abbreviations recognized by -M.
+var months = []string{"JAN", "FEB", "MAR", "APR", "MAY", "JUN", "JUL", "AUG", "SEP", "OCT", "NOV", "DEC"}
+
+// monthIndex returns the month number of the abbreviation at the start of s,
+// or zero for unknown names.
+func monthIndex(s []byte) int {
+	s = trimBlanks(s)
+	if len(s) < 3 {
+		return 0
+	}
+
+	name := strings.ToUpper(string(s[:3]))
+	for i, m := range months {
+		if m == name {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// compareVersion compares version numbers within text, ordering runs of
+// digits numerically and other characters with letters before punctuation
+// and '~' before anything, even the end of the string.
+func compareVersion(a, b []byte) int {
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		firstDiff := 0
+		for (i < len(a) && !isDigit(a[i])) || (j < len(b) && !isDigit(b[j])) {
+			ca, cb := versionOrder(a, i), versionOrder(b, j)
+			if ca != cb {
+				return compareInts(ca, cb)
+			}
+			i++
+			j++
+		}
+
+		for i < len(a) && a[i] == '0' {
+			i++
+		}
+		for j < len(b) && b[j] == '0' {
+			j++
+		}
+		for i < len(a) && isDigit(a[i]) && j < len(b) && isDigit(b[j]) {
+			if firstDiff == 0 {
+				firstDiff = int(a[i]) - int(b[j])
+			}
+			i++
+			j++
+		}
+
+		if i < len(a) && isDigit(a[i]) {
+			return 1
+		}
+		if j < len(b) && isDigit(b[j]) {
+			return -1
+		}
+		if firstDiff != 0 {
+			return compareInts(firstDiff, 0)
+		}
+	}
+	return 0
+}
+
+// versionOrder returns the weight of the character at offset i of s in
+// version comparison.
+func versionOrder(s []byte, i int) int {
+	if i >= len(s) {
+		return 0
+	}
+
+	c := s[i]
+	switch {
+	case isDigit(c):
+		return 0
+	case isAlpha(c):
+		return int(c)
+	case c == '~':
+		return -1
+	}
+	return int(c) + 256
+}
+
+// compareInts returns -1, 0 or 1 depending on the order of a and b.
+func compareInts(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// trimBlanks returns s without leading blanks.
+func trimBlanks(s []byte) []byte {
+	i := 0
+	for i < len(s) && lineio.IsBlank(s[i]) {
+		i++
+	}
+	return s[i:]
+}
+
+// isDigit reports whether c is a decimal digit.
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+// isAlpha reports whether c is an ASCII letter.
+func isAlpha(c byte) bool {
+	return ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z')
+}
+
+// isAlnum reports whether c is an ASCII letter or digit.
+func isAlnum(c byte) bool {
+	return isAlpha(c) || isDigit(c)
+}
+
+// toUpper converts an ASCII letter to upper case.
+func toUpper(c byte) byte {
+	if 'a' <= c && c <= 'z' {
+		return c - 'a' + 'A'
+	}
+	return c
+}
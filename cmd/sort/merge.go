@@ -0,0 +1,337 @@
+package sort
+
+import (
+	"bufio"
+	"bytes"
+	"container/heap"
+	"io"
+	"os"
+	"slices"
+	"sync"
+
+	"github.com/skraio/unix-utilities/internal/lineio"
+)
+
+const (
+	// lineOverhead approximates the memory used by a line besides its bytes.
+	lineOverhead = 40
+
+	// mergeFanIn is the maximum number of runs merged at once.
+	mergeFanIn = 16
+)
+
+// lineWriter writes lines followed by the delimiter, dropping lines whose
+// keys equal those of the previous line when unique is set.
+type lineWriter struct {
+	w       *bufio.Writer
+	delim   byte
+	cmp     *comparator
+	unique  bool
+	last    []byte
+	hasLast bool
+}
+
+// write writes a single line given without its delimiter.
+func (lw *lineWriter) write(line []byte) error {
+	if lw.unique {
+		if lw.hasLast && lw.cmp.compareKeys(lw.last, line) == 0 {
+			return nil
+		}
+		lw.last = append(lw.last[:0], line...)
+		lw.hasLast = true
+	}
+
+	if _, err := lw.w.Write(line); err != nil {
+		return err
+	}
+	return lw.w.WriteByte(lw.delim)
+}
+
+// sorter sorts lines in memory up to the buffer size, spilling sorted runs
+// to temporary files and merging them once all input has been read.
+type sorter struct {
+	cmp      *comparator
+	delim    byte
+	unique   bool
+	bufSize  int64
+	parallel int
+	tmpDir   string
+
+	lines [][]byte
+	size  int64
+	runs  []string
+}
+
+// add adds a line, given without its delimiter, spilling the buffered lines
+// to a run when the buffer is full.
+func (s *sorter) add(line []byte) error {
+	s.lines = append(s.lines, bytes.Clone(line))
+	s.size += int64(len(line)) + lineOverhead
+	if s.size >= s.bufSize {
+		return s.spill()
+	}
+	return nil
+}
+
+// spill sorts the buffered lines and writes them to a new temporary run.
+func (s *sorter) spill() error {
+	s.sortLines()
+
+	f, err := os.CreateTemp(s.tmpDir, "sort")
+	if err != nil {
+		return err
+	}
+	s.runs = append(s.runs, f.Name())
+
+	lw := &lineWriter{w: bufio.NewWriter(f), delim: s.delim, cmp: s.cmp, unique: s.unique}
+	for _, line := range s.lines {
+		if err := lw.write(line); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	if err := lw.w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+
+	s.lines = nil
+	s.size = 0
+	return f.Close()
+}
+
+// finish writes all lines in order to lw and removes the temporary runs.
+func (s *sorter) finish(lw *lineWriter) error {
+	defer s.cleanup()
+
+	if len(s.runs) == 0 {
+		s.sortLines()
+		for _, line := range s.lines {
+			if err := lw.write(line); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if len(s.lines) > 0 {
+		if err := s.spill(); err != nil {
+			return err
+		}
+	}
+
+	for len(s.runs) > mergeFanIn {
+		if err := s.mergeRuns(); err != nil {
+			return err
+		}
+	}
+
+	return mergeFiles(s.runs, s.delim, s.cmp, lw)
+}
+
+// mergeRuns merges the oldest runs into a single new run, so that the final
+// merge never opens more than mergeFanIn files at once.
+func (s *sorter) mergeRuns() error {
+	batch := s.runs[:mergeFanIn]
+
+	f, err := os.CreateTemp(s.tmpDir, "sort")
+	if err != nil {
+		return err
+	}
+	lw := &lineWriter{w: bufio.NewWriter(f), delim: s.delim, cmp: s.cmp, unique: s.unique}
+
+	err = mergeFiles(batch, s.delim, s.cmp, lw)
+	if err == nil {
+		err = lw.w.Flush()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+
+	for _, name := range batch {
+		os.Remove(name)
+	}
+	// The merged run replaces the oldest runs at the front so that equal
+	// lines keep their input order.
+	s.runs = append([]string{f.Name()}, s.runs[mergeFanIn:]...)
+
+	return err
+}
+
+// cleanup removes the temporary runs.
+func (s *sorter) cleanup() {
+	for _, name := range s.runs {
+		os.Remove(name)
+	}
+	s.runs = nil
+}
+
+// sortLines sorts the buffered lines, splitting the work between up to
+// parallel goroutines whose results are then merged.
+func (s *sorter) sortLines() {
+	cmp := func(a, b []byte) int { return s.cmp.compare(a, b) }
+
+	parts := min(s.parallel, len(s.lines)/1024+1)
+	if parts <= 1 {
+		slices.SortStableFunc(s.lines, cmp)
+		return
+	}
+
+	chunks := make([][][]byte, parts)
+	step := (len(s.lines) + parts - 1) / parts
+	var wg sync.WaitGroup
+	for i := range chunks {
+		chunks[i] = s.lines[min(i*step, len(s.lines)):min((i+1)*step, len(s.lines))]
+		wg.Add(1)
+		go func(chunk [][]byte) {
+			defer wg.Done()
+			slices.SortStableFunc(chunk, cmp)
+		}(chunks[i])
+	}
+	wg.Wait()
+
+	for len(chunks) > 1 {
+		merged := make([][][]byte, (len(chunks)+1)/2)
+		for i := range merged {
+			if 2*i+1 == len(chunks) {
+				merged[i] = chunks[2*i]
+				continue
+			}
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				merged[i] = mergeSlices(chunks[2*i], chunks[2*i+1], cmp)
+			}(i)
+		}
+		wg.Wait()
+		chunks = merged
+	}
+
+	s.lines = chunks[0]
+}
+
+// mergeSlices merges two sorted slices, taking equal lines from a first.
+func mergeSlices(a, b [][]byte, cmp func(a, b []byte) int) [][]byte {
+	result := make([][]byte, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		if cmp(b[j], a[i]) < 0 {
+			result = append(result, b[j])
+			j++
+		} else {
+			result = append(result, a[i])
+			i++
+		}
+	}
+	result = append(result, a[i:]...)
+	return append(result, b[j:]...)
+}
+
+// mergeSource is one sorted input of a k-way merge.
+type mergeSource struct {
+	lr    *lineio.Reader
+	line  []byte
+	index int
+}
+
+// next advances the source to its next line and reports whether there was
+// one.
+func (ms *mergeSource) next() (bool, error) {
+	line, err := ms.lr.ReadLine()
+	if err == io.EOF {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	ms.line = ms.lr.TrimDelim(line)
+	return true, nil
+}
+
+// mergeHeap orders merge sources by their current line, keeping the input
+// order for equal lines so that the merge is stable.
+type mergeHeap struct {
+	sources []*mergeSource
+	cmp     *comparator
+}
+
+// Len implements heap.Interface.
+func (h *mergeHeap) Len() int {
+	return len(h.sources)
+}
+
+// Less implements heap.Interface.
+func (h *mergeHeap) Less(i, j int) bool {
+	if r := h.cmp.compare(h.sources[i].line, h.sources[j].line); r != 0 {
+		return r < 0
+	}
+	return h.sources[i].index < h.sources[j].index
+}
+
+// Swap implements heap.Interface.
+func (h *mergeHeap) Swap(i, j int) {
+	h.sources[i], h.sources[j] = h.sources[j], h.sources[i]
+}
+
+// Push implements heap.Interface.
+func (h *mergeHeap) Push(x any) {
+	h.sources = append(h.sources, x.(*mergeSource))
+}
+
+// Pop implements heap.Interface.
+func (h *mergeHeap) Pop() any {
+	n := len(h.sources)
+	x := h.sources[n-1]
+	h.sources = h.sources[:n-1]
+	return x
+}
+
+// mergeFiles merges the sorted named files into lw.
+func mergeFiles(names []string, delim byte, cmp *comparator, lw *lineWriter) error {
+	readers := make([]io.Reader, 0, len(names))
+	for _, name := range names {
+		f, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		readers = append(readers, f)
+	}
+
+	return merge(readers, delim, cmp, lw)
+}
+
+// merge performs a k-way merge of the sorted readers into lw.
+func merge(readers []io.Reader, delim byte, cmp *comparator, lw *lineWriter) error {
+	h := &mergeHeap{cmp: cmp}
+	for i, r := range readers {
+		ms := &mergeSource{lr: lineio.NewReaderDelim(r, delim), index: i}
+		ok, err := ms.next()
+		if err != nil {
+			return err
+		}
+		if ok {
+			h.sources = append(h.sources, ms)
+		}
+	}
+	heap.Init(h)
+
+	for h.Len() > 0 {
+		ms := h.sources[0]
+		if err := lw.write(ms.line); err != nil {
+			return err
+		}
+
+		ok, err := ms.next()
+		if err != nil {
+			return err
+		}
+		if ok {
+			heap.Fix(h, 0)
+		} else {
+			heap.Pop(h)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,12 @@
+package sort
+
+import "syscall"
+
+// physMem returns the size of the physical memory, or 0 if it is unknown.
+func physMem() int64 {
+	var info syscall.Sysinfo_t
+	if err := syscall.Sysinfo(&info); err != nil {
+		return 0
+	}
+	return int64(uint64(info.Totalram) * uint64(info.Unit))
+}
@@ -0,0 +1,9 @@
+//go:build !linux
+
+package sort
+
+// physMem returns the size of the physical memory, or 0 if it is unknown,
+// as it is here.
+func physMem() int64 {
+	return 0
+}
@@ -0,0 +1,398 @@
+// Package sort provides functionality for sorting, merging and checking
+// lines of text files, spilling to temporary files for inputs larger than
+// memory.
+package sort
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/skraio/unix-utilities/cmdflags"
+	"github.com/skraio/unix-utilities/internal/exit"
+	"github.com/skraio/unix-utilities/internal/fileinput"
+	"github.com/skraio/unix-utilities/internal/lineio"
+	"github.com/spf13/cobra"
+)
+
+// Exit statuses of the sort command.
+const (
+	statusOK       = 0
+	statusDisorder = 1
+	statusError    = 2
+)
+
+// sortFlags holds flags for sort command.
+type sortFlags struct {
+	blanks      bool
+	dictionary  bool
+	fold        bool
+	general     bool
+	human       bool
+	nonprinting bool
+	month       bool
+	numeric     bool
+	reverse     bool
+	version     bool
+	unique      bool
+	stable      bool
+	check       bool
+	checkQuiet  bool
+	merge       bool
+	zero        bool
+	separator   string
+	output      string
+	bufferSize  string
+	tmpDir      string
+	parallel    int
+	keys        []string
+}
+
+var pFlags sortFlags
+
+// flags definition for sort command.
+var flags = []cmdflags.Flag{
+	{Value: &pFlags.blanks, Name: "ignore-leading-blanks", ShortHand: "b", DefaultValue: false, Description: "ignore leading blanks"},
+	{Value: &pFlags.dictionary, Name: "dictionary-order", ShortHand: "d", DefaultValue: false, Description: "consider only blanks and alphanumeric characters"},
+	{Value: &pFlags.fold, Name: "ignore-case", ShortHand: "f", DefaultValue: false, Description: "fold lower case to upper case characters"},
+	{Value: &pFlags.general, Name: "general-numeric-sort", ShortHand: "g", DefaultValue: false, Description: "compare according to general numerical value"},
+	{Value: &pFlags.human, Name: "human-numeric-sort", ShortHand: "h", DefaultValue: false, Description: "compare human readable numbers (e.g., 2K 1G)"},
+	{Value: &pFlags.nonprinting, Name: "ignore-nonprinting", ShortHand: "i", DefaultValue: false, Description: "consider only printable characters"},
+	{Value: &pFlags.month, Name: "month-sort", ShortHand: "M", DefaultValue: false, Description: "compare (unknown) < 'JAN' < ... < 'DEC'"},
+	{Value: &pFlags.numeric, Name: "numeric-sort", ShortHand: "n", DefaultValue: false, Description: "compare according to string numerical value"},
+	{Value: &pFlags.reverse, Name: "reverse", ShortHand: "r", DefaultValue: false, Description: "reverse the result of comparisons"},
+	{Value: &pFlags.version, Name: "version-sort", ShortHand: "V", DefaultValue: false, Description: "natural sort of (version) numbers within text"},
+	{Value: &pFlags.unique, Name: "unique", ShortHand: "u", DefaultValue: false, Description: "output only the first of an equal run"},
+	{Value: &pFlags.stable, Name: "stable", ShortHand: "s", DefaultValue: false, Description: "stabilize sort by disabling last-resort comparison"},
+	{Value: &pFlags.check, Name: "check", ShortHand: "c", DefaultValue: false, Description: "check for sorted input; do not sort"},
+	{Value: &pFlags.checkQuiet, Name: "check-quiet", ShortHand: "C", DefaultValue: false, Description: "like -c, but do not report first bad line"},
+	{Value: &pFlags.merge, Name: "merge", ShortHand: "m", DefaultValue: false, Description: "merge already sorted files; do not sort"},
+	{Value: &pFlags.zero, Name: "zero-terminated", ShortHand: "z", DefaultValue: false, Description: "line delimiter is NUL, not newline"},
+}
+
+// stringFlags definition for sort command.
+var stringFlags = []cmdflags.StringFlag{
+	{Value: &pFlags.separator, Name: "field-separator", ShortHand: "t", DefaultValue: "", Description: "use SEP instead of non-blank to blank transition"},
+	{Value: &pFlags.output, Name: "output", ShortHand: "o", DefaultValue: "", Description: "write result to FILE instead of standard output"},
+	{Value: &pFlags.bufferSize, Name: "buffer-size", ShortHand: "S", DefaultValue: "256M", Description: "use SIZE for main memory buffer"},
+	{Value: &pFlags.tmpDir, Name: "temporary-directory", ShortHand: "T", DefaultValue: os.TempDir(), Description: "use DIR for temporaries"},
+}
+
+// intFlags definition for sort command.
+var intFlags = []cmdflags.IntFlag{
+	{Value: &pFlags.parallel, Name: "parallel", ShortHand: "", DefaultValue: runtime.NumCPU(), Description: "change the number of sorts run concurrently to N"},
+}
+
+// Cmd represents the 'sort' command configuration using Cobra.
+var Cmd = &cobra.Command{
+	Use:           "sort [-f flags] [file]...",
+	Short:         "Sort lines of text files",
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return exit.Status(executeSort(fileinput.Args(args)))
+	},
+}
+
+// init initializes the 'sort' command by setting up flags.
+func init() {
+	cmdflags.ParseFlags(flags, Cmd)
+	cmdflags.ParseStringFlags(stringFlags, Cmd)
+	cmdflags.ParseIntFlags(intFlags, Cmd)
+	Cmd.Flags().StringArrayVarP(&pFlags.keys, "key", "k", nil, "sort via a key; KEYDEF gives location and type")
+	Cmd.PersistentFlags().BoolP("help", "", false, "help for this command")
+
+	Cmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		fmt.Fprintf(os.Stderr, "sort: %v\n", err)
+		return exit.Status(statusError)
+	})
+}
+
+// executeSort executes the sort command with given arguments and returns its
+// exit status.
+func executeSort(args []string) int {
+	global, err := globalOrdering()
+	if err != nil {
+		return fail(err)
+	}
+
+	cmp, err := newComparator(pFlags.keys, pFlags.separator, global, pFlags.blanks, pFlags.stable || pFlags.unique)
+	if err != nil {
+		return fail(err)
+	}
+
+	delim := byte('\n')
+	if pFlags.zero {
+		delim = 0
+	}
+
+	if pFlags.check || pFlags.checkQuiet {
+		if len(args) > 1 {
+			return fail(fmt.Errorf("extra operand %q not allowed with -c", args[1]))
+		}
+		return checkSorted(args[0], delim, cmp)
+	}
+
+	if pFlags.merge {
+		err = mergeInputs(args, delim, cmp)
+	} else {
+		err = sortInputs(args, delim, cmp)
+	}
+	if err != nil {
+		return fail(err)
+	}
+
+	return statusOK
+}
+
+// globalOrdering builds the ordering given by the global option flags.
+func globalOrdering() (ordering, error) {
+	ord := ordering{
+		reverse:     pFlags.reverse,
+		fold:        pFlags.fold,
+		dictionary:  pFlags.dictionary,
+		nonprinting: pFlags.nonprinting,
+	}
+
+	modes := []struct {
+		set  bool
+		mode byte
+	}{
+		{pFlags.numeric, 'n'}, {pFlags.general, 'g'}, {pFlags.human, 'h'},
+		{pFlags.month, 'M'}, {pFlags.version, 'V'},
+	}
+	for _, m := range modes {
+		if !m.set {
+			continue
+		}
+		if ord.mode != 0 {
+			return ordering{}, fmt.Errorf("options '-%c%c' are incompatible", ord.mode, m.mode)
+		}
+		ord.mode = m.mode
+	}
+
+	return ord, nil
+}
+
+// sortInputs sorts the lines of all inputs and writes them to the output.
+// The output is only opened once all input has been read, so it may be one
+// of the inputs.
+func sortInputs(args []string, delim byte, cmp *comparator) error {
+	bufSize, err := parseSize(pFlags.bufferSize)
+	if err != nil {
+		return err
+	}
+
+	s := &sorter{
+		cmp:      cmp,
+		delim:    delim,
+		unique:   pFlags.unique,
+		bufSize:  bufSize,
+		parallel: max(pFlags.parallel, 1),
+		tmpDir:   pFlags.tmpDir,
+	}
+	defer s.cleanup()
+
+	for _, name := range args {
+		if err := readLines(name, delim, s.add); err != nil {
+			return err
+		}
+	}
+
+	return writeOutput(func(lw *lineWriter) error {
+		return s.finish(lw)
+	}, delim, cmp)
+}
+
+// readLines calls add with every line of the named input, without its
+// delimiter.
+func readLines(name string, delim byte, add func([]byte) error) error {
+	f, err := fileinput.Open(name)
+	if err != nil {
+		return err
+	}
+	defer fileinput.Close(f)
+
+	lr := lineio.NewReaderDelim(f, delim)
+	for {
+		line, err := lr.ReadLine()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := add(lr.TrimDelim(line)); err != nil {
+			return err
+		}
+	}
+}
+
+// mergeInputs merges the already sorted inputs. An input that is also the
+// output file is copied to a temporary file first.
+func mergeInputs(args []string, delim byte, cmp *comparator) error {
+	readers := []io.Reader{}
+	for _, name := range args {
+		f, err := fileinput.Open(name)
+		if err != nil {
+			return err
+		}
+		defer fileinput.Close(f)
+
+		if pFlags.output != "" && sameFile(name, pFlags.output) {
+			tmp, err := copyToTemp(f)
+			if err != nil {
+				return err
+			}
+			defer os.Remove(tmp.Name())
+			defer tmp.Close()
+			f = tmp
+		}
+		readers = append(readers, f)
+	}
+
+	return writeOutput(func(lw *lineWriter) error {
+		return merge(readers, delim, cmp, lw)
+	}, delim, cmp)
+}
+
+// writeOutput opens the output and lets produce write the lines to it.
+func writeOutput(produce func(*lineWriter) error, delim byte, cmp *comparator) error {
+	out := os.Stdout
+	if pFlags.output != "" {
+		f, err := os.Create(pFlags.output)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+	}
+
+	lw := &lineWriter{w: bufio.NewWriter(out), delim: delim, cmp: cmp, unique: pFlags.unique}
+	if err := produce(lw); err != nil {
+		return err
+	}
+	if err := lw.w.Flush(); err != nil {
+		return err
+	}
+
+	if out != os.Stdout {
+		return out.Close()
+	}
+	return nil
+}
+
+// checkSorted reports the first line of the named input that is out of
+// order, returning statusDisorder if there is one.
+func checkSorted(name string, delim byte, cmp *comparator) int {
+	prev := []byte{}
+	number := 0
+	disorder := errors.New("disorder")
+
+	err := readLines(name, delim, func(line []byte) error {
+		number++
+		if number > 1 {
+			r := cmp.compare(prev, line)
+			if r > 0 || (pFlags.unique && r == 0) {
+				if !pFlags.checkQuiet {
+					fmt.Fprintf(os.Stderr, "sort: %s:%d: disorder: %s\n", fileinput.DisplayName(name), number, line)
+				}
+				return disorder
+			}
+		}
+		prev = append(prev[:0], line...)
+		return nil
+	})
+
+	switch {
+	case err == disorder:
+		return statusDisorder
+	case err != nil:
+		return fail(err)
+	}
+	return statusOK
+}
+
+// sizeSuffixes maps the suffixes accepted by -S to their multipliers. A
+// number without suffix is in kibibytes.
+var sizeSuffixes = map[byte]int64{
+	'b': 1, 'K': 1 << 10, 'k': 1 << 10, 'M': 1 << 20, 'm': 1 << 20,
+	'G': 1 << 30, 'g': 1 << 30, 'T': 1 << 40, 't': 1 << 40,
+}
+
+// defaultBufSize is the buffer size used when -S gives a percentage of a
+// memory size that is unknown.
+const defaultBufSize = 256 << 20
+
+// parseSize parses the argument of -S, a size or a percentage of the
+// physical memory.
+func parseSize(arg string) (int64, error) {
+	s := strings.TrimSpace(arg)
+	mult := int64(1 << 10)
+	percent := strings.HasSuffix(s, "%")
+	if percent {
+		s = s[:len(s)-1]
+	} else if n := len(s); n > 0 {
+		if m, ok := sizeSuffixes[s[n-1]]; ok {
+			mult = m
+			s = s[:n-1]
+		}
+	}
+
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid buffer size %q", arg)
+	}
+	if percent {
+		mem := physMem()
+		if mem == 0 {
+			mem = defaultBufSize
+		}
+		return int64(float64(mem) * float64(n) / 100), nil
+	}
+	return n * mult, nil
+}
+
+// sameFile reports whether the named input and output refer to the same
+// file.
+func sameFile(input, output string) bool {
+	if input == fileinput.Stdin {
+		return false
+	}
+
+	fi1, err1 := os.Stat(input)
+	fi2, err2 := os.Stat(output)
+	return err1 == nil && err2 == nil && os.SameFile(fi1, fi2)
+}
+
+// copyToTemp copies r to a new temporary file and rewinds it.
+func copyToTemp(r io.Reader) (*os.File, error) {
+	tmp, err := os.CreateTemp(pFlags.tmpDir, "sort")
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return nil, err
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return nil, err
+	}
+
+	return tmp, nil
+}
+
+// fail reports an error and returns the error exit status.
+func fail(err error) int {
+	exit.Fail("sort", err)
+	return statusError
+}
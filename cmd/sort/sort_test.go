@@ -0,0 +1,202 @@
+package sort
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/skraio/unix-utilities/internal/assert"
+)
+
+func TestCompareField(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		mode byte
+		want int
+	}{
+		{
+			name: "Numeric beyond float precision",
+			a:    "123456789012345678901",
+			b:    "123456789012345678902",
+			mode: 'n',
+			want: -1,
+		},
+		{
+			name: "Numeric negative fractions",
+			a:    "-1.5",
+			b:    "-1.25",
+			mode: 'n',
+			want: -1,
+		},
+		{
+			name: "Numeric negative zero",
+			a:    "-0",
+			b:    "0.000",
+			mode: 'n',
+			want: 0,
+		},
+		{
+			name: "Human sizes",
+			a:    "900K",
+			b:    "1M",
+			mode: 'h',
+			want: -1,
+		},
+		{
+			name: "General numbers",
+			a:    "1e3",
+			b:    "999",
+			mode: 'g',
+			want: 1,
+		},
+		{
+			name: "Months",
+			a:    "  feb",
+			b:    "Jan",
+			mode: 'M',
+			want: 1,
+		},
+		{
+			name: "Versions",
+			a:    "file-1.9.tar",
+			b:    "file-1.10.tar",
+			mode: 'V',
+			want: -1,
+		},
+		{
+			name: "Version tilde",
+			a:    "1.0~rc1",
+			b:    "1.0",
+			mode: 'V',
+			want: -1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ans := compareField([]byte(tt.a), []byte(tt.b), ordering{mode: tt.mode})
+
+			assert.Equal(t, ans, tt.want)
+		})
+	}
+}
+
+func TestKeyFields(t *testing.T) {
+	tests := []struct {
+		name string
+		spec string
+		tab  string
+		line string
+		want string
+	}{
+		{
+			name: "Blank separated field includes leading blanks",
+			spec: "2,2",
+			line: "Without  just one nest",
+			want: "  just",
+		},
+		{
+			name: "Skipped blanks",
+			spec: "2b,2",
+			line: "Without  just one nest",
+			want: "just",
+		},
+		{
+			name: "Character offsets",
+			spec: "2.2,3.2",
+			tab:  ":",
+			line: "bird:call:world:home",
+			want: "all:wo",
+		},
+		{
+			name: "To end of line",
+			spec: "3",
+			tab:  ":",
+			line: "bird:call:world:home",
+			want: "world:home",
+		},
+		{
+			name: "Missing field",
+			spec: "5,5",
+			tab:  ":",
+			line: "bird:call",
+			want: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := newComparator([]string{tt.spec}, tt.tab, ordering{}, false, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			line := []byte(tt.line)
+			k := &c.keys[0]
+			ans := string(line[c.begField(line, k):c.limField(line, k)])
+
+			assert.Equal(t, ans, tt.want)
+		})
+	}
+}
+
+func TestParseSize(t *testing.T) {
+	mem := physMem()
+	if mem == 0 {
+		mem = defaultBufSize
+	}
+	tests := []struct {
+		arg     string
+		want    int64
+		wantErr bool
+	}{
+		{arg: "3", want: 3 << 10},
+		{arg: "10b", want: 10},
+		{arg: "2M", want: 2 << 20},
+		{arg: "50%", want: mem / 2},
+		{arg: "%", wantErr: true},
+		{arg: "10%K", wantErr: true},
+		{arg: "0", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.arg, func(t *testing.T) {
+			got, err := parseSize(tt.arg)
+			assert.Equal(t, err != nil, tt.wantErr)
+			assert.Equal(t, got, tt.want)
+		})
+	}
+}
+
+func TestSorterSpillsRuns(t *testing.T) {
+	c, err := newComparator([]string{"1n"}, "", ordering{}, false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := &sorter{cmp: c, delim: '\n', bufSize: 512, parallel: 2, tmpDir: t.TempDir()}
+	want := &bytes.Buffer{}
+	for i := 0; i < 1000; i++ {
+		fmt.Fprintf(want, "%d\n", i)
+	}
+	for i := 999; i >= 0; i-- {
+		if err := s.add([]byte(fmt.Sprint(i))); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(s.runs) <= mergeFanIn {
+		t.Fatalf("got %d runs; want more than %d", len(s.runs), mergeFanIn)
+	}
+
+	var out bytes.Buffer
+	lw := &lineWriter{w: bufio.NewWriter(&out), delim: '\n', cmp: c}
+	if err := s.finish(lw); err != nil {
+		t.Fatal(err)
+	}
+	lw.w.Flush()
+
+	assert.Equal(t, out.String(), want.String())
+	assert.Equal(t, len(s.runs), 0)
+}
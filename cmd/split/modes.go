@@ -0,0 +1,258 @@
+package split
+
+import (
+	"bufio"
+	"io"
+	"os"
+
+	"github.com/skraio/unix-utilities/internal/lineio"
+)
+
+// bufferSize is the size of the buffer the input is read in.
+const bufferSize = 64 * 1024
+
+// byLines writes lines records of the input to each output.
+func byLines(r io.Reader, s *splitter, lines int64, sep byte) error {
+	buf := make([]byte, bufferSize)
+	written := int64(0)
+	for {
+		n, err := r.Read(buf)
+		for b := buf[:n]; len(b) > 0; {
+			end, count := lineio.Cut(b, sep, int(min(lines-written, int64(len(b)))))
+			if _, err := s.Write(b[:end]); err != nil {
+				return err
+			}
+			b = b[end:]
+			if written += int64(count); written == lines {
+				if err := s.close(); err != nil {
+					return err
+				}
+				written = 0
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// byBytes writes size bytes of the input to each output.
+func byBytes(r io.Reader, s *splitter, size int64) error {
+	for {
+		n, err := io.CopyN(s, r, size)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if n == size {
+			if err := s.close(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// byLineBytes writes as many whole records of the input as fit in size
+// bytes to each output. Records longer than size are split over outputs of
+// size bytes.
+func byLineBytes(r io.Reader, s *splitter, size int64, sep byte) error {
+	lr := lineio.NewReaderDelim(r, sep)
+	written := int64(0)
+	for {
+		line, err := lr.ReadLine()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if written > 0 && written+int64(len(line)) > size {
+			if err := s.close(); err != nil {
+				return err
+			}
+			written = 0
+		}
+		for int64(len(line)) > size {
+			if _, err := s.Write(line[:size]); err != nil {
+				return err
+			}
+			if err := s.close(); err != nil {
+				return err
+			}
+			line = line[size:]
+		}
+		if len(line) > 0 {
+			if _, err := s.Write(line); err != nil {
+				return err
+			}
+			written += int64(len(line))
+		}
+	}
+}
+
+// split splits the input named name into the chunks.
+func (c *chunks) split(in *os.File, name string, s *splitter, sep byte) error {
+	if c.mode == 'r' {
+		return c.roundRobin(in, s, sep)
+	}
+
+	size, err := inputSize(in, name)
+	if err != nil {
+		return err
+	}
+	// Chunks are at least a byte, the last one taking the rest of the input.
+	chunkSize := max(size/c.count, 1)
+	if c.mode == 'l' {
+		return c.lines(in, s, sep, chunkSize)
+	}
+
+	length := func(k int64) int64 {
+		if k == c.count {
+			return max(size-(k-1)*chunkSize, 0)
+		}
+		return min(chunkSize, max(size-(k-1)*chunkSize, 0))
+	}
+	if c.extract > 0 {
+		if _, err := in.Seek(min((c.extract-1)*chunkSize, size), io.SeekCurrent); err != nil {
+			return err
+		}
+		_, err := io.CopyN(os.Stdout, in, length(c.extract))
+		return err
+	}
+
+	for k := int64(1); k <= c.count; k++ {
+		if length(k) == 0 && pFlags.elide {
+			continue
+		}
+		if err := s.open(); err != nil {
+			return err
+		}
+		if _, err := io.CopyN(s, in, length(k)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// lines writes each record to the chunk of chunkSize bytes it starts in.
+func (c *chunks) lines(in io.Reader, s *splitter, sep byte, chunkSize int64) error {
+	w := bufio.NewWriter(os.Stdout)
+	lr := lineio.NewReaderDelim(in, sep)
+	offset := int64(0)
+	chunk := int64(0)
+	for {
+		line, err := lr.ReadLine()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		k := min(offset/chunkSize+1, c.count)
+		offset += int64(len(line))
+		if c.extract > 0 {
+			if k == c.extract {
+				w.Write(line)
+			}
+			continue
+		}
+
+		for ; chunk < k; chunk++ {
+			if pFlags.elide && chunk+1 < k {
+				continue
+			}
+			if err := s.open(); err != nil {
+				return err
+			}
+		}
+		if _, err := s.Write(line); err != nil {
+			return err
+		}
+	}
+
+	if c.extract > 0 {
+		return w.Flush()
+	}
+	for ; chunk < c.count && !pFlags.elide; chunk++ {
+		if err := s.open(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// roundRobin writes the records to the chunks in turn.
+func (c *chunks) roundRobin(in io.Reader, s *splitter, sep byte) error {
+	w := bufio.NewWriter(os.Stdout)
+	outputs := make([]*output, 0, c.count)
+	defer func() {
+		for _, out := range outputs {
+			out.Close()
+		}
+	}()
+
+	lr := lineio.NewReaderDelim(in, sep)
+	for i := int64(0); ; i++ {
+		line, err := lr.ReadLine()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		k := i % c.count
+		if c.extract > 0 {
+			if k == c.extract-1 {
+				w.Write(line)
+			}
+			continue
+		}
+		if k == int64(len(outputs)) {
+			out, err := newOutput(s.names)
+			if err != nil {
+				return err
+			}
+			outputs = append(outputs, out)
+		}
+		if _, err := outputs[k].Write(line); err != nil {
+			return err
+		}
+	}
+
+	if c.extract > 0 {
+		return w.Flush()
+	}
+	for int64(len(outputs)) < c.count && !pFlags.elide {
+		out, err := newOutput(s.names)
+		if err != nil {
+			return err
+		}
+		outputs = append(outputs, out)
+	}
+
+	var err error
+	for _, out := range outputs {
+		if closeErr := out.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	outputs = nil
+	return err
+}
+
+// newOutput creates the output with the next name.
+func newOutput(names *namer) (*output, error) {
+	name, err := names.next()
+	if err != nil {
+		return nil, err
+	}
+	return create(name)
+}
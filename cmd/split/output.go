@@ -0,0 +1,292 @@
+package split
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/skraio/unix-utilities/internal/exit"
+)
+
+// Alphabets of the suffixes of output file names.
+const (
+	letters     = "abcdefghijklmnopqrstuvwxyz"
+	decimal     = "0123456789"
+	hexadecimal = "0123456789abcdef"
+)
+
+// errExhausted is returned when there are more output files than suffixes
+// of the given length.
+var errExhausted = errors.New("output file suffixes exhausted")
+
+// namer generates the names of the output files: a prefix, a suffix
+// counting in an alphabet, and an additional suffix.
+type namer struct {
+	prefix     string
+	additional string
+	alphabet   string
+	digits     []int
+	started    bool
+
+	// widen lengthens the suffix when its first digit becomes the last of
+	// the alphabet, so that names never run out: xyz is followed by xzaaa.
+	widen bool
+}
+
+// newNamer returns the namer of the output files chosen by the flags and
+// the prefix operand, with suffixes long enough for the chunks c.
+func newNamer(args []string, c *chunks) (*namer, error) {
+	n := &namer{prefix: "x", additional: pFlags.additional, alphabet: letters}
+	if len(args) == 2 {
+		n.prefix = args[1]
+	}
+	if strings.ContainsRune(n.additional, '/') {
+		return nil, fmt.Errorf("invalid suffix '%s', contains directory separator", n.additional)
+	}
+
+	from := ""
+	switch {
+	case pFlags.hex != "":
+		n.alphabet, from = hexadecimal, pFlags.hex
+	case pFlags.numeric != "":
+		n.alphabet, from = decimal, pFlags.numeric
+	}
+	if strings.Trim(from, decimal) != "" {
+		return nil, fmt.Errorf("'%s': invalid start value for numerical suffix", from)
+	}
+
+	length := 0
+	if pFlags.suffixWidth != "" {
+		width, err := strconv.Atoi(pFlags.suffixWidth)
+		if err != nil || width < 0 {
+			return nil, fmt.Errorf("invalid suffix length: '%s'", pFlags.suffixWidth)
+		}
+		length = width
+	}
+	given := length > 0
+	if !given {
+		length = 2
+	}
+	n.widen = !given && from == "" && c == nil
+
+	if c != nil && c.extract == 0 {
+		needed := 1
+		for last := c.count - 1; last >= int64(len(n.alphabet)); last /= int64(len(n.alphabet)) {
+			needed++
+		}
+		if given && length < needed {
+			return nil, fmt.Errorf("the suffix length needs to be at least %d", needed)
+		}
+		length = max(length, needed)
+	}
+
+	if len(from) > length {
+		return nil, errors.New("numerical suffix start value is too large for the suffix length")
+	}
+	n.digits = make([]int, length)
+	for i := range from {
+		n.digits[length-len(from)+i] = int(from[i] - '0')
+	}
+	return n, nil
+}
+
+// next returns the name of the next output file.
+func (n *namer) next() (string, error) {
+	if n.started {
+		i := len(n.digits) - 1
+		for ; i >= 0 && n.digits[i] == len(n.alphabet)-1; i-- {
+			n.digits[i] = 0
+		}
+		if i < 0 {
+			return "", errExhausted
+		}
+		n.digits[i]++
+	}
+	n.started = true
+
+	last := len(n.alphabet) - 1
+	if n.widen && n.digits[0] == last {
+		n.prefix += n.alphabet[last:]
+		n.digits = make([]int, len(n.digits)+1)
+	}
+
+	var b strings.Builder
+	b.WriteString(n.prefix)
+	for _, d := range n.digits {
+		b.WriteByte(n.alphabet[d])
+	}
+	b.WriteString(n.additional)
+	return b.String(), nil
+}
+
+// output is an output file, or the standard input of the filter command
+// given to write it.
+type output struct {
+	name string
+	w    io.WriteCloser
+	cmd  *exec.Cmd
+}
+
+// create creates the output named name, starting the filter command with
+// the name in $FILE when one is given.
+func create(name string) (*output, error) {
+	if pFlags.filter == "" {
+		if pFlags.verbose {
+			fmt.Printf("creating file '%s'\n", name)
+		}
+		f, err := os.Create(name)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", name, exit.Unwrap(err))
+		}
+		return &output{name: name, w: f}, nil
+	}
+
+	if pFlags.verbose {
+		fmt.Printf("executing with FILE=%s\n", name)
+	}
+	cmd := exec.Command("sh", "-c", pFlags.filter)
+	cmd.Env = append(os.Environ(), "FILE="+name)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	w, err := cmd.StdinPipe()
+	if err == nil {
+		err = cmd.Start()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to run command: \"sh -c %s\": %v", pFlags.filter, err)
+	}
+	return &output{name: name, w: w, cmd: cmd}, nil
+}
+
+// Write writes b to the output. A filter may exit without reading all of
+// its input, which is not an error.
+func (o *output) Write(b []byte) (int, error) {
+	n, err := o.w.Write(b)
+	if o.cmd != nil && errors.Is(err, syscall.EPIPE) {
+		return len(b), nil
+	}
+	if err != nil {
+		return n, fmt.Errorf("%s: %v", o.name, exit.Unwrap(err))
+	}
+	return n, nil
+}
+
+// Close closes the output and waits for its filter to exit.
+func (o *output) Close() error {
+	err := o.w.Close()
+	if o.cmd == nil {
+		if err != nil {
+			return fmt.Errorf("%s: %v", o.name, exit.Unwrap(err))
+		}
+		return nil
+	}
+
+	var ee *exec.ExitError
+	if err := o.cmd.Wait(); errors.As(err, &ee) {
+		status := ee.Sys().(syscall.WaitStatus)
+		if status.Signaled() && status.Signal() == syscall.SIGPIPE {
+			return nil
+		}
+		return &filterError{name: o.name, status: status}
+	} else if err != nil {
+		return err
+	}
+	return nil
+}
+
+// filterError reports a filter command that failed.
+type filterError struct {
+	name   string
+	status syscall.WaitStatus
+}
+
+// Error describes how the filter failed.
+func (e *filterError) Error() string {
+	how := fmt.Sprintf("exit %d", e.status.ExitStatus())
+	if e.status.Signaled() {
+		how = "signal " + signalName(e.status.Signal())
+	}
+	return fmt.Sprintf("with FILE=%s, %s from command: %s", e.name, how, pFlags.filter)
+}
+
+// exitStatus returns the exit status of split for the failed filter: its
+// own, or 128 plus the number of the signal that killed it.
+func (e *filterError) exitStatus() int {
+	if e.status.Signaled() {
+		return 128 + int(e.status.Signal())
+	}
+	return e.status.ExitStatus()
+}
+
+// signalNames are the names of the signals commonly ending processes.
+var signalNames = map[syscall.Signal]string{
+	syscall.SIGHUP:  "HUP",
+	syscall.SIGINT:  "INT",
+	syscall.SIGQUIT: "QUIT",
+	syscall.SIGILL:  "ILL",
+	syscall.SIGTRAP: "TRAP",
+	syscall.SIGABRT: "ABRT",
+	syscall.SIGBUS:  "BUS",
+	syscall.SIGFPE:  "FPE",
+	syscall.SIGKILL: "KILL",
+	syscall.SIGUSR1: "USR1",
+	syscall.SIGSEGV: "SEGV",
+	syscall.SIGUSR2: "USR2",
+	syscall.SIGALRM: "ALRM",
+	syscall.SIGTERM: "TERM",
+}
+
+// signalName returns the name of sig without its SIG prefix, or its number
+// for other signals.
+func signalName(sig syscall.Signal) string {
+	if name, ok := signalNames[sig]; ok {
+		return name
+	}
+	return strconv.Itoa(int(sig))
+}
+
+// splitter writes the input to successive outputs, created as data is
+// written to them.
+type splitter struct {
+	names *namer
+	out   *output
+}
+
+// open closes the current output and creates the next one.
+func (s *splitter) open() error {
+	if err := s.close(); err != nil {
+		return err
+	}
+	name, err := s.names.next()
+	if err != nil {
+		return err
+	}
+	s.out, err = create(name)
+	return err
+}
+
+// Write writes b to the current output, creating it if needed.
+func (s *splitter) Write(b []byte) (int, error) {
+	if s.out == nil {
+		if err := s.open(); err != nil {
+			return 0, err
+		}
+	}
+	return s.out.Write(b)
+}
+
+// close closes the current output, so that the next write goes to a new
+// one.
+func (s *splitter) close() error {
+	if s.out == nil {
+		return nil
+	}
+	err := s.out.Close()
+	s.out = nil
+	return err
+}
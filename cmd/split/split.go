@@ -0,0 +1,269 @@
+// Package split provides functionality for splitting a file into pieces of
+// a given number of lines or bytes, or into a given number of chunks.
+package split
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/skraio/unix-utilities/cmdflags"
+	"github.com/skraio/unix-utilities/internal/exit"
+	"github.com/skraio/unix-utilities/internal/fileinput"
+	"github.com/skraio/unix-utilities/internal/units"
+	"github.com/spf13/cobra"
+)
+
+// defaultLines is the number of lines of each piece when no way of
+// splitting is given.
+const defaultLines = 1000
+
+// errRange is the reason given for sizes and counts of zero, in the words
+// of the C library.
+var errRange = errors.New("Numerical result out of range")
+
+// splitFlags holds flags for split command.
+type splitFlags struct {
+	elide       bool
+	verbose     bool
+	lines       string
+	bytes       string
+	lineBytes   string
+	number      string
+	separator   string
+	filter      string
+	additional  string
+	numeric     string
+	hex         string
+	suffixWidth string
+}
+
+var pFlags splitFlags
+
+// flags definition for split command.
+var flags = []cmdflags.Flag{
+	{Value: &pFlags.elide, Name: "elide-empty-files", ShortHand: "e", DefaultValue: false, Description: "do not generate empty output files with '-n'"},
+	{Value: &pFlags.verbose, Name: "verbose", ShortHand: "", DefaultValue: false, Description: "print a diagnostic just before each output file is opened"},
+}
+
+// stringFlags definition for split command.
+var stringFlags = []cmdflags.StringFlag{
+	{Value: &pFlags.lines, Name: "lines", ShortHand: "l", DefaultValue: "", Description: "put NUMBER lines/records per output file"},
+	{Value: &pFlags.bytes, Name: "bytes", ShortHand: "b", DefaultValue: "", Description: "put SIZE bytes per output file"},
+	{Value: &pFlags.lineBytes, Name: "line-bytes", ShortHand: "C", DefaultValue: "", Description: "put at most SIZE bytes of records per output file"},
+	{Value: &pFlags.number, Name: "number", ShortHand: "n", DefaultValue: "", Description: "generate CHUNKS output files: N, K/N, l/N, l/K/N, r/N or r/K/N"},
+	{Value: &pFlags.separator, Name: "separator", ShortHand: "t", DefaultValue: "", Description: "use SEP instead of newline as the record separator; '\\0' specifies the NUL character"},
+	{Value: &pFlags.filter, Name: "filter", ShortHand: "", DefaultValue: "", Description: "write to shell COMMAND; file name is $FILE"},
+	{Value: &pFlags.additional, Name: "additional-suffix", ShortHand: "", DefaultValue: "", Description: "append an additional SUFFIX to file names"},
+	{Value: &pFlags.numeric, Name: "numeric-suffixes", ShortHand: "d", DefaultValue: "", Description: "use numeric suffixes starting at 0, or at FROM when given"},
+	{Value: &pFlags.hex, Name: "hex-suffixes", ShortHand: "x", DefaultValue: "", Description: "use hex suffixes starting at 0, or at FROM when given"},
+	{Value: &pFlags.suffixWidth, Name: "suffix-length", ShortHand: "a", DefaultValue: "", Description: "generate suffixes of length N (default 2)"},
+}
+
+// Cmd represents the 'split' command configuration using Cobra.
+var Cmd = &cobra.Command{
+	Use:           "split [-f flags] [file [prefix]]",
+	Short:         "Split a file into pieces",
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return exit.Status(executeSplit(args))
+	},
+}
+
+// init initializes the 'split' command by setting up flags.
+func init() {
+	cmdflags.ParseFlags(flags, Cmd)
+	cmdflags.ParseStringFlags(stringFlags, Cmd)
+	Cmd.Flags().Lookup("numeric-suffixes").NoOptDefVal = "0"
+	Cmd.Flags().Lookup("hex-suffixes").NoOptDefVal = "0"
+
+	Cmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		fmt.Fprintf(os.Stderr, "split: %v\n", err)
+		return exit.Status(1)
+	})
+}
+
+// executeSplit executes the split command with given arguments and returns
+// its exit status.
+func executeSplit(args []string) int {
+	if len(args) > 2 {
+		fmt.Fprintf(os.Stderr, "split: extra operand '%s'\n", args[2])
+		return 1
+	}
+	ways := 0
+	for _, way := range []string{pFlags.lines, pFlags.bytes, pFlags.lineBytes, pFlags.number} {
+		if way != "" {
+			ways++
+		}
+	}
+	if ways > 1 {
+		return exit.Fail("split", errors.New("cannot split in more than one way"))
+	}
+
+	sep, err := parseSeparator(pFlags.separator)
+	if err != nil {
+		return exit.Fail("split", err)
+	}
+	var c *chunks
+	if pFlags.number != "" {
+		if c, err = parseChunks(pFlags.number); err != nil {
+			return exit.Fail("split", err)
+		}
+		if c.extract > 0 && pFlags.filter != "" {
+			return exit.Fail("split", errors.New("--filter does not process a chunk extracted to stdout"))
+		}
+	}
+	names, err := newNamer(args, c)
+	if err != nil {
+		return exit.Fail("split", err)
+	}
+
+	name := fileinput.Args(args)[0]
+	in, err := fileinput.Open(name)
+	if err != nil {
+		return exit.Fail("split", fmt.Errorf("cannot open '%s' for reading: %v", name, exit.Unwrap(err)))
+	}
+	defer fileinput.Close(in)
+
+	s := &splitter{names: names}
+	switch {
+	case c != nil:
+		err = c.split(in, name, s, sep)
+	case pFlags.bytes != "":
+		var size int64
+		if size, err = parseSize("bytes", pFlags.bytes); err == nil {
+			err = byBytes(in, s, size)
+		}
+	case pFlags.lineBytes != "":
+		var size int64
+		if size, err = parseSize("bytes", pFlags.lineBytes); err == nil {
+			err = byLineBytes(in, s, size, sep)
+		}
+	default:
+		lines := int64(defaultLines)
+		if pFlags.lines != "" {
+			lines, err = parseCount("lines", pFlags.lines)
+		}
+		if err == nil {
+			err = byLines(in, s, lines, sep)
+		}
+	}
+	if closeErr := s.close(); err == nil {
+		err = closeErr
+	}
+
+	var fe *filterError
+	if errors.As(err, &fe) {
+		fmt.Fprintf(os.Stderr, "split: %v\n", err)
+		return fe.exitStatus()
+	}
+	if err != nil {
+		return exit.Fail("split", err)
+	}
+	return 0
+}
+
+// parseSeparator parses the argument of -t: a single byte, or \0 for the
+// NUL byte. Records are lines when it is not given.
+func parseSeparator(arg string) (byte, error) {
+	switch {
+	case arg == "":
+		return '\n', nil
+	case arg == `\0`:
+		return 0, nil
+	case len(arg) > 1:
+		return 0, fmt.Errorf("multi-character separator '%s'", arg)
+	}
+	return arg[0], nil
+}
+
+// parseCount parses a positive count of what, with no unit suffix.
+func parseCount(what, arg string) (int64, error) {
+	n, err := strconv.ParseInt(arg, 10, 64)
+	return checkCount(what, arg, n, err)
+}
+
+// parseSize parses a positive size of what, which can have a unit suffix.
+func parseSize(what, arg string) (int64, error) {
+	n, err := units.Parse(arg)
+	return checkCount(what, arg, n, err)
+}
+
+// checkCount returns the error for a count of what that could not be
+// parsed or is not positive.
+func checkCount(what, arg string, n int64, err error) (int64, error) {
+	switch {
+	case err != nil || n < 0:
+		return 0, fmt.Errorf("invalid number of %s: '%s'", what, arg)
+	case n == 0:
+		return 0, fmt.Errorf("invalid number of %s: '%s': %v", what, arg, errRange)
+	}
+	return n, nil
+}
+
+// chunks is the way of splitting given to -n.
+type chunks struct {
+	// mode is 'b' for chunks of bytes, 'l' for chunks of bytes ending at
+	// the end of a record and 'r' for records distributed round robin.
+	mode byte
+
+	// count is the number of chunks.
+	count int64
+
+	// extract is the number of the only chunk written, to the standard
+	// output, or zero to write all chunks to files.
+	extract int64
+}
+
+// parseChunks parses the argument of -n: [l/|r/][K/]N.
+func parseChunks(arg string) (*chunks, error) {
+	c := &chunks{mode: 'b'}
+	s := arg
+	if strings.HasPrefix(s, "l/") || strings.HasPrefix(s, "r/") {
+		c.mode, s = s[0], s[2:]
+	}
+
+	k, n, found := strings.Cut(s, "/")
+	if !found {
+		n = k
+	}
+	var err error
+	c.count, err = strconv.ParseInt(n, 10, 64)
+	switch {
+	case err != nil || c.count < 0:
+		return nil, fmt.Errorf("invalid number of chunks: '%s'", n)
+	case c.count == 0:
+		return nil, fmt.Errorf("invalid number of chunks: '%s': %v", n, errRange)
+	}
+	if found {
+		c.extract, err = strconv.ParseInt(k, 10, 64)
+		switch {
+		case err != nil || c.extract < 0:
+			return nil, fmt.Errorf("invalid chunk number: '%s'", k)
+		case c.extract == 0 || c.extract > c.count:
+			return nil, fmt.Errorf("invalid chunk number: '%s': %v", k, errRange)
+		}
+	}
+	return c, nil
+}
+
+// inputSize returns the number of bytes of f from its current offset to
+// its end.
+func inputSize(f *os.File, name string) (int64, error) {
+	offset, err := f.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return 0, fmt.Errorf("%s: cannot determine file size", name)
+	}
+	end, err := f.Seek(0, io.SeekEnd)
+	if err != nil {
+		return 0, fmt.Errorf("%s: cannot determine file size", name)
+	}
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return 0, err
+	}
+	return end - offset, nil
+}
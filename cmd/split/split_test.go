@@ -0,0 +1,90 @@
+package split
+
+import (
+	"testing"
+
+	"github.com/skraio/unix-utilities/internal/assert"
+)
+
+func TestNamer(t *testing.T) {
+	tests := []struct {
+		name   string
+		namer  namer
+		skip   int
+		want   []string
+		exceed bool
+	}{
+		{
+			name:  "Widened letters",
+			namer: namer{prefix: "x", alphabet: letters, digits: make([]int, 2), widen: true},
+			skip:  648,
+			want:  []string{"xyy", "xyz", "xzaaa", "xzaab"},
+		},
+		{
+			name:  "Widened digits",
+			namer: namer{prefix: "x", alphabet: decimal, digits: make([]int, 2), widen: true},
+			skip:  88,
+			want:  []string{"x88", "x89", "x9000"},
+		},
+		{
+			name:   "Exhausted suffixes",
+			namer:  namer{prefix: "p", additional: ".txt", alphabet: hexadecimal, digits: []int{14}},
+			want:   []string{"pe.txt", "pf.txt"},
+			exceed: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i := 0; i < tt.skip; i++ {
+				tt.namer.next()
+			}
+			names := []string{}
+			for range tt.want {
+				name, err := tt.namer.next()
+				if err != nil {
+					t.Fatal(err)
+				}
+				names = append(names, name)
+			}
+			assert.EqualStr(t, names, tt.want)
+
+			if tt.exceed {
+				_, err := tt.namer.next()
+				assert.Equal(t, err, errExhausted)
+			}
+		})
+	}
+}
+
+func TestParseChunks(t *testing.T) {
+	tests := []struct {
+		arg  string
+		want chunks
+		err  string
+	}{
+		{arg: "4", want: chunks{mode: 'b', count: 4}},
+		{arg: "2/4", want: chunks{mode: 'b', count: 4, extract: 2}},
+		{arg: "l/3", want: chunks{mode: 'l', count: 3}},
+		{arg: "r/3/3", want: chunks{mode: 'r', count: 3, extract: 3}},
+		{arg: "l/x", err: "invalid number of chunks: 'x'"},
+		{arg: "5/4", err: "invalid chunk number: '5': Numerical result out of range"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.arg, func(t *testing.T) {
+			c, err := parseChunks(tt.arg)
+			if tt.err != "" {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				assert.Equal(t, err.Error(), tt.err)
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, *c, tt.want)
+		})
+	}
+}
@@ -0,0 +1,302 @@
+package stat
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/skraio/unix-utilities/internal/fileinfo"
+	"github.com/skraio/unix-utilities/internal/filemode"
+	"github.com/skraio/unix-utilities/internal/shellquote"
+)
+
+// timeLayout is the layout of human-readable timestamps.
+const timeLayout = "2006-01-02 15:04:05.000000000 -0700"
+
+// expand replaces the directives of format with the values returned by
+// directive, which receives the H or L modifier, if any, and the
+// conversion character. A directive without a value becomes '?'.
+func expand(format string, directive func(modifier, verb byte) (string, bool)) string {
+	var b strings.Builder
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' || i+1 == len(format) {
+			b.WriteByte(format[i])
+			continue
+		}
+
+		j := i + 1
+		for j < len(format) && strings.IndexByte("-+ #0'", format[j]) >= 0 {
+			j++
+		}
+		for j < len(format) && (format[j] >= '0' && format[j] <= '9' || format[j] == '.') {
+			j++
+		}
+		spec := strings.ReplaceAll(format[i+1:j], "'", "")
+
+		modifier := byte(0)
+		if j+1 < len(format) && (format[j] == 'H' || format[j] == 'L') && strings.IndexByte("dr", format[j+1]) >= 0 {
+			modifier = format[j]
+			j++
+		}
+		if j == len(format) {
+			b.WriteString(format[i:])
+			break
+		}
+
+		verb := format[j]
+		i = j
+		if verb == '%' {
+			b.WriteByte('%')
+			continue
+		}
+
+		value, ok := directive(modifier, verb)
+		if !ok {
+			value = "?"
+		}
+		b.WriteString(fmt.Sprintf("%"+spec+"s", value))
+	}
+
+	return b.String()
+}
+
+// escapes maps the backslash escapes of --printf to their characters.
+var escapes = map[byte]byte{
+	'a': '\a', 'b': '\b', 'e': 0x1b, 'f': '\f', 'n': '\n', 'r': '\r',
+	't': '\t', 'v': '\v', '\\': '\\', '"': '"',
+}
+
+// interpretEscapes replaces backslash escapes, including octal \NNN and
+// hexadecimal \xHH ones, with the characters they stand for.
+func interpretEscapes(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 == len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+
+		i++
+		c := s[i]
+		if e, ok := escapes[c]; ok {
+			b.WriteByte(e)
+			continue
+		}
+
+		digits, base, maxLen := "01234567", 8, 3
+		start := i
+		if c == 'x' {
+			digits, base, maxLen = "0123456789abcdefABCDEF", 16, 2
+			start++
+		}
+		end := start
+		for end < len(s) && end-start < maxLen && strings.IndexByte(digits, s[end]) >= 0 {
+			end++
+		}
+		if end == start {
+			b.WriteByte('\\')
+			b.WriteByte(c)
+			continue
+		}
+
+		n, _ := strconv.ParseUint(s[start:end], base, 16)
+		b.WriteByte(byte(n))
+		i = end - 1
+	}
+
+	return b.String()
+}
+
+// fileStatus holds the status of a file.
+type fileStatus struct {
+	name                string
+	info                fs.FileInfo
+	stat                *syscall.Stat_t
+	atime, mtime, ctime time.Time
+	birth               time.Time
+	hasBirth            bool
+}
+
+// newFileStatus gathers the status of the named file from its information.
+func newFileStatus(name string, info fs.FileInfo, follow bool) *fileStatus {
+	f := &fileStatus{name: name, info: info}
+	f.stat, _ = fileinfo.Sys(info)
+	f.atime, f.mtime, f.ctime = fileinfo.Times(info)
+	f.birth, f.hasBirth = fileinfo.Birth(name, follow)
+	return f
+}
+
+// fileType returns the description of the type of a file.
+func fileType(info fs.FileInfo) string {
+	mode := info.Mode()
+	switch {
+	case mode.IsRegular() && info.Size() == 0:
+		return "regular empty file"
+	case mode.IsRegular():
+		return "regular file"
+	case mode.IsDir():
+		return "directory"
+	case mode&fs.ModeSymlink != 0:
+		return "symbolic link"
+	case mode&fs.ModeNamedPipe != 0:
+		return "fifo"
+	case mode&fs.ModeSocket != 0:
+		return "socket"
+	case mode&fs.ModeCharDevice != 0:
+		return "character special file"
+	case mode&fs.ModeDevice != 0:
+		return "block special file"
+	}
+	return "weird file"
+}
+
+// directive returns the value of a file status directive.
+func (f *fileStatus) directive(modifier, verb byte) (string, bool) {
+	u := func(n uint64) string { return strconv.FormatUint(n, 10) }
+	epoch := func(t time.Time) string { return strconv.FormatInt(t.Unix(), 10) }
+
+	switch verb {
+	case 'n':
+		return f.name, true
+	case 'N':
+		if f.info.Mode()&fs.ModeSymlink != 0 {
+			if target, err := os.Readlink(f.name); err == nil {
+				return shellquote.Always(f.name) + " -> " + shellquote.Always(target), true
+			}
+		}
+		return shellquote.Always(f.name), true
+	case 's':
+		return strconv.FormatInt(f.info.Size(), 10), true
+	case 'a':
+		return strconv.FormatUint(uint64(filemode.Bits(f.info.Mode())), 8), true
+	case 'A':
+		return filemode.String(f.info.Mode()), true
+	case 'F':
+		return fileType(f.info), true
+	case 'x':
+		return f.atime.Format(timeLayout), true
+	case 'X':
+		return epoch(f.atime), true
+	case 'y':
+		return f.mtime.Format(timeLayout), true
+	case 'Y':
+		return epoch(f.mtime), true
+	case 'z':
+		return f.ctime.Format(timeLayout), true
+	case 'Z':
+		return epoch(f.ctime), true
+	case 'w':
+		if !f.hasBirth {
+			return "-", true
+		}
+		return f.birth.Format(timeLayout), true
+	case 'W':
+		if !f.hasBirth {
+			return "0", true
+		}
+		return epoch(f.birth), true
+	}
+
+	st := f.stat
+	if st == nil {
+		return "", false
+	}
+
+	switch verb {
+	case 'f':
+		return strconv.FormatUint(uint64(st.Mode), 16), true
+	case 'b':
+		return strconv.FormatInt(int64(st.Blocks), 10), true
+	case 'B':
+		return "512", true
+	case 'o':
+		return strconv.FormatInt(int64(st.Blksize), 10), true
+	case 'h':
+		return u(uint64(st.Nlink)), true
+	case 'i':
+		return u(uint64(st.Ino)), true
+	case 'u':
+		return u(uint64(st.Uid)), true
+	case 'g':
+		return u(uint64(st.Gid)), true
+	case 'U':
+		return fileinfo.OwnerName(st), true
+	case 'G':
+		return fileinfo.GroupOwnerName(st), true
+	case 'd', 'r':
+		dev := uint64(st.Dev)
+		if verb == 'r' {
+			dev = uint64(st.Rdev)
+		}
+		switch modifier {
+		case 'H':
+			return u(fileinfo.Major(dev)), true
+		case 'L':
+			return u(fileinfo.Minor(dev)), true
+		}
+		return u(dev), true
+	case 'D':
+		return strconv.FormatUint(uint64(st.Dev), 16), true
+	case 't':
+		return strconv.FormatUint(fileinfo.Major(uint64(st.Rdev)), 16), true
+	case 'T':
+		return strconv.FormatUint(fileinfo.Minor(uint64(st.Rdev)), 16), true
+	}
+
+	return "", false
+}
+
+// fsStatus holds the status of a file system.
+type fsStatus struct {
+	name string
+	st   *fileinfo.FSStats
+}
+
+// newFSStatus gathers the status of the file system containing the named
+// file.
+func newFSStatus(name string) (*fsStatus, error) {
+	st, err := fileinfo.Statfs(name)
+	if err != nil {
+		return nil, err
+	}
+	return &fsStatus{name: name, st: st}, nil
+}
+
+// directive returns the value of a file system status directive.
+func (f *fsStatus) directive(modifier, verb byte) (string, bool) {
+	u := func(n uint64) string { return strconv.FormatUint(n, 10) }
+	st := f.st
+
+	switch verb {
+	case 'n':
+		return f.name, true
+	case 'a':
+		return u(st.BlocksAvail), true
+	case 'b':
+		return u(st.Blocks), true
+	case 'c':
+		return u(st.Files), true
+	case 'd':
+		return u(st.FilesFree), true
+	case 'f':
+		return u(st.BlocksFree), true
+	case 'i':
+		return strconv.FormatUint(st.ID, 16), true
+	case 'l':
+		return strconv.FormatInt(st.NameLen, 10), true
+	case 's':
+		return strconv.FormatInt(st.BlockSize, 10), true
+	case 'S':
+		return strconv.FormatInt(st.FragSize, 10), true
+	case 't':
+		return strconv.FormatInt(st.Type, 16), true
+	case 'T':
+		return st.TypeName(), true
+	}
+
+	return "", false
+}
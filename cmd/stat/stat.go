@@ -0,0 +1,152 @@
+// Package stat provides functionality for displaying the status of files
+// and file systems.
+package stat
+
+import (
+	"bufio"
+	"fmt"
+	"io/fs"
+	"os"
+
+	"github.com/skraio/unix-utilities/cmdflags"
+	"github.com/skraio/unix-utilities/internal/exit"
+	"github.com/spf13/cobra"
+)
+
+// Default formats, following GNU stat.
+const (
+	fileFormat = "  File: %N\n" +
+		"  Size: %-10s\tBlocks: %-10b IO Block: %-6o %F\n" +
+		"Device: %Hd,%Ld\tInode: %-11i Links: %h\n" +
+		"Access: (%04a/%10A)  Uid: (%5u/%8U)   Gid: (%5g/%8G)\n" +
+		"Access: %x\n" +
+		"Modify: %y\n" +
+		"Change: %z\n" +
+		" Birth: %w\n"
+	deviceFormat = "  File: %N\n" +
+		"  Size: %-10s\tBlocks: %-10b IO Block: %-6o %F\n" +
+		"Device: %Hd,%Ld\tInode: %-11i Links: %-5h Device type: %Hr,%Lr\n" +
+		"Access: (%04a/%10A)  Uid: (%5u/%8U)   Gid: (%5g/%8G)\n" +
+		"Access: %x\n" +
+		"Modify: %y\n" +
+		"Change: %z\n" +
+		" Birth: %w\n"
+	fsFormat = "  File: \"%n\"\n" +
+		"    ID: %-8i Namelen: %-7l Type: %T\n" +
+		"Block size: %-10s Fundamental block size: %S\n" +
+		"Blocks: Total: %-10b Free: %-10f Available: %a\n" +
+		"Inodes: Total: %-10c Free: %d\n"
+)
+
+// statFlags holds flags for stat command.
+type statFlags struct {
+	dereference bool
+	fileSystem  bool
+	format      string
+	printf      string
+}
+
+var pFlags statFlags
+
+// flags definition for stat command.
+var flags = []cmdflags.Flag{
+	{Value: &pFlags.dereference, Name: "dereference", ShortHand: "L", DefaultValue: false, Description: "follow links"},
+	{Value: &pFlags.fileSystem, Name: "file-system", ShortHand: "f", DefaultValue: false, Description: "display file system status instead of file status"},
+}
+
+// stringFlags definition for stat command.
+var stringFlags = []cmdflags.StringFlag{
+	{Value: &pFlags.format, Name: "format", ShortHand: "c", DefaultValue: "", Description: "use the specified FORMAT instead of the default; output a newline after each use of FORMAT"},
+	{Value: &pFlags.printf, Name: "printf", ShortHand: "", DefaultValue: "", Description: "like --format, but interpret backslash escapes, and do not output a mandatory trailing newline"},
+}
+
+// Cmd represents the 'stat' command configuration using Cobra.
+var Cmd = &cobra.Command{
+	Use:           "stat [-f flags] file...",
+	Short:         "Display file or file system status",
+	Args:          cobra.MinimumNArgs(1),
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return exit.Status(executeStat(cmd, args))
+	},
+}
+
+// init initializes the 'stat' command by setting up flags.
+func init() {
+	cmdflags.ParseFlags(flags, Cmd)
+	cmdflags.ParseStringFlags(stringFlags, Cmd)
+
+	Cmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		fmt.Fprintf(os.Stderr, "stat: %v\n", err)
+		return exit.Status(1)
+	})
+}
+
+// executeStat executes the stat command with given arguments and returns its
+// exit status.
+func executeStat(cmd *cobra.Command, args []string) int {
+	out := bufio.NewWriter(os.Stdout)
+	defer out.Flush()
+
+	format, custom := pFlags.format+"\n", cmd.Flags().Changed("format")
+	if cmd.Flags().Changed("printf") {
+		format, custom = interpretEscapes(pFlags.printf), true
+	}
+
+	status := 0
+	for _, name := range args {
+		var text string
+		var err error
+		if pFlags.fileSystem {
+			text, err = statFileSystem(name, format, custom)
+		} else {
+			text, err = statFile(name, format, custom)
+		}
+		if err != nil {
+			out.Flush()
+			fmt.Fprintf(os.Stderr, "stat: %s: %v\n", name, exit.Unwrap(err))
+			status = 1
+			continue
+		}
+		out.WriteString(text)
+	}
+
+	return status
+}
+
+// statFile returns the status of the named file in the given format, or the
+// default one unless custom is set.
+func statFile(name, format string, custom bool) (string, error) {
+	lstat := os.Lstat
+	if pFlags.dereference {
+		lstat = os.Stat
+	}
+	info, err := lstat(name)
+	if err != nil {
+		return "", err
+	}
+
+	f := newFileStatus(name, info, pFlags.dereference)
+	if !custom {
+		format = fileFormat
+		if info.Mode()&fs.ModeDevice != 0 {
+			format = deviceFormat
+		}
+	}
+	return expand(format, f.directive), nil
+}
+
+// statFileSystem returns the status of the file system containing the named
+// file in the given format, or the default one unless custom is set.
+func statFileSystem(name, format string, custom bool) (string, error) {
+	f, err := newFSStatus(name)
+	if err != nil {
+		return "", err
+	}
+
+	if !custom {
+		format = fsFormat
+	}
+	return expand(format, f.directive), nil
+}
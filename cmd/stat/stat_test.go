@@ -0,0 +1,76 @@
+package stat
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/skraio/unix-utilities/internal/assert"
+)
+
+func TestExpand(t *testing.T) {
+	values := map[byte]string{'s': "42", 'n': "file"}
+	directive := func(modifier, verb byte) (string, bool) {
+		if modifier != 0 {
+			return string(modifier) + string(verb), true
+		}
+		v, ok := values[verb]
+		return v, ok
+	}
+
+	tests := []struct {
+		name   string
+		format string
+		want   string
+	}{
+		{name: "Plain directives", format: "%n: %s", want: "file: 42"},
+		{name: "Width", format: "[%5s][%-5s]", want: "[   42][42   ]"},
+		{name: "Percent sign", format: "100%%", want: "100%"},
+		{name: "Modifiers", format: "%Hd,%Ld", want: "Hd,Ld"},
+		{name: "Unknown directive", format: "%q", want: "?"},
+		{name: "Trailing percent sign", format: "%s%", want: "42%"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, expand(tt.format, directive), tt.want)
+		})
+	}
+}
+
+func TestInterpretEscapes(t *testing.T) {
+	assert.Equal(t, interpretEscapes(`a\tb\n`), "a\tb\n")
+	assert.Equal(t, interpretEscapes(`\101\x42\\`), "AB\\")
+	assert.Equal(t, interpretEscapes(`\q`), `\q`)
+}
+
+func TestStatFile(t *testing.T) {
+	dir := t.TempDir()
+	name := filepath.Join(dir, "file")
+	if err := os.WriteFile(name, []byte("hello"), 0640); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chmod(name, 0640); err != nil {
+		t.Fatal(err)
+	}
+	link := filepath.Join(dir, "link")
+	if err := os.Symlink("file", link); err != nil {
+		t.Fatal(err)
+	}
+
+	text, err := statFile(name, "%s %a %A %F\n", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, text, "5 640 -rw-r----- regular file\n")
+
+	text, err = statFile(link, "%F %N", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, text, "symbolic link '"+link+"' -> 'file'")
+
+	if _, err := statFile(filepath.Join(dir, "missing"), "%n", true); err == nil {
+		t.Error("statFile succeeded on a missing file; want error")
+	}
+}
@@ -0,0 +1,210 @@
+// Package tac provides functionality for printing files with their records
+// in reverse order, last record first.
+package tac
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"regexp"
+
+	"github.com/skraio/unix-utilities/cmdflags"
+	"github.com/skraio/unix-utilities/internal/exit"
+	"github.com/skraio/unix-utilities/internal/fileinput"
+	"github.com/skraio/unix-utilities/internal/posixre"
+	"github.com/spf13/cobra"
+)
+
+// chunkSize is the least number of bytes read at a time going backwards
+// through a file.
+const chunkSize = 64 * 1024
+
+// tacFlags holds flags for tac command.
+type tacFlags struct {
+	before    bool
+	regex     bool
+	separator string
+}
+
+var pFlags tacFlags
+
+// flags definition for tac command.
+var flags = []cmdflags.Flag{
+	{Value: &pFlags.before, Name: "before", ShortHand: "b", DefaultValue: false, Description: "attach the separator before instead of after"},
+	{Value: &pFlags.regex, Name: "regex", ShortHand: "r", DefaultValue: false, Description: "interpret the separator as a basic regular expression"},
+}
+
+// stringFlags definition for tac command.
+var stringFlags = []cmdflags.StringFlag{
+	{Value: &pFlags.separator, Name: "separator", ShortHand: "s", DefaultValue: "\n", Description: "use STRING as the separator instead of newline"},
+}
+
+// Cmd represents the 'tac' command configuration using Cobra.
+var Cmd = &cobra.Command{
+	Use:           "tac [-f flags] [file]...",
+	Short:         "Concatenate and print files in reverse",
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return exit.Status(executeTac(args))
+	},
+}
+
+// init initializes the 'tac' command by setting up flags.
+func init() {
+	cmdflags.ParseFlags(flags, Cmd)
+	cmdflags.ParseStringFlags(stringFlags, Cmd)
+
+	Cmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		fmt.Fprintf(os.Stderr, "tac: %v\n", err)
+		return exit.Status(1)
+	})
+}
+
+// executeTac executes the tac command with given arguments and returns its
+// exit status. Files that cannot be read are reported and skipped.
+func executeTac(args []string) int {
+	t := &reverser{before: pFlags.before}
+	switch {
+	case pFlags.regex:
+		expr, err := posixre.TranslateBRE(pFlags.separator)
+		if err != nil {
+			return exit.Fail("tac", err)
+		}
+		re, err := regexp.Compile(`^(?:` + expr + `)`)
+		if err != nil {
+			return exit.Fail("tac", err)
+		}
+		re.Longest()
+		t.search = regexSearch(re)
+	default:
+		t.search = stringSearch([]byte(pFlags.separator))
+	}
+
+	out := bufio.NewWriter(os.Stdout)
+	defer out.Flush()
+	t.out = out
+
+	status := 0
+	for _, name := range fileinput.Args(args) {
+		if err := t.reverseFile(name); err != nil {
+			out.Flush()
+			status = exit.Fail("tac", err)
+		}
+	}
+	return status
+}
+
+// searchFunc returns the start and end of the last separator in b, the one
+// starting last.
+type searchFunc func(b []byte) (int, int, bool)
+
+// stringSearch returns a searchFunc finding sep. An empty separator is
+// never found.
+func stringSearch(sep []byte) searchFunc {
+	return func(b []byte) (int, int, bool) {
+		if len(sep) == 0 {
+			return 0, 0, false
+		}
+		i := bytes.LastIndex(b, sep)
+		return i, i + len(sep), i >= 0
+	}
+}
+
+// regexSearch returns a searchFunc finding the matches of re, which must be
+// anchored at its start. As the input is searched backwards, the match
+// found is the longest starting at the last position where one does.
+func regexSearch(re *regexp.Regexp) searchFunc {
+	return func(b []byte) (int, int, bool) {
+		for i := len(b) - 1; i >= 0; i-- {
+			if loc := re.FindIndex(b[i:]); loc != nil {
+				return i, i + loc[1], true
+			}
+		}
+		return 0, 0, false
+	}
+}
+
+// reverser prints inputs with their records reversed.
+type reverser struct {
+	out    io.Writer
+	search searchFunc
+
+	// before attaches each separator to the record after it.
+	before bool
+}
+
+// reverseFile prints the records of the named file in reverse. Files that
+// cannot seek are read into memory first.
+func (t *reverser) reverseFile(name string) error {
+	f, err := fileinput.Open(name)
+	if err != nil {
+		var pe *fs.PathError
+		if errors.As(err, &pe) {
+			return fmt.Errorf("failed to open '%s' for reading: %v", pe.Path, exit.Unwrap(pe))
+		}
+		return err
+	}
+	defer fileinput.Close(f)
+
+	rs, err := fileinput.Rewindable(f)
+	if err != nil {
+		return err
+	}
+	return t.reverse(rs)
+}
+
+// reverse prints the records of rs, from its current offset to its end,
+// in reverse. The input is read backwards a chunk at a time into a window
+// that only keeps the record being looked for.
+func (t *reverser) reverse(rs io.ReadSeeker) error {
+	start, err := rs.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+	pos, err := rs.Seek(0, io.SeekEnd)
+	if err != nil {
+		return err
+	}
+
+	// The window holds the input from pos. Separators are looked for
+	// before limit and the record to print next ends at next.
+	var window []byte
+	limit, next := 0, 0
+	for {
+		s, e, ok := t.search(window[:limit])
+		if ok {
+			from := e
+			if t.before {
+				from = s
+			}
+			if _, err := t.out.Write(window[from:next]); err != nil {
+				return err
+			}
+			limit, next = s, from
+			window = window[:next]
+			continue
+		}
+
+		if pos == start {
+			_, err := t.out.Write(window[:next])
+			return err
+		}
+		n := min(max(int64(len(window)), chunkSize), pos-start)
+		pos -= n
+		chunk := make([]byte, int(n), int(n)+len(window))
+		if _, err := rs.Seek(pos, io.SeekStart); err != nil {
+			return err
+		}
+		if _, err := io.ReadFull(rs, chunk); err != nil {
+			return err
+		}
+		window = append(chunk, window...)
+		limit += int(n)
+		next += int(n)
+	}
+}
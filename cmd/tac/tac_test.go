@@ -0,0 +1,60 @@
+package tac
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/skraio/unix-utilities/internal/assert"
+)
+
+func TestReverse(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		search searchFunc
+		before bool
+		want   string
+	}{
+		{name: "Lines", input: "a\nbb\nccc\n", search: stringSearch([]byte("\n")), want: "ccc\nbb\na\n"},
+		{name: "No final separator", input: "a\nbb\nccc", search: stringSearch([]byte("\n")), want: "cccbb\na\n"},
+		{name: "Separator", input: "a,b,,c,", search: stringSearch([]byte(",")), want: "c,,b,a,"},
+		{name: "Separator before", input: "a,b,,c,", search: stringSearch([]byte(",")), before: true, want: ",,c,,ba"},
+		{name: "Empty separator", input: "a\nb\n", search: stringSearch(nil), want: "a\nb\n"},
+		{name: "Regular expression", input: "1ab2aab3", search: regexSearch(regexp.MustCompile(`^(?:a*b)`)), want: "32aab1ab"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out strings.Builder
+			r := &reverser{out: &out, search: tt.search, before: tt.before}
+			if err := r.reverse(strings.NewReader(tt.input)); err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, out.String(), tt.want)
+		})
+	}
+}
+
+func TestReverseLongInput(t *testing.T) {
+	var input bytes.Buffer
+	lines := make([]string, 50000)
+	for i := range lines {
+		lines[i] = fmt.Sprintf("line %d\n", i)
+		input.WriteString(lines[i])
+	}
+
+	var out strings.Builder
+	r := &reverser{out: &out, search: stringSearch([]byte("\n"))}
+	if err := r.reverse(bytes.NewReader(input.Bytes())); err != nil {
+		t.Fatal(err)
+	}
+
+	var want strings.Builder
+	for i := len(lines) - 1; i >= 0; i-- {
+		want.WriteString(lines[i])
+	}
+	assert.Equal(t, out.String(), want.String())
+}
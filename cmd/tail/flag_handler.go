@@ -0,0 +1,269 @@
+package tail
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/skraio/unix-utilities/internal/lineio"
+)
+
+// blockSize is the size of the blocks read backwards from the end of a file.
+const blockSize = 8192
+
+// count describes which part of the input tail prints.
+type count struct {
+	n         int64
+	bytes     bool
+	fromStart bool
+}
+
+// multipliers maps size suffixes accepted by -n and -c to their values.
+var multipliers = map[string]int64{
+	"b": 512,
+	"K": 1024, "k": 1024, "KiB": 1024, "kB": 1000,
+	"M": 1024 * 1024, "MiB": 1024 * 1024, "MB": 1000 * 1000,
+	"G": 1024 * 1024 * 1024, "GiB": 1024 * 1024 * 1024, "GB": 1000 * 1000 * 1000,
+}
+
+// parseCount builds the count from the -n and -c arguments, where -c takes
+// precedence when given.
+func parseCount(lines, byteCount string) (count, error) {
+	c := count{}
+	arg := lines
+	if byteCount != "" {
+		c.bytes = true
+		arg = byteCount
+	}
+
+	n, fromStart, err := parseNumber(arg)
+	if err != nil {
+		return count{}, err
+	}
+	c.n, c.fromStart = n, fromStart
+
+	return c, nil
+}
+
+// parseNumber parses a count argument such as "10", "-10", "+10" or "2K".
+func parseNumber(arg string) (int64, bool, error) {
+	s := arg
+	fromStart := false
+	if strings.HasPrefix(s, "+") {
+		fromStart = true
+		s = s[1:]
+	} else {
+		s = strings.TrimPrefix(s, "-")
+	}
+
+	end := len(s)
+	for end > 0 && (s[end-1] < '0' || s[end-1] > '9') {
+		end--
+	}
+
+	mult := int64(1)
+	if suffix := s[end:]; suffix != "" {
+		m, ok := multipliers[suffix]
+		if !ok {
+			return 0, false, fmt.Errorf("invalid number: %q", arg)
+		}
+		mult = m
+	}
+
+	n, err := strconv.ParseInt(s[:end], 10, 64)
+	if err != nil || n < 0 {
+		return 0, false, fmt.Errorf("invalid number: %q", arg)
+	}
+
+	return n * mult, fromStart, nil
+}
+
+// output writes the part of f selected by the count to w.
+func (c count) output(w io.Writer, f *os.File) error {
+	seekable := false
+	if fi, err := f.Stat(); err == nil && fi.Mode().IsRegular() {
+		seekable = true
+	}
+
+	switch {
+	case c.fromStart && c.bytes:
+		return fromByte(w, f, c.n, seekable)
+	case c.fromStart:
+		return fromLine(w, f, c.n)
+	case seekable && c.bytes:
+		return lastBytesSeek(w, f, c.n)
+	case seekable:
+		return lastLinesSeek(w, f, c.n)
+	case c.bytes:
+		return lastBytesStream(w, f, c.n)
+	default:
+		return lastLinesStream(w, f, c.n)
+	}
+}
+
+// fromByte copies the input starting with the n-th byte.
+func fromByte(w io.Writer, f *os.File, n int64, seekable bool) error {
+	skip := max(n-1, 0)
+	if seekable {
+		if _, err := f.Seek(skip, io.SeekCurrent); err != nil {
+			return err
+		}
+	} else if _, err := io.CopyN(io.Discard, f, skip); err != nil {
+		if err == io.EOF {
+			return nil
+		}
+		return err
+	}
+
+	_, err := io.Copy(w, f)
+	return err
+}
+
+// fromLine copies the input starting with the n-th line.
+func fromLine(w io.Writer, f *os.File, n int64) error {
+	lr := lineio.NewReader(f)
+	for i := int64(1); ; i++ {
+		line, err := lr.ReadLine()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if i >= n {
+			if _, err := w.Write(line); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// lastBytesSeek copies the last n bytes of a seekable file.
+func lastBytesSeek(w io.Writer, f *os.File, n int64) error {
+	size, err := f.Seek(0, io.SeekEnd)
+	if err != nil {
+		return err
+	}
+
+	if _, err := f.Seek(max(size-n, 0), io.SeekStart); err != nil {
+		return err
+	}
+
+	_, err = io.Copy(w, f)
+	return err
+}
+
+// lastLinesSeek copies the last n lines of a seekable file, reading it
+// backwards from the end so that only the printed part is scanned.
+func lastLinesSeek(w io.Writer, f *os.File, n int64) error {
+	size, err := f.Seek(0, io.SeekEnd)
+	if err != nil {
+		return err
+	}
+
+	start, err := lastLinesOffset(f, size, n)
+	if err != nil {
+		return err
+	}
+
+	if _, err := f.Seek(start, io.SeekStart); err != nil {
+		return err
+	}
+
+	_, err = io.Copy(w, f)
+	return err
+}
+
+// lastLinesOffset returns the offset at which the last n lines of the first
+// size bytes of r begin. A newline ending the data does not start a new line.
+func lastLinesOffset(r io.ReaderAt, size, n int64) (int64, error) {
+	if n == 0 {
+		return size, nil
+	}
+
+	buf := make([]byte, blockSize)
+	pos := size
+	found := int64(0)
+	skipLast := true
+	for pos > 0 {
+		chunk := int64(blockSize)
+		if pos < chunk {
+			chunk = pos
+		}
+		pos -= chunk
+
+		if _, err := r.ReadAt(buf[:chunk], pos); err != nil && err != io.EOF {
+			return 0, err
+		}
+
+		block := buf[:chunk]
+		if skipLast {
+			skipLast = false
+			if block[len(block)-1] == '\n' {
+				block = block[:len(block)-1]
+			}
+		}
+
+		for i := len(block) - 1; i >= 0; i-- {
+			if block[i] != '\n' {
+				continue
+			}
+			found++
+			if found == n {
+				return pos + int64(i) + 1, nil
+			}
+		}
+	}
+
+	return 0, nil
+}
+
+// lastBytesStream copies the last n bytes of a non-seekable input.
+func lastBytesStream(w io.Writer, r io.Reader, n int64) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(data[max(int64(len(data))-n, 0):])
+	return err
+}
+
+// lastLinesStream copies the last n lines of a non-seekable input, keeping
+// only those lines in memory.
+func lastLinesStream(w io.Writer, r io.Reader, n int64) error {
+	if n == 0 {
+		_, err := io.Copy(io.Discard, r)
+		return err
+	}
+
+	ring := make([][]byte, 0, min(n, 1024))
+	next := 0
+	lr := lineio.NewReader(r)
+	for {
+		line, err := lr.ReadLine()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		if int64(len(ring)) < n {
+			ring = append(ring, bytes.Clone(line))
+			continue
+		}
+		ring[next] = append(ring[next][:0], line...)
+		next = (next + 1) % len(ring)
+	}
+
+	for i := range ring {
+		if _, err := w.Write(ring[(next+i)%len(ring)]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,244 @@
+package tail
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"syscall"
+	"time"
+
+	"github.com/skraio/unix-utilities/internal/exit"
+)
+
+// watcher notifies the follow loop that one of the followed files may have
+// changed.
+type watcher interface {
+	// add starts watching the named file.
+	add(name string) error
+
+	// events returns the channel receiving a value after each change.
+	events() <-chan struct{}
+
+	// close stops watching all files.
+	close() error
+}
+
+// followedFile holds the state of a file being followed.
+type followedFile struct {
+	name    string
+	file    *os.File
+	offset  int64
+	dev     uint64
+	ino     uint64
+	missing bool
+}
+
+// follower outputs data appended to the followed files.
+type follower struct {
+	out      *bufio.Writer
+	files    []*followedFile
+	last     *followedFile
+	watcher  watcher
+	byName   bool
+	headers  bool
+	interval time.Duration
+	pid      int
+}
+
+// run follows the files until the watched process exits. Files are kept
+// even once they cannot be read, so it only returns at once when there are
+// none to begin with.
+func (f *follower) run() error {
+	w, err := newWatcher(f.byName)
+	if err == nil {
+		f.watcher = w
+		defer w.close()
+		for _, ff := range f.files {
+			w.add(ff.name)
+		}
+	}
+
+	var events <-chan struct{}
+	if f.watcher != nil {
+		events = f.watcher.events()
+	}
+
+	for len(f.files) > 0 {
+		done := f.pid != 0 && !processAlive(f.pid)
+
+		for _, ff := range f.files {
+			f.check(ff)
+		}
+		if err := f.out.Flush(); err != nil {
+			return err
+		}
+
+		if done {
+			break
+		}
+
+		select {
+		case <-events:
+		case <-time.After(f.interval):
+		}
+	}
+
+	for _, ff := range f.files {
+		ff.close()
+	}
+	return nil
+}
+
+// check outputs any data appended to ff, detecting truncation and, when
+// following by name, replacement of the file.
+func (f *follower) check(ff *followedFile) {
+	if f.byName && !f.reopen(ff) {
+		return
+	}
+	if ff.file == nil {
+		return
+	}
+
+	fi, err := ff.file.Stat()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "tail: %v\n", err)
+		return
+	}
+
+	if fi.Mode().IsRegular() {
+		if fi.Size() < ff.offset {
+			fmt.Fprintf(os.Stderr, "tail: %s: file truncated\n", ff.name)
+			if _, err := ff.file.Seek(0, io.SeekStart); err != nil {
+				fmt.Fprintf(os.Stderr, "tail: %v\n", err)
+				return
+			}
+			ff.offset = 0
+		}
+		if fi.Size() == ff.offset {
+			return
+		}
+	}
+
+	f.drain(ff)
+}
+
+// reopen checks whether the file behind ff.name was removed or replaced and
+// opens the new file when it was. It reports whether ff can be read.
+func (f *follower) reopen(ff *followedFile) bool {
+	fi, err := os.Stat(ff.name)
+	if err != nil {
+		if !ff.missing {
+			fmt.Fprintf(os.Stderr, "tail: '%s' has become inaccessible: %v\n", ff.name, exit.Unwrap(err))
+			ff.missing = true
+		}
+		if ff.file != nil {
+			f.drain(ff)
+			ff.close()
+		}
+		return false
+	}
+
+	if ff.file != nil && !ff.missing && sameFile(fi, ff.dev, ff.ino) {
+		return true
+	}
+
+	if ff.file != nil {
+		f.drain(ff)
+		ff.close()
+	}
+
+	file, err := os.Open(ff.name)
+	if err != nil {
+		if !ff.missing {
+			fmt.Fprintf(os.Stderr, "tail: %v\n", err)
+			ff.missing = true
+		}
+		return false
+	}
+
+	if ff.missing {
+		fmt.Fprintf(os.Stderr, "tail: '%s' has appeared;  following new file\n", ff.name)
+	} else {
+		fmt.Fprintf(os.Stderr, "tail: '%s' has been replaced;  following new file\n", ff.name)
+	}
+
+	ff.file = file
+	ff.missing = false
+	if err := ff.sync(); err != nil {
+		fmt.Fprintf(os.Stderr, "tail: %v\n", err)
+	}
+	if f.watcher != nil {
+		f.watcher.add(ff.name)
+	}
+
+	return true
+}
+
+// drain copies everything that can currently be read from ff to the output,
+// preceded by a header when the output switches between files.
+func (f *follower) drain(ff *followedFile) {
+	buf := make([]byte, 32*1024)
+	for {
+		n, err := ff.file.Read(buf)
+		if n > 0 {
+			if f.headers && f.last != ff {
+				printHeader(f.out, ff.name, false)
+			}
+			f.last = ff
+			f.out.Write(buf[:n])
+			ff.offset += int64(n)
+		}
+		if err != nil {
+			if err != io.EOF {
+				fmt.Fprintf(os.Stderr, "tail: %v\n", err)
+			}
+			return
+		}
+	}
+}
+
+// sync records the current offset and identity of the open file.
+func (ff *followedFile) sync() error {
+	offset, err := ff.file.Seek(0, io.SeekCurrent)
+	if err != nil {
+		// Pipes and terminals cannot seek; they are simply read until EOF.
+		offset = 0
+	}
+	ff.offset = offset
+
+	fi, err := ff.file.Stat()
+	if err != nil {
+		return err
+	}
+	if stat, ok := fi.Sys().(*syscall.Stat_t); ok {
+		ff.dev = uint64(stat.Dev)
+		ff.ino = uint64(stat.Ino)
+	}
+
+	return nil
+}
+
+// close closes the underlying file of ff, if any.
+func (ff *followedFile) close() {
+	if ff.file != nil {
+		ff.file.Close()
+		ff.file = nil
+	}
+}
+
+// sameFile reports whether fi describes the file with the given device and
+// inode numbers.
+func sameFile(fi os.FileInfo, dev, ino uint64) bool {
+	stat, ok := fi.Sys().(*syscall.Stat_t)
+	if !ok {
+		return true
+	}
+	return uint64(stat.Dev) == dev && uint64(stat.Ino) == ino
+}
+
+// processAlive reports whether the process with the given ID still exists.
+func processAlive(pid int) bool {
+	err := syscall.Kill(pid, 0)
+	return err == nil || err == syscall.EPERM
+}
@@ -0,0 +1,81 @@
+//go:build linux
+
+package tail
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+)
+
+// inotifyWatcher is a watcher backed by the Linux inotify API. Watches are
+// added through fd, as the Fd method of file would put it back into blocking
+// mode and leave read unable to return once file is closed.
+type inotifyWatcher struct {
+	fd     int
+	file   *os.File
+	byName bool
+	ch     chan struct{}
+}
+
+// newWatcher returns an inotify watcher. When following by name, the parent
+// directories are watched as well so that rotated files are noticed.
+func newWatcher(byName bool) (watcher, error) {
+	fd, err := syscall.InotifyInit1(syscall.IN_CLOEXEC | syscall.IN_NONBLOCK)
+	if err != nil {
+		return nil, os.NewSyscallError("inotify_init1", err)
+	}
+
+	w := &inotifyWatcher{
+		fd:     fd,
+		file:   os.NewFile(uintptr(fd), "inotify"),
+		byName: byName,
+		ch:     make(chan struct{}, 1),
+	}
+	go w.read()
+
+	return w, nil
+}
+
+// add starts watching the named file and, when following by name, its
+// directory.
+func (w *inotifyWatcher) add(name string) error {
+	mask := uint32(syscall.IN_MODIFY | syscall.IN_ATTRIB | syscall.IN_CLOSE_WRITE |
+		syscall.IN_DELETE_SELF | syscall.IN_MOVE_SELF)
+	_, err := syscall.InotifyAddWatch(w.fd, name, mask)
+
+	if w.byName {
+		dirMask := uint32(syscall.IN_CREATE | syscall.IN_MOVED_TO | syscall.IN_MOVED_FROM | syscall.IN_DELETE)
+		if _, dirErr := syscall.InotifyAddWatch(w.fd, filepath.Dir(name), dirMask); err == nil {
+			err = dirErr
+		}
+	}
+
+	return err
+}
+
+// events returns the channel receiving a value after each batch of events.
+func (w *inotifyWatcher) events() <-chan struct{} {
+	return w.ch
+}
+
+// close stops the watcher.
+func (w *inotifyWatcher) close() error {
+	return w.file.Close()
+}
+
+// read consumes inotify events and signals the follow loop. The event
+// details are not needed since every followed file is checked anyway.
+func (w *inotifyWatcher) read() {
+	buf := make([]byte, 64*(syscall.SizeofInotifyEvent+syscall.NAME_MAX+1))
+	for {
+		if _, err := w.file.Read(buf); err != nil {
+			return
+		}
+
+		select {
+		case w.ch <- struct{}{}:
+		default:
+		}
+	}
+}
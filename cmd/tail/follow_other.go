@@ -0,0 +1,11 @@
+//go:build !linux
+
+package tail
+
+import "errors"
+
+// newWatcher reports that change notification is unavailable, so that the
+// follow loop polls the files every sleep interval instead.
+func newWatcher(byName bool) (watcher, error) {
+	return nil, errors.New("file change notification is not supported on this platform")
+}
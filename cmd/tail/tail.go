@@ -0,0 +1,177 @@
+// Package tail provides functionality for printing the last part of files and
+// following them as they grow.
+package tail
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/skraio/unix-utilities/cmdflags"
+	"github.com/skraio/unix-utilities/internal/exit"
+	"github.com/skraio/unix-utilities/internal/fileinput"
+	"github.com/spf13/cobra"
+)
+
+// tailFlags holds flags for tail command.
+type tailFlags struct {
+	follow     bool
+	followName bool
+	quiet      bool
+	verbose    bool
+	lines      string
+	bytes      string
+	interval   string
+	pid        int
+}
+
+var pFlags tailFlags
+
+// flags definition for tail command.
+var flags = []cmdflags.Flag{
+	{Value: &pFlags.follow, Name: "follow", ShortHand: "f", DefaultValue: false, Description: "output appended data as the file grows"},
+	{Value: &pFlags.followName, Name: "follow-name", ShortHand: "F", DefaultValue: false, Description: "follow the file by name and retry, surviving log rotation"},
+	{Value: &pFlags.quiet, Name: "quiet", ShortHand: "q", DefaultValue: false, Description: "never print headers giving file names"},
+	{Value: &pFlags.verbose, Name: "verbose", ShortHand: "v", DefaultValue: false, Description: "always print headers giving file names"},
+}
+
+// stringFlags definition for tail command.
+var stringFlags = []cmdflags.StringFlag{
+	{Value: &pFlags.lines, Name: "lines", ShortHand: "n", DefaultValue: "10", Description: "output the last N lines, or use +N to output starting with line N"},
+	{Value: &pFlags.bytes, Name: "bytes", ShortHand: "c", DefaultValue: "", Description: "output the last N bytes, or use +N to output starting with byte N"},
+	{Value: &pFlags.interval, Name: "sleep-interval", ShortHand: "s", DefaultValue: "1.0", Description: "with -f, sleep for about N seconds between checks"},
+}
+
+// intFlags definition for tail command.
+var intFlags = []cmdflags.IntFlag{
+	{Value: &pFlags.pid, Name: "pid", ShortHand: "", DefaultValue: 0, Description: "with -f, terminate after process ID dies"},
+}
+
+// Cmd represents the 'tail' command configuration using Cobra.
+var Cmd = &cobra.Command{
+	Use:           "tail [-f flags] [file]...",
+	Short:         "Output the last part of files",
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return exit.Status(executeTail(fileinput.Args(args)))
+	},
+}
+
+// init initializes the 'tail' command by setting up flags.
+func init() {
+	cmdflags.ParseFlags(flags, Cmd)
+	cmdflags.ParseStringFlags(stringFlags, Cmd)
+	cmdflags.ParseIntFlags(intFlags, Cmd)
+
+	Cmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		fmt.Fprintf(os.Stderr, "tail: %v\n", err)
+		return exit.Status(1)
+	})
+}
+
+// executeTail executes the tail command with given arguments and returns its
+// exit status, 1 when any file could not be read.
+func executeTail(args []string) int {
+	c, err := parseCount(pFlags.lines, pFlags.bytes)
+	if err != nil {
+		return exit.Fail("tail", err)
+	}
+
+	interval, err := strconv.ParseFloat(pFlags.interval, 64)
+	if err != nil || interval < 0 {
+		return exit.Fail("tail", fmt.Errorf("invalid number of seconds: %q", pFlags.interval))
+	}
+
+	out := bufio.NewWriter(os.Stdout)
+	defer out.Flush()
+
+	headers := pFlags.verbose || (len(args) > 1 && !pFlags.quiet)
+	followed := []*followedFile{}
+	status := 0
+	for i, name := range args {
+		ff, err := tailFile(out, name, c, headers, i == 0)
+		if err != nil {
+			out.Flush()
+			status = exit.Fail("tail", err)
+		}
+		if ff == nil && pFlags.followName && name != fileinput.Stdin {
+			ff = &followedFile{name: name, missing: true}
+		}
+		if ff != nil {
+			followed = append(followed, ff)
+		}
+	}
+
+	if !pFlags.follow && !pFlags.followName {
+		for _, ff := range followed {
+			ff.close()
+		}
+		return status
+	}
+
+	if err := out.Flush(); err != nil {
+		return exit.Fail("tail", err)
+	}
+	if len(followed) == 0 {
+		return exit.Fail("tail", errors.New("no files remaining"))
+	}
+
+	f := &follower{
+		out:      out,
+		files:    followed,
+		byName:   pFlags.followName,
+		headers:  headers,
+		interval: time.Duration(interval * float64(time.Second)),
+		pid:      pFlags.pid,
+	}
+	if len(followed) > 0 {
+		f.last = followed[len(followed)-1]
+	}
+	if err := f.run(); err != nil {
+		return exit.Fail("tail", err)
+	}
+	return status
+}
+
+// tailFile prints the requested part of the named file, preceded by a header
+// if requested, and returns the file ready to be followed from its current
+// end, or nil if it cannot be followed.
+func tailFile(w io.Writer, name string, c count, header, first bool) (*followedFile, error) {
+	file, err := fileinput.Open(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if header {
+		printHeader(w, name, first)
+	}
+
+	if err := c.output(w, file); err != nil {
+		fileinput.Close(file)
+		return nil, fmt.Errorf("error reading %q: %w", fileinput.DisplayName(name), err)
+	}
+
+	if file == os.Stdin {
+		return nil, nil
+	}
+
+	ff := &followedFile{name: name, file: file}
+	if err := ff.sync(); err != nil {
+		ff.close()
+		return nil, err
+	}
+	return ff, nil
+}
+
+// printHeader prints the header introducing the output of a file.
+func printHeader(w io.Writer, name string, first bool) {
+	if !first {
+		fmt.Fprintln(w)
+	}
+	fmt.Fprintf(w, "==> %s <==\n", fileinput.DisplayName(name))
+}
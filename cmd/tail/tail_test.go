@@ -0,0 +1,134 @@
+package tail
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/skraio/unix-utilities/internal/assert"
+)
+
+func TestParseNumber(t *testing.T) {
+	tests := []struct {
+		name      string
+		arg       string
+		want      int64
+		fromStart bool
+	}{
+		{
+			name: "Plain number",
+			arg:  "10",
+			want: 10,
+		},
+		{
+			name: "Negative number",
+			arg:  "-3",
+			want: 3,
+		},
+		{
+			name:      "From start",
+			arg:       "+5",
+			want:      5,
+			fromStart: true,
+		},
+		{
+			name: "Size suffix",
+			arg:  "2K",
+			want: 2048,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n, fromStart, err := parseNumber(tt.arg)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assert.Equal(t, n, tt.want)
+			assert.Equal(t, fromStart, tt.fromStart)
+		})
+	}
+}
+
+func TestLastLinesOffset(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		n    int64
+		want int64
+	}{
+		{
+			name: "Trailing newline",
+			text: "Without just one nest\nA bird can call the world home\nLife is your career\n",
+			n:    2,
+			want: 22,
+		},
+		{
+			name: "No trailing newline",
+			text: "Without just one nest\nA bird can call the world home\nLife is your career",
+			n:    1,
+			want: 53,
+		},
+		{
+			name: "More lines than the file has",
+			text: "Without just one nest\n",
+			n:    5,
+			want: 0,
+		},
+		{
+			name: "Spanning several blocks",
+			text: strings.Repeat("x", 3*blockSize) + "\n" + strings.Repeat("y", blockSize) + "\n",
+			n:    1,
+			want: 3*blockSize + 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := strings.NewReader(tt.text)
+
+			ans, err := lastLinesOffset(r, int64(len(tt.text)), tt.n)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assert.Equal(t, ans, tt.want)
+		})
+	}
+}
+
+func TestLastLinesStream(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		n    int64
+		want string
+	}{
+		{
+			name: "Short file",
+			text: "Without just one nest\nA bird can call the world home\nLife is your career\n",
+			n:    2,
+			want: "A bird can call the world home\nLife is your career\n",
+		},
+		{
+			name: "Empty file",
+			text: "",
+			n:    10,
+			want: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			err := lastLinesStream(&buf, strings.NewReader(tt.text), tt.n)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assert.Equal(t, buf.String(), tt.want)
+		})
+	}
+}
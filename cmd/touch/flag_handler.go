@@ -0,0 +1,70 @@
+package touch
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// dateLayouts lists the date formats accepted by -d. Layouts without a
+// zone are read in local time, and those without a date refer to today.
+var dateLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999 -0700",
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	time.RFC1123Z,
+	time.RFC1123,
+	time.UnixDate,
+	time.ANSIC,
+	"15:04:05.999999999",
+	"15:04",
+}
+
+// parseDate parses the argument of -d: a date in one of dateLayouts, a
+// number of seconds since the epoch prefixed by '@', or one of the words
+// now, today, yesterday and tomorrow.
+func parseDate(s string, now time.Time) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	switch strings.ToLower(s) {
+	case "now", "today":
+		return now, nil
+	case "yesterday":
+		return now.AddDate(0, 0, -1), nil
+	case "tomorrow":
+		return now.AddDate(0, 0, 1), nil
+	}
+
+	if strings.HasPrefix(s, "@") {
+		secs, frac, _ := strings.Cut(s[1:], ".")
+		sec, err := strconv.ParseInt(secs, 10, 64)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid date format '%s'", s)
+		}
+		nsec := int64(0)
+		if frac != "" {
+			frac = (frac + "000000000")[:9]
+			if nsec, err = strconv.ParseInt(frac, 10, 64); err != nil {
+				return time.Time{}, fmt.Errorf("invalid date format '%s'", s)
+			}
+		}
+		return time.Unix(sec, nsec), nil
+	}
+
+	for _, layout := range dateLayouts {
+		t, err := time.ParseInLocation(layout, s, now.Location())
+		if err != nil {
+			continue
+		}
+		if !strings.Contains(layout, "2006") {
+			t = time.Date(now.Year(), now.Month(), now.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), now.Location())
+		}
+		return t, nil
+	}
+
+	return time.Time{}, fmt.Errorf("invalid date format '%s'", s)
+}
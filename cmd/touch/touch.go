@@ -0,0 +1,141 @@
+// Package touch provides functionality for changing file timestamps.
+package touch
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"time"
+
+	"github.com/skraio/unix-utilities/cmdflags"
+	"github.com/skraio/unix-utilities/internal/exit"
+	"github.com/skraio/unix-utilities/internal/fileinfo"
+	"github.com/spf13/cobra"
+)
+
+// touchFlags holds flags for touch command.
+type touchFlags struct {
+	access    bool
+	modify    bool
+	noCreate  bool
+	date      string
+	reference string
+}
+
+var pFlags touchFlags
+
+// flags definition for touch command.
+var flags = []cmdflags.Flag{
+	{Value: &pFlags.access, Name: "access", ShortHand: "a", DefaultValue: false, Description: "change only the access time"},
+	{Value: &pFlags.modify, Name: "modify", ShortHand: "m", DefaultValue: false, Description: "change only the modification time"},
+	{Value: &pFlags.noCreate, Name: "no-create", ShortHand: "c", DefaultValue: false, Description: "do not create any files"},
+}
+
+// stringFlags definition for touch command.
+var stringFlags = []cmdflags.StringFlag{
+	{Value: &pFlags.date, Name: "date", ShortHand: "d", DefaultValue: "", Description: "parse STRING and use it instead of current time"},
+	{Value: &pFlags.reference, Name: "reference", ShortHand: "r", DefaultValue: "", Description: "use this file's times instead of current time"},
+}
+
+// Cmd represents the 'touch' command configuration using Cobra.
+var Cmd = &cobra.Command{
+	Use:           "touch [-f flags] file...",
+	Short:         "Change file timestamps",
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return exit.Status(executeTouch(cmd, args))
+	},
+}
+
+// init initializes the 'touch' command by setting up flags.
+func init() {
+	cmdflags.ParseFlags(flags, Cmd)
+	cmdflags.ParseStringFlags(stringFlags, Cmd)
+
+	Cmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		fmt.Fprintf(os.Stderr, "touch: %v\n", err)
+		return exit.Status(1)
+	})
+}
+
+// executeTouch executes the touch command with given arguments and returns
+// its exit status.
+func executeTouch(cmd *cobra.Command, args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, "touch: missing file operand")
+		return 1
+	}
+
+	atime, mtime, err := newTimes(cmd.Flags().Changed("date"), cmd.Flags().Changed("reference"), time.Now())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "touch: %v\n", err)
+		return 1
+	}
+
+	// Without -a or -m both times change.
+	setAccess := pFlags.access || !pFlags.modify
+	setModify := pFlags.modify || !pFlags.access
+
+	status := 0
+	for _, name := range args {
+		if err := touch(name, atime, mtime, setAccess, setModify); err != nil {
+			fmt.Fprintf(os.Stderr, "touch: %v\n", err)
+			status = 1
+		}
+	}
+	return status
+}
+
+// newTimes returns the access and modification times to set: those of the
+// reference file, the parsed date or now.
+func newTimes(hasDate, hasReference bool, now time.Time) (atime, mtime time.Time, err error) {
+	switch {
+	case hasDate && hasReference:
+		return atime, mtime, errors.New("cannot specify times from more than one source")
+	case hasReference:
+		info, err := os.Stat(pFlags.reference)
+		if err != nil {
+			return atime, mtime, fmt.Errorf("failed to get attributes of '%s': %v", pFlags.reference, exit.Unwrap(err))
+		}
+		atime, mtime, _ = fileinfo.Times(info)
+		return atime, mtime, nil
+	case hasDate:
+		t, err := parseDate(pFlags.date, now)
+		return t, t, err
+	}
+	return now, now, nil
+}
+
+// touch creates the named file if needed and sets the selected times,
+// keeping the current value of the other.
+func touch(name string, atime, mtime time.Time, setAccess, setModify bool) error {
+	info, err := os.Stat(name)
+	if errors.Is(err, fs.ErrNotExist) {
+		if pFlags.noCreate {
+			return nil
+		}
+		f, createErr := os.OpenFile(name, os.O_WRONLY|os.O_CREATE, 0666)
+		if createErr != nil {
+			return fmt.Errorf("cannot touch '%s': %v", name, exit.Unwrap(createErr))
+		}
+		f.Close()
+		info, err = os.Stat(name)
+	}
+	if err != nil {
+		return fmt.Errorf("cannot touch '%s': %v", name, exit.Unwrap(err))
+	}
+
+	curAtime, curMtime, _ := fileinfo.Times(info)
+	if !setAccess {
+		atime = curAtime
+	}
+	if !setModify {
+		mtime = curMtime
+	}
+	if err := os.Chtimes(name, atime, mtime); err != nil {
+		return fmt.Errorf("setting times of '%s': %v", name, exit.Unwrap(err))
+	}
+	return nil
+}
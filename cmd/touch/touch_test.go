@@ -0,0 +1,68 @@
+package touch
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/skraio/unix-utilities/internal/assert"
+	"github.com/skraio/unix-utilities/internal/fileinfo"
+)
+
+func TestParseDate(t *testing.T) {
+	now := time.Date(2024, 3, 10, 12, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		date    string
+		want    time.Time
+		wantErr bool
+	}{
+		{date: "2020-01-02", want: time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC)},
+		{date: "2020-01-02 03:04:05.5", want: time.Date(2020, 1, 2, 3, 4, 5, 5e8, time.UTC)},
+		{date: "2020-01-02T03:04:05+02:00", want: time.Date(2020, 1, 2, 1, 4, 5, 0, time.UTC)},
+		{date: "@86400.25", want: time.Unix(86400, 25e7)},
+		{date: "08:15", want: time.Date(2024, 3, 10, 8, 15, 0, 0, time.UTC)},
+		{date: "yesterday", want: time.Date(2024, 3, 9, 12, 30, 0, 0, time.UTC)},
+		{date: "next week", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.date, func(t *testing.T) {
+			got, err := parseDate(tt.date, now)
+			assert.Equal(t, err != nil, tt.wantErr)
+			assert.Equal(t, got.Equal(tt.want), true)
+		})
+	}
+}
+
+func TestTouch(t *testing.T) {
+	dir := t.TempDir()
+	name := filepath.Join(dir, "file")
+	t1 := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	t2 := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	times := func() (time.Time, time.Time) {
+		info, err := os.Stat(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		atime, mtime, _ := fileinfo.Times(info)
+		return atime, mtime
+	}
+
+	pFlags = touchFlags{noCreate: true}
+	assert.Equal(t, touch(name, t1, t1, true, true), nil)
+	_, err := os.Stat(name)
+	assert.Equal(t, os.IsNotExist(err), true)
+
+	pFlags = touchFlags{}
+	assert.Equal(t, touch(name, t1, t1, true, true), nil)
+	atime, mtime := times()
+	assert.Equal(t, atime.Equal(t1) && mtime.Equal(t1), true)
+
+	assert.Equal(t, touch(name, t2, t2, false, true), nil)
+	atime, mtime = times()
+	assert.Equal(t, atime.Equal(t1) && mtime.Equal(t2), true)
+
+	assert.Equal(t, touch(filepath.Join(dir, "missing/file"), t1, t1, true, true) != nil, true)
+}
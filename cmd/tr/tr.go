@@ -0,0 +1,85 @@
+// Package tr provides functionality for translating, squeezing and deleting
+// characters of the standard input.
+package tr
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/skraio/unix-utilities/cmdflags"
+	"github.com/skraio/unix-utilities/internal/exit"
+	"github.com/skraio/unix-utilities/internal/translate"
+	"github.com/spf13/cobra"
+)
+
+// trFlags holds flags for tr command.
+type trFlags struct {
+	complement bool
+	delete     bool
+	squeeze    bool
+	truncate   bool
+	utf8       bool
+}
+
+var pFlags trFlags
+
+// flags definition for tr command.
+var flags = []cmdflags.Flag{
+	{Value: &pFlags.complement, Name: "complement", ShortHand: "c", DefaultValue: false, Description: "use the complement of SET1"},
+	{Value: &pFlags.delete, Name: "delete", ShortHand: "d", DefaultValue: false, Description: "delete characters in SET1, do not translate"},
+	{Value: &pFlags.squeeze, Name: "squeeze-repeats", ShortHand: "s", DefaultValue: false, Description: "replace each sequence of a repeated character that is listed in the last specified SET, with a single occurrence of that character"},
+	{Value: &pFlags.truncate, Name: "truncate-set1", ShortHand: "t", DefaultValue: false, Description: "first truncate SET1 to length of SET2"},
+	{Value: &pFlags.utf8, Name: "utf8", ShortHand: "", DefaultValue: false, Description: "operate on UTF-8 characters instead of bytes"},
+}
+
+// Cmd represents the 'tr' command configuration using Cobra.
+var Cmd = &cobra.Command{
+	Use:           "tr [-f flags] SET1 [SET2]",
+	Short:         "Translate, squeeze and/or delete characters",
+	Args:          cobra.RangeArgs(1, 2),
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := executeTr(args); err != nil {
+			fmt.Fprintf(os.Stderr, "tr: %v\n", err)
+			return exit.Status(1)
+		}
+		return nil
+	},
+}
+
+// init initializes the 'tr' command by setting up flags.
+func init() {
+	cmdflags.ParseFlags(flags, Cmd)
+	// POSIX distinguishes complementing characters (-C) from values (-c);
+	// both complement the set in the unit selected by --utf8.
+	Cmd.Flags().BoolVarP(&pFlags.complement, "complement-chars", "C", false, "same as -c")
+
+	Cmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		fmt.Fprintf(os.Stderr, "tr: %v\n", err)
+		return exit.Status(1)
+	})
+}
+
+// executeTr executes the tr command with given arguments.
+func executeTr(args []string) error {
+	set2 := ""
+	if len(args) == 2 {
+		set2 = args[1]
+	}
+
+	t, err := translate.New(args[0], set2, len(args) == 2, translate.Options{
+		Complement: pFlags.complement,
+		Delete:     pFlags.delete,
+		Squeeze:    pFlags.squeeze,
+		Truncate:   pFlags.truncate,
+		UTF8:       pFlags.utf8,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(os.Stdout, t.Reader(os.Stdin))
+	return err
+}
@@ -0,0 +1,166 @@
+package tr
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/skraio/unix-utilities/internal/assert"
+	"github.com/skraio/unix-utilities/internal/translate"
+)
+
+func TestTransformStream(t *testing.T) {
+	tests := []struct {
+		name  string
+		set1  string
+		set2  string
+		opts  translate.Options
+		input string
+		want  string
+	}{
+		{
+			name:  "Ranges",
+			set1:  "a-z",
+			set2:  "A-Z",
+			input: "Without just one nest\n",
+			want:  "WITHOUT JUST ONE NEST\n",
+		},
+		{
+			name:  "Classes",
+			set1:  "[:upper:][:digit:]",
+			set2:  "[:lower:]#",
+			input: "Bird 42\n",
+			want:  "bird ##\n",
+		},
+		{
+			name:  "Shorter second set repeats its last character",
+			set1:  "abc",
+			set2:  "x",
+			input: "cab\n",
+			want:  "xxx\n",
+		},
+		{
+			name:  "Truncated first set",
+			set1:  "abc",
+			set2:  "x",
+			opts:  translate.Options{Truncate: true},
+			input: "cab\n",
+			want:  "cxb\n",
+		},
+		{
+			name:  "Repeat construct",
+			set1:  "a-e",
+			set2:  "[x*2][y*]",
+			input: "abcde\n",
+			want:  "xxyyy\n",
+		},
+		{
+			name:  "Octal escapes",
+			set1:  `\141\n`,
+			set2:  `b\072`,
+			input: "banana\n",
+			want:  "bbnbnb:",
+		},
+		{
+			name:  "Delete complement",
+			set1:  `[:alpha:]\n`,
+			opts:  translate.Options{Delete: true, Complement: true},
+			input: "bird, call; world!\n",
+			want:  "birdcallworld\n",
+		},
+		{
+			name:  "Squeeze",
+			set1:  " ",
+			opts:  translate.Options{Squeeze: true},
+			input: "call   world  home\n",
+			want:  "call world home\n",
+		},
+		{
+			name:  "Translate and squeeze",
+			set1:  "[:blank:]",
+			set2:  `\n`,
+			opts:  translate.Options{Squeeze: true},
+			input: "call \t world",
+			want:  "call\nworld",
+		},
+		{
+			name:  "Delete and squeeze",
+			set1:  "0-9",
+			set2:  "a",
+			opts:  translate.Options{Delete: true, Squeeze: true},
+			input: "ba1a2an3a\n",
+			want:  "bana\n",
+		},
+		{
+			name:  "UTF-8 case conversion",
+			set1:  "[:lower:]",
+			set2:  "[:upper:]",
+			opts:  translate.Options{UTF8: true},
+			input: "żółw\n",
+			want:  "ŻÓŁW\n",
+		},
+		{
+			name:  "UTF-8 characters",
+			set1:  "ąę",
+			set2:  "ae",
+			opts:  translate.Options{UTF8: true},
+			input: "zęby ząb\n",
+			want:  "zeby zab\n",
+		},
+		{
+			name:  "UTF-8 complement",
+			set1:  `a-z\n`,
+			set2:  "_",
+			opts:  translate.Options{UTF8: true, Complement: true},
+			input: "żółw ok\n",
+			want:  "___w_ok\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr, err := translate.New(tt.set1, tt.set2, tt.set2 != "", tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// Reading a byte at a time splits UTF-8 sequences and runs to
+			// squeeze across chunks.
+			var out bytes.Buffer
+			_, err = io.Copy(&out, tr.Reader(iotest.OneByteReader(strings.NewReader(tt.input))))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assert.Equal(t, out.String(), tt.want)
+		})
+	}
+}
+
+func TestNewErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		set1    string
+		set2    string
+		hasSet2 bool
+		opts    translate.Options
+	}{
+		{name: "Missing second set", set1: "a"},
+		{name: "Empty second set", set1: "a", hasSet2: true},
+		{name: "Reversed range", set1: "z-a", set2: "x", hasSet2: true},
+		{name: "Invalid class", set1: "[:word:]", set2: "x", hasSet2: true},
+		{name: "Class in second set", set1: "a", set2: "[:digit:]", hasSet2: true},
+		{name: "Repeat in first set", set1: "[a*2]", set2: "x", hasSet2: true},
+		{name: "Extra set when deleting", set1: "a", set2: "b", hasSet2: true, opts: translate.Options{Delete: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := translate.New(tt.set1, tt.set2, tt.hasSet2, tt.opts); err == nil {
+				t.Errorf("New(%q, %q) succeeded; want error", tt.set1, tt.set2)
+			}
+		})
+	}
+}
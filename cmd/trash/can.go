@@ -0,0 +1,352 @@
+package trash
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/skraio/unix-utilities/internal/fileinfo"
+	"github.com/skraio/unix-utilities/internal/mountinfo"
+)
+
+// dateLayout is the layout of DeletionDate in .trashinfo files.
+const dateLayout = "2006-01-02T15:04:05"
+
+// can is a trash directory, holding the trashed files in files/ and their
+// metadata in info/.
+type can struct {
+	dir string
+
+	// topdir is the mount point the original paths are relative to, or the
+	// empty string for the home trash, whose paths are absolute.
+	topdir string
+}
+
+// item is a file in the trash.
+type item struct {
+	can     *can
+	name    string
+	path    string
+	deleted time.Time
+}
+
+// homeCan returns the trash of the user's home directory, in
+// $XDG_DATA_HOME/Trash.
+func homeCan() (*can, error) {
+	data := os.Getenv("XDG_DATA_HOME")
+	if data == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, err
+		}
+		data = filepath.Join(home, ".local", "share")
+	}
+	return &can{dir: filepath.Join(data, "Trash")}, nil
+}
+
+// topdirCans returns the trash directories of a mount point: the per-user
+// directory of an administrator-created $topdir/.Trash, which must have the
+// sticky bit set and not be a symbolic link, and $topdir/.Trash-$uid.
+func topdirCans(topdir string) []*can {
+	uid := strconv.Itoa(os.Getuid())
+	cans := []*can{}
+	if info, err := os.Lstat(filepath.Join(topdir, ".Trash")); err == nil &&
+		info.IsDir() && info.Mode()&fs.ModeSticky != 0 {
+		cans = append(cans, &can{dir: filepath.Join(topdir, ".Trash", uid), topdir: topdir})
+	}
+	return append(cans, &can{dir: filepath.Join(topdir, ".Trash-"+uid), topdir: topdir})
+}
+
+// sameDevice reports whether two paths are on the same file system. The
+// first existing ancestor stands for a path that does not exist yet.
+func sameDevice(a, b string) bool {
+	stat := func(path string) fs.FileInfo {
+		for {
+			if info, err := os.Stat(path); err == nil {
+				return info
+			}
+			parent := filepath.Dir(path)
+			if parent == path {
+				return nil
+			}
+			path = parent
+		}
+	}
+	ia, ib := stat(a), stat(b)
+	return ia != nil && ib != nil && deviceOf(ia) == deviceOf(ib)
+}
+
+// deviceOf returns the device a file resides on.
+func deviceOf(info fs.FileInfo) uint64 {
+	if stat, ok := fileinfo.Sys(info); ok {
+		return uint64(stat.Dev)
+	}
+	return 0
+}
+
+// mountPoint returns the mount point of the file system holding an absolute
+// path: the longest mount target containing it.
+func mountPoint(mounts []*mountinfo.Mount, path string) string {
+	best := "/"
+	for _, m := range mounts {
+		if within(path, m.Target) && len(m.Target) > len(best) {
+			best = m.Target
+		}
+	}
+	return best
+}
+
+// within reports whether path is dir or lies inside it.
+func within(path, dir string) bool {
+	return dir == "/" || path == dir || strings.HasPrefix(path, dir+"/")
+}
+
+// canFor returns the trash a file goes to: the home trash when the file is
+// on the same file system, and the trash of the file's mount point
+// otherwise, creating it if needed.
+func canFor(home *can, path string) (*can, error) {
+	if sameDevice(path, home.dir) {
+		return home, home.create()
+	}
+
+	mounts, err := mountinfo.Read()
+	if err != nil {
+		return nil, err
+	}
+	cans := topdirCans(mountPoint(mounts, filepath.Dir(path)))
+	for _, c := range cans[:len(cans)-1] {
+		if err := c.create(); err == nil {
+			return c, nil
+		}
+	}
+	c := cans[len(cans)-1]
+	return c, c.create()
+}
+
+// allCans returns the home trash and the existing trash directories of all
+// mounted file systems.
+func allCans(home *can) []*can {
+	cans := []*can{home}
+	mounts, err := mountinfo.Read()
+	if err != nil {
+		return cans
+	}
+
+	seen := map[string]bool{home.dir: true}
+	for _, m := range mounts {
+		for _, c := range topdirCans(m.Target) {
+			if seen[c.dir] {
+				continue
+			}
+			seen[c.dir] = true
+			if info, err := os.Stat(c.dir); err == nil && info.IsDir() {
+				cans = append(cans, c)
+			}
+		}
+	}
+	return cans
+}
+
+// create creates the trash directory and its subdirectories, accessible
+// only to the user.
+func (c *can) create() error {
+	for _, sub := range []string{"files", "info"} {
+		if err := os.MkdirAll(filepath.Join(c.dir, sub), 0700); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// filePath returns the path of a trashed file.
+func (c *can) filePath(name string) string {
+	return filepath.Join(c.dir, "files", name)
+}
+
+// infoPath returns the path of the metadata of a trashed file.
+func (c *can) infoPath(name string) string {
+	return filepath.Join(c.dir, "info", name+".trashinfo")
+}
+
+// put moves the file at the absolute path into the trash. The metadata file
+// is created first, exclusively, to claim a name no other file uses.
+func (c *can) put(path string, now time.Time) (*item, error) {
+	original := path
+	if c.topdir != "" {
+		rel, err := filepath.Rel(c.topdir, path)
+		if err != nil {
+			return nil, err
+		}
+		original = rel
+	}
+
+	base := filepath.Base(path)
+	for i := 1; ; i++ {
+		name := base
+		if i > 1 {
+			name = base + "." + strconv.Itoa(i)
+		}
+
+		f, err := os.OpenFile(c.infoPath(name), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+		if errors.Is(err, fs.ErrExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if _, err := os.Lstat(c.filePath(name)); err == nil {
+			f.Close()
+			os.Remove(c.infoPath(name))
+			continue
+		}
+
+		it := &item{can: c, name: name, path: original, deleted: now}
+		_, err = io.WriteString(f, it.info())
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err == nil {
+			err = os.Rename(path, c.filePath(name))
+		}
+		if err != nil {
+			os.Remove(c.infoPath(name))
+			return nil, err
+		}
+		return it, nil
+	}
+}
+
+// items returns the files in the trash. Metadata that cannot be read is
+// skipped.
+func (c *can) items() ([]*item, error) {
+	entries, err := os.ReadDir(filepath.Join(c.dir, "info"))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	items := []*item{}
+	for _, e := range entries {
+		name, ok := strings.CutSuffix(e.Name(), ".trashinfo")
+		if !ok {
+			continue
+		}
+		f, err := os.Open(c.infoPath(name))
+		if err != nil {
+			continue
+		}
+		it, err := parseInfo(f)
+		f.Close()
+		if err != nil {
+			continue
+		}
+		it.can, it.name = c, name
+		items = append(items, it)
+	}
+	return items, nil
+}
+
+// info returns the contents of the .trashinfo file of an item.
+func (it *item) info() string {
+	return "[Trash Info]\n" +
+		"Path=" + escape(it.path) + "\n" +
+		"DeletionDate=" + it.deleted.Format(dateLayout) + "\n"
+}
+
+// parseInfo parses a .trashinfo file.
+func parseInfo(r io.Reader) (*item, error) {
+	it := &item{}
+	sc := bufio.NewScanner(r)
+	inGroup, hasPath := false, false
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if strings.HasPrefix(line, "[") {
+			inGroup = line == "[Trash Info]"
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !inGroup || !ok {
+			continue
+		}
+
+		switch key {
+		case "Path":
+			path, err := url.PathUnescape(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid path '%s'", value)
+			}
+			it.path, hasPath = path, true
+		case "DeletionDate":
+			t, err := time.ParseInLocation(dateLayout, value, time.Local)
+			if err != nil {
+				return nil, fmt.Errorf("invalid deletion date '%s'", value)
+			}
+			it.deleted = t
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	if !hasPath {
+		return nil, errors.New("missing path")
+	}
+	return it, nil
+}
+
+// escape escapes a path as in URLs, keeping the slashes and the characters
+// RFC 2396 leaves unreserved.
+func escape(path string) string {
+	const unreserved = "-_.!~*'()/"
+	var b strings.Builder
+	for i := 0; i < len(path); i++ {
+		c := path[i]
+		if 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' || strings.IndexByte(unreserved, c) >= 0 {
+			b.WriteByte(c)
+			continue
+		}
+		fmt.Fprintf(&b, "%%%02X", c)
+	}
+	return b.String()
+}
+
+// originalPath returns the absolute path an item was trashed from.
+func (it *item) originalPath() string {
+	if it.can.topdir == "" || filepath.IsAbs(it.path) {
+		return it.path
+	}
+	return filepath.Join(it.can.topdir, it.path)
+}
+
+// remove deletes an item from the trash for good.
+func (it *item) remove() error {
+	if err := os.RemoveAll(it.can.filePath(it.name)); err != nil {
+		return err
+	}
+	return os.Remove(it.can.infoPath(it.name))
+}
+
+// restore moves an item back to its original path, recreating missing
+// parent directories. An existing file at that path is left alone.
+func (it *item) restore() error {
+	dest := it.originalPath()
+	if _, err := os.Lstat(dest); err == nil {
+		return fmt.Errorf("cannot restore '%s': file exists", dest)
+	}
+	if err := os.MkdirAll(filepath.Dir(dest), 0777); err != nil {
+		return err
+	}
+	if err := os.Rename(it.can.filePath(it.name), dest); err != nil {
+		return err
+	}
+	return os.Remove(it.can.infoPath(it.name))
+}
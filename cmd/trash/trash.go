@@ -0,0 +1,310 @@
+// Package trash provides functionality for moving files to the trash and
+// managing them there, following the freedesktop.org Trash specification.
+package trash
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/skraio/unix-utilities/internal/exit"
+	"github.com/skraio/unix-utilities/internal/listing"
+	"github.com/spf13/cobra"
+)
+
+// trashFlags holds flags for trash subcommands.
+type trashFlags struct {
+	force     bool
+	verbose   bool
+	human     bool
+	olderThan string
+}
+
+var pFlags trashFlags
+
+// Cmd represents the 'trash' command configuration using Cobra.
+var Cmd = &cobra.Command{
+	Use:   "trash command [file]...",
+	Short: "Move files to the trash, list, restore or empty it",
+}
+
+// putCmd represents the 'trash put' subcommand.
+var putCmd = &cobra.Command{
+	Use:           "put [-f flags] file...",
+	Short:         "Move files to the trash",
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return exit.Status(executePut(args))
+	},
+}
+
+// listCmd represents the 'trash list' subcommand.
+var listCmd = &cobra.Command{
+	Use:           "list [-f flags]",
+	Short:         "List the files in the trash",
+	Args:          cobra.NoArgs,
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return exit.Status(executeList())
+	},
+}
+
+// restoreCmd represents the 'trash restore' subcommand.
+var restoreCmd = &cobra.Command{
+	Use:           "restore file...",
+	Short:         "Restore files from the trash to their original location",
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return exit.Status(executeRestore(args))
+	},
+}
+
+// emptyCmd represents the 'trash empty' subcommand.
+var emptyCmd = &cobra.Command{
+	Use:           "empty [-f flags]",
+	Short:         "Delete the files in the trash for good",
+	Args:          cobra.NoArgs,
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return exit.Status(executeEmpty())
+	},
+}
+
+// init initializes the 'trash' command by setting up its subcommands and
+// their flags.
+func init() {
+	putCmd.Flags().BoolVarP(&pFlags.force, "force", "f", false, "ignore nonexistent files")
+	putCmd.Flags().BoolVarP(&pFlags.verbose, "verbose", "v", false, "explain what is being done")
+	listCmd.Flags().BoolVarP(&pFlags.human, "human-readable", "h", false, "print sizes in powers of 1024 (e.g., 1023M)")
+	listCmd.Flags().BoolP("help", "", false, "help for this command")
+	emptyCmd.Flags().StringVarP(&pFlags.olderThan, "older-than", "", "", "only delete files trashed more than AGE ago, e.g. 30d, 12h or 2w")
+
+	for _, c := range []*cobra.Command{putCmd, listCmd, restoreCmd, emptyCmd} {
+		c.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+			fmt.Fprintf(os.Stderr, "trash: %v\n", err)
+			return exit.Status(1)
+		})
+		Cmd.AddCommand(c)
+	}
+}
+
+// executePut executes the trash put subcommand with given arguments and
+// returns its exit status.
+func executePut(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, "trash: missing operand")
+		return 1
+	}
+	home, err := homeCan()
+	if err != nil {
+		return exit.Fail("trash", err)
+	}
+
+	status := 0
+	now := time.Now()
+	for _, arg := range args {
+		if err := put(home, arg, now); err != nil {
+			if pFlags.force && errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			status = exit.Fail("trash", fmt.Errorf("cannot trash '%s': %v", arg, exit.Unwrap(err)))
+		}
+	}
+	return status
+}
+
+// put moves a file to the trash of its file system.
+func put(home *can, name string, now time.Time) error {
+	path, err := filepath.Abs(name)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Lstat(path); err != nil {
+		return err
+	}
+	if base := filepath.Base(name); base == "." || base == ".." || path == "/" {
+		return errors.New("refusing to trash '.', '..' or '/'")
+	}
+	if within(path, home.dir) || strings.Contains(path, "/.Trash") {
+		return errors.New("file is in a trash directory")
+	}
+
+	c, err := canFor(home, path)
+	if err != nil {
+		return err
+	}
+	it, err := c.put(path, now)
+	if err != nil {
+		return err
+	}
+	if pFlags.verbose {
+		fmt.Printf("trashed '%s' to '%s'\n", name, c.filePath(it.name))
+	}
+	return nil
+}
+
+// allItems returns the items of all trash directories, oldest first.
+func allItems() ([]*item, error) {
+	home, err := homeCan()
+	if err != nil {
+		return nil, err
+	}
+
+	all := []*item{}
+	for _, c := range allCans(home) {
+		items, err := c.items()
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, items...)
+	}
+
+	sort.SliceStable(all, func(i, j int) bool {
+		return all[i].deleted.Before(all[j].deleted)
+	})
+	return all, nil
+}
+
+// executeList executes the trash list subcommand and returns its exit
+// status. Items are shown in the columns of ls -l, with the deletion time in
+// place of the modification time and the original path as the name.
+func executeList() int {
+	items, err := allItems()
+	if err != nil {
+		return exit.Fail("trash", err)
+	}
+
+	w := listing.NewWriter(os.Stdout)
+	defer w.Flush()
+
+	for _, it := range items {
+		info, err := os.Lstat(it.can.filePath(it.name))
+		if err != nil {
+			continue
+		}
+		c, err := listing.Long(info, pFlags.human)
+		if err != nil {
+			return exit.Fail("trash", err)
+		}
+		c.Time = it.deleted.Format(listing.TimeLayout)
+		listing.Write(w, c, it.originalPath())
+	}
+	return 0
+}
+
+// executeRestore executes the trash restore subcommand with given arguments
+// and returns its exit status. Each operand names an original path, or the
+// name of a file inside the trash; the most recently trashed match is
+// restored.
+func executeRestore(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, "trash: missing operand")
+		return 1
+	}
+	items, err := allItems()
+	if err != nil {
+		return exit.Fail("trash", err)
+	}
+
+	status := 0
+	for _, arg := range args {
+		it := findItem(items, arg)
+		if it == nil {
+			status = exit.Fail("trash", fmt.Errorf("'%s': not found in trash", arg))
+			continue
+		}
+		if err := it.restore(); err != nil {
+			status = exit.Fail("trash", err)
+			continue
+		}
+		items = removeItem(items, it)
+	}
+	return status
+}
+
+// findItem returns the most recently trashed item from the given path or
+// with the given name in the trash.
+func findItem(items []*item, arg string) *item {
+	path, _ := filepath.Abs(arg)
+	for i := len(items) - 1; i >= 0; i-- {
+		if it := items[i]; it.originalPath() == path || it.name == arg {
+			return it
+		}
+	}
+	return nil
+}
+
+// removeItem returns items without it.
+func removeItem(items []*item, it *item) []*item {
+	for i := range items {
+		if items[i] == it {
+			return append(items[:i], items[i+1:]...)
+		}
+	}
+	return items
+}
+
+// executeEmpty executes the trash empty subcommand and returns its exit
+// status.
+func executeEmpty() int {
+	var age time.Duration
+	if pFlags.olderThan != "" {
+		a, err := parseAge(pFlags.olderThan)
+		if err != nil {
+			return exit.Fail("trash", err)
+		}
+		age = a
+	}
+	items, err := allItems()
+	if err != nil {
+		return exit.Fail("trash", err)
+	}
+
+	status := 0
+	cutoff := time.Now().Add(-age)
+	for _, it := range items {
+		if age > 0 && !it.deleted.Before(cutoff) {
+			continue
+		}
+		if err := it.remove(); err != nil {
+			status = exit.Fail("trash", err)
+		}
+	}
+	return status
+}
+
+// ageUnits maps the suffixes of --older-than to their duration.
+var ageUnits = map[byte]time.Duration{
+	's': time.Second,
+	'm': time.Minute,
+	'h': time.Hour,
+	'd': 24 * time.Hour,
+	'w': 7 * 24 * time.Hour,
+}
+
+// parseAge parses the argument of --older-than: a number of days, or of the
+// unit given by a suffix among s, m, h, d and w.
+func parseAge(s string) (time.Duration, error) {
+	unit, digits := 24*time.Hour, s
+	if s != "" {
+		if u, ok := ageUnits[s[len(s)-1]]; ok {
+			unit, digits = u, s[:len(s)-1]
+		}
+	}
+
+	n, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid age '%s'", s)
+	}
+	return time.Duration(n) * unit, nil
+}
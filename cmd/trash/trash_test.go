@@ -0,0 +1,107 @@
+package trash
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/skraio/unix-utilities/internal/assert"
+)
+
+func TestParseAge(t *testing.T) {
+	tests := []struct {
+		age     string
+		want    time.Duration
+		wantErr bool
+	}{
+		{age: "30d", want: 30 * 24 * time.Hour},
+		{age: "30", want: 30 * 24 * time.Hour},
+		{age: "12h", want: 12 * time.Hour},
+		{age: "2w", want: 14 * 24 * time.Hour},
+		{age: "d", wantErr: true},
+		{age: "-1d", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.age, func(t *testing.T) {
+			got, err := parseAge(tt.age)
+			assert.Equal(t, err != nil, tt.wantErr)
+			assert.Equal(t, got, tt.want)
+		})
+	}
+}
+
+func TestInfo(t *testing.T) {
+	deleted := time.Date(2004, 8, 31, 22, 32, 8, 0, time.Local)
+	it := &item{path: "/home/user/my file%.txt", deleted: deleted}
+	text := it.info()
+	assert.Equal(t, text, "[Trash Info]\nPath=/home/user/my%20file%25.txt\nDeletionDate=2004-08-31T22:32:08\n")
+
+	parsed, err := parseInfo(strings.NewReader("# comment\n" + text))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, parsed.path, it.path)
+	assert.Equal(t, parsed.deleted.Equal(deleted), true)
+
+	_, err = parseInfo(strings.NewReader("[Other]\nPath=/x\n"))
+	assert.Equal(t, err != nil, true)
+}
+
+func TestPutRestoreEmpty(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_DATA_HOME", filepath.Join(dir, "data"))
+	home, err := homeCan()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	file := filepath.Join(dir, "file")
+	write := func(content string) {
+		if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	read := func() string {
+		b, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(b)
+	}
+
+	pFlags = trashFlags{}
+	write("first")
+	assert.Equal(t, executePut([]string{file, filepath.Join(dir, "missing")}), 1)
+	write("second")
+	if err := put(home, file, time.Now().Add(time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	_, err = os.Stat(file)
+	assert.Equal(t, os.IsNotExist(err), true)
+
+	items, err := home.items()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, len(items), 2)
+
+	// The most recently trashed file comes back first.
+	assert.Equal(t, executeRestore([]string{file}), 0)
+	assert.Equal(t, read(), "second")
+	assert.Equal(t, executeRestore([]string{file}), 1)
+
+	pFlags = trashFlags{olderThan: "1d"}
+	assert.Equal(t, executeEmpty(), 0)
+	items, _ = home.items()
+	assert.Equal(t, len(items), 1)
+
+	pFlags = trashFlags{}
+	assert.Equal(t, executeEmpty(), 0)
+	items, _ = home.items()
+	assert.Equal(t, len(items), 0)
+	entries, _ := os.ReadDir(filepath.Join(home.dir, "files"))
+	assert.Equal(t, len(entries), 0)
+}
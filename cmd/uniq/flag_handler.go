@@ -0,0 +1,162 @@
+package uniq
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/skraio/unix-utilities/internal/lineio"
+)
+
+// Grouping methods accepted by --group.
+const (
+	groupNone     = ""
+	groupSeparate = "separate"
+	groupPrepend  = "prepend"
+	groupAppend   = "append"
+	groupBoth     = "both"
+)
+
+// filter holds the options selecting and comparing lines.
+type filter struct {
+	count       bool
+	repeated    bool
+	allRepeated bool
+	unique      bool
+	ignoreCase  bool
+	skipFields  int
+	skipChars   int
+	checkChars  int
+	delim       byte
+	group       string
+}
+
+// compareKey returns the part of line that is compared with its neighbours.
+func (f *filter) compareKey(line []byte) []byte {
+	i := 0
+	for n := 0; n < f.skipFields && i < len(line); n++ {
+		for i < len(line) && lineio.IsBlank(line[i]) {
+			i++
+		}
+		for i < len(line) && !lineio.IsBlank(line[i]) {
+			i++
+		}
+	}
+
+	i = min(i+f.skipChars, len(line))
+	key := line[i:]
+	if f.checkChars > 0 && len(key) > f.checkChars {
+		key = key[:f.checkChars]
+	}
+
+	return key
+}
+
+// equal reports whether two lines belong to the same group.
+func (f *filter) equal(a, b []byte) bool {
+	ka, kb := f.compareKey(a), f.compareKey(b)
+	if f.ignoreCase {
+		return bytes.EqualFold(ka, kb)
+	}
+	return bytes.Equal(ka, kb)
+}
+
+// process reads lines from r and writes the selected ones to w, keeping only
+// the previous line in memory.
+func (f *filter) process(r io.Reader, w io.Writer) error {
+	out := bufio.NewWriter(w)
+	lr := lineio.NewReaderDelim(r, f.delim)
+
+	prev := []byte{}
+	count := 0
+	groups := 0
+	for {
+		line, err := lr.ReadLine()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		line = lr.TrimDelim(line)
+
+		if count > 0 && f.equal(prev, line) {
+			count++
+			f.writeDuplicate(out, prev, line, count)
+			continue
+		}
+
+		if count > 0 {
+			f.writeGroupEnd(out, prev, count)
+		}
+		if f.group != groupNone {
+			f.writeSeparator(out, groups)
+		}
+
+		prev = append(prev[:0], line...)
+		count = 1
+		groups++
+		if f.group != groupNone {
+			f.writeLine(out, line)
+		}
+	}
+
+	if count > 0 {
+		f.writeGroupEnd(out, prev, count)
+	}
+	if groups > 0 && (f.group == groupAppend || f.group == groupBoth) {
+		out.WriteByte(f.delim)
+	}
+
+	return out.Flush()
+}
+
+// writeDuplicate handles a line equal to the previous one, which is printed
+// right away with --group and -D. The count includes line.
+func (f *filter) writeDuplicate(w *bufio.Writer, prev, line []byte, count int) {
+	switch {
+	case f.group != groupNone:
+		f.writeLine(w, line)
+	case f.allRepeated:
+		if count == 2 {
+			f.writeLine(w, prev)
+		}
+		f.writeLine(w, line)
+	}
+}
+
+// writeGroupEnd prints the representative of a finished group of count
+// equal lines if the selection flags ask for it.
+func (f *filter) writeGroupEnd(w *bufio.Writer, line []byte, count int) {
+	if f.group != groupNone || f.allRepeated {
+		return
+	}
+	if (f.repeated && count == 1) || (f.unique && count > 1) {
+		return
+	}
+
+	if f.count {
+		fmt.Fprintf(w, "%7d ", count)
+	}
+	f.writeLine(w, line)
+}
+
+// writeSeparator prints the empty line that --group puts before a group,
+// given the number of groups printed so far.
+func (f *filter) writeSeparator(w *bufio.Writer, groups int) {
+	switch f.group {
+	case groupPrepend, groupBoth:
+		w.WriteByte(f.delim)
+	case groupSeparate, groupAppend:
+		if groups > 0 {
+			w.WriteByte(f.delim)
+		}
+	}
+}
+
+// writeLine prints a line followed by the delimiter.
+func (f *filter) writeLine(w *bufio.Writer, line []byte) {
+	w.Write(line)
+	w.WriteByte(f.delim)
+}
@@ -0,0 +1,143 @@
+// Package uniq provides functionality for reporting or omitting repeated
+// adjacent lines.
+package uniq
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/skraio/unix-utilities/cmdflags"
+	"github.com/skraio/unix-utilities/internal/exit"
+	"github.com/skraio/unix-utilities/internal/fileinput"
+	"github.com/spf13/cobra"
+)
+
+// uniqFlags holds flags for uniq command.
+type uniqFlags struct {
+	count       bool
+	repeated    bool
+	allRepeated bool
+	unique      bool
+	ignoreCase  bool
+	zero        bool
+	skipFields  int
+	skipChars   int
+	checkChars  int
+	group       string
+}
+
+var pFlags uniqFlags
+
+// flags definition for uniq command.
+var flags = []cmdflags.Flag{
+	{Value: &pFlags.count, Name: "count", ShortHand: "c", DefaultValue: false, Description: "prefix lines by the number of occurrences"},
+	{Value: &pFlags.repeated, Name: "repeated", ShortHand: "d", DefaultValue: false, Description: "only print duplicate lines, one for each group"},
+	{Value: &pFlags.allRepeated, Name: "all-repeated", ShortHand: "D", DefaultValue: false, Description: "print all duplicate lines"},
+	{Value: &pFlags.unique, Name: "unique", ShortHand: "u", DefaultValue: false, Description: "only print unique lines"},
+	{Value: &pFlags.ignoreCase, Name: "ignore-case", ShortHand: "i", DefaultValue: false, Description: "ignore differences in case when comparing"},
+	{Value: &pFlags.zero, Name: "zero-terminated", ShortHand: "z", DefaultValue: false, Description: "line delimiter is NUL, not newline"},
+}
+
+// intFlags definition for uniq command.
+var intFlags = []cmdflags.IntFlag{
+	{Value: &pFlags.skipFields, Name: "skip-fields", ShortHand: "f", DefaultValue: 0, Description: "avoid comparing the first N fields"},
+	{Value: &pFlags.skipChars, Name: "skip-chars", ShortHand: "s", DefaultValue: 0, Description: "avoid comparing the first N characters"},
+	{Value: &pFlags.checkChars, Name: "check-chars", ShortHand: "w", DefaultValue: 0, Description: "compare no more than N characters in lines"},
+}
+
+// stringFlags definition for uniq command.
+var stringFlags = []cmdflags.StringFlag{
+	{Value: &pFlags.group, Name: "group", ShortHand: "", DefaultValue: "", Description: "show all items, separating groups with an empty line; METHOD={separate(default),prepend,append,both}"},
+}
+
+// Cmd represents the 'uniq' command configuration using Cobra.
+var Cmd = &cobra.Command{
+	Use:           "uniq [-f flags] [input [output]]",
+	Short:         "Report or omit repeated lines",
+	Args:          cobra.MaximumNArgs(2),
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := executeUniq(args); err != nil {
+			return exit.Status(exit.Fail("uniq", err))
+		}
+		return nil
+	},
+}
+
+// init initializes the 'uniq' command by setting up flags.
+func init() {
+	cmdflags.ParseFlags(flags, Cmd)
+	cmdflags.ParseIntFlags(intFlags, Cmd)
+	cmdflags.ParseStringFlags(stringFlags, Cmd)
+	Cmd.Flags().Lookup("group").NoOptDefVal = groupSeparate
+
+	Cmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		fmt.Fprintf(os.Stderr, "uniq: %v\n", err)
+		return exit.Status(1)
+	})
+}
+
+// executeUniq executes the uniq command with given arguments.
+func executeUniq(args []string) error {
+	f, err := newFilter()
+	if err != nil {
+		return err
+	}
+
+	args = fileinput.Args(args)
+	in, err := fileinput.Open(args[0])
+	if err != nil {
+		return err
+	}
+	defer fileinput.Close(in)
+
+	out := os.Stdout
+	if len(args) == 2 {
+		out, err = os.Create(args[1])
+		if err != nil {
+			return err
+		}
+		defer out.Close()
+	}
+
+	return f.process(in, out)
+}
+
+// newFilter builds the filter from the flags, rejecting meaningless
+// combinations.
+func newFilter() (*filter, error) {
+	f := &filter{
+		count:       pFlags.count,
+		repeated:    pFlags.repeated,
+		allRepeated: pFlags.allRepeated,
+		unique:      pFlags.unique,
+		ignoreCase:  pFlags.ignoreCase,
+		skipFields:  pFlags.skipFields,
+		skipChars:   pFlags.skipChars,
+		checkChars:  pFlags.checkChars,
+		delim:       '\n',
+		group:       pFlags.group,
+	}
+	if pFlags.zero {
+		f.delim = 0
+	}
+
+	switch f.group {
+	case groupNone, groupSeparate, groupPrepend, groupAppend, groupBoth:
+	default:
+		return nil, fmt.Errorf("invalid argument %q for --group", f.group)
+	}
+
+	if f.group != groupNone && (f.count || f.repeated || f.allRepeated || f.unique) {
+		return nil, fmt.Errorf("--group is mutually exclusive with -c/-d/-D/-u")
+	}
+	if f.count && f.allRepeated {
+		return nil, fmt.Errorf("printing all duplicated lines and repeat counts is meaningless")
+	}
+	if f.skipFields < 0 || f.skipChars < 0 || f.checkChars < 0 {
+		return nil, fmt.Errorf("invalid negative count")
+	}
+
+	return f, nil
+}
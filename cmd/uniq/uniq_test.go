@@ -0,0 +1,109 @@
+package uniq
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/skraio/unix-utilities/internal/assert"
+)
+
+func TestCompareKey(t *testing.T) {
+	tests := []struct {
+		name string
+		f    filter
+		line string
+		want string
+	}{
+		{
+			name: "Whole line",
+			f:    filter{},
+			line: "Without just one nest",
+			want: "Without just one nest",
+		},
+		{
+			name: "Skipped fields keep leading blanks",
+			f:    filter{skipFields: 2},
+			line: "Without just  one nest",
+			want: "  one nest",
+		},
+		{
+			name: "Skipped fields and characters",
+			f:    filter{skipFields: 1, skipChars: 2},
+			line: "Without just one nest",
+			want: "ust one nest",
+		},
+		{
+			name: "Limited width",
+			f:    filter{checkChars: 4},
+			line: "Without just one nest",
+			want: "With",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ans := tt.f.compareKey([]byte(tt.line))
+
+			assert.Equal(t, string(ans), tt.want)
+		})
+	}
+}
+
+func TestProcess(t *testing.T) {
+	text := "nest\nnest\nbird\nHome\nhome\ncareer\n"
+	tests := []struct {
+		name string
+		f    filter
+		want string
+	}{
+		{
+			name: "Default",
+			f:    filter{},
+			want: "nest\nbird\nHome\nhome\ncareer\n",
+		},
+		{
+			name: "Counted and case-insensitive",
+			f:    filter{count: true, ignoreCase: true},
+			want: "      2 nest\n      1 bird\n      2 Home\n      1 career\n",
+		},
+		{
+			name: "All repeated",
+			f:    filter{allRepeated: true, ignoreCase: true},
+			want: "nest\nnest\nHome\nhome\n",
+		},
+		{
+			name: "Unique only",
+			f:    filter{unique: true},
+			want: "bird\nHome\nhome\ncareer\n",
+		},
+		{
+			name: "Prepended groups",
+			f:    filter{group: groupPrepend, ignoreCase: true},
+			want: "\nnest\nnest\n\nbird\n\nHome\nhome\n\ncareer\n",
+		},
+		{
+			name: "NUL-terminated lines",
+			f:    filter{repeated: true},
+			want: "nest\x00",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := text
+			tt.f.delim = '\n'
+			if strings.HasSuffix(tt.want, "\x00") {
+				input = strings.ReplaceAll(text, "\n", "\x00")
+				tt.f.delim = 0
+			}
+
+			var out bytes.Buffer
+			if err := tt.f.process(strings.NewReader(input), &out); err != nil {
+				t.Fatal(err)
+			}
+
+			assert.Equal(t, out.String(), tt.want)
+		})
+	}
+}
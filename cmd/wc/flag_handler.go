@@ -2,35 +2,23 @@ package wc
 
 import (
 	"bufio"
-	"bytes"
 	"io"
-	"os"
+
+	"github.com/skraio/unix-utilities/internal/lineio"
 )
 
 // lineCounter counts the number of lines in a file.
-func lineCounter(f *os.File) (int, error) {
+func lineCounter(f io.ReadSeeker) (int, error) {
 	_, err := f.Seek(0, 0)
 	if err != nil {
 		return 0, err
 	}
 
-	count := 0
-	newLineChar := []byte{'\n'}
-	for {
-		buf := make([]byte, bufio.MaxScanTokenSize)
-		r, err := f.Read(buf)
-		if err == io.EOF {
-			break
-		}
-
-		count += bytes.Count(buf[:r], newLineChar)
-	}
-
-	return count, nil
+	return lineio.Count(f, '\n')
 }
 
 // wordCounter counts the number of words in a file.
-func wordCounter(f *os.File) (int, error) {
+func wordCounter(f io.ReadSeeker) (int, error) {
 	_, err := f.Seek(0, 0)
 	if err != nil {
 		return 0, err
@@ -48,7 +36,7 @@ func wordCounter(f *os.File) (int, error) {
 }
 
 // byteCounter counts the number of bytes in a file.
-func byteCounter(f *os.File) (int, error) {
+func byteCounter(f io.ReadSeeker) (int, error) {
 	_, err := f.Seek(0, 0)
 	if err != nil {
 		return 0, err
@@ -66,7 +54,7 @@ func byteCounter(f *os.File) (int, error) {
 }
 
 // longestLine finds the length of the longest line in a file.
-func longestLine(f *os.File) (int, error) {
+func longestLine(f io.ReadSeeker) (int, error) {
 	_, err := f.Seek(0, 0)
 	if err != nil {
 		return 0, err
@@ -10,6 +10,7 @@ import (
 	"text/tabwriter"
 
 	"github.com/skraio/unix-utilities/cmdflags"
+	"github.com/skraio/unix-utilities/internal/fileinput"
 	"github.com/spf13/cobra"
 )
 
@@ -25,9 +26,9 @@ var flags = []cmdflags.Flag{
 var Cmd = &cobra.Command{
 	Use:   "wc [-f flags] [file]... ",
 	Short: "Line, word, byte and longest line count",
-	Args:  cobra.MinimumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
-		if cmd.Flags().NFlag() == 0 {
+		args = fileinput.Args(args)
+		if !anySet() {
 			setDefault()
 		}
 		stats, longestLine, err := executeWc(args)
@@ -44,6 +45,17 @@ func init() {
 	cmdflags.ParseFlags(flags, Cmd)
 }
 
+// anySet reports whether any flag is set. The flag set cannot tell, as it
+// remembers the flags of earlier runs in the same process.
+func anySet() bool {
+	for _, f := range flags {
+		if *f.Value {
+			return true
+		}
+	}
+	return false
+}
+
 // setDefault sets default flags if no flag provided
 func setDefault() {
 	for i := range flags {
@@ -60,11 +72,17 @@ func executeWc(args []string) ([][]int, int, error) {
 	stats := [][]int{}
 	longestLine := -1
 	for _, filename := range args {
-		file, err := os.Open(filename)
+		f, err := fileinput.Open(filename)
+		if err != nil {
+			return nil, 0, err
+		}
+		defer fileinput.Close(f)
+
+		// Every count reads the file from the start again.
+		file, err := fileinput.Rewindable(f)
 		if err != nil {
 			return nil, 0, err
 		}
-		defer file.Close()
 
 		fileStats := []int{}
 		for _, f := range flags {
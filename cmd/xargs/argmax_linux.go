@@ -0,0 +1,5 @@
+package xargs
+
+// argMax is the most bytes the arguments and environment of a new process
+// take, with the usual stack size limit of 8 MiB.
+const argMax = 2 * 1024 * 1024
@@ -0,0 +1,7 @@
+//go:build !linux
+
+package xargs
+
+// argMax is the most bytes the arguments and environment of a new process
+// take, that of macOS and the BSDs being at least this.
+const argMax = 256 * 1024
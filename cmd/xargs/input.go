@@ -0,0 +1,165 @@
+package xargs
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/skraio/unix-utilities/internal/lineio"
+)
+
+// argReader reads the arguments of the commands from the input.
+type argReader struct {
+	in *bufio.Reader
+
+	// delim terminates each argument read as is, or is negative for
+	// arguments separated by blanks and newlines, with quotes and
+	// backslashes escaping them.
+	delim int
+
+	// lines reads each line as a single argument, only leading blanks being
+	// dropped, as -I does.
+	lines bool
+}
+
+// next returns the next argument and whether it ends an input line, or
+// io.EOF at the end of the input. With blank separated arguments, a line
+// ending in a blank goes on with the next one.
+func (r *argReader) next() (string, bool, error) {
+	if r.delim >= 0 {
+		return r.nextDelimited()
+	}
+
+	var arg []byte
+	started := false
+	var quote byte
+	for {
+		c, err := r.in.ReadByte()
+		if err == io.EOF {
+			if quote != 0 {
+				return "", false, unmatched(quote)
+			}
+			if started {
+				return string(arg), true, nil
+			}
+			return "", false, io.EOF
+		}
+		if err != nil {
+			return "", false, err
+		}
+
+		switch {
+		case quote != 0:
+			if c == '\n' {
+				return "", false, unmatched(quote)
+			}
+			if c == quote {
+				quote = 0
+			} else {
+				arg = append(arg, c)
+			}
+		case c == '\n':
+			if started {
+				return string(arg), true, nil
+			}
+		case lineio.IsBlank(c) && (!r.lines || !started):
+			if started {
+				return string(arg), r.skipBlanks(), nil
+			}
+		case c == '\'' || c == '"':
+			quote, started = c, true
+		case c == '\\':
+			c, err := r.in.ReadByte()
+			if err != nil {
+				return "", false, errors.New("backslash at end of input")
+			}
+			arg, started = append(arg, c), true
+		default:
+			arg, started = append(arg, c), true
+		}
+	}
+}
+
+// skipBlanks skips the blanks after an argument, reporting whether they end
+// the input line. Those ending it make it go on with the next line, so that
+// it is not counted as ended.
+func (r *argReader) skipBlanks() bool {
+	for {
+		c, err := r.in.ReadByte()
+		if err != nil {
+			return true
+		}
+		if c == '\n' {
+			return false
+		}
+		if !lineio.IsBlank(c) {
+			r.in.UnreadByte()
+			return false
+		}
+	}
+}
+
+// nextDelimited returns the next argument terminated by the delimiter, the
+// last one possibly unterminated.
+func (r *argReader) nextDelimited() (string, bool, error) {
+	arg, err := r.in.ReadString(byte(r.delim))
+	if err == io.EOF {
+		if arg == "" {
+			return "", false, io.EOF
+		}
+		return arg, true, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	return arg[:len(arg)-1], true, nil
+}
+
+// unmatched returns the error for a quote left open.
+func unmatched(quote byte) error {
+	name := "single"
+	if quote == '"' {
+		name = "double"
+	}
+	return fmt.Errorf("unmatched %s quote; by default quotes are special to xargs unless you use the -0 option", name)
+}
+
+// parseDelimiter parses the delimiter given with -d: a single character or
+// a backslash escape, either of a character or of its code in octal or in
+// hexadecimal with \x.
+func parseDelimiter(s string) (byte, error) {
+	if len(s) == 1 {
+		return s[0], nil
+	}
+	if len(s) > 1 && s[0] == '\\' {
+		switch s[1:] {
+		case "a":
+			return '\a', nil
+		case "b":
+			return '\b', nil
+		case "f":
+			return '\f', nil
+		case "n":
+			return '\n', nil
+		case "r":
+			return '\r', nil
+		case "t":
+			return '\t', nil
+		case "v":
+			return '\v', nil
+		case "\\":
+			return '\\', nil
+		}
+
+		code, base := s[1:], 8
+		if code[0] == 'x' {
+			code, base = code[1:], 16
+		}
+		if n, err := strconv.ParseUint(code, base, 8); err == nil {
+			return byte(n), nil
+		}
+	}
+	return 0, fmt.Errorf("invalid input delimiter specification %s: the delimiter must be either a single character or an escape sequence starting with \\", s)
+}
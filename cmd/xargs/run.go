@@ -0,0 +1,275 @@
+package xargs
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/skraio/unix-utilities/internal/exit"
+	"github.com/skraio/unix-utilities/internal/shellquote"
+)
+
+// The exit statuses of xargs, as POSIX has them.
+const (
+	statusFailed     = 123 // some command exited with a status from 1 to 125
+	statusAborted    = 124 // a command exited with status 255
+	statusSignaled   = 125 // a command was killed by a signal
+	statusCannotRun  = 126 // a command could not be run
+	statusNotFound   = 127 // a command was not found
+	statusWrongInput = 1   // the input or the options were wrong
+)
+
+// Builtin returns a function running the named utility in this process
+// with the given arguments and returning its exit status, or nil when there
+// is no such utility. The root command sets it, as this package cannot
+// import it.
+var Builtin func(name string) func(args []string) int
+
+// xargs builds command lines from the initial arguments and those read, and
+// runs them.
+type xargs struct {
+	// command is the command and its initial arguments, and size the
+	// number of bytes they take in a command line.
+	command []string
+	size    int
+
+	maxArgs  int
+	maxLines int
+	maxChars int
+
+	// replace is replaced in the initial arguments by each argument read,
+	// which then makes a command line of its own.
+	replace string
+
+	noRunIfEmpty bool
+	trace        bool
+	prompt       bool
+	tty          *bufio.Reader
+
+	// run runs a command line, in the background when several may run at
+	// once. The slots hold a value for each command running or about to.
+	run   func(argv []string)
+	slots chan struct{}
+	wg    sync.WaitGroup
+
+	mu     sync.Mutex
+	status int
+	stop   bool
+}
+
+// execute runs commands with the arguments read by r and returns the exit
+// status of xargs.
+func (x *xargs) execute(r *argReader) int {
+	var args []string
+	size, lines := x.size, 0
+	ran := false
+	flush := func() {
+		x.launch(append(x.command[:len(x.command):len(x.command)], args...))
+		args, size, lines, ran = nil, x.size, 0, true
+	}
+
+	for !x.stopped() {
+		arg, eol, err := r.next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			x.wait()
+			return x.fatal(statusWrongInput, err.Error())
+		}
+
+		if x.replace != "" {
+			x.launch(x.replaced(arg))
+			ran = true
+			continue
+		}
+		if size+len(arg)+1 > x.maxChars {
+			if len(args) == 0 {
+				x.wait()
+				return x.fatal(statusWrongInput, "argument line too long")
+			}
+			flush()
+		}
+		args = append(args, arg)
+		size += len(arg) + 1
+		if eol {
+			lines++
+		}
+		if x.maxArgs > 0 && len(args) == x.maxArgs || x.maxLines > 0 && lines == x.maxLines {
+			flush()
+		}
+	}
+
+	if len(args) > 0 || !ran && !x.noRunIfEmpty && x.replace == "" {
+		flush()
+	}
+	x.wait()
+	return x.status
+}
+
+// replaced returns the command line with replace standing for arg.
+func (x *xargs) replaced(arg string) []string {
+	argv := make([]string, len(x.command))
+	for i, a := range x.command {
+		argv[i] = strings.ReplaceAll(a, x.replace, arg)
+	}
+	return argv
+}
+
+// launch runs a command line once there is a slot to run it in, tracing
+// it and asking to confirm it if needed. The command releases the slot
+// when done.
+func (x *xargs) launch(argv []string) {
+	x.slots <- struct{}{}
+	if x.stopped() {
+		<-x.slots
+		return
+	}
+	if x.trace || x.prompt {
+		quoted := make([]string, len(argv))
+		for i, arg := range argv {
+			quoted[i] = shellquote.Quote(arg)
+		}
+		line := strings.Join(quoted, " ")
+		if !x.prompt {
+			fmt.Fprintln(os.Stderr, line)
+		} else if !x.confirm(line) {
+			<-x.slots
+			return
+		}
+	}
+	x.run(argv)
+}
+
+// confirm asks on the terminal whether to run a command line.
+func (x *xargs) confirm(line string) bool {
+	if x.tty == nil {
+		tty, err := os.Open("/dev/tty")
+		if err != nil {
+			x.fatal(statusWrongInput, "failed to open /dev/tty for reading")
+			return false
+		}
+		x.tty = bufio.NewReader(tty)
+	}
+	fmt.Fprintf(os.Stderr, "%s ?...", line)
+	answer, _ := x.tty.ReadString('\n')
+	return strings.HasPrefix(answer, "y") || strings.HasPrefix(answer, "Y")
+}
+
+// runCommand runs a command line as a process. The utilities other than
+// xargs run in this process instead when commands run one at a time, and
+// else in processes of this executable. Commands read their input from the
+// null device, leaving that of xargs alone.
+func (x *xargs) runCommand(argv []string) {
+	name := argv[0]
+	c := exec.Command(name, argv[1:]...)
+	if run := builtin(name); run != nil {
+		if cap(x.slots) == 1 {
+			status := runBuiltin(run, argv[1:])
+			<-x.slots
+			x.exited(name, status)
+			return
+		}
+		// Run under the name of the utility, this executable runs it.
+		if exe, err := os.Executable(); err == nil {
+			c.Path, c.Err = exe, nil
+		}
+	}
+	c.Stdout, c.Stderr = os.Stdout, os.Stderr
+	if err := c.Start(); err != nil {
+		<-x.slots
+		x.failed(name, err)
+		return
+	}
+	x.wg.Add(1)
+	go func() {
+		defer x.wg.Done()
+		err := c.Wait()
+		<-x.slots
+		x.failed(name, err)
+	}()
+}
+
+// builtin returns the function running the named utility, or nil when it
+// must run as a process.
+func builtin(name string) func(args []string) int {
+	if Builtin == nil || name == "xargs" {
+		return nil
+	}
+	return Builtin(name)
+}
+
+// runBuiltin runs a utility with its standard input set to the null
+// device.
+func runBuiltin(run func(args []string) int, args []string) int {
+	if null, err := os.Open(os.DevNull); err == nil {
+		defer null.Close()
+		stdin := os.Stdin
+		os.Stdin = null
+		defer func() { os.Stdin = stdin }()
+	}
+	return run(args)
+}
+
+// failed records how a command run as a process ended.
+func (x *xargs) failed(name string, err error) {
+	var ee *exec.ExitError
+	switch {
+	case err == nil:
+	case errors.As(err, &ee):
+		if ws, ok := ee.Sys().(syscall.WaitStatus); ok && ws.Signaled() {
+			x.fatal(statusSignaled, fmt.Sprintf("%s: terminated by signal %d", name, ws.Signal()))
+			return
+		}
+		x.exited(name, ee.ExitCode())
+	case errors.Is(err, exec.ErrNotFound), errors.Is(err, fs.ErrNotExist):
+		x.fatal(statusNotFound, fmt.Sprintf("%s: %v", name, syscall.ENOENT))
+	default:
+		x.fatal(statusCannotRun, fmt.Sprintf("%s: %v", name, exit.Unwrap(err)))
+	}
+}
+
+// exited records the exit status of a command. Status 255 stops xargs.
+func (x *xargs) exited(name string, status int) {
+	switch {
+	case status == 255:
+		x.fatal(statusAborted, fmt.Sprintf("%s: exited with status 255; aborting", name))
+	case status != 0:
+		x.mu.Lock()
+		if !x.stop {
+			x.status = statusFailed
+		}
+		x.mu.Unlock()
+	}
+}
+
+// fatal prints msg and stops running commands, with the given exit status
+// unless another stopped them first. It returns the final exit status.
+func (x *xargs) fatal(status int, msg string) int {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	fmt.Fprintf(os.Stderr, "xargs: %s\n", msg)
+	if !x.stop {
+		x.stop, x.status = true, status
+	}
+	return x.status
+}
+
+// stopped reports whether no more commands are to run.
+func (x *xargs) stopped() bool {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	return x.stop
+}
+
+// wait waits for the commands still running.
+func (x *xargs) wait() {
+	x.wg.Wait()
+}
@@ -0,0 +1,166 @@
+// Package xargs provides functionality for building and running command
+// lines from arguments read from the standard input, several at a time if
+// asked.
+package xargs
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+
+	"github.com/skraio/unix-utilities/cmdflags"
+	"github.com/skraio/unix-utilities/internal/exit"
+	"github.com/spf13/cobra"
+)
+
+// defaultMaxChars is the size of command lines unless -s gives another.
+const defaultMaxChars = 128 * 1024
+
+// headroom is left out of argMax for the environment of the commands to
+// grow.
+const headroom = 2048
+
+// xargsFlags holds flags for xargs command.
+type xargsFlags struct {
+	null         bool
+	noRunIfEmpty bool
+	verbose      bool
+	interactive  bool
+	delimiter    string
+	replace      string
+	maxArgs      int
+	maxLines     int
+	maxChars     int
+	maxProcs     int
+}
+
+var pFlags xargsFlags
+
+// flags definition for xargs command.
+var flags = []cmdflags.Flag{
+	{Value: &pFlags.null, Name: "null", ShortHand: "0", DefaultValue: false, Description: "items are separated by a null, not whitespace; disables quote and backslash processing"},
+	{Value: &pFlags.noRunIfEmpty, Name: "no-run-if-empty", ShortHand: "r", DefaultValue: false, Description: "if there are no arguments, then do not run COMMAND"},
+	{Value: &pFlags.verbose, Name: "verbose", ShortHand: "t", DefaultValue: false, Description: "print commands before executing them"},
+	{Value: &pFlags.interactive, Name: "interactive", ShortHand: "p", DefaultValue: false, Description: "prompt before running commands"},
+}
+
+// stringFlags definition for xargs command.
+var stringFlags = []cmdflags.StringFlag{
+	{Value: &pFlags.delimiter, Name: "delimiter", ShortHand: "d", DefaultValue: "", Description: "items in input stream are separated by CHARACTER, not by whitespace"},
+	{Value: &pFlags.replace, Name: "replace", ShortHand: "I", DefaultValue: "", Description: "replace R in INITIAL-ARGS with names read from standard input"},
+}
+
+// intFlags definition for xargs command.
+var intFlags = []cmdflags.IntFlag{
+	{Value: &pFlags.maxArgs, Name: "max-args", ShortHand: "n", DefaultValue: 0, Description: "use at most MAX-ARGS arguments per command line"},
+	{Value: &pFlags.maxLines, Name: "max-lines", ShortHand: "L", DefaultValue: 0, Description: "use at most MAX-LINES non-blank input lines per command line"},
+	{Value: &pFlags.maxChars, Name: "max-chars", ShortHand: "s", DefaultValue: 0, Description: "limit length of command line to MAX-CHARS"},
+	{Value: &pFlags.maxProcs, Name: "max-procs", ShortHand: "P", DefaultValue: 1, Description: "run at most MAX-PROCS processes at a time"},
+}
+
+// Cmd represents the 'xargs' command configuration using Cobra.
+var Cmd = &cobra.Command{
+	Use:           "xargs [-f flags] [command [initial-arguments]]",
+	Short:         "Build and execute command lines from standard input",
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return exit.Status(executeXargs(args, cmd.Flags().Changed))
+	},
+}
+
+// init initializes the 'xargs' command by setting up flags.
+func init() {
+	cmdflags.ParseFlags(flags, Cmd)
+	cmdflags.ParseStringFlags(stringFlags, Cmd)
+	cmdflags.ParseIntFlags(intFlags, Cmd)
+
+	Cmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		fmt.Fprintf(os.Stderr, "xargs: %v\n", err)
+		return exit.Status(statusWrongInput)
+	})
+}
+
+// executeXargs executes the xargs command with given arguments and returns
+// its exit status. Changed reports the flags given.
+func executeXargs(args []string, changed func(name string) bool) int {
+	x, err := newXargs(args, changed)
+	if err != nil {
+		return exit.Fail("xargs", err)
+	}
+
+	r := &argReader{in: bufio.NewReader(os.Stdin), delim: -1, lines: x.replace != ""}
+	switch {
+	case pFlags.null:
+		r.delim = 0
+	case changed("delimiter"):
+		delim, err := parseDelimiter(pFlags.delimiter)
+		if err != nil {
+			return exit.Fail("xargs", err)
+		}
+		r.delim = int(delim)
+	}
+	return x.execute(r)
+}
+
+// newXargs builds the xargs runner from the flags and the command line.
+func newXargs(args []string, changed func(name string) bool) (*xargs, error) {
+	x := &xargs{
+		command:      args,
+		maxArgs:      pFlags.maxArgs,
+		maxLines:     pFlags.maxLines,
+		replace:      pFlags.replace,
+		noRunIfEmpty: pFlags.noRunIfEmpty,
+		trace:        pFlags.verbose,
+		prompt:       pFlags.interactive,
+	}
+	if len(x.command) == 0 {
+		x.command = []string{"echo"}
+	}
+	for _, arg := range x.command {
+		x.size += len(arg) + 1
+	}
+	x.run = x.runCommand
+
+	for _, f := range []struct {
+		name      string
+		shortHand string
+		value     int
+		min       int
+	}{
+		{"max-args", "n", pFlags.maxArgs, 1},
+		{"max-lines", "L", pFlags.maxLines, 1},
+		{"max-chars", "s", pFlags.maxChars, 1},
+		{"max-procs", "P", pFlags.maxProcs, 0},
+	} {
+		if changed(f.name) && f.value < f.min {
+			return nil, fmt.Errorf("value %d for -%s option should be >= %d", f.value, f.shortHand, f.min)
+		}
+	}
+
+	// The command lines share argMax with the environment.
+	limit := argMax - headroom
+	for _, env := range os.Environ() {
+		limit -= len(env) + 1
+	}
+	x.maxChars = min(defaultMaxChars, limit)
+	if changed("max-chars") {
+		x.maxChars = pFlags.maxChars
+		if x.maxChars > limit {
+			fmt.Fprintf(os.Stderr, "xargs: value for -s option should be <= %d\n", limit)
+			x.maxChars = limit
+		}
+	}
+	if x.size > x.maxChars {
+		return nil, errors.New("cannot fit single argument within argument list size limit")
+	}
+
+	procs := pFlags.maxProcs
+	if procs == 0 {
+		procs = math.MaxInt32
+	}
+	x.slots = make(chan struct{}, procs)
+	return x, nil
+}
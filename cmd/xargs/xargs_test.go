@@ -0,0 +1,152 @@
+package xargs
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/skraio/unix-utilities/internal/assert"
+)
+
+// readAll returns the arguments read by r, each followed by | when it ends
+// a line, or the error ending them.
+func readAll(r *argReader) ([]string, error) {
+	var args []string
+	for {
+		arg, eol, err := r.next()
+		if err == io.EOF {
+			return args, nil
+		}
+		if err != nil {
+			return args, err
+		}
+		if eol {
+			arg += "|"
+		}
+		args = append(args, arg)
+	}
+}
+
+func TestArgReader(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		delim int
+		lines bool
+		want  []string
+		err   string
+	}{
+		{name: "Blanks", input: "a  b\tc\n\nd\n", delim: -1, want: []string{"a", "b", "c|", "d|"}},
+		{name: "Quotes and backslashes", input: `'a b' "c d" e\ f ''` + "\n", delim: -1, want: []string{"a b", "c d", "e f", "|"}},
+		{name: "Trailing blank", input: "a \nb\nc", delim: -1, want: []string{"a", "b|", "c|"}},
+		{name: "Unmatched quote", input: "a 'b\n", delim: -1, want: []string{"a"}, err: "unmatched single quote; by default quotes are special to xargs unless you use the -0 option"},
+		{name: "Lines", input: "  a b \n\nc\n", delim: -1, lines: true, want: []string{"a b |", "c|"}},
+		{name: "Delimiter", input: "a b\x00\x00c", delim: 0, want: []string{"a b|", "|", "c|"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &argReader{in: bufio.NewReader(strings.NewReader(tt.input)), delim: tt.delim, lines: tt.lines}
+			args, err := readAll(r)
+			assert.EqualStr(t, args, tt.want)
+			if tt.err != "" {
+				assert.Equal(t, err.Error(), tt.err)
+			}
+		})
+	}
+}
+
+func TestParseDelimiter(t *testing.T) {
+	for arg, want := range map[string]byte{",": ',', `\n`: '\n', `\\`: '\\', `\x41`: 'A', `\0`: 0, `\101`: 'A'} {
+		got, err := parseDelimiter(arg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, got, want)
+	}
+
+	_, err := parseDelimiter("ab")
+	assert.Equal(t, err.Error(), `invalid input delimiter specification ab: the delimiter must be either a single character or an escape sequence starting with \`)
+}
+
+func TestExecute(t *testing.T) {
+	tests := []struct {
+		name         string
+		input        string
+		maxArgs      int
+		maxLines     int
+		maxChars     int
+		replace      string
+		noRunIfEmpty bool
+		want         []string
+	}{
+		{name: "All at once", input: "a b\nc\n", want: []string{"echo a b c"}},
+		{name: "Arguments", input: "a b\nc\n", maxArgs: 2, want: []string{"echo a b", "echo c"}},
+		{name: "Lines", input: "a b\nc\nd e\n", maxLines: 2, want: []string{"echo a b c", "echo d e"}},
+		{name: "Characters", input: "ab cd ef\n", maxChars: 11, want: []string{"echo ab cd", "echo ef"}},
+		{name: "Replace", input: "a\nb c\n", replace: "{}", want: []string{"echo [a] a", "echo [b c] b c"}},
+		{name: "Empty input", input: "", want: []string{"echo"}},
+		{name: "No run if empty", input: "", noRunIfEmpty: true, want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			x := &xargs{
+				command:      []string{"echo"},
+				size:         5,
+				maxArgs:      tt.maxArgs,
+				maxLines:     tt.maxLines,
+				maxChars:     defaultMaxChars,
+				replace:      tt.replace,
+				noRunIfEmpty: tt.noRunIfEmpty,
+				slots:        make(chan struct{}, 1),
+			}
+			if tt.maxChars > 0 {
+				x.maxChars = tt.maxChars
+			}
+			if tt.replace != "" {
+				x.command = []string{"echo", "[{}]", "{}"}
+			}
+			var ran []string
+			x.run = func(argv []string) {
+				ran = append(ran, strings.Join(argv, " "))
+				<-x.slots
+			}
+
+			r := &argReader{in: bufio.NewReader(strings.NewReader(tt.input)), delim: -1, lines: tt.replace != ""}
+			assert.Equal(t, x.execute(r), 0)
+			assert.EqualStr(t, ran, tt.want)
+		})
+	}
+}
+
+func TestExitStatus(t *testing.T) {
+	tests := []struct {
+		name     string
+		statuses []int
+		want     int
+		ran      int
+	}{
+		{name: "Success", statuses: []int{0, 0}, want: 0, ran: 2},
+		{name: "Failure", statuses: []int{1, 0}, want: statusFailed, ran: 2},
+		{name: "Abort", statuses: []int{2, 255, 0}, want: statusAborted, ran: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			x := &xargs{command: []string{"cmd"}, size: 4, maxArgs: 1, maxChars: defaultMaxChars, slots: make(chan struct{}, 1)}
+			ran := 0
+			x.run = func(argv []string) {
+				x.exited(argv[0], tt.statuses[ran])
+				ran++
+				<-x.slots
+			}
+
+			input := strings.Repeat("a\n", len(tt.statuses))
+			r := &argReader{in: bufio.NewReader(strings.NewReader(input)), delim: -1}
+			assert.Equal(t, x.execute(r), tt.want)
+			assert.Equal(t, ran, tt.ran)
+		})
+	}
+}
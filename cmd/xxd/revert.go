@@ -0,0 +1,166 @@
+package xxd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/skraio/unix-utilities/internal/exit"
+)
+
+// errSeekBack is the error for a dump going back to an offset already
+// written to an output that cannot seek.
+var errSeekBack = errors.New("sorry, cannot seek backwards")
+
+// patcher writes bytes at given offsets of a file, seeking to them when the
+// file allows it and filling gaps with zero bytes otherwise.
+type patcher struct {
+	file     *os.File
+	w        *bufio.Writer
+	seekable bool
+	offset   int64
+}
+
+// newPatcher returns a patcher writing to file from its start.
+func newPatcher(file *os.File) *patcher {
+	_, err := file.Seek(0, io.SeekStart)
+	return &patcher{file: file, w: bufio.NewWriter(file), seekable: err == nil}
+}
+
+// writeAt writes b at offset.
+func (p *patcher) writeAt(b byte, offset int64) error {
+	switch {
+	case offset == p.offset:
+	case p.seekable:
+		if err := p.w.Flush(); err != nil {
+			return err
+		}
+		if _, err := p.file.Seek(offset, io.SeekStart); err != nil {
+			return err
+		}
+	case offset < p.offset:
+		return errSeekBack
+	default:
+		for ; p.offset < offset; p.offset++ {
+			if err := p.w.WriteByte(0); err != nil {
+				return err
+			}
+		}
+	}
+
+	p.offset = offset + 1
+	return p.w.WriteByte(b)
+}
+
+// hexValue returns the value of a hexadecimal digit, or -1 for other
+// characters.
+func hexValue(c byte) int {
+	switch {
+	case c >= '0' && c <= '9':
+		return int(c - '0')
+	case c >= 'a' && c <= 'f':
+		return int(c-'a') + 10
+	case c >= 'A' && c <= 'F':
+		return int(c-'A') + 10
+	}
+	return -1
+}
+
+// revert writes the bytes of the dump read from in to out, at the offsets
+// of the dump plus base, and returns the exit status. Each line of a
+// normal dump has an offset, then at most columns bytes in hexadecimal,
+// ended by two blanks; plain dumps are hexadecimal digits only. Whatever
+// else a line holds is ignored.
+func revert(in io.Reader, out *os.File, columns int, plain bool, base int64) int {
+	r := bufio.NewReader(in)
+	p := newPatcher(out)
+
+	// n1, n2 and n3 are the values of the last three characters, or -1 for
+	// characters that are not hexadecimal digits. column is the number of
+	// bytes read from the line so far, or columns while reading its offset.
+	n1, n2, n3 := -1, -1, -1
+	column := columns
+	ignore := true
+	offset := int64(0)
+	if plain {
+		column = 0
+	}
+
+	for {
+		c, err := r.ReadByte()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "xxd: %v\n", exit.Unwrap(err))
+			return statusInput
+		}
+		if c == '\r' || (plain && (c == ' ' || c == '\n' || c == '\t')) {
+			continue
+		}
+
+		n3, n2, n1 = n2, n1, hexValue(c)
+		if n1 < 0 && ignore {
+			continue
+		}
+		ignore = false
+
+		if !plain && column >= columns {
+			if n1 < 0 {
+				column = 0
+			} else {
+				offset = offset<<4 | int64(n1)
+			}
+			continue
+		}
+
+		switch {
+		case n2 >= 0 && n1 >= 0:
+			if err := p.writeAt(byte(n2<<4|n1), base+offset); err != nil {
+				p.w.Flush()
+				return revertError(err)
+			}
+			offset++
+			n1 = -1
+			if column++; !plain && column >= columns {
+				c = skipLine(r)
+			}
+		case n1 < 0 && n2 < 0 && n3 < 0:
+			c = skipLine(r)
+		}
+
+		if c == '\n' {
+			if !plain {
+				offset = 0
+			}
+			column = columns
+			ignore = true
+		}
+	}
+
+	if err := p.w.Flush(); err != nil {
+		return revertError(err)
+	}
+	return statusOK
+}
+
+// skipLine discards the rest of the line read by r and returns its
+// newline, or 0 at the end of the input.
+func skipLine(r *bufio.Reader) byte {
+	if _, err := r.ReadString('\n'); err != nil {
+		return 0
+	}
+	return '\n'
+}
+
+// revertError prints an error of writing the output and returns the exit
+// status for it.
+func revertError(err error) int {
+	fmt.Fprintf(os.Stderr, "xxd: %v\n", exit.Unwrap(err))
+	if err == errSeekBack {
+		return statusRevert
+	}
+	return statusOutput
+}
@@ -0,0 +1,275 @@
+// Package xxd provides functionality for making hex dumps of files and for
+// turning such dumps back into the bytes they show.
+package xxd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/skraio/unix-utilities/cmdflags"
+	"github.com/skraio/unix-utilities/internal/dump"
+	"github.com/skraio/unix-utilities/internal/exit"
+	"github.com/skraio/unix-utilities/internal/fileinput"
+	"github.com/spf13/cobra"
+)
+
+// Exit statuses of the xxd command.
+const (
+	statusOK     = 0
+	statusUsage  = 1
+	statusInput  = 2
+	statusOutput = 3
+	statusSeek   = 4
+	statusRevert = 5
+)
+
+// maxColumns is the largest number of bytes on a line, except for plain
+// dumps.
+const maxColumns = 256
+
+// xxdFlags holds flags for xxd command.
+type xxdFlags struct {
+	autoskip bool
+	bits     bool
+	plain    bool
+	revert   bool
+	upper    bool
+	columns  int
+	group    int
+	length   string
+	seek     string
+}
+
+var pFlags xxdFlags
+
+// flags definition for xxd command.
+var flags = []cmdflags.Flag{
+	{Value: &pFlags.autoskip, Name: "autoskip", ShortHand: "a", DefaultValue: false, Description: "toggle autoskip: a single '*' replaces nul-lines"},
+	{Value: &pFlags.bits, Name: "bits", ShortHand: "b", DefaultValue: false, Description: "binary digit dump; default 6 octets per line"},
+	{Value: &pFlags.plain, Name: "plain", ShortHand: "p", DefaultValue: false, Description: "output in plain hexdump style, 30 octets per line"},
+	{Value: &pFlags.revert, Name: "revert", ShortHand: "r", DefaultValue: false, Description: "reverse operation: convert (or patch) hexdump into binary"},
+	{Value: &pFlags.upper, Name: "uppercase", ShortHand: "u", DefaultValue: false, Description: "use upper case hex letters"},
+}
+
+// intFlags definition for xxd command.
+var intFlags = []cmdflags.IntFlag{
+	{Value: &pFlags.columns, Name: "cols", ShortHand: "c", DefaultValue: 0, Description: "format octets per line; default 16, 6 with -b, 30 with -p"},
+	{Value: &pFlags.group, Name: "groupsize", ShortHand: "g", DefaultValue: -1, Description: "number of octets per group in normal output; default 2, 1 with -b; 0 for no grouping"},
+}
+
+// stringFlags definition for xxd command.
+var stringFlags = []cmdflags.StringFlag{
+	{Value: &pFlags.length, Name: "len", ShortHand: "l", DefaultValue: "", Description: "stop after len octets"},
+	{Value: &pFlags.seek, Name: "seek", ShortHand: "s", DefaultValue: "", Description: "start at seek bytes offset in the input, or from its end when negative; with -r, add it to the offsets of the dump"},
+}
+
+// Cmd represents the 'xxd' command configuration using Cobra.
+var Cmd = &cobra.Command{
+	Use:           "xxd [-f flags] [infile [outfile]]",
+	Short:         "Make a hex dump or do the reverse",
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return exit.Status(executeXxd(args))
+	},
+}
+
+// init initializes the 'xxd' command by setting up flags.
+func init() {
+	cmdflags.ParseFlags(flags, Cmd)
+	cmdflags.ParseIntFlags(intFlags, Cmd)
+	cmdflags.ParseStringFlags(stringFlags, Cmd)
+
+	Cmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		fmt.Fprintf(os.Stderr, "xxd: %v\n", err)
+		return exit.Status(statusUsage)
+	})
+}
+
+// executeXxd executes the xxd command with given arguments and returns its
+// exit status.
+func executeXxd(args []string) int {
+	if len(args) > 2 {
+		fmt.Fprintf(os.Stderr, "xxd: extra operand '%s'\n", args[2])
+		return statusUsage
+	}
+	f, err := newFormat()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "xxd: %v\n", err)
+		return statusUsage
+	}
+	seek, fromEnd, err := parseSeek(pFlags.seek)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "xxd: %v\n", err)
+		return statusUsage
+	}
+	length, err := parseNumber(pFlags.length)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "xxd: %v\n", err)
+		return statusUsage
+	}
+
+	inName := fileinput.Args(args)[0]
+	in, err := fileinput.Open(inName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "xxd: %s: %v\n", inName, exit.Unwrap(err))
+		return statusInput
+	}
+	defer fileinput.Close(in)
+
+	out := os.Stdout
+	if len(args) == 2 && args[1] != fileinput.Stdin {
+		// Reverting patches the output file rather than replacing it.
+		mode := os.O_WRONLY | os.O_CREATE
+		if !pFlags.revert {
+			mode |= os.O_TRUNC
+		}
+		out, err = os.OpenFile(args[1], mode, 0666)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "xxd: %s: %v\n", args[1], exit.Unwrap(err))
+			return statusOutput
+		}
+		defer out.Close()
+	}
+
+	if pFlags.revert {
+		if pFlags.bits {
+			fmt.Fprintln(os.Stderr, "xxd: sorry, cannot revert this type")
+			return statusUsage
+		}
+		return revert(in, out, f.columns, pFlags.plain, seek)
+	}
+
+	offset := seek
+	if fromEnd {
+		offset, err = in.Seek(-seek, io.SeekEnd)
+	} else {
+		_, err = dump.Skip(in, seek)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "xxd: sorry, cannot seek")
+		return statusSeek
+	}
+
+	var r io.Reader = in
+	if pFlags.length != "" {
+		r = io.LimitReader(in, length)
+	}
+	d := dump.Dumper{Width: f.columns, Line: f.line}
+	if pFlags.autoskip && !pFlags.plain {
+		d.Squeeze = dump.Zeros
+	}
+	if _, err := d.Dump(out, r, offset); err != nil {
+		fmt.Fprintf(os.Stderr, "xxd: %v\n", exit.Unwrap(err))
+		return statusInput
+	}
+	return statusOK
+}
+
+// format is the format of the lines of a dump.
+type format struct {
+	columns int
+	group   int
+	bits    bool
+	plain   bool
+	upper   bool
+}
+
+// newFormat returns the format chosen by the flags.
+func newFormat() (*format, error) {
+	f := &format{columns: 16, group: 2, bits: pFlags.bits, plain: pFlags.plain, upper: pFlags.upper}
+	switch {
+	case f.plain:
+		f.columns = 30
+	case f.bits:
+		f.columns, f.group = 6, 1
+	}
+
+	if pFlags.columns < 0 || (!f.plain && pFlags.columns > maxColumns) {
+		return nil, fmt.Errorf("invalid number of columns (max. %d).", maxColumns)
+	}
+	if pFlags.columns > 0 {
+		f.columns = pFlags.columns
+	}
+	if pFlags.group >= 0 {
+		f.group = pFlags.group
+	}
+	if f.group == 0 || f.group > f.columns {
+		f.group = f.columns
+	}
+	return f, nil
+}
+
+// line writes the line of data at offset: the offset, the bytes in groups,
+// and their printable characters. Plain dumps only show the bytes.
+func (f *format) line(w *bufio.Writer, offset int64, data []byte) {
+	if f.plain {
+		w.WriteString(f.hex(data))
+		w.WriteByte('\n')
+		return
+	}
+
+	fmt.Fprintf(w, "%08x:", offset)
+	for i := 0; i < f.columns; i++ {
+		if i%f.group == 0 {
+			w.WriteByte(' ')
+		}
+		switch {
+		case i >= len(data) && f.bits:
+			w.WriteString("        ")
+		case i >= len(data):
+			w.WriteString("  ")
+		case f.bits:
+			fmt.Fprintf(w, "%08b", data[i])
+		default:
+			w.WriteString(f.hex(data[i : i+1]))
+		}
+	}
+
+	w.WriteString("  ")
+	for _, b := range data {
+		w.WriteByte(dump.Printable(b))
+	}
+	w.WriteByte('\n')
+}
+
+// hex returns data in hexadecimal.
+func (f *format) hex(data []byte) string {
+	if f.upper {
+		return fmt.Sprintf("%X", data)
+	}
+	return fmt.Sprintf("%x", data)
+}
+
+// parseNumber parses a number in the notation of C: decimal, hexadecimal
+// with 0x or octal with a leading 0. It is zero when not given.
+func parseNumber(arg string) (int64, error) {
+	if arg == "" {
+		return 0, nil
+	}
+	base := 10
+	s := arg
+	switch {
+	case strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X"):
+		base, s = 16, s[2:]
+	case strings.HasPrefix(s, "0") && len(s) > 1:
+		base, s = 8, s[1:]
+	}
+	n, err := strconv.ParseInt(s, base, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid number: '%s'", arg)
+	}
+	return n, nil
+}
+
+// parseSeek parses the argument of -s: an offset, which is counted from
+// the end of the input when negative. A leading + is allowed.
+func parseSeek(arg string) (int64, bool, error) {
+	fromEnd := strings.HasPrefix(arg, "-")
+	n, err := parseNumber(strings.TrimLeft(arg, "+-"))
+	return n, fromEnd, err
+}
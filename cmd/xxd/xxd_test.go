@@ -0,0 +1,87 @@
+package xxd
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/skraio/unix-utilities/internal/assert"
+)
+
+func TestLine(t *testing.T) {
+	tests := []struct {
+		f    format
+		data string
+		want string
+	}{
+		{format{columns: 16, group: 2}, "hello world\x00\x01\xff\x80a", "00000010: 6865 6c6c 6f20 776f 726c 6400 01ff 8061  hello world....a\n"},
+		{format{columns: 16, group: 2}, "789", "00000010: 3738 39                                  789\n"},
+		{format{columns: 8, group: 3, upper: true}, "\xab\xcd\xef", "00000010: ABCDEF              ...\n"},
+		{format{columns: 3, group: 1, bits: true}, "hi", "00000010: 01101000 01101001           hi\n"},
+		{format{columns: 30, plain: true}, "hi", "6869\n"},
+	}
+
+	for _, test := range tests {
+		var out bytes.Buffer
+		w := bufio.NewWriter(&out)
+		test.f.line(w, 16, []byte(test.data))
+		w.Flush()
+		assert.Equal(t, out.String(), test.want)
+	}
+}
+
+func TestParseSeek(t *testing.T) {
+	tests := []struct {
+		arg     string
+		n       int64
+		fromEnd bool
+		ok      bool
+	}{
+		{"", 0, false, true},
+		{"16", 16, false, true},
+		{"0x10", 16, false, true},
+		{"020", 16, false, true},
+		{"+16", 16, false, true},
+		{"-16", 16, true, true},
+		{"x", 0, false, false},
+	}
+
+	for _, test := range tests {
+		n, fromEnd, err := parseSeek(test.arg)
+		assert.Equal(t, n, test.n)
+		assert.Equal(t, fromEnd, test.fromEnd)
+		assert.Equal(t, err == nil, test.ok)
+	}
+}
+
+func TestRevert(t *testing.T) {
+	tests := []struct {
+		dump  string
+		plain bool
+		base  int64
+		want  string
+	}{
+		{"00000000: 6865 6c6c 6f  hello\n", false, 0, "hello"},
+		{"00000002: 4142  AB\n00000000: 6162  ab\n", false, 0, "abAB"},
+		{"00000000: 41\n", false, 2, "\x00\x00A"},
+		{"00000000: 4142 4344 4546 4748 5051 5253 5455 5657 5859  ABCDEFGHPQRSTUVWXY\n", false, 0, "ABCDEFGHPQRSTUVW"},
+		{"4142 43\n44zz\n45\n", true, 0, "ABCDE"},
+	}
+
+	for _, test := range tests {
+		out, err := os.Create(filepath.Join(t.TempDir(), "out"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		status := revert(strings.NewReader(test.dump), out, 16, test.plain, test.base)
+		out.Close()
+		assert.Equal(t, status, statusOK)
+
+		got, err := os.ReadFile(out.Name())
+		assert.Equal(t, err, nil)
+		assert.Equal(t, string(got), test.want)
+	}
+}
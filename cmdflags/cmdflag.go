@@ -2,7 +2,7 @@
 package cmdflags
 
 import (
-	"os"
+	"io"
 
 	"github.com/spf13/cobra"
 )
@@ -25,7 +25,7 @@ type Flag struct {
 	Description string
 
 	// Handler is the function that will be executed when the flag is encountered.
-	Handler func(*os.File) (int, error)
+	Handler func(io.ReadSeeker) (int, error)
 }
 
 // ParseFlags parses the provides flags and associates them with Flag structure.
@@ -37,3 +37,61 @@ func ParseFlags(flags []Flag, cmd *cobra.Command) {
 
 	cmd.Flags().SetInterspersed(false)
 }
+
+// StringFlag represents a command-line flag that takes a string argument.
+type StringFlag struct {
+	// Value holds the argument of the flag.
+	Value *string
+
+	// Name is the full name of the flag.
+	Name string
+
+	// ShortHand is the shorthand abbreviation for the flag.
+	ShortHand string
+
+	// DefaultValue is the default value of the flag.
+	DefaultValue string
+
+	// Description provides a brief description of the flag's purpose.
+	Description string
+}
+
+// ParseStringFlags parses the provided string flags and associates them with
+// StringFlag structure.
+func ParseStringFlags(flags []StringFlag, cmd *cobra.Command) {
+	for i := range flags {
+		f := &flags[i]
+		cmd.Flags().StringVarP(f.Value, f.Name, f.ShortHand, f.DefaultValue, f.Description)
+	}
+
+	cmd.Flags().SetInterspersed(false)
+}
+
+// IntFlag represents a command-line flag that takes an integer argument.
+type IntFlag struct {
+	// Value holds the argument of the flag.
+	Value *int
+
+	// Name is the full name of the flag.
+	Name string
+
+	// ShortHand is the shorthand abbreviation for the flag.
+	ShortHand string
+
+	// DefaultValue is the default value of the flag.
+	DefaultValue int
+
+	// Description provides a brief description of the flag's purpose.
+	Description string
+}
+
+// ParseIntFlags parses the provided integer flags and associates them with
+// IntFlag structure.
+func ParseIntFlags(flags []IntFlag, cmd *cobra.Command) {
+	for i := range flags {
+		f := &flags[i]
+		cmd.Flags().IntVarP(f.Value, f.Name, f.ShortHand, f.DefaultValue, f.Description)
+	}
+
+	cmd.Flags().SetInterspersed(false)
+}
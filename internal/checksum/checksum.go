@@ -0,0 +1,169 @@
+// Package checksum computes checksums of files in parallel and reads the
+// checksum lists that md5sum and its siblings print, in the GNU format and
+// in the BSD one of --tag.
+package checksum
+
+import (
+	"encoding/hex"
+	"hash"
+	"io"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/skraio/unix-utilities/internal/fileinput"
+)
+
+// Result is the checksum of a file.
+type Result struct {
+	Name string
+	Sum  []byte
+	Size int64
+	Err  error
+}
+
+// Files hashes the named files, "-" being the standard input, on as many
+// goroutines as there are CPUs, the file at index i with newHash(i). report
+// is called with the results in the order of names.
+func Files(names []string, newHash func(i int) hash.Hash, report func(Result)) {
+	results := make([]Result, len(names))
+	done := make([]chan struct{}, len(names))
+	for i := range done {
+		done[i] = make(chan struct{})
+	}
+
+	next := make(chan int)
+	go func() {
+		for i := range names {
+			next <- i
+		}
+		close(next)
+	}()
+
+	// The standard input may be named more than once, and must then be
+	// read by one goroutine at a time.
+	var stdin sync.Mutex
+	for w := 0; w < min(runtime.NumCPU(), len(names)); w++ {
+		go func() {
+			for i := range next {
+				if names[i] == fileinput.Stdin {
+					stdin.Lock()
+					results[i] = File(names[i], newHash(i))
+					stdin.Unlock()
+				} else {
+					results[i] = File(names[i], newHash(i))
+				}
+				close(done[i])
+			}
+		}()
+	}
+
+	for i := range names {
+		<-done[i]
+		report(results[i])
+	}
+}
+
+// File hashes the named file with h.
+func File(name string, h hash.Hash) Result {
+	r := Result{Name: name}
+	f, err := fileinput.Open(name)
+	if err != nil {
+		r.Err = err
+		return r
+	}
+	defer fileinput.Close(f)
+
+	r.Size, r.Err = io.Copy(h, f)
+	if r.Err == nil {
+		r.Sum = h.Sum(nil)
+	}
+	return r
+}
+
+// EscapeName returns a file name as checksum lines hold it, with
+// backslashes, newlines and carriage returns escaped, and whether it had to
+// be escaped. Lines with escaped names start with a backslash.
+func EscapeName(name string) (string, bool) {
+	if !strings.ContainsAny(name, "\\\n\r") {
+		return name, false
+	}
+	r := strings.NewReplacer("\\", "\\\\", "\n", "\\n", "\r", "\\r")
+	return r.Replace(name), true
+}
+
+// unescapeName reverses EscapeName, failing on unknown escapes.
+func unescapeName(name string) (string, bool) {
+	var b strings.Builder
+	for i := 0; i < len(name); i++ {
+		if name[i] != '\\' {
+			b.WriteByte(name[i])
+			continue
+		}
+		if i++; i == len(name) {
+			return "", false
+		}
+		switch name[i] {
+		case '\\':
+			b.WriteByte('\\')
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		default:
+			return "", false
+		}
+	}
+	return b.String(), true
+}
+
+// Line is a line of a checksum list.
+type Line struct {
+	// Tag is the algorithm named by a BSD-style line, and empty otherwise.
+	Tag string
+
+	Name string
+	Sum  []byte
+}
+
+// ParseLine parses a line of a checksum list, either "SUM  NAME" or
+// "SUM *NAME" as printed by default, or "TAG (NAME) = SUM" as printed with
+// --tag.
+func ParseLine(s string) (Line, bool) {
+	s = strings.TrimSuffix(s, "\r")
+	escaped := strings.HasPrefix(s, "\\")
+	if escaped {
+		s = s[1:]
+	}
+
+	var l Line
+	var sum string
+	if tag, rest, ok := strings.Cut(s, " ("); ok && !strings.Contains(tag, " ") {
+		i := strings.LastIndex(rest, ") = ")
+		if i < 0 {
+			return l, false
+		}
+		l.Tag, l.Name, sum = tag, rest[:i], rest[i+4:]
+	} else {
+		i := strings.IndexByte(s, ' ')
+		if i < 0 || i+2 > len(s) || (s[i+1] != ' ' && s[i+1] != '*') {
+			return l, false
+		}
+		sum, l.Name = s[:i], s[i+2:]
+	}
+
+	if escaped {
+		name, ok := unescapeName(l.Name)
+		if !ok {
+			return l, false
+		}
+		l.Name = name
+	}
+
+	b, err := hex.DecodeString(sum)
+	if err != nil || len(b) == 0 || l.Name == "" {
+		return l, false
+	}
+	l.Sum = b
+	return l, true
+}
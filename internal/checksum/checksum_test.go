@@ -0,0 +1,128 @@
+package checksum
+
+import (
+	"bytes"
+	"encoding/hex"
+	"hash"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/skraio/unix-utilities/internal/assert"
+)
+
+func TestCRC(t *testing.T) {
+	tests := []struct {
+		data []byte
+		want uint32
+	}{
+		{data: []byte(""), want: 4294967295},
+		{data: []byte("abc"), want: 1219131554},
+		{data: make([]byte, 1000), want: 2610763910},
+	}
+
+	for _, tt := range tests {
+		h := NewCRC()
+		h.Write(tt.data)
+		assert.Equal(t, h.Sum32(), tt.want)
+	}
+}
+
+func TestBlake2b(t *testing.T) {
+	tests := []struct {
+		data []byte
+		size int
+		want string
+	}{
+		{data: []byte(""), size: 64, want: "786a02f742015903c6c6fd852552d272912f4740e15847618a86e217f71f5419d25e1031afee585313896444934eb04b903a685b1448b755d56f701afe9be2ce"},
+		{data: []byte("abc"), size: 64, want: "ba80a53f981c4d0d6a2797b69f12f6e94c212f14685ac4b74b12bb6fdbffa2d17d87c5392aab792dc252d5de4533cc9518d38aa8dbf1925ab92386edd4009923"},
+		{data: []byte("abc"), size: 32, want: "bddd813c634239723171ef3fee98579b94964e3bb1cb3e427262c8c068d52319"},
+		{data: make([]byte, 1000), size: 64, want: "1ee4e51ecab5210a518f26150e882627ec839967f19d763e1508b12cfefed14858f6a1c9d1f969bc224dc9440f5a6955277e755b9c513f9ba4421c5e50c8d787"},
+	}
+
+	for _, tt := range tests {
+		h := NewBlake2b(tt.size)
+		// Writing in uneven pieces must not change the digest.
+		h.Write(tt.data[:len(tt.data)/3])
+		h.Write(tt.data[len(tt.data)/3:])
+		assert.Equal(t, hex.EncodeToString(h.Sum(nil)), tt.want)
+	}
+}
+
+func TestFiles(t *testing.T) {
+	dir := t.TempDir()
+	names := []string{}
+	for i, data := range []string{"one", "two", "three"} {
+		name := filepath.Join(dir, data)
+		if err := os.WriteFile(name, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+		names = append(names, name)
+		if i == 1 {
+			names = append(names, filepath.Join(dir, "missing"))
+		}
+	}
+
+	got := []Result{}
+	Files(names, func(int) hash.Hash { return NewCRC() }, func(r Result) {
+		got = append(got, r)
+	})
+
+	assert.Equal(t, len(got), 4)
+	for i, r := range got {
+		assert.Equal(t, r.Name, names[i])
+	}
+	assert.Equal(t, got[2].Err != nil, true)
+	assert.Equal(t, got[3].Size, int64(5))
+}
+
+func TestEscapeName(t *testing.T) {
+	name, escaped := EscapeName("a\\b\nc")
+	assert.Equal(t, name, `a\\b\nc`)
+	assert.Equal(t, escaped, true)
+
+	name, escaped = EscapeName("plain name")
+	assert.Equal(t, name, "plain name")
+	assert.Equal(t, escaped, false)
+}
+
+func TestParseLine(t *testing.T) {
+	tests := []struct {
+		line    string
+		wantTag string
+		want    string
+		wantSum string
+		wantOK  bool
+	}{
+		{line: "764efa883dda1e11db47671c4a3bbd9e  a file", want: "a file", wantSum: "764efa883dda1e11db47671c4a3bbd9e", wantOK: true},
+		{line: "764EFA883DDA1E11DB47671C4A3BBD9E *bin", want: "bin", wantSum: "764efa883dda1e11db47671c4a3bbd9e", wantOK: true},
+		{line: "MD5 (x (1).txt) = 764efa88", wantTag: "MD5", want: "x (1).txt", wantSum: "764efa88", wantOK: true},
+		{line: `\764efa88  a\\b\nc`, want: "a\\b\nc", wantSum: "764efa88", wantOK: true},
+		{line: `\764efa88  a\qb`},
+		{line: "764efa88 a"},
+		{line: "xyz  a"},
+		{line: "764efa88  "},
+		{line: "MD5 (a) 764efa88"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			l, ok := ParseLine(tt.line)
+			assert.Equal(t, ok, tt.wantOK)
+			if ok {
+				assert.Equal(t, l.Tag, tt.wantTag)
+				assert.Equal(t, l.Name, tt.want)
+				assert.Equal(t, bytes.Equal(l.Sum, mustDecode(t, tt.wantSum)), true)
+			}
+		})
+	}
+}
+
+// mustDecode decodes a hexadecimal string.
+func mustDecode(t *testing.T, s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
@@ -0,0 +1,74 @@
+package checksum
+
+import "hash"
+
+// crcPoly is the generator polynomial of the POSIX cksum CRC.
+const crcPoly = 0x04c11db7
+
+// crcTable holds the CRC of every byte value, most significant bit first.
+var crcTable = func() [256]uint32 {
+	var t [256]uint32
+	for i := range t {
+		c := uint32(i) << 24
+		for j := 0; j < 8; j++ {
+			if c&0x80000000 != 0 {
+				c = c<<1 ^ crcPoly
+			} else {
+				c <<= 1
+			}
+		}
+		t[i] = c
+	}
+	return t
+}()
+
+// crc is the CRC of POSIX cksum, which follows the data with its length.
+type crc struct {
+	crc  uint32
+	size uint64
+}
+
+// NewCRC returns a hash computing the CRC cksum prints.
+func NewCRC() hash.Hash32 {
+	return &crc{}
+}
+
+// Size returns the number of bytes Sum returns.
+func (c *crc) Size() int { return 4 }
+
+// BlockSize returns the hash's underlying block size.
+func (c *crc) BlockSize() int { return 1 }
+
+// Reset resets the hash to its initial state.
+func (c *crc) Reset() { *c = crc{} }
+
+// Write adds more data to the running CRC.
+func (c *crc) Write(p []byte) (int, error) {
+	c.crc = update(c.crc, p)
+	c.size += uint64(len(p))
+	return len(p), nil
+}
+
+// Sum32 returns the CRC of the data written, once its length, in as few
+// bytes as needed, least significant first, is added.
+func (c *crc) Sum32() uint32 {
+	sum := c.crc
+	for n := c.size; n != 0; n >>= 8 {
+		sum = update(sum, []byte{byte(n)})
+	}
+	return ^sum
+}
+
+// Sum appends the big-endian CRC to b.
+func (c *crc) Sum(b []byte) []byte {
+	s := c.Sum32()
+	return append(b, byte(s>>24), byte(s>>16), byte(s>>8), byte(s))
+}
+
+// update adds p to a CRC.
+func update(crc uint32, p []byte) uint32 {
+	for _, b := range p {
+		crc = crc<<8 ^ crcTable[byte(crc>>24)^b]
+	}
+	return crc
+}
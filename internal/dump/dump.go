@@ -0,0 +1,144 @@
+// Package dump is the formatting core of the binary viewers od, hexdump and
+// xxd: it cuts their input into lines of bytes at known offsets, folds
+// repeated lines, and provides the columns the viewers have in common.
+package dump
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+)
+
+// Squeeze says which lines of a dump are folded into a "*" line.
+type Squeeze int
+
+const (
+	// Verbose prints every line.
+	Verbose Squeeze = iota
+
+	// Repeats replaces the lines equal to the one before them with a
+	// single "*" line, as od and hexdump do.
+	Repeats
+
+	// Zeros replaces runs of lines of zero bytes with a "*" line, keeping
+	// the first line of a run and the last line of the input, as xxd does.
+	// Runs of two lines, or three at the end of the input, are printed in
+	// full.
+	Zeros
+)
+
+// Dumper writes a dump of its input, line by line.
+type Dumper struct {
+	// Width is the number of bytes of each line.
+	Width int
+
+	// Squeeze selects the lines folded into "*" lines.
+	Squeeze Squeeze
+
+	// Line writes the line of data found at offset. Only the last line of
+	// the input may be shorter than Width.
+	Line func(w *bufio.Writer, offset int64, data []byte)
+}
+
+// line is a line of input kept for later.
+type line struct {
+	offset int64
+	data   []byte
+}
+
+// Dump writes the dump of r, the first byte of which is at offset, to w.
+// It returns the offset of the end of the input.
+func (d *Dumper) Dump(w io.Writer, r io.Reader, offset int64) (int64, error) {
+	out := bufio.NewWriter(w)
+	data := make([]byte, d.Width)
+	prev := make([]byte, d.Width)
+	hasPrev, folded := false, false
+
+	// A run of zero lines is printed from its first line; its second line
+	// and its latest one are kept until the run ends.
+	run := 0
+	held := line{data: make([]byte, d.Width)}
+	last := line{data: make([]byte, d.Width)}
+
+	for {
+		n, err := io.ReadFull(r, data)
+		if n == 0 {
+			if err == io.EOF || err == io.ErrUnexpectedEOF {
+				break
+			}
+			out.Flush()
+			return offset, err
+		}
+		full := n == d.Width
+
+		switch {
+		case d.Squeeze == Repeats && full && hasPrev && bytes.Equal(data, prev):
+			if !folded {
+				out.WriteString("*\n")
+				folded = true
+			}
+		case d.Squeeze == Zeros && full && isZero(data):
+			run++
+			switch run {
+			case 1:
+				d.Line(out, offset, data)
+			case 2:
+				held.offset = offset
+				copy(held.data, data)
+			}
+			last.offset = offset
+			copy(last.data, data)
+		default:
+			switch {
+			case run == 2:
+				d.Line(out, held.offset, held.data)
+			case run > 2:
+				out.WriteString("*\n")
+			}
+			run = 0
+			d.Line(out, offset, data[:n])
+			folded = false
+		}
+
+		copy(prev, data)
+		hasPrev = full
+		offset += int64(n)
+		if err == io.ErrUnexpectedEOF {
+			break
+		}
+		if err != nil {
+			out.Flush()
+			return offset, err
+		}
+	}
+
+	switch {
+	case run == 3:
+		d.Line(out, held.offset, held.data)
+	case run > 3:
+		out.WriteString("*\n")
+	}
+	if run > 1 {
+		d.Line(out, last.offset, last.data)
+	}
+	return offset, out.Flush()
+}
+
+// isZero reports whether data is made of zero bytes only.
+func isZero(data []byte) bool {
+	for _, b := range data {
+		if b != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Printable returns b if it is a printable ASCII character, and '.'
+// otherwise, as the character columns of the dumps show it.
+func Printable(b byte) byte {
+	if b < ' ' || b > '~' {
+		return '.'
+	}
+	return b
+}
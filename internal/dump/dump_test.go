@@ -0,0 +1,78 @@
+package dump
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/skraio/unix-utilities/internal/assert"
+)
+
+// hexLine writes a line as its offset and bytes in hexadecimal.
+func hexLine(w *bufio.Writer, offset int64, data []byte) {
+	fmt.Fprintf(w, "%d %x\n", offset, data)
+}
+
+func TestDump(t *testing.T) {
+	zeros := string(make([]byte, 2))
+	tests := []struct {
+		squeeze Squeeze
+		input   string
+		want    string
+	}{
+		{Verbose, "abcde", "10 6162\n12 6364\n14 65\n"},
+		{Verbose, "", ""},
+		{Repeats, "ababab", "10 6162\n*\n"},
+		{Repeats, "abababc", "10 6162\n*\n16 63\n"},
+		{Repeats, "ababcdab", "10 6162\n*\n14 6364\n16 6162\n"},
+		{Zeros, "ab" + zeros, "10 6162\n12 0000\n"},
+		{Zeros, "ab" + zeros + zeros + "cd", "10 6162\n12 0000\n14 0000\n16 6364\n"},
+		{Zeros, "ab" + zeros + zeros + zeros + "cd", "10 6162\n12 0000\n*\n18 6364\n"},
+		{Zeros, "ab" + zeros + zeros + zeros, "10 6162\n12 0000\n14 0000\n16 0000\n"},
+		{Zeros, "ab" + zeros + zeros + zeros + zeros, "10 6162\n12 0000\n*\n18 0000\n"},
+		{Zeros, zeros + zeros + zeros + "\x00", "10 0000\n*\n16 00\n"},
+	}
+
+	for _, test := range tests {
+		var out bytes.Buffer
+		d := Dumper{Width: 2, Squeeze: test.squeeze, Line: hexLine}
+		end, err := d.Dump(&out, strings.NewReader(test.input), 10)
+		assert.Equal(t, err, nil)
+		assert.Equal(t, end, 10+int64(len(test.input)))
+		assert.Equal(t, out.String(), test.want)
+	}
+}
+
+func TestFiles(t *testing.T) {
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a")
+	b := filepath.Join(dir, "b")
+	if err := os.WriteFile(a, []byte("abc"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(b, []byte("defg"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	failed := []string{}
+	report := func(name string, err error) { failed = append(failed, name) }
+
+	f := NewFiles([]string{a, filepath.Join(dir, "missing"), b}, report)
+	assert.Equal(t, f.Skip(4), int64(0))
+	var out bytes.Buffer
+	out.ReadFrom(f)
+	assert.Equal(t, out.String(), "efg")
+	assert.EqualStr(t, failed, []string{filepath.Join(dir, "missing")})
+	assert.Equal(t, f.Opened(), true)
+
+	f = NewFiles([]string{a, b}, report)
+	assert.Equal(t, f.Skip(10), int64(3))
+
+	f = NewFiles([]string{filepath.Join(dir, "missing")}, report)
+	assert.Equal(t, f.Skip(0), int64(0))
+	assert.Equal(t, f.Opened(), false)
+}
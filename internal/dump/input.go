@@ -0,0 +1,108 @@
+package dump
+
+import (
+	"io"
+	"os"
+
+	"github.com/skraio/unix-utilities/internal/fileinput"
+)
+
+// Files reads the named operands one after the other as a single stream,
+// so that lines of a dump may span them. Operands that cannot be opened or
+// read are reported and left out.
+type Files struct {
+	names  []string
+	name   string
+	file   *os.File
+	opened bool
+	report func(name string, err error)
+}
+
+// NewFiles returns the stream of the named operands, reporting the errors
+// of each to report.
+func NewFiles(names []string, report func(name string, err error)) *Files {
+	return &Files{names: names, report: report}
+}
+
+// next opens the next operand if the current one is done, and reports
+// whether there is one left to read.
+func (f *Files) next() bool {
+	for f.file == nil {
+		if len(f.names) == 0 {
+			return false
+		}
+		f.name = f.names[0]
+		f.names = f.names[1:]
+
+		file, err := fileinput.Open(f.name)
+		if err != nil {
+			f.report(f.name, err)
+			continue
+		}
+		f.file = file
+		f.opened = true
+	}
+	return true
+}
+
+// Opened reports whether any of the operands could be opened.
+func (f *Files) Opened() bool {
+	return f.opened
+}
+
+// done closes the current operand, reporting err unless it is the end of
+// the file.
+func (f *Files) done(err error) {
+	if err != nil && err != io.EOF {
+		f.report(f.name, err)
+	}
+	fileinput.Close(f.file)
+	f.file = nil
+}
+
+// Read reads from the operands in turn.
+func (f *Files) Read(p []byte) (int, error) {
+	for f.next() {
+		n, err := f.file.Read(p)
+		if err != nil {
+			f.done(err)
+		}
+		if n > 0 {
+			return n, nil
+		}
+	}
+	return 0, io.EOF
+}
+
+// Skip discards the first n bytes of the stream and returns how many of
+// them could not be skipped for lack of input. The first operand that can
+// be opened is opened even when there is nothing to skip.
+func (f *Files) Skip(n int64) int64 {
+	for f.next() && n > 0 {
+		skipped, err := Skip(f.file, n)
+		n -= skipped
+		if n > 0 || err != nil {
+			f.done(err)
+		}
+	}
+	return n
+}
+
+// Skip discards up to n bytes of file, seeking over regular files rather
+// than reading them. It returns the number of bytes skipped.
+func Skip(file *os.File, n int64) (int64, error) {
+	if info, err := file.Stat(); err == nil && info.Mode().IsRegular() {
+		pos, err := file.Seek(0, io.SeekCurrent)
+		if err == nil {
+			n = min(n, max(info.Size()-pos, 0))
+			_, err = file.Seek(n, io.SeekCurrent)
+			return n, err
+		}
+	}
+
+	skipped, err := io.CopyN(io.Discard, file, n)
+	if err == io.EOF {
+		err = nil
+	}
+	return skipped, err
+}
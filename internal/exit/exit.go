@@ -0,0 +1,95 @@
+// Package exit provides an error that carries the exit status of a command
+// whose diagnostics have already been printed, and the helpers printing
+// them.
+package exit
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// Error reports that a command finished with a non-zero exit status.
+type Error struct {
+	Code int
+}
+
+// Error implements the error interface.
+func (e *Error) Error() string {
+	return "exit status " + strconv.Itoa(e.Code)
+}
+
+// Status returns nil for a zero exit status and an *Error otherwise.
+func Status(code int) error {
+	if code == 0 {
+		return nil
+	}
+	return &Error{Code: code}
+}
+
+// Code returns the exit status corresponding to err: 0 for nil, the code of
+// an *Error and 1 for any other error.
+func Code(err error) int {
+	if err == nil {
+		return 0
+	}
+
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Code
+	}
+	return 1
+}
+
+// Fail prints err as a diagnostic of the named command and returns the exit
+// status for errors. A path error is printed as the path and the system
+// error behind it.
+func Fail(name string, err error) int {
+	var pe *fs.PathError
+	if errors.As(err, &pe) {
+		fmt.Fprintf(os.Stderr, "%s: %s: %v\n", name, pe.Path, Unwrap(pe))
+	} else {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", name, err)
+	}
+	return 1
+}
+
+// Unwrap returns the system error behind a path or link error, for messages
+// that name the files themselves. Its text is capitalized as the C library
+// has it, such as "No such file or directory".
+func Unwrap(err error) error {
+	var pe *fs.PathError
+	var le *os.LinkError
+	switch {
+	case errors.As(err, &pe):
+		err = pe.Err
+	case errors.As(err, &le):
+		err = le.Err
+	}
+
+	var errno syscall.Errno
+	if errors.As(err, &errno) {
+		return sysError{errno}
+	}
+	return err
+}
+
+// sysError is a system error printed as the C library prints it.
+type sysError struct {
+	errno syscall.Errno
+}
+
+// Error implements the error interface.
+func (e sysError) Error() string {
+	s := e.errno.Error()
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+// Unwrap returns the system error, so that errors.Is still matches it.
+func (e sysError) Unwrap() error {
+	return e.errno
+}
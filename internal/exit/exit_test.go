@@ -0,0 +1,44 @@
+package exit
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"syscall"
+	"testing"
+
+	"github.com/skraio/unix-utilities/internal/assert"
+)
+
+func TestCode(t *testing.T) {
+	assert.Equal(t, Code(nil), 0)
+	assert.Equal(t, Code(Status(0)), 0)
+	assert.Equal(t, Code(Status(2)), 2)
+	assert.Equal(t, Code(fmt.Errorf("wrapped: %w", Status(3))), 3)
+	assert.Equal(t, Code(errors.New("other")), 1)
+}
+
+func TestUnwrap(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{name: "Path error", err: &fs.PathError{Op: "open", Path: "a", Err: fs.ErrNotExist}},
+		{name: "Link error", err: &os.LinkError{Op: "link", Old: "a", New: "b", Err: fs.ErrNotExist}},
+		{name: "Wrapped", err: fmt.Errorf("reading: %w", &fs.PathError{Op: "read", Path: "a", Err: fs.ErrNotExist})},
+		{name: "Other", err: fs.ErrNotExist},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, Unwrap(tt.err), fs.ErrNotExist)
+		})
+	}
+}
+
+func TestUnwrapErrno(t *testing.T) {
+	err := Unwrap(&fs.PathError{Op: "open", Path: "a", Err: syscall.ENOENT})
+	assert.Equal(t, err.Error(), "No such file or directory")
+	assert.Equal(t, errors.Is(err, fs.ErrNotExist), true)
+}
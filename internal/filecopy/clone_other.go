@@ -0,0 +1,13 @@
+//go:build !linux
+
+package filecopy
+
+import (
+	"errors"
+	"os"
+)
+
+// clone is not supported outside Linux.
+func clone(dst, src *os.File) error {
+	return errors.ErrUnsupported
+}
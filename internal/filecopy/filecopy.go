@@ -0,0 +1,450 @@
+// Package filecopy copies and moves files and directory trees, optionally
+// preserving their attributes, for the commands that duplicate or relocate
+// files.
+package filecopy
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"github.com/skraio/unix-utilities/internal/exit"
+	"github.com/skraio/unix-utilities/internal/fileinfo"
+)
+
+// Reflink tells whether data is shared with the source through a
+// copy-on-write clone instead of being copied.
+type Reflink int
+
+const (
+	// ReflinkNever always copies the data.
+	ReflinkNever Reflink = iota
+	// ReflinkAuto clones when the file system supports it and copies
+	// otherwise.
+	ReflinkAuto
+	// ReflinkAlways clones and fails when that is not possible.
+	ReflinkAlways
+)
+
+// Options control how files are copied.
+type Options struct {
+	// Recursive copies directories with their contents.
+	Recursive bool
+
+	// Dereference copies the files symbolic links point to instead of the
+	// links themselves.
+	Dereference bool
+
+	// Preserve keeps the mode, ownership, timestamps and extended
+	// attributes of the copied files.
+	Preserve bool
+
+	// NoClobber leaves existing destination files alone.
+	NoClobber bool
+
+	// Update replaces destination files only when they are older than
+	// their source.
+	Update bool
+
+	// Force removes destination files that cannot be opened for writing
+	// and tries again.
+	Force bool
+
+	// Reflink selects whether data is cloned.
+	Reflink Reflink
+
+	// Report is called with every error, letting the copy continue with
+	// the remaining files.
+	Report func(err error)
+
+	// Verbose, if set, is called after every file is copied or moved.
+	Verbose func(src, dst string)
+}
+
+// Targets pairs each source operand with its destination. The last operand
+// is the destination of a single source unless it is a directory, in which
+// case every source goes into it under its own base name.
+func Targets(args []string) ([][2]string, error) {
+	if len(args) < 2 {
+		return nil, errors.New("missing destination file operand")
+	}
+
+	sources, dest := args[:len(args)-1], args[len(args)-1]
+	info, err := os.Stat(dest)
+	isDir := err == nil && info.IsDir()
+	if len(sources) > 1 && !isDir {
+		return nil, fmt.Errorf("target '%s' is not a directory", dest)
+	}
+
+	pairs := [][2]string{}
+	for _, src := range sources {
+		if !isDir {
+			pairs = append(pairs, [2]string{src, dest})
+			continue
+		}
+		pairs = append(pairs, [2]string{src, filepath.Join(dest, filepath.Base(src))})
+	}
+	return pairs, nil
+}
+
+// Copy copies src to dst and reports whether it succeeded for every file.
+func Copy(src, dst string, opts Options) bool {
+	c := &copier{opts: opts, ok: true}
+	c.copy(src, dst, true)
+	return c.ok
+}
+
+// Move renames src to dst, falling back to copying and removing src when
+// they are on different file systems, and reports whether it succeeded.
+func Move(src, dst string, opts Options) bool {
+	c := &copier{opts: opts, ok: true}
+
+	info, err := os.Lstat(src)
+	if err != nil {
+		return c.fail("cannot stat", src, err)
+	}
+	if info.IsDir() && isWithin(dst, src) {
+		c.report(fmt.Errorf("cannot move '%s' to a subdirectory of itself, '%s'", src, dst))
+		return false
+	}
+	if skip, ok := c.checkDest(src, info, dst); skip || !ok {
+		return ok
+	}
+
+	err = os.Rename(src, dst)
+	if err == nil {
+		c.verbose(src, dst)
+		return true
+	}
+	if !errors.Is(err, syscall.EXDEV) {
+		return c.fail("cannot move '"+src+"' to", dst, err)
+	}
+
+	c.opts.Recursive, c.opts.Preserve, c.opts.Dereference = true, true, false
+	c.opts.Verbose = nil
+	if c.copy(src, dst, false); !c.ok {
+		return false
+	}
+	if err := os.RemoveAll(src); err != nil {
+		return c.fail("cannot remove", src, err)
+	}
+	if opts.Verbose != nil {
+		opts.Verbose(src, dst)
+	}
+	return true
+}
+
+// copier holds the state of a copy.
+type copier struct {
+	opts Options
+	ok   bool
+}
+
+// report passes an error to the caller and marks the copy as failed.
+func (c *copier) report(err error) {
+	c.ok = false
+	if c.opts.Report != nil {
+		c.opts.Report(err)
+	}
+}
+
+// fail reports an error of an operation on path and returns false.
+func (c *copier) fail(op, path string, err error) bool {
+	c.report(fmt.Errorf("%s '%s': %v", op, path, exit.Unwrap(err)))
+	return false
+}
+
+// verbose announces a copied file.
+func (c *copier) verbose(src, dst string) {
+	if c.opts.Verbose != nil {
+		c.opts.Verbose(src, dst)
+	}
+}
+
+// isWithin reports whether path is dir or lies inside it.
+func isWithin(path, dir string) bool {
+	absPath, err1 := filepath.Abs(path)
+	absDir, err2 := filepath.Abs(dir)
+	if err1 != nil || err2 != nil {
+		return false
+	}
+	return absPath == absDir || strings.HasPrefix(absPath, absDir+string(filepath.Separator))
+}
+
+// checkDest examines an existing destination, returning skip when the
+// options say to leave it alone and ok false when it cannot be replaced.
+func (c *copier) checkDest(src string, info fs.FileInfo, dst string) (skip, ok bool) {
+	dstInfo, err := os.Lstat(dst)
+	if err != nil {
+		return false, true
+	}
+
+	switch {
+	case os.SameFile(info, dstInfo):
+		c.report(fmt.Errorf("'%s' and '%s' are the same file", src, dst))
+		return false, false
+	case c.opts.NoClobber:
+		return true, true
+	case c.opts.Update && !info.IsDir() && !dstInfo.ModTime().Before(info.ModTime()):
+		return true, true
+	case info.IsDir() && !dstInfo.IsDir():
+		c.report(fmt.Errorf("cannot overwrite non-directory '%s' with directory '%s'", dst, src))
+		return false, false
+	case !info.IsDir() && dstInfo.IsDir():
+		c.report(fmt.Errorf("cannot overwrite directory '%s' with non-directory", dst))
+		return false, false
+	}
+	return false, true
+}
+
+// copy copies a file of any type. Symbolic links are copied as links
+// unless dereferencing. Only top-level operands have their destination
+// checked, as the entries of a new directory cannot exist yet.
+func (c *copier) copy(src, dst string, top bool) {
+	stat := os.Lstat
+	if c.opts.Dereference {
+		stat = os.Stat
+	}
+	info, err := stat(src)
+	if err != nil {
+		c.fail("cannot stat", src, err)
+		return
+	}
+
+	if top {
+		if info.IsDir() && c.opts.Recursive && isWithin(dst, src) {
+			c.report(fmt.Errorf("cannot copy a directory, '%s', into itself, '%s'", src, dst))
+			return
+		}
+		if skip, ok := c.checkDest(src, info, dst); skip || !ok {
+			return
+		}
+	}
+
+	mode := info.Mode()
+	switch {
+	case mode.IsDir():
+		c.copyDir(src, dst, info)
+		return
+	case mode.IsRegular():
+		err = c.copyFile(src, dst, info)
+	case mode&fs.ModeSymlink != 0:
+		err = c.copySymlink(src, dst)
+	case mode&(fs.ModeNamedPipe|fs.ModeDevice) != 0:
+		err = c.copySpecial(dst, info)
+	default:
+		c.report(fmt.Errorf("cannot copy '%s': unsupported file type", src))
+		return
+	}
+	if err != nil {
+		c.report(err)
+		return
+	}
+
+	if c.opts.Preserve {
+		c.preserve(src, dst, info)
+	}
+	c.verbose(src, dst)
+}
+
+// copyDir copies a directory and, recursively, its entries.
+func (c *copier) copyDir(src, dst string, info fs.FileInfo) {
+	if !c.opts.Recursive {
+		c.report(fmt.Errorf("-r not specified; omitting directory '%s'", src))
+		return
+	}
+
+	// The directory stays writable while its entries are copied; its
+	// permissions are set afterwards.
+	created := false
+	if err := os.Mkdir(dst, info.Mode().Perm()|0700); err == nil {
+		created = true
+	} else if dstInfo, statErr := os.Stat(dst); statErr != nil || !dstInfo.IsDir() {
+		c.fail("cannot create directory", dst, err)
+		return
+	}
+	c.verbose(src, dst)
+
+	entries, err := os.ReadDir(src)
+	if err != nil {
+		c.fail("cannot access", src, err)
+	}
+	for _, e := range entries {
+		c.copy(filepath.Join(src, e.Name()), filepath.Join(dst, e.Name()), false)
+	}
+
+	switch {
+	case c.opts.Preserve:
+		c.preserve(src, dst, info)
+	case created && info.Mode().Perm()&0700 != 0700:
+		if dstInfo, err := os.Stat(dst); err == nil {
+			perm := dstInfo.Mode().Perm()&^0700 | info.Mode().Perm()&0700
+			if err := os.Chmod(dst, perm); err != nil {
+				c.fail("cannot set permissions of", dst, err)
+			}
+		}
+	}
+}
+
+// copySymlink recreates a symbolic link.
+func (c *copier) copySymlink(src, dst string) error {
+	target, err := os.Readlink(src)
+	if err != nil {
+		return pathError("cannot read symbolic link", src, err)
+	}
+	if _, err := os.Lstat(dst); err == nil {
+		if err := os.Remove(dst); err != nil {
+			return pathError("cannot remove", dst, err)
+		}
+	}
+	if err := os.Symlink(target, dst); err != nil {
+		return pathError("cannot create symbolic link", dst, err)
+	}
+	return nil
+}
+
+// copySpecial recreates a named pipe or device node.
+func (c *copier) copySpecial(dst string, info fs.FileInfo) error {
+	stat, ok := fileinfo.Sys(info)
+	if !ok {
+		return fmt.Errorf("cannot create special file '%s': unsupported", dst)
+	}
+	if _, err := os.Lstat(dst); err == nil {
+		if err := os.Remove(dst); err != nil {
+			return pathError("cannot remove", dst, err)
+		}
+	}
+	if err := mknod(dst, uint32(stat.Mode), uint64(stat.Rdev)); err != nil {
+		return pathError("cannot create special file", dst, err)
+	}
+	return nil
+}
+
+// copyFile copies the contents of a regular file, cloning them or keeping
+// the holes of sparse files when possible.
+func (c *copier) copyFile(src, dst string, info fs.FileInfo) (err error) {
+	in, err := os.Open(src)
+	if err != nil {
+		return pathError("cannot open", src, err)
+	}
+	defer in.Close()
+
+	flags := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	out, err := os.OpenFile(dst, flags, info.Mode().Perm())
+	if err != nil && c.opts.Force {
+		if os.Remove(dst) == nil {
+			out, err = os.OpenFile(dst, flags, info.Mode().Perm())
+		}
+	}
+	if err != nil {
+		return pathError("cannot create regular file", dst, err)
+	}
+	defer func() {
+		if closeErr := out.Close(); err == nil && closeErr != nil {
+			err = pathError("failed to close", dst, closeErr)
+		}
+	}()
+
+	if c.opts.Reflink != ReflinkNever {
+		cloneErr := clone(out, in)
+		if cloneErr == nil {
+			return nil
+		}
+		if c.opts.Reflink == ReflinkAlways {
+			return fmt.Errorf("failed to clone '%s' from '%s': %v", dst, src, cloneErr)
+		}
+	}
+
+	if isSparse(info) {
+		err = copySparse(out, in, info.Size())
+	} else {
+		_, err = io.Copy(out, in)
+	}
+	if err != nil {
+		return fmt.Errorf("error copying '%s' to '%s': %v", src, dst, err)
+	}
+	return nil
+}
+
+// isSparse reports whether a file occupies fewer blocks than its size
+// needs, meaning it has holes.
+func isSparse(info fs.FileInfo) bool {
+	stat, ok := fileinfo.Sys(info)
+	return ok && int64(stat.Blocks)*512 < info.Size()
+}
+
+// copySparse copies size bytes, seeking over blocks of zeros instead of
+// writing them so that they become holes in the copy.
+func copySparse(out, in *os.File, size int64) error {
+	buf := make([]byte, 64*1024)
+	for {
+		n, err := in.Read(buf)
+		if n > 0 {
+			if isZero(buf[:n]) {
+				if _, err := out.Seek(int64(n), io.SeekCurrent); err != nil {
+					return err
+				}
+			} else if _, err := out.Write(buf[:n]); err != nil {
+				return err
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return out.Truncate(size)
+}
+
+// isZero reports whether b holds only zero bytes.
+func isZero(b []byte) bool {
+	for _, c := range b {
+		if c != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// preserve copies the mode, ownership, timestamps and extended attributes
+// of src to dst. Ownership that cannot be kept without privileges is
+// silently dropped.
+func (c *copier) preserve(src, dst string, info fs.FileInfo) {
+	if stat, ok := fileinfo.Sys(info); ok {
+		err := os.Lchown(dst, int(stat.Uid), int(stat.Gid))
+		if err != nil && !errors.Is(err, fs.ErrPermission) {
+			c.fail("failed to preserve ownership for", dst, err)
+		}
+	}
+
+	if info.Mode()&fs.ModeSymlink != 0 {
+		return
+	}
+
+	mode := info.Mode() & (fs.ModePerm | fs.ModeSetuid | fs.ModeSetgid | fs.ModeSticky)
+	if err := os.Chmod(dst, mode); err != nil {
+		c.fail("failed to preserve permissions for", dst, err)
+	}
+
+	atime, mtime, _ := fileinfo.Times(info)
+	if err := os.Chtimes(dst, atime, mtime); err != nil {
+		c.fail("failed to preserve times for", dst, err)
+	}
+
+	if err := copyXattrs(src, dst); err != nil {
+		c.fail("failed to preserve extended attributes for", dst, err)
+	}
+}
+
+// pathError returns an error about an operation on path, without the
+// operation name the os package adds.
+func pathError(op, path string, err error) error {
+	return fmt.Errorf("%s '%s': %v", op, path, exit.Unwrap(err))
+}
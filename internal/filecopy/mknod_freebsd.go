@@ -0,0 +1,8 @@
+package filecopy
+
+import "syscall"
+
+// mknod creates a special file, whose device number is 64 bits wide here.
+func mknod(path string, mode uint32, dev uint64) error {
+	return syscall.Mknod(path, mode, dev)
+}
@@ -0,0 +1,10 @@
+//go:build !freebsd
+
+package filecopy
+
+import "syscall"
+
+// mknod creates a special file.
+func mknod(path string, mode uint32, dev uint64) error {
+	return syscall.Mknod(path, mode, int(dev))
+}
@@ -0,0 +1,49 @@
+package filecopy
+
+import (
+	"bytes"
+	"errors"
+	"syscall"
+)
+
+// copyXattrs copies the extended attributes of src to dst. File systems
+// without extended attributes are not an error.
+func copyXattrs(src, dst string) error {
+	size, err := syscall.Listxattr(src, nil)
+	if err != nil || size == 0 {
+		return ignoreUnsupported(err)
+	}
+	list := make([]byte, size)
+	if size, err = syscall.Listxattr(src, list); err != nil {
+		return ignoreUnsupported(err)
+	}
+
+	for _, name := range bytes.Split(list[:size], []byte{0}) {
+		if len(name) == 0 {
+			continue
+		}
+		attr := string(name)
+
+		n, err := syscall.Getxattr(src, attr, nil)
+		if err != nil {
+			return ignoreUnsupported(err)
+		}
+		value := make([]byte, n)
+		if n, err = syscall.Getxattr(src, attr, value); err != nil {
+			return ignoreUnsupported(err)
+		}
+		if err := syscall.Setxattr(dst, attr, value[:n], 0); err != nil {
+			return ignoreUnsupported(err)
+		}
+	}
+	return nil
+}
+
+// ignoreUnsupported drops the errors of file systems that do not support
+// extended attributes, or the ones in use.
+func ignoreUnsupported(err error) error {
+	if errors.Is(err, syscall.ENOTSUP) || errors.Is(err, syscall.EPERM) {
+		return nil
+	}
+	return err
+}
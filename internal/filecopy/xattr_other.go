@@ -0,0 +1,8 @@
+//go:build !linux
+
+package filecopy
+
+// copyXattrs does nothing outside Linux.
+func copyXattrs(src, dst string) error {
+	return nil
+}
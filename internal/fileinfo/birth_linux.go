@@ -0,0 +1,52 @@
+//go:build linux && (amd64 || arm64)
+
+package fileinfo
+
+import (
+	"encoding/binary"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+// statx request and result flags from <linux/stat.h> and <fcntl.h>.
+const (
+	statxBtime        = 0x800
+	atFdcwd           = -100
+	atSymlinkNoFollow = 0x100
+	// statxSize is the size of struct statx and btimeOffset the offset of
+	// its stx_btime field.
+	statxSize   = 256
+	btimeOffset = 80
+)
+
+// Birth returns the creation time of the named file, if the file system
+// records it. Symbolic links are followed when follow is set.
+func Birth(name string, follow bool) (time.Time, bool) {
+	path, err := syscall.BytePtrFromString(name)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	flags := 0
+	if !follow {
+		flags = atSymlinkNoFollow
+	}
+
+	var buf [statxSize]byte
+	fd := atFdcwd
+	_, _, errno := syscall.Syscall6(sysStatx, uintptr(fd), uintptr(unsafe.Pointer(path)),
+		uintptr(flags), statxBtime, uintptr(unsafe.Pointer(&buf[0])), 0)
+	if errno != 0 {
+		return time.Time{}, false
+	}
+
+	mask := binary.LittleEndian.Uint32(buf[0:4])
+	if mask&statxBtime == 0 {
+		return time.Time{}, false
+	}
+
+	sec := int64(binary.LittleEndian.Uint64(buf[btimeOffset:]))
+	nsec := int64(binary.LittleEndian.Uint32(buf[btimeOffset+8:]))
+	return time.Unix(sec, nsec), true
+}
@@ -0,0 +1,11 @@
+//go:build !linux || !(amd64 || arm64)
+
+package fileinfo
+
+import "time"
+
+// Birth returns the creation time of the named file, if the file system
+// records it. It is not available on this platform.
+func Birth(name string, follow bool) (time.Time, bool) {
+	return time.Time{}, false
+}
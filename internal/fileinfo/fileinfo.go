@@ -0,0 +1,175 @@
+// Package fileinfo provides access to the Unix attributes of files, such as
+// owners and link counts, shared by the commands that display or test them.
+package fileinfo
+
+import (
+	"io/fs"
+	"os/user"
+	"strconv"
+	"sync"
+	"syscall"
+)
+
+var (
+	mu     sync.Mutex
+	users  = map[uint32]string{}
+	groups = map[uint32]string{}
+)
+
+// Sys returns the system stat structure of a file, if available.
+func Sys(info fs.FileInfo) (*syscall.Stat_t, bool) {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	return stat, ok
+}
+
+// UserName returns the name of the user with the given id. Names are cached
+// as many files usually share the same owner.
+func UserName(uid uint32) (string, error) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if name, ok := users[uid]; ok {
+		return name, nil
+	}
+
+	u, err := user.LookupId(strconv.FormatUint(uint64(uid), 10))
+	if err != nil {
+		return "", err
+	}
+	users[uid] = u.Username
+	return u.Username, nil
+}
+
+// GroupName returns the name of the group with the given id.
+func GroupName(gid uint32) (string, error) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if name, ok := groups[gid]; ok {
+		return name, nil
+	}
+
+	g, err := user.LookupGroupId(strconv.FormatUint(uint64(gid), 10))
+	if err != nil {
+		return "", err
+	}
+	groups[gid] = g.Name
+	return g.Name, nil
+}
+
+// UserID returns the id of the user given by name or by number.
+func UserID(name string) (uint32, error) {
+	if id, err := strconv.ParseUint(name, 10, 32); err == nil {
+		return uint32(id), nil
+	}
+
+	u, err := user.Lookup(name)
+	if err != nil {
+		return 0, err
+	}
+	id, err := strconv.ParseUint(u.Uid, 10, 32)
+	return uint32(id), err
+}
+
+// GroupID returns the id of the group given by name or by number.
+func GroupID(name string) (uint32, error) {
+	if id, err := strconv.ParseUint(name, 10, 32); err == nil {
+		return uint32(id), nil
+	}
+
+	g, err := user.LookupGroup(name)
+	if err != nil {
+		return 0, err
+	}
+	id, err := strconv.ParseUint(g.Gid, 10, 32)
+	return uint32(id), err
+}
+
+// OwnerName returns the name of the user owning a file, or the numeric id
+// when it has no name.
+func OwnerName(stat *syscall.Stat_t) string {
+	if name, err := UserName(stat.Uid); err == nil {
+		return name
+	}
+	return strconv.FormatUint(uint64(stat.Uid), 10)
+}
+
+// GroupOwnerName returns the name of the group owning a file, or the
+// numeric id when it has no name.
+func GroupOwnerName(stat *syscall.Stat_t) string {
+	if name, err := GroupName(stat.Gid); err == nil {
+		return name
+	}
+	return strconv.FormatUint(uint64(stat.Gid), 10)
+}
+
+// FSStats holds the statistics of a file system.
+type FSStats struct {
+	// Type is the magic number identifying the file system type.
+	Type int64
+
+	// BlockSize is the optimal transfer block size.
+	BlockSize int64
+
+	// FragSize is the fundamental block size in which the block counts
+	// are given.
+	FragSize int64
+
+	// Blocks, BlocksFree and BlocksAvail count all blocks, the free ones
+	// and those available to unprivileged users.
+	Blocks      uint64
+	BlocksFree  uint64
+	BlocksAvail uint64
+
+	// Files and FilesFree count all inodes and the free ones.
+	Files     uint64
+	FilesFree uint64
+
+	// ID is the file system identifier.
+	ID uint64
+
+	// NameLen is the maximum length of file names.
+	NameLen int64
+}
+
+// fsTypes maps the magic numbers of common file systems to their names.
+var fsTypes = map[int64]string{
+	0x0000EF53: "ext2/ext3",
+	0x01021994: "tmpfs",
+	0x00009FA0: "proc",
+	0x62656572: "sysfs",
+	0x58465342: "xfs",
+	0x9123683E: "btrfs",
+	0x794C7630: "overlayfs",
+	0x00006969: "nfs",
+	0x00001CD1: "devpts",
+	0x63677270: "cgroup2fs",
+	0x27E0EB:   "cgroupfs",
+	0x73717368: "squashfs",
+	0x00004D44: "msdos",
+	0x65735546: "fuseblk",
+	0x2011BAB0: "exfat",
+	0x5346544E: "ntfs",
+	0x858458F6: "ramfs",
+	0x9660:     "isofs",
+	0x01021997: "v9fs",
+}
+
+// TypeName returns the name of the file system type, or a placeholder for
+// unknown types.
+func (fs *FSStats) TypeName() string {
+	if name, ok := fsTypes[fs.Type]; ok {
+		return name
+	}
+	return "UNKNOWN (0x" + strconv.FormatInt(fs.Type, 16) + ")"
+}
+
+// Major returns the major number of a device id.
+func Major(dev uint64) uint64 {
+	return (dev>>8)&0xfff | (dev>>32)&^0xfff
+}
+
+// Minor returns the minor number of a device id.
+func Minor(dev uint64) uint64 {
+	return dev&0xff | (dev>>12)&^0xff
+}
@@ -0,0 +1,28 @@
+package fileinfo
+
+import "syscall"
+
+// Statfs returns the statistics of the file system containing path.
+func Statfs(path string) (*FSStats, error) {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(path, &st); err != nil {
+		return nil, err
+	}
+
+	fs := &FSStats{
+		Type:        int64(st.Type),
+		BlockSize:   int64(st.Bsize),
+		FragSize:    int64(st.Frsize),
+		Blocks:      uint64(st.Blocks),
+		BlocksFree:  uint64(st.Bfree),
+		BlocksAvail: uint64(st.Bavail),
+		Files:       uint64(st.Files),
+		FilesFree:   uint64(st.Ffree),
+		ID:          uint64(uint32(st.Fsid.X__val[0]))<<32 | uint64(uint32(st.Fsid.X__val[1])),
+		NameLen:     int64(st.Namelen),
+	}
+	if fs.FragSize == 0 {
+		fs.FragSize = fs.BlockSize
+	}
+	return fs, nil
+}
@@ -0,0 +1,10 @@
+//go:build !linux
+
+package fileinfo
+
+import "errors"
+
+// Statfs returns the statistics of the file system containing path.
+func Statfs(path string) (*FSStats, error) {
+	return nil, errors.New("file system statistics are only supported on Linux")
+}
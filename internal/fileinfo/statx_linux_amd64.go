@@ -0,0 +1,4 @@
+package fileinfo
+
+// sysStatx is the number of the statx system call.
+const sysStatx = 332
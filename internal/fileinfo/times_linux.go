@@ -0,0 +1,21 @@
+package fileinfo
+
+import (
+	"io/fs"
+	"syscall"
+	"time"
+)
+
+// Times returns the access, modification and status change times of a file.
+func Times(info fs.FileInfo) (atime, mtime, ctime time.Time) {
+	stat, ok := Sys(info)
+	if !ok {
+		return info.ModTime(), info.ModTime(), info.ModTime()
+	}
+	return timespec(stat.Atim), timespec(stat.Mtim), timespec(stat.Ctim)
+}
+
+// timespec converts a system time to a time.Time.
+func timespec(ts syscall.Timespec) time.Time {
+	return time.Unix(int64(ts.Sec), int64(ts.Nsec))
+}
@@ -0,0 +1,59 @@
+// Package fileinput provides helpers for opening command operands, treating
+// "-" as the standard input.
+package fileinput
+
+import (
+	"bytes"
+	"io"
+	"os"
+)
+
+// Stdin is the operand name that stands for the standard input.
+const Stdin = "-"
+
+// Args returns the given operands, or a single Stdin operand when none were
+// provided.
+func Args(args []string) []string {
+	if len(args) == 0 {
+		return []string{Stdin}
+	}
+	return args
+}
+
+// Open opens the named operand for reading.
+func Open(name string) (*os.File, error) {
+	if name == Stdin {
+		return os.Stdin, nil
+	}
+	return os.Open(name)
+}
+
+// Close closes a file returned by Open, leaving the standard input open.
+func Close(f *os.File) error {
+	if f == os.Stdin {
+		return nil
+	}
+	return f.Close()
+}
+
+// DisplayName returns the name used for an operand in headers and messages.
+func DisplayName(name string) string {
+	if name == Stdin {
+		return "standard input"
+	}
+	return name
+}
+
+// Rewindable returns f as a reader that can be read again from the start.
+// Files that cannot seek, such as pipes and terminals, are read into memory
+// first.
+func Rewindable(f *os.File) (io.ReadSeeker, error) {
+	if _, err := f.Seek(0, io.SeekCurrent); err == nil {
+		return f, nil
+	}
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return nil, err
+	}
+	return bytes.NewReader(data), nil
+}
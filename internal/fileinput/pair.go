@@ -0,0 +1,111 @@
+package fileinput
+
+import (
+	"bufio"
+	"io"
+	"os"
+
+	"github.com/skraio/unix-utilities/internal/lineio"
+)
+
+// pairBufferSize is the size of the buffers of a Pair, which line readers
+// built on them reuse.
+const pairBufferSize = 64 * 1024
+
+// Pair holds the two operands of a command comparing files, read side by
+// side. When both are the standard input, they share a single reader.
+type Pair struct {
+	Names   [2]string
+	files   [2]*os.File
+	readers [2]*bufio.Reader
+}
+
+// OpenPair opens two operands. The error, if any, names the operand that
+// could not be opened.
+func OpenPair(name1, name2 string) (*Pair, error) {
+	p := &Pair{Names: [2]string{name1, name2}}
+	for i, name := range p.Names {
+		if i == 1 && name == Stdin && name1 == Stdin {
+			p.files[1], p.readers[1] = p.files[0], p.readers[0]
+			break
+		}
+		f, err := Open(name)
+		if err != nil {
+			p.Close()
+			return nil, err
+		}
+		p.files[i] = f
+		p.readers[i] = bufio.NewReaderSize(f, pairBufferSize)
+	}
+	return p, nil
+}
+
+// Close closes both operands.
+func (p *Pair) Close() {
+	for _, f := range p.files {
+		if f != nil {
+			Close(f)
+		}
+	}
+}
+
+// Reader returns the reader of operand i, 0 or 1.
+func (p *Pair) Reader(i int) *bufio.Reader {
+	return p.readers[i]
+}
+
+// Lines returns readers of the lines of both operands, split on delim.
+func (p *Pair) Lines(delim byte) [2]*lineio.Reader {
+	return [2]*lineio.Reader{
+		lineio.NewReaderDelim(p.readers[0], delim),
+		lineio.NewReaderDelim(p.readers[1], delim),
+	}
+}
+
+// Skip discards the first n bytes of operand i, seeking past them when it
+// can. Skipping past the end leaves nothing to read.
+func (p *Pair) Skip(i int, n int64) error {
+	if n == 0 {
+		return nil
+	}
+	f, r := p.files[i], p.readers[i]
+	if r.Buffered() == 0 && p.files[0] != p.files[1] {
+		if _, err := f.Seek(n, io.SeekCurrent); err == nil {
+			r.Reset(f)
+			return nil
+		}
+	}
+	for n > 0 {
+		skipped, err := r.Discard(int(min(n, pairBufferSize)))
+		n -= int64(skipped)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Same reports whether both operands are the same file.
+func (p *Pair) Same() bool {
+	fi1, err1 := p.files[0].Stat()
+	fi2, err2 := p.files[1].Stat()
+	return err1 == nil && err2 == nil && os.SameFile(fi1, fi2)
+}
+
+// Remaining returns the number of bytes left to read from operand i when
+// it is a regular file, and -1 otherwise.
+func (p *Pair) Remaining(i int) int64 {
+	f := p.files[i]
+	fi, err := f.Stat()
+	if err != nil || !fi.Mode().IsRegular() {
+		return -1
+	}
+	pos, err := f.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return -1
+	}
+	return max(fi.Size()-pos+int64(p.readers[i].Buffered()), 0)
+}
@@ -0,0 +1,266 @@
+// Package filemode parses file modes given in octal or in the symbolic
+// notation of chmod, such as "u+rwx,g-w,o=" or "a+X", and converts between
+// fs.FileMode and the Unix encoding of permission bits.
+package filemode
+
+import (
+	"fmt"
+	"io/fs"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// Unix permission classes, each with its special bit.
+const (
+	userBits  = 04700
+	groupBits = 02070
+	otherBits = 01007
+	allBits   = 07777
+)
+
+// Bits returns the permission bits of mode in their Unix encoding,
+// including the set-id and sticky bits.
+func Bits(mode fs.FileMode) uint32 {
+	bits := uint32(mode.Perm())
+	if mode&fs.ModeSetuid != 0 {
+		bits |= 04000
+	}
+	if mode&fs.ModeSetgid != 0 {
+		bits |= 02000
+	}
+	if mode&fs.ModeSticky != 0 {
+		bits |= 01000
+	}
+	return bits
+}
+
+// FromBits returns the fs.FileMode of Unix permission bits.
+func FromBits(bits uint32) fs.FileMode {
+	mode := fs.FileMode(bits & 0777)
+	if bits&04000 != 0 {
+		mode |= fs.ModeSetuid
+	}
+	if bits&02000 != 0 {
+		mode |= fs.ModeSetgid
+	}
+	if bits&01000 != 0 {
+		mode |= fs.ModeSticky
+	}
+	return mode
+}
+
+// String returns the permissions of a file as Unix tools show them: the
+// type letter followed by the read, write and execute bits, with the
+// set-id and sticky bits in place of the execute ones.
+func String(mode fs.FileMode) string {
+	b := []byte("-rwxrwxrwx")
+	switch {
+	case mode.IsDir():
+		b[0] = 'd'
+	case mode&fs.ModeSymlink != 0:
+		b[0] = 'l'
+	case mode&fs.ModeNamedPipe != 0:
+		b[0] = 'p'
+	case mode&fs.ModeSocket != 0:
+		b[0] = 's'
+	case mode&fs.ModeCharDevice != 0:
+		b[0] = 'c'
+	case mode&fs.ModeDevice != 0:
+		b[0] = 'b'
+	}
+
+	for i := 0; i < 9; i++ {
+		if mode&(1<<uint(8-i)) == 0 {
+			b[i+1] = '-'
+		}
+	}
+
+	special := func(i int, set bool, c byte) {
+		if !set {
+			return
+		}
+		if b[i] == 'x' {
+			b[i] = c
+		} else {
+			b[i] = c - 'a' + 'A'
+		}
+	}
+	special(3, mode&fs.ModeSetuid != 0, 's')
+	special(6, mode&fs.ModeSetgid != 0, 's')
+	special(9, mode&fs.ModeSticky != 0, 't')
+
+	return string(b)
+}
+
+// Umask returns the file mode creation mask of the process.
+func Umask() fs.FileMode {
+	mask := syscall.Umask(0)
+	syscall.Umask(mask)
+	return FromBits(uint32(mask))
+}
+
+// action is an operator of a symbolic clause with its operand: permission
+// letters, or the class whose current permissions are copied.
+type action struct {
+	op    byte
+	perms string
+	copy  byte
+}
+
+// clause is a comma-separated part of a symbolic mode, such as "ug+rw".
+type clause struct {
+	who     uint32
+	actions []action
+}
+
+// Mode is a parsed mode that can be applied to the mode of a file.
+type Mode struct {
+	octal   bool
+	bits    uint32
+	clauses []clause
+
+	// keepSetID keeps the set-id bits of directories, which octal modes of
+	// up to four digits do not change.
+	keepSetID bool
+}
+
+// Of returns a mode setting the permissions of mode exactly, as taken from
+// a reference file.
+func Of(mode fs.FileMode) *Mode {
+	return &Mode{octal: true, bits: Bits(mode)}
+}
+
+// Parse parses an octal mode or a symbolic one made of comma-separated
+// clauses [ugoa]*([-+=]([rwxXst]*|[ugo]))+.
+func Parse(s string) (*Mode, error) {
+	if s != "" && s[0] >= '0' && s[0] <= '7' {
+		bits, err := strconv.ParseUint(s, 8, 32)
+		if err != nil || bits > allBits {
+			return nil, fmt.Errorf("invalid mode: '%s'", s)
+		}
+		return &Mode{octal: true, bits: uint32(bits), keepSetID: len(s) < 5}, nil
+	}
+
+	m := &Mode{}
+	for _, part := range strings.Split(s, ",") {
+		c, err := parseClause(part)
+		if err != nil {
+			return nil, fmt.Errorf("invalid mode: '%s'", s)
+		}
+		m.clauses = append(m.clauses, c)
+	}
+	return m, nil
+}
+
+// parseClause parses one clause of a symbolic mode.
+func parseClause(s string) (clause, error) {
+	c := clause{}
+	i := 0
+	for ; i < len(s) && strings.IndexByte("ugoa", s[i]) >= 0; i++ {
+		c.who |= map[byte]uint32{'u': userBits, 'g': groupBits, 'o': otherBits, 'a': allBits}[s[i]]
+	}
+	if i == len(s) {
+		return c, fmt.Errorf("missing operator")
+	}
+
+	for i < len(s) {
+		a := action{op: s[i]}
+		if strings.IndexByte("+-=", a.op) < 0 {
+			return c, fmt.Errorf("invalid operator")
+		}
+		i++
+
+		if i < len(s) && strings.IndexByte("ugo", s[i]) >= 0 {
+			a.copy = s[i]
+			i++
+		} else {
+			start := i
+			for i < len(s) && strings.IndexByte("rwxXst", s[i]) >= 0 {
+				i++
+			}
+			a.perms = s[start:i]
+		}
+		c.actions = append(c.actions, a)
+	}
+	return c, nil
+}
+
+// Apply returns mode changed as the parsed mode says, keeping its type
+// bits. umask limits the clauses that name no class, which set or clear
+// the bits not in umask, though '=' still clears all of them. As in chmod, the set-id bits of a
+// directory are kept unless the mode names them explicitly.
+func (m *Mode) Apply(mode fs.FileMode, umask fs.FileMode) fs.FileMode {
+	typ := mode &^ (fs.ModePerm | fs.ModeSetuid | fs.ModeSetgid | fs.ModeSticky)
+	bits := Bits(mode)
+	if m.octal {
+		if mode.IsDir() && m.keepSetID {
+			return typ | FromBits(bits&06000|m.bits)
+		}
+		return typ | FromBits(m.bits)
+	}
+
+	for _, c := range m.clauses {
+		who, mask := c.who, uint32(allBits)
+		if who == 0 {
+			who, mask = allBits, allBits&^Bits(umask)
+		}
+
+		for _, a := range c.actions {
+			value := a.value(bits, who, mode.IsDir())
+			var omit uint32
+			if mode.IsDir() {
+				omit = 06000 &^ value
+			}
+			value &= mask &^ omit
+
+			switch a.op {
+			case '+':
+				bits |= value
+			case '-':
+				bits &^= value
+			case '=':
+				bits = bits&(^who|omit)&allBits | value
+			}
+		}
+	}
+	return typ | FromBits(bits)
+}
+
+// value returns the bits an action sets or clears for the classes in who,
+// given the current bits of the file.
+func (a action) value(bits, who uint32, isDir bool) uint32 {
+	if a.copy != 0 {
+		var perm uint32
+		switch a.copy {
+		case 'u':
+			perm = bits >> 6 & 7
+		case 'g':
+			perm = bits >> 3 & 7
+		case 'o':
+			perm = bits & 7
+		}
+		return (perm<<6 | perm<<3 | perm) & who & 0777
+	}
+
+	var v uint32
+	for i := 0; i < len(a.perms); i++ {
+		switch a.perms[i] {
+		case 'r':
+			v |= 0444 & who
+		case 'w':
+			v |= 0222 & who
+		case 'x':
+			v |= 0111 & who
+		case 'X':
+			if isDir || bits&0111 != 0 {
+				v |= 0111 & who
+			}
+		case 's':
+			v |= 06000 & who
+		case 't':
+			v |= 01000 & who
+		}
+	}
+	return v
+}
@@ -0,0 +1,74 @@
+package filemode
+
+import (
+	"io/fs"
+	"testing"
+
+	"github.com/skraio/unix-utilities/internal/assert"
+)
+
+func TestBits(t *testing.T) {
+	assert.Equal(t, Bits(fs.ModeDir|fs.ModeSetgid|0755), uint32(02755))
+	assert.Equal(t, Bits(fs.ModeSetuid|fs.ModeSticky|0644), uint32(05644))
+	assert.Equal(t, FromBits(07644), fs.ModeSetuid|fs.ModeSetgid|fs.ModeSticky|0644)
+}
+
+func TestString(t *testing.T) {
+	assert.Equal(t, String(0644), "-rw-r--r--")
+	assert.Equal(t, String(fs.ModeDir|fs.ModeSetgid|fs.ModeSticky|0775), "drwxrwsr-t")
+	assert.Equal(t, String(fs.ModeSetuid|0644), "-rwSr--r--")
+	assert.Equal(t, String(fs.ModeDevice|fs.ModeCharDevice|0666), "crw-rw-rw-")
+}
+
+func TestParse(t *testing.T) {
+	tests := []string{"u", "u+q", "+r,", "8", "17777", "u=rw,", ""}
+	for _, s := range tests {
+		t.Run(s, func(t *testing.T) {
+			_, err := Parse(s)
+			assert.Equal(t, err != nil, true)
+		})
+	}
+}
+
+func TestApply(t *testing.T) {
+	tests := []struct {
+		mode  string
+		from  fs.FileMode
+		umask fs.FileMode
+		want  string
+	}{
+		{mode: "755", from: 0600, want: "-rwxr-xr-x"},
+		{mode: "4755", from: 0600, want: "urwxr-xr-x"},
+		{mode: "755", from: fs.ModeDir | fs.ModeSetgid | 0700, want: "dgrwxr-xr-x"},
+		{mode: "00755", from: fs.ModeDir | fs.ModeSetgid | 0700, want: "drwxr-xr-x"},
+		{mode: "u+rwx,g-w,o=", from: 0666, want: "-rwxr-----"},
+		{mode: "a+X", from: 0644, want: "-rw-r--r--"},
+		{mode: "a+X", from: 0744, want: "-rwxr-xr-x"},
+		{mode: "a+X", from: fs.ModeDir | 0644, want: "drwxr-xr-x"},
+		{mode: "+t", from: fs.ModeDir | 0777, want: "dtrwxrwxrwx"},
+		{mode: "g+s", from: 0755, want: "grwxr-xr-x"},
+		{mode: "u+s,g+s,o+t", from: 0644, want: "ugtrw-r--r--"},
+		{mode: "ug+s", from: 0755, want: "ugrwxr-xr-x"},
+		{mode: "u+t", from: 0644, want: "-rw-r--r--"},
+		{mode: "go=u", from: 0750, want: "-rwxrwxrwx"},
+		{mode: "g=u-w", from: 0640, want: "-rw-r-----"},
+		{mode: "u=rw,go=r", from: fs.ModeSetuid | 0777, want: "-rw-r--r--"},
+		{mode: "g=rx", from: fs.ModeDir | fs.ModeSetgid | 0777, want: "dgrwxr-xrwx"},
+		{mode: "u+", from: 0640, want: "-rw-r-----"},
+		{mode: "g-s", from: fs.ModeDir | fs.ModeSetgid | 0755, want: "drwxr-xr-x"},
+		{mode: "+w", from: 0444, umask: 022, want: "-rw-r--r--"},
+		{mode: "a+w", from: 0444, umask: 022, want: "-rw-rw-rw-"},
+		{mode: "=r", from: 0777, umask: 022, want: "-r--r--r--"},
+		{mode: "-x", from: 0777, umask: 0111, want: "-rwxrwxrwx"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			m, err := Parse(tt.mode)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, m.Apply(tt.from, tt.umask).String(), tt.want)
+		})
+	}
+}
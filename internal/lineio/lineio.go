@@ -0,0 +1,89 @@
+// Package lineio provides a streaming line reader that, unlike
+// bufio.Scanner, places no limit on the length of a line.
+package lineio
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+)
+
+// Reader reads delimiter-terminated lines from an underlying reader.
+type Reader struct {
+	r     *bufio.Reader
+	delim byte
+	buf   []byte
+}
+
+// NewReader returns a Reader splitting r on newlines.
+func NewReader(r io.Reader) *Reader {
+	return NewReaderDelim(r, '\n')
+}
+
+// NewReaderDelim returns a Reader splitting r on the given delimiter.
+func NewReaderDelim(r io.Reader, delim byte) *Reader {
+	return &Reader{r: bufio.NewReaderSize(r, 64*1024), delim: delim}
+}
+
+// ReadLine returns the next line including its delimiter, if any. The
+// returned slice is only valid until the next call. At the end of input it
+// returns io.EOF with no data.
+func (lr *Reader) ReadLine() ([]byte, error) {
+	lr.buf = lr.buf[:0]
+	for {
+		chunk, err := lr.r.ReadSlice(lr.delim)
+		lr.buf = append(lr.buf, chunk...)
+		if err == bufio.ErrBufferFull {
+			continue
+		}
+		if err == io.EOF && len(lr.buf) > 0 {
+			return lr.buf, nil
+		}
+		return lr.buf, err
+	}
+}
+
+// TrimDelim returns line without its trailing delimiter.
+func (lr *Reader) TrimDelim(line []byte) []byte {
+	if n := len(line); n > 0 && line[n-1] == lr.delim {
+		return line[:n-1]
+	}
+	return line
+}
+
+// Count returns the number of delimiters read from r until its end.
+func Count(r io.Reader, delim byte) (int, error) {
+	count := 0
+	buf := make([]byte, bufio.MaxScanTokenSize)
+	for {
+		n, err := r.Read(buf)
+		count += bytes.Count(buf[:n], []byte{delim})
+		if err == io.EOF {
+			return count, nil
+		}
+		if err != nil {
+			return count, err
+		}
+	}
+}
+
+// Cut returns the offset in b just past its nth delimiter, or len(b) when it
+// holds fewer, along with the number of delimiters before that offset.
+func Cut(b []byte, delim byte, n int) (int, int) {
+	end, count := 0, 0
+	for count < n {
+		i := bytes.IndexByte(b[end:], delim)
+		if i < 0 {
+			return len(b), count
+		}
+		end += i + 1
+		count++
+	}
+	return end, count
+}
+
+// IsBlank reports whether c is a blank, a space or a tab, as the commands
+// splitting lines into fields take them.
+func IsBlank(c byte) bool {
+	return c == ' ' || c == '\t'
+}
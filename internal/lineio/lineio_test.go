@@ -0,0 +1,56 @@
+package lineio
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/skraio/unix-utilities/internal/assert"
+)
+
+func TestCount(t *testing.T) {
+	count, err := Count(strings.NewReader("a\nb\x00c\n\nd"), '\n')
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, count, 3)
+}
+
+func TestCut(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		n     int
+		end   int
+		count int
+	}{
+		{
+			name:  "Enough lines",
+			text:  "a\nbb\nc\n",
+			n:     2,
+			end:   5,
+			count: 2,
+		},
+		{
+			name:  "Fewer lines",
+			text:  "a\nbb",
+			n:     2,
+			end:   4,
+			count: 1,
+		},
+		{
+			name:  "No lines wanted",
+			text:  "a\n",
+			n:     0,
+			end:   0,
+			count: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			end, count := Cut([]byte(tt.text), '\n', tt.n)
+			assert.Equal(t, end, tt.end)
+			assert.Equal(t, count, tt.count)
+		})
+	}
+}
@@ -0,0 +1,130 @@
+// Package linenum numbers lines as cat -n and nl do: a style selects the
+// lines given a number, a counter hands the numbers out and a format lays
+// them out in a field.
+package linenum
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/skraio/unix-utilities/internal/posixre"
+)
+
+// ErrStyle is returned for a style that is not one of those known.
+var ErrStyle = errors.New("invalid numbering style")
+
+// Style selects the lines that are numbered.
+type Style struct {
+	kind byte
+	re   *regexp.Regexp
+}
+
+// The styles other than those matching a regular expression.
+var (
+	All      = Style{kind: 'a'}
+	NonEmpty = Style{kind: 't'}
+	None     = Style{kind: 'n'}
+)
+
+// ParseStyle parses a style as given to nl: a for all lines, t for
+// non-empty lines, n for no lines and pBRE for lines matching the basic
+// regular expression BRE.
+func ParseStyle(s string) (Style, error) {
+	switch {
+	case s == "a":
+		return All, nil
+	case s == "t":
+		return NonEmpty, nil
+	case s == "n":
+		return None, nil
+	case len(s) > 0 && s[0] == 'p':
+		expr, err := posixre.TranslateBRE(s[1:])
+		if err != nil {
+			return Style{}, err
+		}
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return Style{}, err
+		}
+		return Style{kind: 'p', re: re}, nil
+	}
+	return Style{}, ErrStyle
+}
+
+// Selects reports whether line, given without its newline, is numbered.
+func (s Style) Selects(line []byte) bool {
+	switch s.kind {
+	case 'a':
+		return true
+	case 't':
+		return len(line) > 0
+	case 'p':
+		return s.re.Match(line)
+	}
+	return false
+}
+
+// IsAll reports whether s numbers all lines.
+func (s Style) IsAll() bool {
+	return s.kind == 'a'
+}
+
+// Format lays a number out in a field.
+type Format string
+
+// The formats of nl -n.
+const (
+	LeftJustified  Format = "ln"
+	RightJustified Format = "rn"
+	ZeroPadded     Format = "rz"
+)
+
+// ParseFormat parses a format as given to nl -n.
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(s); f {
+	case LeftJustified, RightJustified, ZeroPadded:
+		return f, nil
+	}
+	return "", fmt.Errorf("invalid line numbering format: '%s'", s)
+}
+
+// Pad returns n laid out in a field of the given width, which longer
+// numbers overflow.
+func (f Format) Pad(n int64, width int) string {
+	switch f {
+	case LeftJustified:
+		return fmt.Sprintf("%-*d", width, n)
+	case ZeroPadded:
+		return fmt.Sprintf("%0*d", width, n)
+	}
+	return fmt.Sprintf("%*d", width, n)
+}
+
+// Counter hands out line numbers.
+type Counter struct {
+	// Start is the number handed out first and after each Reset.
+	Start int64
+
+	// Increment is added to a number once it is handed out.
+	Increment int64
+
+	next int64
+}
+
+// NewCounter returns a counter starting at start.
+func NewCounter(start, increment int64) *Counter {
+	return &Counter{Start: start, Increment: increment, next: start}
+}
+
+// Next returns the next number.
+func (c *Counter) Next() int64 {
+	n := c.next
+	c.next += c.Increment
+	return n
+}
+
+// Reset starts the numbering over.
+func (c *Counter) Reset() {
+	c.next = c.Start
+}
@@ -0,0 +1,50 @@
+package linenum
+
+import (
+	"testing"
+
+	"github.com/skraio/unix-utilities/internal/assert"
+)
+
+func TestStyle(t *testing.T) {
+	tests := []struct {
+		style string
+		line  string
+		want  bool
+	}{
+		{style: "a", line: "", want: true},
+		{style: "t", line: "", want: false},
+		{style: "t", line: "x", want: true},
+		{style: "n", line: "x", want: false},
+		{style: "p^a\\+b$", line: "aab", want: true},
+		{style: "p^a\\+b$", line: "b", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.style+" "+tt.line, func(t *testing.T) {
+			s, err := ParseStyle(tt.style)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, s.Selects([]byte(tt.line)), tt.want)
+		})
+	}
+
+	_, err := ParseStyle("x")
+	assert.Equal(t, err, ErrStyle)
+}
+
+func TestPad(t *testing.T) {
+	assert.Equal(t, LeftJustified.Pad(12, 4), "12  ")
+	assert.Equal(t, RightJustified.Pad(12, 4), "  12")
+	assert.Equal(t, ZeroPadded.Pad(12, 4), "0012")
+	assert.Equal(t, RightJustified.Pad(12345, 4), "12345")
+}
+
+func TestCounter(t *testing.T) {
+	c := NewCounter(5, 2)
+	assert.Equal(t, c.Next(), int64(5))
+	assert.Equal(t, c.Next(), int64(7))
+	c.Reset()
+	assert.Equal(t, c.Next(), int64(5))
+}
@@ -0,0 +1,65 @@
+// Package listing formats files in the columns of a long listing, as ls -l
+// prints them, for the commands that show files the same way.
+package listing
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/skraio/unix-utilities/internal/fileinfo"
+	"github.com/skraio/unix-utilities/internal/units"
+)
+
+// TimeLayout is the layout of the time column.
+const TimeLayout = "Jan _2 15:04"
+
+// Columns holds the columns of a file in a long listing.
+type Columns struct {
+	Mode  fs.FileMode
+	Links string
+	Owner string
+	Size  string
+	Time  string
+}
+
+// Long returns the columns of a file, with its size in a human-readable
+// format if asked and its modification time.
+func Long(info fs.FileInfo, human bool) (Columns, error) {
+	c := Columns{Mode: info.Mode(), Time: info.ModTime().Format(TimeLayout)}
+	if stat, ok := fileinfo.Sys(info); ok {
+		c.Links = strconv.FormatUint(uint64(stat.Nlink), 10)
+
+		name, err := fileinfo.UserName(stat.Uid)
+		if err != nil {
+			return Columns{}, err
+		}
+		c.Owner = name
+	}
+
+	if human {
+		c.Size = HumanSize(info.Size())
+	} else {
+		c.Size = strconv.FormatInt(info.Size(), 10)
+	}
+	return c, nil
+}
+
+// HumanSize formats a size in whole mebibytes from one mebibyte on, larger
+// sizes included, as ls -h always has.
+func HumanSize(size int64) string {
+	return units.Format{Min: 1024 * 1024, Max: 1024 * 1024}.Size(size)
+}
+
+// NewWriter returns a writer aligning the lines written with Write. It must
+// be flushed once all lines are written.
+func NewWriter(w io.Writer) *tabwriter.Writer {
+	return tabwriter.NewWriter(w, 0, 0, 1, ' ', tabwriter.AlignRight)
+}
+
+// Write writes the line of a file.
+func Write(w io.Writer, c Columns, name string) {
+	fmt.Fprintf(w, "%s\t\t%s\t\t%s\t\t%s\t\t%s\t\t%s\n", c.Mode, c.Links, c.Owner, c.Size, c.Time, name)
+}
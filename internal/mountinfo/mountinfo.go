@@ -0,0 +1,98 @@
+// Package mountinfo reads the mount table of the current process, for the
+// commands that report on or act per mounted file system.
+package mountinfo
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Path is the mount table of the current process.
+const Path = "/proc/self/mountinfo"
+
+// Mount is an entry of the mount table.
+type Mount struct {
+	// Dev is the major:minor device number of the file system.
+	Dev string
+
+	// Root is the directory of the file system mounted at Target.
+	Root string
+
+	// Target is the mount point.
+	Target string
+
+	// FSType is the type of the file system.
+	FSType string
+
+	// Source is the mounted device or other source of the file system.
+	Source string
+}
+
+// Read reads the mount table of the current process.
+func Read() ([]*Mount, error) {
+	f, err := os.Open(Path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return Parse(f)
+}
+
+// Parse parses a mount table in the /proc/self/mountinfo format:
+//
+//	36 35 98:0 /mnt1 /mnt2 rw,noatime master:1 - ext3 /dev/root rw,errors=continue
+//
+// where the optional fields before the hyphen vary in number.
+func Parse(r io.Reader) ([]*Mount, error) {
+	mounts := []*Mount{}
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		fields := strings.Fields(sc.Text())
+		sep := -1
+		for i := 6; i < len(fields); i++ {
+			if fields[i] == "-" {
+				sep = i
+				break
+			}
+		}
+		if len(fields) < 6 || sep < 0 || sep+2 >= len(fields) {
+			return nil, fmt.Errorf("%s: malformed line: %s", Path, sc.Text())
+		}
+
+		mounts = append(mounts, &Mount{
+			Dev:    fields[2],
+			Root:   unescape(fields[3]),
+			Target: unescape(fields[4]),
+			FSType: fields[sep+1],
+			Source: unescape(fields[sep+2]),
+		})
+	}
+
+	return mounts, sc.Err()
+}
+
+// unescape decodes the octal escapes the kernel uses for spaces and other
+// special characters in paths.
+func unescape(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+4 <= len(s) {
+			if n, err := strconv.ParseUint(s[i+1:i+4], 8, 8); err == nil {
+				b.WriteByte(byte(n))
+				i += 3
+				continue
+			}
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
@@ -0,0 +1,28 @@
+package mountinfo
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/skraio/unix-utilities/internal/assert"
+)
+
+func TestParse(t *testing.T) {
+	text := `22 1 253:1 / / rw,relatime shared:1 - ext4 /dev/vda1 rw
+36 22 0:32 / /mnt/my\040disk rw,nosuid master:2 shared:7 - fuse.sshfs user@host:/home rw
+40 22 0:5 / /dev rw - devtmpfs udev rw,size=4k
+`
+	mounts, err := Parse(strings.NewReader(text))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, len(mounts), 3)
+	assert.Equal(t, *mounts[0], Mount{Dev: "253:1", Root: "/", Target: "/", FSType: "ext4", Source: "/dev/vda1"})
+	assert.Equal(t, *mounts[1], Mount{Dev: "0:32", Root: "/", Target: "/mnt/my disk", FSType: "fuse.sshfs", Source: "user@host:/home"})
+	assert.Equal(t, mounts[2].FSType, "devtmpfs")
+
+	if _, err := Parse(strings.NewReader("22 1 253:1 / / rw\n")); err == nil {
+		t.Error("Parse succeeded on a malformed line; want error")
+	}
+}
@@ -0,0 +1,233 @@
+// Package owner changes the user and group owning files, for the chown and
+// chgrp commands.
+package owner
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/skraio/unix-utilities/internal/exit"
+	"github.com/skraio/unix-utilities/internal/fileinfo"
+)
+
+// Spec is a user and group owning a file. An id of -1 leaves the owner
+// unchanged, or matches any owner in Options.From.
+type Spec struct {
+	UID int
+	GID int
+}
+
+// Any is the spec changing nothing and matching every file.
+var Any = Spec{UID: -1, GID: -1}
+
+// Parse parses an owner given as user, user:group, :group or user:, the
+// last one meaning the login group of the user. Users and groups are names
+// or numeric ids.
+func Parse(spec string) (Spec, error) {
+	s := Any
+	name, group, hasGroup := strings.Cut(spec, ":")
+	if name != "" {
+		uid, err := fileinfo.UserID(name)
+		if err != nil {
+			return Any, fmt.Errorf("invalid user: '%s'", spec)
+		}
+		s.UID = int(uid)
+	}
+
+	switch {
+	case group != "":
+		gid, err := ParseGroup(group)
+		if err != nil {
+			return Any, fmt.Errorf("invalid group: '%s'", spec)
+		}
+		s.GID = gid
+	case hasGroup && name != "":
+		u, err := user.LookupId(strconv.Itoa(s.UID))
+		if err != nil {
+			return Any, fmt.Errorf("invalid spec: '%s'", spec)
+		}
+		gid, err := strconv.Atoi(u.Gid)
+		if err != nil {
+			return Any, fmt.Errorf("invalid spec: '%s'", spec)
+		}
+		s.GID = gid
+	}
+	return s, nil
+}
+
+// ParseGroup parses a group name or numeric id.
+func ParseGroup(group string) (int, error) {
+	gid, err := fileinfo.GroupID(group)
+	if err != nil {
+		return -1, fmt.Errorf("invalid group: '%s'", group)
+	}
+	return int(gid), nil
+}
+
+// Of returns the owner of a file.
+func Of(info fs.FileInfo) Spec {
+	if stat, ok := fileinfo.Sys(info); ok {
+		return Spec{UID: int(stat.Uid), GID: int(stat.Gid)}
+	}
+	return Any
+}
+
+// Options configures how owners are changed.
+type Options struct {
+	// Recursive changes the files below directories too.
+	Recursive bool
+
+	// NoDereference changes symbolic links themselves rather than the
+	// files they point to. Links met while recursing are never followed.
+	NoDereference bool
+
+	// From restricts the change to files owned by this user and group.
+	From Spec
+
+	// Report is called with every error, letting the change continue with
+	// the next file.
+	Report func(err error)
+
+	// Describe is called for every file processed with what was done to
+	// it, and whether its owner changed.
+	Describe func(message string, changed bool)
+}
+
+// Change changes the owner of path, and of the files below it when
+// recursive, to the user and group of to. It reports whether all files
+// could be changed.
+func Change(path string, to Spec, opts Options) bool {
+	c := &changer{to: to, opts: opts, ok: true}
+	stat := os.Stat
+	if opts.Recursive || opts.NoDereference {
+		stat = os.Lstat
+	}
+	info, err := stat(path)
+	if err != nil {
+		c.report(fmt.Errorf("cannot access '%s': %v", path, exit.Unwrap(err)))
+		return false
+	}
+	c.change(path, info, !opts.Recursive && !opts.NoDereference)
+	return c.ok
+}
+
+// changer changes the owner of a hierarchy, remembering failures.
+type changer struct {
+	to   Spec
+	opts Options
+	ok   bool
+}
+
+// report passes an error on and marks the change as failed.
+func (c *changer) report(err error) {
+	if c.opts.Report != nil {
+		c.opts.Report(err)
+	}
+	c.ok = false
+}
+
+// change changes the owner of a file, following it if it is a symbolic
+// link and follow is set, then descends into directories when recursive.
+func (c *changer) change(path string, info fs.FileInfo, follow bool) {
+	old := Of(info)
+	if c.opts.From.matches(old) {
+		chown := os.Lchown
+		if follow {
+			chown = os.Chown
+		}
+		if err := chown(path, c.to.UID, c.to.GID); err != nil {
+			op := "changing ownership of"
+			if c.to.UID == -1 {
+				op = "changing group of"
+			}
+			c.report(fmt.Errorf("%s '%s': %v", op, path, exit.Unwrap(err)))
+			return
+		}
+		c.describe(path, old, c.to.apply(old))
+	} else {
+		c.describe(path, old, old)
+	}
+
+	if !c.opts.Recursive || !info.IsDir() {
+		return
+	}
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		c.report(fmt.Errorf("cannot read directory '%s': %v", path, exit.Unwrap(err)))
+		return
+	}
+	for _, e := range entries {
+		child := filepath.Join(path, e.Name())
+		info, err := os.Lstat(child)
+		if err != nil {
+			c.report(fmt.Errorf("cannot access '%s': %v", child, exit.Unwrap(err)))
+			continue
+		}
+		c.change(child, info, false)
+	}
+}
+
+// describe passes on what happened to a file, naming the user only when
+// it is being changed.
+func (c *changer) describe(path string, old, owner Spec) {
+	if c.opts.Describe == nil {
+		return
+	}
+
+	what := "ownership"
+	if c.to.UID == -1 {
+		what = "group"
+	}
+	from, to := c.to.names(old), c.to.names(owner)
+	if old != owner {
+		c.opts.Describe(fmt.Sprintf("changed %s of '%s' from %s to %s", what, path, from, to), true)
+	} else {
+		c.opts.Describe(fmt.Sprintf("%s of '%s' retained as %s", what, path, to), false)
+	}
+}
+
+// matches reports whether a file owned by owner matches the spec.
+func (s Spec) matches(owner Spec) bool {
+	return (s.UID == -1 || s.UID == owner.UID) && (s.GID == -1 || s.GID == owner.GID)
+}
+
+// apply returns the owner a file owned by owner gets.
+func (s Spec) apply(owner Spec) Spec {
+	if s.UID != -1 {
+		owner.UID = s.UID
+	}
+	if s.GID != -1 {
+		owner.GID = s.GID
+	}
+	return owner
+}
+
+// names returns the user and group of owner that s changes, as user:group,
+// user or group. Ids without a name are shown as numbers.
+func (s Spec) names(owner Spec) string {
+	userName := func() string {
+		if name, err := fileinfo.UserName(uint32(owner.UID)); err == nil {
+			return name
+		}
+		return strconv.Itoa(owner.UID)
+	}
+	groupName := func() string {
+		if name, err := fileinfo.GroupName(uint32(owner.GID)); err == nil {
+			return name
+		}
+		return strconv.Itoa(owner.GID)
+	}
+
+	switch {
+	case s.UID != -1 && s.GID != -1:
+		return userName() + ":" + groupName()
+	case s.UID != -1:
+		return userName()
+	}
+	return groupName()
+}
@@ -0,0 +1,68 @@
+package owner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/skraio/unix-utilities/internal/assert"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    Spec
+		wantErr string
+	}{
+		{spec: "0", want: Spec{UID: 0, GID: -1}},
+		{spec: "root:0", want: Spec{UID: 0, GID: 0}},
+		{spec: ":0", want: Spec{UID: -1, GID: 0}},
+		{spec: "root:", want: Spec{UID: 0, GID: 0}},
+		{spec: "123:456", want: Spec{UID: 123, GID: 456}},
+		{spec: "", want: Any},
+		{spec: "no such user", want: Any, wantErr: "invalid user: 'no such user'"},
+		{spec: "0:no such group", want: Any, wantErr: "invalid group: '0:no such group'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			got, err := Parse(tt.spec)
+			assert.Equal(t, got, tt.want)
+			if err != nil || tt.wantErr != "" {
+				assert.Equal(t, err.Error(), tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestChange(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "a/b"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("missing", filepath.Join(dir, "a/l")); err != nil {
+		t.Fatal(err)
+	}
+
+	// Changing files to their own owner works without privileges.
+	uid, gid := os.Getuid(), os.Getgid()
+	messages := []string{}
+	opts := Options{
+		Recursive: true,
+		From:      Any,
+		Report:    func(err error) { t.Error(err) },
+		Describe: func(message string, changed bool) {
+			assert.Equal(t, changed, false)
+			messages = append(messages, message)
+		},
+	}
+	assert.Equal(t, Change(filepath.Join(dir, "a"), Spec{UID: -1, GID: gid}, opts), true)
+	assert.Equal(t, len(messages), 3)
+
+	opts.From = Spec{UID: uid + 1, GID: -1}
+	opts.Describe = nil
+	assert.Equal(t, Change(filepath.Join(dir, "a"), Spec{UID: uid, GID: gid}, opts), true)
+
+	opts.Report = nil
+	assert.Equal(t, Change(filepath.Join(dir, "missing"), Spec{UID: uid, GID: -1}, opts), false)
+}
@@ -0,0 +1,121 @@
+// Package posixre translates POSIX basic and extended regular expressions
+// into the syntax of the regexp package.
+package posixre
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrBackReference is returned for patterns using back-references, which the
+// regexp package does not support.
+var ErrBackReference = errors.New("back-references are not supported")
+
+// TranslateBRE converts a POSIX basic regular expression into the syntax of
+// the regexp package.
+func TranslateBRE(p string) (string, error) {
+	var b strings.Builder
+	// atStart is set where '*' is literal and '^' is an anchor.
+	atStart := true
+	for i := 0; i < len(p); i++ {
+		c := p[i]
+		switch {
+		case c == '\\' && i+1 < len(p):
+			i++
+			switch e := p[i]; e {
+			case '(', ')', '{', '}', '|', '+', '?':
+				b.WriteByte(e)
+				atStart = e == '(' || e == '|'
+				continue
+			case '<', '>':
+				b.WriteString(`\b`)
+			case '1', '2', '3', '4', '5', '6', '7', '8', '9':
+				return "", ErrBackReference
+			default:
+				b.WriteByte('\\')
+				b.WriteByte(e)
+			}
+		case c == '[':
+			end := bracketEnd(p, i)
+			b.WriteString(p[i:end])
+			i = end - 1
+		case c == '*' && atStart:
+			b.WriteString(`\*`)
+		case c == '^' && !atStart:
+			b.WriteString(`\^`)
+		case c == '$' && !atEnd(p, i+1):
+			b.WriteString(`\$`)
+		case strings.IndexByte("(){}|+?", c) >= 0:
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		default:
+			b.WriteByte(c)
+		}
+		atStart = c == '^' && atStart
+	}
+
+	return b.String(), nil
+}
+
+// TranslateERE converts a POSIX extended regular expression into the syntax
+// of the regexp package.
+func TranslateERE(p string) (string, error) {
+	var b strings.Builder
+	for i := 0; i < len(p); i++ {
+		c := p[i]
+		switch {
+		case c == '\\' && i+1 < len(p):
+			i++
+			switch e := p[i]; e {
+			case '<', '>':
+				b.WriteString(`\b`)
+			case '1', '2', '3', '4', '5', '6', '7', '8', '9':
+				return "", ErrBackReference
+			default:
+				b.WriteByte('\\')
+				b.WriteByte(e)
+			}
+		case c == '[':
+			end := bracketEnd(p, i)
+			b.WriteString(p[i:end])
+			i = end - 1
+		default:
+			b.WriteByte(c)
+		}
+	}
+
+	return b.String(), nil
+}
+
+// atEnd reports whether offset i of a basic regular expression ends the
+// expression or a group, where '$' is an anchor.
+func atEnd(p string, i int) bool {
+	return i == len(p) || strings.HasPrefix(p[i:], `\)`) || strings.HasPrefix(p[i:], `\|`)
+}
+
+// bracketEnd returns the offset just past the bracket expression starting at
+// offset i of p, or len(p) when it is not terminated.
+func bracketEnd(p string, i int) int {
+	j := i + 1
+	if j < len(p) && p[j] == '^' {
+		j++
+	}
+	if j < len(p) && p[j] == ']' {
+		j++
+	}
+
+	for j < len(p) {
+		switch {
+		case p[j] == '[' && j+1 < len(p) && strings.IndexByte(":.=", p[j+1]) >= 0:
+			if end := strings.Index(p[j+2:], string(p[j+1])+"]"); end >= 0 {
+				j += end + 4
+				continue
+			}
+		case p[j] == ']':
+			return j + 1
+		}
+		j++
+	}
+
+	return len(p)
+}
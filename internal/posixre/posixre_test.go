@@ -0,0 +1,52 @@
+package posixre
+
+import (
+	"testing"
+
+	"github.com/skraio/unix-utilities/internal/assert"
+)
+
+func TestTranslateBRE(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		want    string
+	}{
+		{
+			name:    "Escaped groups and intervals",
+			pattern: `\(ab\)\{2\}`,
+			want:    `(ab){2}`,
+		},
+		{
+			name:    "Literal ERE operators",
+			pattern: `a+b?(c)|d`,
+			want:    `a\+b\?\(c\)\|d`,
+		},
+		{
+			name:    "Leading star and inner anchors",
+			pattern: `*a^b$c$`,
+			want:    `\*a\^b\$c$`,
+		},
+		{
+			name:    "Bracket expression",
+			pattern: `[]*[:alpha:]]*`,
+			want:    `[]*[:alpha:]]*`,
+		},
+		{
+			name:    "Word boundaries",
+			pattern: `\<word\>`,
+			want:    `\bword\b`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ans, err := TranslateBRE(tt.pattern)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assert.Equal(t, ans, tt.want)
+		})
+	}
+}
@@ -0,0 +1,156 @@
+// Package shellquote quotes strings for the shell the way GNU tools print
+// file names and arguments in their shell-escape quoting styles, so that the
+// output can be pasted back into a shell.
+package shellquote
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// class tells how a character of a string is quoted.
+type class int
+
+const (
+	// plain characters need no quoting.
+	plain class = iota
+	// literal characters need no quoting where they are, but keep a string
+	// out of double quotes.
+	literal
+	// blank characters need quoting, but are taken literally in double
+	// quotes as well as in single ones.
+	blank
+	// single is the single quote.
+	single
+	// special characters need single quotes.
+	special
+	// control characters are escaped as in $'...'.
+	control
+)
+
+// safe lists the ASCII characters that never need quoting.
+const safe = "%+,-./0123456789:@ABCDEFGHIJKLMNOPQRSTUVWXYZ]_abcdefghijklmnopqrstuvwxyz"
+
+// escapes maps the control characters with a named escape to it.
+var escapes = map[byte]byte{'\a': 'a', '\b': 'b', '\f': 'f', '\n': 'n', '\r': 'r', '\t': 't', '\v': 'v'}
+
+// Quote returns s as is when the shell would take it literally, and quoted
+// otherwise: in double quotes when that is enough for its single quotes,
+// else in single quotes with control characters escaped as in $'\t'.
+func Quote(s string) string {
+	return quote(s, false)
+}
+
+// Always returns s quoted as Quote does, even when it needs no quoting.
+func Always(s string) string {
+	return quote(s, true)
+}
+
+// quote quotes s, even when it needs no quoting with always.
+func quote(s string, always bool) string {
+	needed, double := s == "", true
+	hasSingle := false
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		switch classify(s, i, r, size) {
+		case blank:
+			needed = true
+		case single:
+			needed, hasSingle = true, true
+		case literal:
+			double = false
+		case special, control:
+			needed, double = true, false
+		}
+		i += size
+	}
+
+	switch {
+	case !needed && !always:
+		return s
+	case hasSingle && double:
+		return `"` + s + `"`
+	}
+
+	// GNU tools quote a string with single quotes twice, the first time
+	// only to measure it, and start the second time in $'...' when the
+	// first one ended in it. The output is kept the same.
+	escaping := false
+	if hasSingle {
+		escaping = singleQuote(&strings.Builder{}, s, false)
+	}
+	var b strings.Builder
+	b.WriteByte('\'')
+	singleQuote(&b, s, escaping)
+	b.WriteByte('\'')
+	return b.String()
+}
+
+// singleQuote writes s to b as the inside of single quotes, with control
+// characters in $'...', and reports whether it ended within $'...'.
+// escaping tells whether it starts within it, in which case a character
+// that is not escaped must first close it.
+func singleQuote(b *strings.Builder, s string, escaping bool) bool {
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		switch classify(s, i, r, size) {
+		case single:
+			b.WriteString(`'\''`)
+			escaping = false
+		case control:
+			if !escaping {
+				b.WriteString(`'$'`)
+				escaping = true
+			}
+			for _, c := range []byte(s[i : i+size]) {
+				b.WriteByte('\\')
+				if e, ok := escapes[c]; ok {
+					b.WriteByte(e)
+				} else {
+					b.WriteString(strconv.FormatUint(uint64(c)|0o1000, 8)[1:])
+				}
+			}
+		default:
+			if escaping {
+				b.WriteString(`''`)
+				escaping = false
+			}
+			b.WriteString(s[i : i+size])
+		}
+		i += size
+	}
+	return escaping
+}
+
+// classify returns the class of the rune r of size bytes at i in s. Some
+// characters only need quoting where the shell would treat them specially:
+// '#' and '~' at the start of a word and braces on their own.
+func classify(s string, i int, r rune, size int) class {
+	switch {
+	case r == utf8.RuneError && size == 1:
+		return control
+	case r == ' ':
+		return blank
+	case r == '\'':
+		return single
+	case r == '#' || r == '~':
+		if i == 0 {
+			return blank
+		}
+		return literal
+	case r == '{' || r == '}':
+		if len(s) == 1 {
+			return blank
+		}
+		return literal
+	case r < utf8.RuneSelf && strings.IndexByte(safe, byte(r)) >= 0:
+		return plain
+	case r < utf8.RuneSelf && unicode.IsPrint(r):
+		return special
+	case r >= utf8.RuneSelf && unicode.IsPrint(r):
+		return plain
+	}
+	return control
+}
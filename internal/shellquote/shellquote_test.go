@@ -0,0 +1,48 @@
+package shellquote
+
+import (
+	"testing"
+
+	"github.com/skraio/unix-utilities/internal/assert"
+)
+
+func TestQuote(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		want string
+	}{
+		{name: "Plain", s: "a-b_c.txt", want: "a-b_c.txt"},
+		{name: "Empty", s: "", want: "''"},
+		{name: "Space", s: "a b", want: "'a b'"},
+		{name: "Special", s: "$q", want: "'$q'"},
+		{name: "Double quote", s: `t"`, want: `'t"'`},
+		{name: "Single quote", s: "c'd", want: `"c'd"`},
+		{name: "Single quote and space", s: "a' b", want: `"a' b"`},
+		{name: "Single quote and special", s: "a'*b", want: `'a'\''*b'`},
+		{name: "Leading tilde", s: "~b", want: "'~b'"},
+		{name: "Inner tilde", s: "a~", want: "a~"},
+		{name: "Brace", s: "{", want: "'{'"},
+		{name: "Brace in word", s: "a{b", want: "a{b"},
+		{name: "Inner hash and single quote", s: "a#'", want: `'a#'\'''`},
+		{name: "Tab", s: "tab\there", want: `'tab'$'\t''here'`},
+		{name: "Trailing control", s: "a\x01", want: `'a'$'\001'`},
+		{name: "Control then quote", s: "a\t'b", want: `'a'$'\t'\''b'`},
+		{name: "Quote then control", s: "a'\tb", want: `'a'\'''$'\t''b'`},
+		{name: "Single quote then trailing control", s: "a'\x01", want: `'''a'\'''$'\001'`},
+		{name: "Invalid UTF-8", s: "\xff", want: `''$'\377'`},
+		{name: "Multibyte", s: "é", want: "é"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, Quote(tt.s), tt.want)
+		})
+	}
+}
+
+func TestAlways(t *testing.T) {
+	assert.Equal(t, Always("e"), "'e'")
+	assert.Equal(t, Always("q'uote"), `"q'uote"`)
+	assert.Equal(t, Always("sp ace"), "'sp ace'")
+}
@@ -0,0 +1,29 @@
+package textwidth
+
+import (
+	"testing"
+
+	"github.com/skraio/unix-utilities/internal/assert"
+)
+
+func TestString(t *testing.T) {
+	tests := []struct {
+		text string
+		want int
+	}{
+		{text: "abc", want: 3},
+		{text: "héllo", want: 5},
+		{text: "é", want: 1},
+		{text: "李小龙", want: 6},
+		{text: "ｈｉ", want: 4},
+		{text: "🙂!", want: 3},
+		{text: "a\xffb", want: 3},
+		{text: "a\tb", want: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			assert.Equal(t, String(tt.text), tt.want)
+		})
+	}
+}
@@ -0,0 +1,288 @@
+package translate
+
+import (
+	"fmt"
+	"strconv"
+	"unicode"
+	"unicode/utf8"
+)
+
+// token is a character of a set operand after escape processing. Escaped
+// characters never start a range, class or repeat construct.
+type token struct {
+	r       rune
+	escaped bool
+}
+
+// item is an element of a parsed set: a run of characters, a character
+// class or a [c*n] repeat, where n is -1 for [c*] which fills the set.
+type item struct {
+	runes  []rune
+	class  string
+	repeat int
+}
+
+// classes maps the POSIX character class names to their membership tests
+// in the C locale, used for bytes.
+var classes = map[string]func(rune) bool{
+	"alnum":  func(r rune) bool { return isAlpha(r) || isDigit(r) },
+	"alpha":  isAlpha,
+	"blank":  func(r rune) bool { return r == ' ' || r == '\t' },
+	"cntrl":  func(r rune) bool { return r < ' ' || r == 0x7f },
+	"digit":  isDigit,
+	"graph":  func(r rune) bool { return r > ' ' && r < 0x7f },
+	"lower":  func(r rune) bool { return r >= 'a' && r <= 'z' },
+	"print":  func(r rune) bool { return r >= ' ' && r < 0x7f },
+	"punct":  func(r rune) bool { return r > ' ' && r < 0x7f && !isAlpha(r) && !isDigit(r) },
+	"space":  func(r rune) bool { return r == ' ' || (r >= '\t' && r <= '\r') },
+	"upper":  func(r rune) bool { return r >= 'A' && r <= 'Z' },
+	"xdigit": func(r rune) bool { return isDigit(r) || (r|0x20 >= 'a' && r|0x20 <= 'f') },
+}
+
+// unicodeClasses maps the character class names to their membership tests
+// for UTF-8 input.
+var unicodeClasses = map[string]func(rune) bool{
+	"alnum":  func(r rune) bool { return unicode.IsLetter(r) || unicode.IsDigit(r) },
+	"alpha":  unicode.IsLetter,
+	"blank":  func(r rune) bool { return r == '\t' || unicode.Is(unicode.Zs, r) },
+	"cntrl":  unicode.IsControl,
+	"digit":  unicode.IsDigit,
+	"graph":  func(r rune) bool { return unicode.IsGraphic(r) && !unicode.IsSpace(r) },
+	"lower":  unicode.IsLower,
+	"print":  unicode.IsPrint,
+	"punct":  func(r rune) bool { return unicode.IsPunct(r) || unicode.IsSymbol(r) },
+	"space":  unicode.IsSpace,
+	"upper":  unicode.IsUpper,
+	"xdigit": classes["xdigit"],
+}
+
+// isAlpha reports whether r is an ASCII letter.
+func isAlpha(r rune) bool {
+	return r|0x20 >= 'a' && r|0x20 <= 'z'
+}
+
+// isDigit reports whether r is an ASCII digit.
+func isDigit(r rune) bool {
+	return r >= '0' && r <= '9'
+}
+
+// tokenize splits a set operand into characters, processing backslash
+// escapes. Without runes every byte is a character of its own.
+func tokenize(s string, runes bool) []token {
+	chars := []rune{}
+	if runes {
+		for len(s) > 0 {
+			r, size := utf8.DecodeRuneInString(s)
+			if r == utf8.RuneError && size == 1 {
+				r = rune(s[0])
+			}
+			chars = append(chars, r)
+			s = s[size:]
+		}
+	} else {
+		for i := 0; i < len(s); i++ {
+			chars = append(chars, rune(s[i]))
+		}
+	}
+
+	tokens := []token{}
+	for i := 0; i < len(chars); i++ {
+		if chars[i] != '\\' || i+1 == len(chars) {
+			tokens = append(tokens, token{r: chars[i]})
+			continue
+		}
+
+		i++
+		switch c := chars[i]; c {
+		case 'a':
+			tokens = append(tokens, token{r: '\a', escaped: true})
+		case 'b':
+			tokens = append(tokens, token{r: '\b', escaped: true})
+		case 'f':
+			tokens = append(tokens, token{r: '\f', escaped: true})
+		case 'n':
+			tokens = append(tokens, token{r: '\n', escaped: true})
+		case 'r':
+			tokens = append(tokens, token{r: '\r', escaped: true})
+		case 't':
+			tokens = append(tokens, token{r: '\t', escaped: true})
+		case 'v':
+			tokens = append(tokens, token{r: '\v', escaped: true})
+		default:
+			if c < '0' || c > '7' {
+				tokens = append(tokens, token{r: c, escaped: true})
+				break
+			}
+
+			n := rune(0)
+			for j := 0; j < 3 && i < len(chars) && chars[i] >= '0' && chars[i] <= '7' && n*8+chars[i]-'0' <= 0377; j++ {
+				n = n*8 + chars[i] - '0'
+				i++
+			}
+			i--
+			tokens = append(tokens, token{r: n, escaped: true})
+		}
+	}
+
+	return tokens
+}
+
+// parseSet parses a set operand into items. Repeats are only accepted when
+// allowRepeat is set, as they may only appear in the second set.
+func parseSet(s string, runes, allowRepeat bool) ([]item, error) {
+	tokens := tokenize(s, runes)
+	items := []item{}
+
+	for i := 0; i < len(tokens); i++ {
+		t := tokens[i]
+		if t.r == '[' && !t.escaped && i+1 < len(tokens) {
+			it, n, ok, err := parseBracket(tokens[i:])
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				if it.repeat != 0 && !allowRepeat {
+					return nil, fmt.Errorf("the [c*] repeat construct may not appear in string1")
+				}
+				items = append(items, it)
+				i += n - 1
+				continue
+			}
+		}
+
+		if i+2 < len(tokens) && tokens[i+1].r == '-' && !tokens[i+1].escaped {
+			end := tokens[i+2].r
+			if end < t.r {
+				return nil, fmt.Errorf("range-endpoints of '%c-%c' are in reverse collating sequence order", t.r, end)
+			}
+			it := item{}
+			for r := t.r; r <= end; r++ {
+				it.runes = append(it.runes, r)
+			}
+			items = append(items, it)
+			i += 2
+			continue
+		}
+
+		items = append(items, item{runes: []rune{t.r}})
+	}
+
+	return items, nil
+}
+
+// parseBracket parses a [:class:], [=c=] or [c*n] construct at the start of
+// tokens, returning the item and the number of tokens it spans. ok is false
+// when the bracket does not start such a construct and is taken literally.
+func parseBracket(tokens []token) (it item, n int, ok bool, err error) {
+	closing := func(from int, delim rune) int {
+		for j := from; j+1 < len(tokens); j++ {
+			if tokens[j].r == delim && !tokens[j].escaped && tokens[j+1].r == ']' && !tokens[j+1].escaped {
+				return j
+			}
+		}
+		return -1
+	}
+
+	switch second := tokens[1]; {
+	case second.r == ':' && !second.escaped:
+		end := closing(2, ':')
+		if end < 0 {
+			return item{}, 0, false, nil
+		}
+		name := ""
+		for _, t := range tokens[2:end] {
+			name += string(t.r)
+		}
+		if _, ok := classes[name]; !ok {
+			return item{}, 0, false, fmt.Errorf("invalid character class '%s'", name)
+		}
+		return item{class: name}, end + 2, true, nil
+
+	case second.r == '=' && !second.escaped:
+		if len(tokens) < 5 || closing(3, '=') != 3 {
+			return item{}, 0, false, nil
+		}
+		return item{runes: []rune{tokens[2].r}}, 5, true, nil
+
+	case len(tokens) > 3 && tokens[2].r == '*' && !tokens[2].escaped:
+		end := 3
+		for end < len(tokens) && tokens[end].r != ']' {
+			end++
+		}
+		if end == len(tokens) {
+			return item{}, 0, false, nil
+		}
+
+		count := ""
+		for _, t := range tokens[3:end] {
+			count += string(t.r)
+		}
+		repeat := -1
+		if count != "" {
+			base := 10
+			if count[0] == '0' {
+				base = 8
+			}
+			v, err := strconv.ParseInt(count, base, 32)
+			if err != nil {
+				return item{}, 0, false, fmt.Errorf("invalid repeat count '%s' in [c*n] construct", count)
+			}
+			if v > 0 {
+				repeat = int(v)
+			}
+		}
+		return item{runes: []rune{second.r}, repeat: repeat}, end + 1, true, nil
+	}
+
+	return item{}, 0, false, nil
+}
+
+// expand lists the characters of a parsed set in order, expanding classes
+// over the byte values and filling a [c*] repeat so that the set has at
+// least length characters.
+func expand(items []item, length int) []rune {
+	fixed := 0
+	for _, it := range items {
+		switch {
+		case it.class != "":
+			fixed += len(classChars(it.class))
+		case it.repeat > 0:
+			fixed += it.repeat
+		case it.repeat == 0:
+			fixed += len(it.runes)
+		}
+	}
+
+	runes := []rune{}
+	for _, it := range items {
+		switch {
+		case it.class != "":
+			runes = append(runes, classChars(it.class)...)
+		case it.repeat > 0:
+			for j := 0; j < it.repeat; j++ {
+				runes = append(runes, it.runes[0])
+			}
+		case it.repeat < 0:
+			for j := fixed; j < length; j++ {
+				runes = append(runes, it.runes[0])
+			}
+			fixed = length
+		default:
+			runes = append(runes, it.runes...)
+		}
+	}
+
+	return runes
+}
+
+// classChars lists the byte values in the named class in ascending order.
+func classChars(name string) []rune {
+	in := classes[name]
+	runes := []rune{}
+	for r := rune(0); r < 256; r++ {
+		if in(r) {
+			runes = append(runes, r)
+		}
+	}
+	return runes
+}
@@ -0,0 +1,297 @@
+// Package translate implements the character translation, deletion and
+// squeezing of tr as a streaming transformer, so that it can be applied to
+// any input as it is read.
+package translate
+
+import (
+	"errors"
+	"io"
+	"slices"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Options selects the operations performed by a Translator.
+type Options struct {
+	// Complement uses the characters not in the first set instead.
+	Complement bool
+
+	// Delete deletes the characters of the first set instead of translating
+	// them.
+	Delete bool
+
+	// Squeeze replaces each run of a repeated character of the last set
+	// with a single occurrence.
+	Squeeze bool
+
+	// Truncate truncates the first set to the length of the second.
+	Truncate bool
+
+	// UTF8 works on UTF-8 encoded characters instead of bytes.
+	UTF8 bool
+}
+
+// charSet is a set of characters given by an explicit list and, for UTF-8
+// input, character classes.
+type charSet struct {
+	runes      map[rune]bool
+	classes    []func(rune) bool
+	complement bool
+}
+
+// newCharSet builds the set of characters of the parsed items.
+func newCharSet(items []item, complement, runes bool) *charSet {
+	s := &charSet{runes: map[rune]bool{}, complement: complement}
+	for _, r := range expand(items, 0) {
+		s.runes[r] = true
+	}
+	if runes {
+		for _, it := range items {
+			if it.class != "" {
+				s.classes = append(s.classes, unicodeClasses[it.class])
+			}
+		}
+	}
+	return s
+}
+
+// contains reports whether r is in the set.
+func (s *charSet) contains(r rune) bool {
+	in := s.runes[r]
+	for _, class := range s.classes {
+		in = in || class(r)
+	}
+	return in != s.complement
+}
+
+// Translator transforms a stream of characters. It keeps state between
+// calls so that runs to squeeze and UTF-8 sequences may span several
+// chunks of input.
+type Translator struct {
+	utf8 bool
+
+	mapping     map[rune]rune
+	caseMap     func(rune) rune
+	caseSet     func(rune) bool
+	excluded    *charSet
+	defaultRune rune
+	deleteSet   *charSet
+	squeezeSet  *charSet
+
+	byteMap     [256]byte
+	byteDelete  [256]bool
+	byteSqueeze [256]bool
+
+	last    rune
+	hasLast bool
+	pending []byte
+}
+
+// New returns a Translator for the given sets. hasSet2 tells whether the
+// second set was given at all.
+func New(set1, set2 string, hasSet2 bool, opts Options) (*Translator, error) {
+	items1, err := parseSet(set1, opts.UTF8, false)
+	if err != nil {
+		return nil, err
+	}
+	items2, err := parseSet(set2, opts.UTF8, true)
+	if err != nil {
+		return nil, err
+	}
+
+	t := &Translator{utf8: opts.UTF8}
+	translating := !opts.Delete && hasSet2
+
+	switch {
+	case opts.Delete && opts.Squeeze && !hasSet2:
+		return nil, errors.New("missing operand after '" + set1 + "'; two strings must be given when both deleting and squeezing repeats")
+	case opts.Delete && !opts.Squeeze && hasSet2:
+		return nil, errors.New("extra operand '" + set2 + "'; only one string may be given when deleting without squeezing repeats")
+	case !opts.Delete && !opts.Squeeze && !hasSet2:
+		return nil, errors.New("missing operand after '" + set1 + "'; two strings must be given when translating")
+	}
+
+	if opts.Delete {
+		t.deleteSet = newCharSet(items1, opts.Complement, opts.UTF8)
+	}
+	if translating {
+		if err := t.buildMapping(items1, items2, opts); err != nil {
+			return nil, err
+		}
+	}
+	if opts.Squeeze {
+		if hasSet2 {
+			t.squeezeSet = newCharSet(items2, false, opts.UTF8)
+		} else {
+			t.squeezeSet = newCharSet(items1, opts.Complement, opts.UTF8)
+		}
+	}
+
+	for b := 0; b < 256; b++ {
+		r := t.translate(rune(b))
+		t.byteMap[b] = byte(r)
+		t.byteDelete[b] = t.deleteSet != nil && t.deleteSet.contains(rune(b))
+		t.byteSqueeze[b] = t.squeezeSet != nil && t.squeezeSet.contains(rune(b))
+	}
+
+	return t, nil
+}
+
+// buildMapping pairs the characters of the first set with those of the
+// second in order, repeating the last character of the second set as
+// needed.
+func (t *Translator) buildMapping(items1, items2 []item, opts Options) error {
+	for _, it := range items2 {
+		if it.class != "" && it.class != "upper" && it.class != "lower" {
+			return errors.New("when translating, the only character classes that may appear in string2 are 'upper' and 'lower'")
+		}
+	}
+
+	from := expand(items1, 0)
+	if opts.Complement {
+		set := newCharSet(items1, false, opts.UTF8)
+		from = from[:0]
+		for r := rune(0); r < 256; r++ {
+			if !set.contains(r) {
+				from = append(from, r)
+			}
+		}
+	}
+	to := expand(items2, len(from))
+	if opts.Truncate && len(to) < len(from) {
+		from = from[:len(to)]
+	}
+	if len(to) == 0 && len(from) > 0 {
+		return errors.New("when not truncating set1, string2 must be non-empty")
+	}
+
+	t.mapping = map[rune]rune{}
+	for i, r := range from {
+		t.mapping[r] = to[min(i, len(to)-1)]
+	}
+
+	// Only byte values are paired above, so with UTF-8 input the remaining
+	// characters of a complement translate to the last character of the
+	// second set, and case classes are converted using the Unicode tables.
+	if opts.UTF8 && opts.Complement && len(to) > 0 {
+		t.excluded = newCharSet(items1, false, true)
+		t.defaultRune = to[len(to)-1]
+	}
+	if opts.UTF8 && !opts.Complement && len(items1) == 1 && len(items2) == 1 {
+		switch {
+		case items1[0].class == "lower" && items2[0].class == "upper":
+			t.caseMap, t.caseSet = unicode.ToUpper, unicode.IsLower
+		case items1[0].class == "upper" && items2[0].class == "lower":
+			t.caseMap, t.caseSet = unicode.ToLower, unicode.IsUpper
+		}
+	}
+
+	return nil
+}
+
+// translate returns the character r is translated to.
+func (t *Translator) translate(r rune) rune {
+	if to, ok := t.mapping[r]; ok {
+		return to
+	}
+	switch {
+	case t.caseMap != nil && t.caseSet(r):
+		return t.caseMap(r)
+	case t.excluded != nil && !t.excluded.contains(r):
+		return t.defaultRune
+	}
+	return r
+}
+
+// Transform appends the transformed src to dst and returns the extended
+// buffer. An incomplete UTF-8 sequence at the end of src is held back until
+// the next call or Flush.
+func (t *Translator) Transform(dst, src []byte) []byte {
+	if !t.utf8 {
+		for _, b := range src {
+			if t.byteDelete[b] {
+				continue
+			}
+			b = t.byteMap[b]
+			if t.byteSqueeze[b] && t.hasLast && t.last == rune(b) {
+				continue
+			}
+			t.last, t.hasLast = rune(b), true
+			dst = append(dst, b)
+		}
+		return dst
+	}
+
+	if len(t.pending) > 0 {
+		src = append(t.pending, src...)
+		t.pending = nil
+	}
+
+	for len(src) > 0 {
+		r, size := utf8.DecodeRune(src)
+		if r == utf8.RuneError && size <= 1 {
+			if !utf8.FullRune(src) {
+				t.pending = slices.Clone(src)
+				break
+			}
+			// Invalid bytes are copied unchanged.
+			dst = append(dst, src[0])
+			t.hasLast = false
+			src = src[1:]
+			continue
+		}
+		src = src[size:]
+
+		if t.deleteSet != nil && t.deleteSet.contains(r) {
+			continue
+		}
+		r = t.translate(r)
+		if t.squeezeSet != nil && t.squeezeSet.contains(r) && t.hasLast && t.last == r {
+			continue
+		}
+		t.last, t.hasLast = r, true
+		dst = utf8.AppendRune(dst, r)
+	}
+
+	return dst
+}
+
+// Flush appends any held back incomplete UTF-8 sequence to dst unchanged.
+func (t *Translator) Flush(dst []byte) []byte {
+	dst = append(dst, t.pending...)
+	t.pending = nil
+	return dst
+}
+
+// Reader returns a reader yielding the transformed content of r.
+func (t *Translator) Reader(r io.Reader) io.Reader {
+	return &reader{r: r, t: t, in: make([]byte, 32*1024)}
+}
+
+// reader applies a Translator to an underlying reader.
+type reader struct {
+	r   io.Reader
+	t   *Translator
+	in  []byte
+	out []byte
+	err error
+}
+
+// Read implements io.Reader.
+func (tr *reader) Read(p []byte) (int, error) {
+	for len(tr.out) == 0 && tr.err == nil {
+		n, err := tr.r.Read(tr.in)
+		tr.out = tr.t.Transform(tr.out[:0], tr.in[:n])
+		if err != nil {
+			tr.out = tr.t.Flush(tr.out)
+			tr.err = err
+		}
+	}
+
+	n := copy(p, tr.out)
+	tr.out = tr.out[n:]
+	if len(tr.out) == 0 {
+		return n, tr.err
+	}
+	return n, nil
+}
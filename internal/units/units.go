@@ -0,0 +1,132 @@
+// Package units formats sizes in a human-readable form with unit suffixes,
+// as printed by the commands reporting file and disk sizes, and parses the
+// sizes given to commands with such suffixes.
+package units
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// suffixes lists the unit suffixes of successive powers of the base.
+const suffixes = "KMGTPE"
+
+// Format describes how sizes are scaled.
+type Format struct {
+	// Base is the size of a unit relative to the previous one, 1024 by
+	// default or 1000 for SI units.
+	Base int64
+
+	// Min is the smallest size shown with a suffix; smaller sizes are shown
+	// in bytes. It defaults to Base.
+	Min int64
+
+	// Max is the size of the largest unit sizes are scaled to, so that
+	// larger sizes are shown as many of that unit. It defaults to no limit.
+	Max int64
+
+	// RoundUp rounds scaled sizes up, to one decimal place below ten, like
+	// GNU tools do. Otherwise they are truncated to an integer.
+	RoundUp bool
+}
+
+// Size formats size according to f.
+func (f Format) Size(size int64) string {
+	base := f.Base
+	if base == 0 {
+		base = 1024
+	}
+	if size < max(f.Min, base) {
+		return strconv.FormatInt(size, 10)
+	}
+
+	value := float64(size)
+	unit := -1
+	for value >= float64(base) && f.scalable(unit, base) {
+		value /= float64(base)
+		unit++
+	}
+
+	if !f.RoundUp {
+		return strconv.FormatInt(int64(value), 10) + f.suffix(unit)
+	}
+
+	if value < 10 {
+		if v := math.Ceil(value*10) / 10; v < 10 {
+			return strconv.FormatFloat(v, 'f', 1, 64) + f.suffix(unit)
+		}
+	}
+	v := math.Ceil(value)
+	if v >= float64(base) && f.scalable(unit, base) {
+		return "1.0" + f.suffix(unit+1)
+	}
+	return strconv.FormatFloat(v, 'f', 0, 64) + f.suffix(unit)
+}
+
+// scalable reports whether sizes shown in the given unit may be shown in
+// the next one instead.
+func (f Format) scalable(unit int, base int64) bool {
+	if unit == len(suffixes)-1 {
+		return false
+	}
+	next := base
+	for i := 0; i <= unit; i++ {
+		next *= base
+	}
+	return f.Max == 0 || next <= f.Max
+}
+
+// suffix returns the suffix of the given unit, using a lowercase k for SI
+// kilobytes.
+func (f Format) suffix(unit int) string {
+	if unit == 0 && f.Base == 1000 {
+		return "k"
+	}
+	return suffixes[unit : unit+1]
+}
+
+// multipliers maps the unit suffixes accepted in size arguments to their
+// values.
+var multipliers = map[string]int64{
+	"": 1, "b": 512,
+	"K": 1 << 10, "k": 1 << 10, "KiB": 1 << 10, "kB": 1e3, "KB": 1e3,
+	"M": 1 << 20, "MiB": 1 << 20, "MB": 1e6,
+	"G": 1 << 30, "GiB": 1 << 30, "GB": 1e9,
+	"T": 1 << 40, "TiB": 1 << 40, "TB": 1e12,
+	"P": 1 << 50, "PiB": 1 << 50, "PB": 1e15,
+	"E": 1 << 60, "EiB": 1 << 60, "EB": 1e18,
+}
+
+// errInvalid is the error for a size that cannot be parsed.
+var errInvalid = errors.New("invalid size")
+
+// Parse parses a size argument: a decimal, hexadecimal (0x) or octal
+// (leading 0) number of bytes, optionally followed by a unit suffix such
+// as "b" for 512-byte blocks, "K", "KiB" or "kB".
+func Parse(s string) (int64, error) {
+	base, digits := 10, "0123456789"
+	num := s
+	switch {
+	case strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X"):
+		base, digits = 16, "0123456789abcdefABCDEF"
+		num = s[2:]
+	case strings.HasPrefix(s, "0") && len(s) > 1:
+		base, digits = 8, "01234567"
+	}
+
+	end := 0
+	for end < len(num) && strings.IndexByte(digits, num[end]) >= 0 {
+		end++
+	}
+	mult, ok := multipliers[num[end:]]
+	if !ok || end == 0 {
+		return 0, errInvalid
+	}
+	n, err := strconv.ParseInt(num[:end], base, 64)
+	if err != nil || n > math.MaxInt64/mult {
+		return 0, errInvalid
+	}
+	return n * mult, nil
+}
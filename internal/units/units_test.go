@@ -0,0 +1,53 @@
+package units
+
+import (
+	"testing"
+
+	"github.com/skraio/unix-utilities/internal/assert"
+)
+
+func TestSize(t *testing.T) {
+	tests := []struct {
+		format Format
+		size   int64
+		want   string
+	}{
+		{Format{}, 1023, "1023"},
+		{Format{}, 1536, "1K"},
+		{Format{RoundUp: true}, 1536, "1.5K"},
+		{Format{Base: 1000, RoundUp: true}, 1500, "1.5k"},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.format.Size(test.size), test.want)
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		arg  string
+		want int64
+		ok   bool
+	}{
+		{"10", 10, true},
+		{"0x10", 16, true},
+		{"010", 8, true},
+		{"0", 0, true},
+		{"2b", 1024, true},
+		{"1K", 1024, true},
+		{"1kB", 1000, true},
+		{"3MiB", 3 << 20, true},
+		{"1G", 1 << 30, true},
+		{"", 0, false},
+		{"K", 0, false},
+		{"1x", 0, false},
+		{"-1", 0, false},
+		{"9E", 0, false},
+	}
+
+	for _, test := range tests {
+		n, err := Parse(test.arg)
+		assert.Equal(t, err == nil, test.ok)
+		assert.Equal(t, n, test.want)
+	}
+}